@@ -0,0 +1,30 @@
+package texpr
+
+import (
+	"errors"
+)
+
+// An expression uses a value the roles given in Options.Roles are not allowed to use.
+var ErrPermission = errors.New("permission denied")
+
+// Returns whether a user with the given roles is allowed to use the value on this type. The user needs
+// one of the type's Roles and one of the value's Roles, where a type or value without roles is allowed
+// for everyone. When roles is nil access is not checked and every value is allowed.
+func (t Type) Allows(v *Value, roles []string) bool {
+	return roles == nil || (hasRole(t.Roles, roles) && hasRole(v.Roles, roles))
+}
+
+// Returns whether the required roles are empty or one of them is in the given roles.
+func hasRole(required []string, roles []string) bool {
+	if len(required) == 0 {
+		return true
+	}
+	for _, r := range required {
+		for _, role := range roles {
+			if r == role {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoles(t *testing.T) {
+	sys := NewSystemRequired([]Type{{
+		Name: "text",
+		Values: []Value{
+			{Path: "upper", Type: "text"},
+		},
+	}, {
+		Name: "customer",
+		Values: []Value{
+			{Path: "name", Type: "text"},
+			{Path: "taxId", Type: "text", Roles: []string{"admin", "billing"}},
+			{Path: "tenant", Type: "tenant"},
+			{Path: "card", Type: "account"},
+		},
+	}, {
+		Name: "account",
+		As:   map[TypeName]string{"text": "ssn"},
+		Values: []Value{
+			{Path: "ssn", Type: "text", Roles: []string{"admin"}},
+		},
+	}, {
+		Name:  "tenant",
+		Roles: []string{"admin"},
+		Values: []Value{
+			{Path: "plan", Type: "text"},
+		},
+	}})
+
+	tests := []struct {
+		expression string
+		roles      []string
+		message    string
+	}{
+		{expression: "taxId.upper"},
+		{expression: "name.upper", roles: []string{}},
+		{expression: "taxId", roles: []string{"billing"}},
+		{expression: "taxId.upper", roles: []string{"user"}, message: "customer.taxId is not allowed"},
+		{expression: "tenant.plan", roles: []string{"admin"}},
+		{expression: "tenant.plan", roles: []string{"billing"}, message: "tenant.plan is not allowed"},
+		{expression: "name.=(taxId)", roles: []string{}, message: "invalid value =\ncustomer.taxId is not allowed"},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			_, err := sys.Parse(Options{RootType: "customer", Expression: test.expression, Roles: test.roles})
+			if test.message == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.message)
+			}
+		})
+	}
+
+	_, err := sys.Parse(Options{RootType: "customer", Expression: "taxId", Roles: []string{"user"}})
+	assert.ErrorIs(t, err, ErrPermission)
+
+	_, err = sys.Parse(Options{RootType: "customer", Expression: "card", ExpectedTypes: []TypeName{"text"}, Roles: []string{"user"}})
+	assert.EqualError(t, err, "expected type(s) text but was given account instead")
+	e, err := sys.Parse(Options{RootType: "customer", Expression: "card", ExpectedTypes: []TypeName{"text"}, Roles: []string{"admin"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "ssn", e.Last().Token)
+
+	a, err := sys.Parse(Options{RootType: "customer", Expression: "card", Roles: []string{"user"}})
+	assert.NoError(t, err)
+	b, err := sys.Parse(Options{RootType: "account", Expression: "ssn"})
+	assert.NoError(t, err)
+	_, err = sys.Compose(a, b)
+	assert.ErrorIs(t, err, ErrPermission)
+
+	texts := func(completions []Completion) []string {
+		out := make([]string, len(completions))
+		for i, c := range completions {
+			out[i] = c.Text
+		}
+		return out
+	}
+	assert.Equal(t, []string{"name", "tenant", "card"}, texts(sys.Complete(Options{RootType: "customer", Expression: "n", Roles: []string{"user"}}, 0)))
+	assert.Equal(t, []string{"taxId", "tenant"}, texts(sys.Complete(Options{RootType: "customer", Expression: "t", Roles: []string{"billing"}}, 1)))
+	assert.Empty(t, sys.Complete(Options{RootType: "customer", Expression: "tenant.p", Roles: []string{"billing"}}, 8))
+}
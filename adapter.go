@@ -0,0 +1,158 @@
+package texpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Maps a type in one system to a type in another system.
+type TypeAdapter struct {
+	// The type in the source system.
+	From TypeName
+	// The type in the target system.
+	To TypeName
+	// The value paths on From mapped to the value paths on To. Values which are not mapped
+	// keep their path in the target system.
+	Values map[string]string
+	// Converts a runtime value of From into a value of To. When nil values are left as is.
+	Convert func(v any) (any, error)
+}
+
+// A set of type adapters from one system to another. Expressions parsed against the source system
+// can be translated into expressions of the target system, and evaluated with an evaluator of
+// the target system. Types without an adapter are mapped to the type with the same name in the target.
+type AdapterRegistry struct {
+	from     System
+	to       System
+	adapters map[TypeName]TypeAdapter
+}
+
+// Returns a new registry of the given adapters and an error if any adapter refers to types or
+// values which don't exist.
+func NewAdapterRegistry(from, to System, adapters ...TypeAdapter) (*AdapterRegistry, error) {
+	r := &AdapterRegistry{
+		from:     from,
+		to:       to,
+		adapters: make(map[TypeName]TypeAdapter, len(adapters)),
+	}
+	for _, adapter := range adapters {
+		fromType, toType := from.Type(adapter.From), to.Type(adapter.To)
+		if fromType == nil {
+			return nil, SystemError{Message: fmt.Sprintf("adapter type %s could not be found", adapter.From), Kind: ErrUnknownType}
+		}
+		if toType == nil {
+			return nil, SystemError{Message: fmt.Sprintf("adapter type %s could not be found", adapter.To), Kind: ErrUnknownType}
+		}
+		values := make(map[string]string, len(adapter.Values))
+		for fromPath, toPath := range adapter.Values {
+			if fromType.Value(fromPath) == nil {
+				return nil, SystemError{Message: fmt.Sprintf("adapter value %s.%s could not be found", adapter.From, fromPath), Type: fromType, Kind: ErrUnknownValue}
+			}
+			if toType.Value(toPath) == nil {
+				return nil, SystemError{Message: fmt.Sprintf("adapter value %s.%s could not be found", adapter.To, toPath), Type: toType, Kind: ErrUnknownValue}
+			}
+			values[strings.ToLower(fromPath)] = toPath
+		}
+		adapter.Values = values
+		r.adapters[adapter.From] = adapter
+	}
+	return r, nil
+}
+
+// Returns the type in the target system the given source type is adapted to, or nil if there is none.
+func (r *AdapterRegistry) AdaptType(name TypeName) *Type {
+	if adapter, exists := r.adapters[name]; exists {
+		return r.to.Type(adapter.To)
+	}
+	return r.to.Type(name)
+}
+
+// Converts a runtime value of the given source type into a value of its adapted type.
+func (r *AdapterRegistry) ConvertValue(name TypeName, v any) (any, error) {
+	if adapter, exists := r.adapters[name]; exists && adapter.Convert != nil {
+		return adapter.Convert(v)
+	}
+	return v, nil
+}
+
+// Translates an expression of the source system into a linked expression of the target system.
+// The expression given is not modified, and it's linked with the roles it was parsed with (see Options.Roles).
+func (r *AdapterRegistry) Translate(e *Expr) (*Expr, error) {
+	if e == nil || e.ParentType == nil {
+		return nil, NewParseErrorKind(e, ErrSyntax, "a linked expression is required to translate")
+	}
+	root := r.AdaptType(e.ParentType.Name)
+	if root == nil {
+		return nil, NewParseErrorKind(e, ErrUnknownType, fmt.Sprintf("type %s has no adapted type", e.ParentType.Name))
+	}
+	var expected []*Type
+	if last := e.Last(); last.Type != nil {
+		if adapted := r.AdaptType(last.Type.Name); adapted != nil {
+			expected = []*Type{adapted}
+		}
+	}
+
+	translated := e.Clone()
+	parameters := make(map[string]*Type)
+	if err := r.rename(translated, parameters); err != nil {
+		return nil, err
+	}
+
+	ctx := newLinkContext(root, parameters)
+	ctx.roles = e.roles
+	errs := r.to.link(translated, expected, ctx)
+	switch len(errs) {
+	case 0:
+		return translated, nil
+	case 1:
+		return translated, errs[0]
+	default:
+		return translated, ParseErrors(errs)
+	}
+}
+
+// Renames the values in the expression to their adapted paths and collects the adapted bind parameter types.
+func (r *AdapterRegistry) rename(e *Expr, parameters map[string]*Type) error {
+	for _, c := range e.Chain() {
+		if c.Bind && c.Type != nil {
+			adapted := r.AdaptType(c.Type.Name)
+			if adapted == nil {
+				return NewParseErrorKind(c, ErrUnknownType, fmt.Sprintf("type %s of parameter :%s has no adapted type", c.Type.Name, c.Token))
+			}
+			parameters[strings.ToLower(c.Token)] = adapted
+		}
+		if c.Value != nil && c.ParentType != nil {
+			parent := c.ParentType
+			if c.Prev != nil {
+				parent = c.Prev.Type
+			}
+			if adapter, exists := r.adapters[parent.Name]; exists {
+				if path, renamed := adapter.Values[strings.ToLower(c.Value.Path)]; renamed {
+					c.Token = path
+				}
+			}
+		}
+		for _, arg := range c.Arguments {
+			if err := r.rename(arg, parameters); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Returns an evaluator for expressions of the source system. Expressions are translated, their
+// root is converted, and then they are evaluated with the target system's evaluator.
+func (r *AdapterRegistry) Evaluator(target Evaluator) Evaluator {
+	return EvaluatorFunc(func(e *Expr, root any) (any, error) {
+		translated, err := r.Translate(e)
+		if err != nil {
+			return nil, err
+		}
+		convertedRoot, err := r.ConvertValue(e.ParentType.Name, root)
+		if err != nil {
+			return nil, err
+		}
+		return target.Evaluate(translated, convertedRoot)
+	})
+}
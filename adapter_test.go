@@ -0,0 +1,70 @@
+package texpr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type storedAccount struct {
+	FullName string
+}
+
+func TestAdapterRegistry(t *testing.T) {
+	storage := NewSystemRequired([]Type{{
+		Name:  "string",
+		Parse: func(x string) (any, error) { return x, nil },
+		Values: []Value{
+			{Path: "toLower", Type: "string"},
+			{Path: "includes", Type: "boolean", Parameters: []Parameter{{Name: "value", Type: "string"}}},
+		},
+	}, {
+		Name: "boolean",
+	}, {
+		Name: "account",
+		Values: []Value{
+			{Path: "fullName", Type: "string"},
+		},
+	}})
+
+	registry, err := NewAdapterRegistry(sys, storage, TypeAdapter{
+		From:   typeUser,
+		To:     "account",
+		Values: map[string]string{"name": "fullName"},
+		Convert: func(v any) (any, error) {
+			return storedAccount{FullName: v.(map[string]any)["name"].(string)}, nil
+		},
+	}, TypeAdapter{
+		From:   typeText,
+		To:     "string",
+		Values: map[string]string{"lower": "toLower", "contains": "includes"},
+	}, TypeAdapter{
+		From: typeBool,
+		To:   "boolean",
+	})
+	assert.NoError(t, err)
+
+	e, err := sys.Parse(Options{RootType: typeUser, Expression: "name.lower.contains(Ma)"})
+	assert.NoError(t, err)
+
+	translated, err := registry.Translate(e)
+	assert.NoError(t, err)
+	assert.Equal(t, "fullName.toLower.includes('Ma')", translated.String())
+	assert.Equal(t, "boolean", string(translated.Last().Type.Name))
+	assert.Equal(t, "name.lower.contains('Ma')", e.String())
+
+	target := EvaluatorFunc(func(e *Expr, root any) (any, error) {
+		account := root.(storedAccount)
+		return strings.Contains(strings.ToLower(account.FullName), e.Last().Arguments[0].Parsed.(string)), nil
+	})
+	result, err := registry.Evaluator(target).Evaluate(e, map[string]any{"name": "MARK"})
+	assert.NoError(t, err)
+	assert.Equal(t, false, result)
+
+	_, err = NewAdapterRegistry(sys, storage, TypeAdapter{From: typeUser, To: "account", Values: map[string]string{"name": "missing"}})
+	assert.ErrorIs(t, err, ErrUnknownValue)
+
+	_, err = NewAdapterRegistry(sys, storage, TypeAdapter{From: typeUser, To: "missing"})
+	assert.ErrorIs(t, err, ErrUnknownType)
+}
@@ -0,0 +1,39 @@
+package texpr
+
+// Returns the type a constant which more than one type can parse should be, given the token and the
+// types which parse it in the order they were tried. Returning nil or a type which is not a candidate
+// uses the first candidate. See SystemOptions.ResolveAmbiguity.
+type AmbiguityResolver func(token string, candidates []*Type) *Type
+
+// Finds the other types which parse the constant linked to its first matching type. When there are
+// any the resolver of the system chooses the type of the constant and a TraceAmbiguous event is given
+// to the trace.
+func (sys System) resolveAmbiguity(current *Expr, others []*Type, ctx *linkContext) {
+	candidates := []*Type{current.Type}
+	constants := []internedConstant{{token: current.Token, parsed: current.Parsed, layout: current.Layout}}
+	for _, other := range others {
+		if constant, err := sys.parseConstant(other, current.Token, ctx); err == nil {
+			candidates = append(candidates, other)
+			constants = append(constants, constant)
+		}
+	}
+	if len(candidates) == 1 {
+		return
+	}
+
+	chosen := 0
+	if sys.options.ResolveAmbiguity != nil {
+		if resolved := sys.options.ResolveAmbiguity(current.Token, candidates); resolved != nil {
+			for i, candidate := range candidates {
+				if candidate == resolved {
+					chosen = i
+				}
+			}
+		}
+	}
+	current.Type = candidates[chosen]
+	current.Token = constants[chosen].token
+	current.Parsed = constants[chosen].parsed
+	current.Layout = constants[chosen].layout
+	ctx.tracef(TraceEvent{Kind: TraceAmbiguous, Expr: current, Type: current.Type, Candidates: candidates}, "constant %s can be %s and %s was chosen", current.Token, getTypeNames(candidates), current.Type.Name)
+}
@@ -0,0 +1,77 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveAmbiguity(t *testing.T) {
+	types := []Type{{
+		Name:  "day",
+		Enums: []string{"monday", "tuesday"},
+	}, {
+		Name:  "text",
+		Parse: func(x string) (any, error) { return x, nil },
+		Values: []Value{
+			{Path: "upper", Type: "text"},
+		},
+	}}
+
+	// Without a resolver the first type in the parse order is used.
+	first := NewSystemRequired(types)
+	e, err := first.Parse(Options{RootType: "text", Expression: "monday"})
+	assert.NoError(t, err)
+	assert.Equal(t, TypeName("text"), e.Type.Name)
+
+	var given []*Type
+	resolved, err := NewSystemWithOptions(types, SystemOptions{ResolveAmbiguity: func(token string, candidates []*Type) *Type {
+		given = candidates
+		for _, candidate := range candidates {
+			if len(candidate.Enums) > 0 {
+				return candidate
+			}
+		}
+		return nil
+	}})
+	assert.NoError(t, err)
+
+	e, err = resolved.Parse(Options{RootType: "text", Expression: "monday"})
+	assert.NoError(t, err)
+	assert.Equal(t, TypeName("day"), e.Type.Name)
+	assert.Equal(t, "monday", e.Parsed)
+	assert.Equal(t, []*Type{resolved.Type("text"), resolved.Type("day")}, given)
+
+	// Constants only one type parses are not given to the resolver.
+	given = nil
+	e, err = resolved.Parse(Options{RootType: "text", Expression: "friday.upper"})
+	assert.NoError(t, err)
+	assert.Equal(t, TypeName("text"), e.Type.Name)
+	assert.Nil(t, given)
+
+	parsed, parsedType, err := resolved.ParseConstant("Tuesday")
+	assert.NoError(t, err)
+	assert.Equal(t, "tuesday", parsed)
+	assert.Equal(t, TypeName("day"), parsedType.Name)
+
+	// Types which are not candidates use the first candidate.
+	other, err := NewSystemWithOptions(types, SystemOptions{ResolveAmbiguity: func(token string, candidates []*Type) *Type {
+		return Unknown
+	}})
+	assert.NoError(t, err)
+	_, parsedType, err = other.ParseConstant("monday")
+	assert.NoError(t, err)
+	assert.Equal(t, TypeName("text"), parsedType.Name)
+}
+
+func TestTraceAmbiguity(t *testing.T) {
+	events := make([]string, 0)
+	_, err := sys.Parse(Options{RootType: typeContext, Expression: "monday", Trace: func(event TraceEvent) {
+		events = append(events, event.String())
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"ambiguous: constant monday can be dayOfWeek, text and dayOfWeek was chosen",
+		"constant: constant monday is dayOfWeek",
+	}, events)
+}
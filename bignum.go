@@ -0,0 +1,398 @@
+package texpr
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// The number of digits after the decimal point of a BigDecimal which has no exact decimal
+// representation (like 1/3) when it's formatted.
+const BigDecimalPrecision = 34
+
+// An arbitrary-precision integer. The zero value is 0 and values are never modified, so they can
+// be shared. The methods are the values of the type given by BigIntType when used with Reflect.
+type BigInt struct {
+	i *big.Int
+}
+
+// An arbitrary-precision decimal stored as an exact fraction, so arithmetic other than rounding never
+// loses precision. The zero value is 0 and values are never modified, so they can be shared. The methods
+// are the values of the type given by BigDecimalType when used with Reflect.
+type BigDecimal struct {
+	r *big.Rat
+}
+
+// Returns a BigInt of the integer.
+func NewBigInt(i int64) BigInt {
+	return BigInt{big.NewInt(i)}
+}
+
+// Returns a BigInt which has a copy of the integer.
+func BigIntOf(i *big.Int) BigInt {
+	return BigInt{new(big.Int).Set(i)}
+}
+
+// Parses an integer in base 10, or in base 16, 8, or 2 with a 0x, 0o, or 0b prefix. Underscores may separate digits.
+func ParseBigInt(x string) (BigInt, error) {
+	i, ok := new(big.Int).SetString(strings.TrimSpace(x), 0)
+	if !ok {
+		return BigInt{}, fmt.Errorf("%q is not an integer", x)
+	}
+	return BigInt{i}, nil
+}
+
+// Returns a BigDecimal of the float, which is exact.
+func NewBigDecimal(f float64) (BigDecimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return BigDecimal{}, fmt.Errorf("%v is not a decimal", f)
+	}
+	return BigDecimal{new(big.Rat).SetFloat64(f)}, nil
+}
+
+// Returns a BigDecimal which has a copy of the fraction.
+func BigDecimalOf(r *big.Rat) BigDecimal {
+	return BigDecimal{new(big.Rat).Set(r)}
+}
+
+// Parses a decimal like 12.50, -3, 1e-9, or a fraction like 1/3.
+func ParseBigDecimal(x string) (BigDecimal, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(x))
+	if !ok {
+		return BigDecimal{}, fmt.Errorf("%q is not a decimal", x)
+	}
+	return BigDecimal{r}, nil
+}
+
+// Returns the integer, which must not be modified.
+func (x BigInt) Big() *big.Int {
+	if x.i == nil {
+		return new(big.Int)
+	}
+	return x.i
+}
+
+func (x BigInt) String() string {
+	return x.Big().String()
+}
+
+func (x BigInt) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+func (x *BigInt) UnmarshalText(text []byte) error {
+	parsed, err := ParseBigInt(string(text))
+	if err == nil {
+		*x = parsed
+	}
+	return err
+}
+
+// Returns the absolute value of the integer.
+func (x BigInt) Abs() BigInt {
+	return BigInt{new(big.Int).Abs(x.Big())}
+}
+
+// Returns the negation of the integer.
+func (x BigInt) Neg() BigInt {
+	return BigInt{new(big.Int).Neg(x.Big())}
+}
+
+// Returns the remainder of dividing the integer by the divisor, which has the sign of the integer.
+func (x BigInt) Mod(divisor BigInt) (BigInt, error) {
+	if divisor.Big().Sign() == 0 {
+		return BigInt{}, fmt.Errorf("division by zero")
+	}
+	return BigInt{new(big.Int).Rem(x.Big(), divisor.Big())}, nil
+}
+
+// Returns the integer raised to the exponent, which can't be negative.
+func (x BigInt) Pow(exponent BigInt) (BigInt, error) {
+	if exponent.Big().Sign() < 0 {
+		return BigInt{}, fmt.Errorf("negative exponent %v", exponent)
+	}
+	return BigInt{new(big.Int).Exp(x.Big(), exponent.Big(), nil)}, nil
+}
+
+// Returns the integer as a decimal.
+func (x BigInt) Decimal() BigDecimal {
+	return BigDecimal{new(big.Rat).SetInt(x.Big())}
+}
+
+// Returns the integer as an int64, or an error if it doesn't fit.
+func (x BigInt) Int64() (int64, error) {
+	if !x.Big().IsInt64() {
+		return 0, fmt.Errorf("%v does not fit in an int64", x)
+	}
+	return x.Big().Int64(), nil
+}
+
+// Returns the nearest float64 of the integer.
+func (x BigInt) Float64() float64 {
+	f, _ := new(big.Float).SetInt(x.Big()).Float64()
+	return f
+}
+
+// Returns the fraction, which must not be modified.
+func (x BigDecimal) Big() *big.Rat {
+	if x.r == nil {
+		return new(big.Rat)
+	}
+	return x.r
+}
+
+// Returns the decimal with every digit when it has an exact decimal representation, otherwise
+// it's rounded to BigDecimalPrecision digits after the decimal point.
+func (x BigDecimal) String() string {
+	r := x.Big()
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	places := decimalPlaces(r.Denom())
+	if places < 0 {
+		places = BigDecimalPrecision
+	}
+	return r.FloatString(places)
+}
+
+func (x BigDecimal) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+func (x *BigDecimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseBigDecimal(string(text))
+	if err == nil {
+		*x = parsed
+	}
+	return err
+}
+
+// Returns the absolute value of the decimal.
+func (x BigDecimal) Abs() BigDecimal {
+	return BigDecimal{new(big.Rat).Abs(x.Big())}
+}
+
+// Returns the negation of the decimal.
+func (x BigDecimal) Neg() BigDecimal {
+	return BigDecimal{new(big.Rat).Neg(x.Big())}
+}
+
+// Returns the decimal rounded half away from zero to the number of digits after the decimal point.
+func (x BigDecimal) Round(places int) BigDecimal {
+	rounded, _ := new(big.Rat).SetString(x.Big().FloatString(places))
+	return BigDecimal{rounded}
+}
+
+// Returns the integer part of the decimal.
+func (x BigDecimal) Truncate() BigInt {
+	return BigInt{new(big.Int).Quo(x.Big().Num(), x.Big().Denom())}
+}
+
+// Returns the nearest float64 of the decimal.
+func (x BigDecimal) Float64() float64 {
+	f, _ := x.Big().Float64()
+	return f
+}
+
+// Returns the number of digits after the decimal point needed to represent a fraction with the
+// denominator exactly, or -1 if it has no exact decimal representation.
+func decimalPlaces(denom *big.Int) int {
+	d := new(big.Int).Set(denom)
+	twos, fives := 0, 0
+	five := big.NewInt(5)
+	remainder := new(big.Int)
+	for d.Bit(0) == 0 && d.Sign() > 0 {
+		d.Rsh(d, 1)
+		twos++
+	}
+	for {
+		q, r := new(big.Int).QuoRem(d, five, remainder)
+		if r.Sign() != 0 {
+			break
+		}
+		d = q
+		fives++
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return -1
+	}
+	if twos > fives {
+		return twos
+	}
+	return fives
+}
+
+// The options of the arbitrary-precision number types, see BigIntType and BigDecimalType.
+type BigNumberOptions struct {
+	// The name of the BigInt type, by default "bigint".
+	BigInt TypeName
+	// The name of the BigDecimal type, by default "bigdecimal".
+	BigDecimal TypeName
+	// The int64 type integers can be converted to, if any.
+	Int TypeName
+	// The float64 type integers and decimals can be converted to, if any.
+	Float TypeName
+}
+
+func (o BigNumberOptions) names() (bigInt TypeName, bigDecimal TypeName) {
+	bigInt, bigDecimal = o.BigInt, o.BigDecimal
+	if bigInt == "" {
+		bigInt = "bigint"
+	}
+	if bigDecimal == "" {
+		bigDecimal = "bigdecimal"
+	}
+	return
+}
+
+// Returns the definition of the BigInt type to give to Reflect with TypeOf[BigInt](). It's comparable,
+// ordered, and numeric with BigOperations, and it converts to the decimal type and the int and float types
+// of the options. Division truncates towards zero. Fixed-size integer types can convert to it with a
+// method that returns NewBigInt and an As conversion.
+func BigIntType(options BigNumberOptions) Type {
+	name, decimal := options.names()
+	t := Type{
+		Name:        name,
+		Description: "An integer of any size",
+		Comparable:  true,
+		Ordered:     true,
+		Numeric:     true,
+		Operations:  BigOperations{},
+		Parse: func(x string) (any, error) {
+			return ParseBigInt(x)
+		},
+		As: map[TypeName]string{
+			decimal: "Decimal",
+		},
+	}
+	if options.Int != "" {
+		t.As[options.Int] = "Int64"
+	}
+	if options.Float != "" {
+		t.As[options.Float] = "Float64"
+	}
+	return t
+}
+
+// Returns the definition of the BigDecimal type to give to Reflect with TypeOf[BigDecimal](). It's
+// comparable, ordered, and numeric with BigOperations, and it converts to the float type of the options.
+// Division is exact. Fixed-size number types can convert to it with a method that returns NewBigDecimal
+// and an As conversion.
+func BigDecimalType(options BigNumberOptions) Type {
+	_, name := options.names()
+	t := Type{
+		Name:        name,
+		Description: "A decimal of any size and precision",
+		Comparable:  true,
+		Ordered:     true,
+		Numeric:     true,
+		Operations:  BigOperations{},
+		Parse: func(x string) (any, error) {
+			return ParseBigDecimal(x)
+		},
+	}
+	if options.Float != "" {
+		t.As = map[TypeName]string{options.Float: "Float64"}
+	}
+	return t
+}
+
+// Operations on BigInt and BigDecimal values, which can be mixed with each other and Go numbers.
+// The result of arithmetic has the type of the first value, so a BigInt divided by anything is
+// truncated. Other values use StandardOperations.
+type BigOperations struct{}
+
+var _ Operations = BigOperations{}
+
+func (BigOperations) Equal(a, b any) (bool, error) {
+	if c, err := (BigOperations{}).Compare(a, b); err == nil {
+		return c == 0, nil
+	}
+	return StandardOperations{}.Equal(a, b)
+}
+
+func (BigOperations) Compare(a, b any) (int, error) {
+	ar, aok := bigRat(a)
+	br, bok := bigRat(b)
+	if !aok || !bok {
+		return StandardOperations{}.Compare(a, b)
+	}
+	return ar.Cmp(br), nil
+}
+
+func (BigOperations) Arithmetic(op Operator, a, b any) (any, error) {
+	br, ok := bigRat(b)
+	if !ok {
+		return nil, fmt.Errorf("operator %s is not supported for %v (%T) and %v (%T)", op, a, a, b, b)
+	}
+	switch x := a.(type) {
+	case BigInt:
+		y := new(big.Int).Quo(br.Num(), br.Denom())
+		result := new(big.Int)
+		switch op {
+		case OpAdd:
+			result.Add(x.Big(), y)
+		case OpSubtract:
+			result.Sub(x.Big(), y)
+		case OpMultiply:
+			result.Mul(x.Big(), y)
+		case OpDivide:
+			if y.Sign() == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			result.Quo(x.Big(), y)
+		default:
+			return nil, fmt.Errorf("unknown operator %s", op)
+		}
+		return BigInt{result}, nil
+	case BigDecimal:
+		result := new(big.Rat)
+		switch op {
+		case OpAdd:
+			result.Add(x.Big(), br)
+		case OpSubtract:
+			result.Sub(x.Big(), br)
+		case OpMultiply:
+			result.Mul(x.Big(), br)
+		case OpDivide:
+			if br.Sign() == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			result.Quo(x.Big(), br)
+		default:
+			return nil, fmt.Errorf("unknown operator %s", op)
+		}
+		return BigDecimal{result}, nil
+	}
+	return StandardOperations{}.Arithmetic(op, a, b)
+}
+
+// Returns the exact fraction of a BigInt, BigDecimal, math/big number, or Go number.
+func bigRat(v any) (*big.Rat, bool) {
+	switch x := v.(type) {
+	case BigInt:
+		return new(big.Rat).SetInt(x.Big()), true
+	case BigDecimal:
+		return x.Big(), true
+	case *big.Int:
+		return new(big.Rat).SetInt(x), x != nil
+	case *big.Rat:
+		return x, x != nil
+	}
+	rv := reflect.ValueOf(v)
+	switch {
+	case isInt(rv):
+		return new(big.Rat).SetInt64(rv.Int()), true
+	case isUint(rv):
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(rv.Uint())), true
+	case rv.Kind() == reflect.Float32 || rv.Kind() == reflect.Float64:
+		r := new(big.Rat)
+		if r.SetFloat64(rv.Float()) == nil {
+			return nil, false
+		}
+		return r, true
+	}
+	return nil, false
+}
@@ -0,0 +1,140 @@
+package texpr
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bigAccount struct {
+	Balance BigDecimal
+	Shares  BigInt
+	Count   Int
+}
+
+func (i Int) BigInt() BigInt {
+	return NewBigInt(int64(i))
+}
+
+func TestBigNumbers(t *testing.T) {
+	options := BigNumberOptions{Int: "long", Float: "float"}
+	intType := BigIntType(options)
+	assert.Equal(t, TypeName("bigint"), intType.Name)
+	intType.As = map[TypeName]string{"bigdecimal": "Decimal", "long": "Int64", "float": "Float64"}
+	assert.Equal(t, intType.As, BigIntType(options).As)
+
+	r, err := NewReflect(ReflectOptions{
+		BoolType: "bool",
+		Types: map[reflect.Type]Type{
+			TypeOf[bool]():    {Name: "bool"},
+			TypeOf[int]():     {Name: "int", Parse: func(x string) (any, error) { return strconv.Atoi(x) }},
+			TypeOf[int64]():   {Name: "long"},
+			TypeOf[float64](): {Name: "float"},
+			TypeOf[Int](): {
+				Parse: func(x string) (any, error) {
+					i, err := strconv.Atoi(x)
+					return Int(i), err
+				},
+				As: map[TypeName]string{"bigint": "BigInt"},
+			},
+			TypeOf[BigInt]():     BigIntType(options),
+			TypeOf[BigDecimal](): BigDecimalType(options),
+			TypeOf[bigAccount](): {},
+		},
+	})
+	assert.NoError(t, err)
+
+	account := bigAccount{
+		Balance: mustBigDecimal(t, "1234567890123456789.10"),
+		Shares:  mustBigInt(t, "98765432109876543210"),
+		Count:   3,
+	}
+
+	tests := []struct {
+		expression string
+		expected   TypeName
+		result     string
+		err        string
+	}{
+		{expression: "shares.+(10)", expected: "bigint", result: "98765432109876543220"},
+		{expression: "shares.*(shares)", expected: "bigint", result: "9754610579850632525677488187778997104100"},
+		{expression: "shares./(7)", expected: "bigint", result: "14109347444268077601"},
+		{expression: "shares./(0)", expected: "bigint", err: "division by zero"},
+		{expression: "shares.mod(7)", expected: "bigint", result: "3"},
+		{expression: "shares.neg.abs", expected: "bigint", result: "98765432109876543210"},
+		{expression: "count.bigInt.pow(100)", expected: "bigint", result: "515377520732011331036461129765621272702107522001"},
+		{expression: "count", expected: "bigint", result: "3"},
+		{expression: "shares.>(count)", expected: "bool", result: "true"},
+		{expression: "shares.min(count, 0x10)", expected: "bigint", result: "3"},
+		{expression: "balance./(3)", expected: "bigdecimal", result: "411522630041152263.0333333333333333333333333333333333"},
+		{expression: "balance.*(3).round(1)", expected: "bigdecimal", result: "3703703670370370367.3"},
+		{expression: "balance.-('0.10').truncate", expected: "bigint", result: "1234567890123456789"},
+		{expression: "balance.+(shares)", expected: "bigdecimal", result: "99999999999999999999.1"},
+		{expression: "balance.=('1234567890123456789.1')", expected: "bool", result: "true"},
+		{expression: "shares.+(count)", expected: "bigint", result: "98765432109876543213"},
+		{expression: "shares.int64", expected: "long", err: "98765432109876543210 does not fit in an int64"},
+		{expression: "shares./(shares).int64", expected: "long", result: "1"},
+		{expression: "shares.float64", expected: "float", result: "9.876543210987654e+19"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := r.Parse(Options{RootType: NameOf[bigAccount](), Expression: test.expression, ExpectedTypes: []TypeName{test.expected}})
+			assert.NoError(t, err)
+			if err != nil {
+				return
+			}
+			result, err := r.Evaluate(e, account)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			switch v := result.(type) {
+			case float64:
+				assert.Equal(t, test.result, strconv.FormatFloat(v, 'g', -1, 64))
+			default:
+				assert.Equal(t, test.result, toText(result))
+			}
+		})
+	}
+
+	_, err = ParseBigInt("12a")
+	assert.EqualError(t, err, `"12a" is not an integer`)
+	_, err = ParseBigDecimal("1..2")
+	assert.EqualError(t, err, `"1..2" is not a decimal`)
+	assert.Equal(t, "0", BigInt{}.String())
+	assert.Equal(t, "0", BigDecimal{}.String())
+	assert.Equal(t, "0.125", mustBigDecimal(t, "1/8").String())
+	assert.Equal(t, "1.5", BigDecimalOf(big.NewRat(3, 2)).String())
+
+	text, err := mustBigInt(t, "-42").MarshalText()
+	assert.NoError(t, err)
+	parsed := BigInt{}
+	assert.NoError(t, parsed.UnmarshalText(text))
+	assert.Equal(t, "-42", parsed.String())
+
+	equal, err := BigOperations{}.Equal(NewBigInt(2), 2.0)
+	assert.NoError(t, err)
+	assert.True(t, equal)
+}
+
+func mustBigInt(t *testing.T, x string) BigInt {
+	i, err := ParseBigInt(x)
+	assert.NoError(t, err)
+	return i
+}
+
+func mustBigDecimal(t *testing.T, x string) BigDecimal {
+	d, err := ParseBigDecimal(x)
+	assert.NoError(t, err)
+	return d
+}
+
+func toText(v any) string {
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,100 @@
+package texpr
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"fmt"
+)
+
+// Gives the system and options a BoundExpr is parsed with. It's implemented by an empty struct type
+// so the binding is part of the BoundExpr type:
+//
+//	type RuleBinding struct{}
+//
+//	func (RuleBinding) Bind() (texpr.System, texpr.Options) {
+//		return rules, texpr.Options{RootType: "context", ExpectedTypes: []texpr.TypeName{"bool"}}
+//	}
+//
+//	type Rule struct {
+//		When texpr.BoundExpr[RuleBinding] `json:"when"`
+//	}
+type ExprBinder interface {
+	// Returns the system and options expressions are parsed with. The expression of the options is ignored.
+	Bind() (System, Options)
+}
+
+// An expression which is parsed and validated when it's decoded from text (like JSON, YAML, or XML) or
+// scanned from a database, using the system and options given by its binding. An empty expression is
+// decoded as an expression with a nil Expr, and encoded as empty text or a NULL database value.
+type BoundExpr[B ExprBinder] struct {
+	// The expression as it was written.
+	Expression string
+	// The parsed expression, nil when there is no expression.
+	Expr *Expr
+}
+
+var (
+	_ encoding.TextMarshaler   = BoundExpr[ExprBinder]{}
+	_ encoding.TextUnmarshaler = &BoundExpr[ExprBinder]{}
+	_ sql.Scanner              = &BoundExpr[ExprBinder]{}
+	_ driver.Valuer            = BoundExpr[ExprBinder]{}
+)
+
+// Parses the expression with the system and options of the binding.
+func ParseBound[B ExprBinder](expression string) (BoundExpr[B], error) {
+	bound := BoundExpr[B]{}
+	err := bound.UnmarshalText([]byte(expression))
+	return bound, err
+}
+
+// Returns the expression as it was written.
+func (b BoundExpr[B]) String() string {
+	return b.Expression
+}
+
+// Returns the expression as it was written.
+func (b BoundExpr[B]) MarshalText() ([]byte, error) {
+	return []byte(b.Expression), nil
+}
+
+// Parses the text with the system and options of the binding. When the expression is invalid the
+// error from System.Parse is returned and the bound expression is not changed.
+func (b *BoundExpr[B]) UnmarshalText(text []byte) error {
+	expression := string(text)
+	if expression == "" {
+		*b = BoundExpr[B]{}
+		return nil
+	}
+	var binder B
+	sys, opts := binder.Bind()
+	opts.Expression = expression
+	e, err := sys.Parse(opts)
+	if err != nil {
+		return err
+	}
+	*b = BoundExpr[B]{Expression: expression, Expr: e}
+	return nil
+}
+
+// Parses the expression in a database value, which can be text, bytes, or NULL.
+func (b *BoundExpr[B]) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		*b = BoundExpr[B]{}
+		return nil
+	case string:
+		return b.UnmarshalText([]byte(value))
+	case []byte:
+		return b.UnmarshalText(value)
+	}
+	return fmt.Errorf("an expression can't be scanned from %T", src)
+}
+
+// Returns the expression as a database value, NULL when there is no expression.
+func (b BoundExpr[B]) Value() (driver.Value, error) {
+	if b.Expression == "" {
+		return nil, nil
+	}
+	return b.Expression, nil
+}
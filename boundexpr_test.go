@@ -0,0 +1,61 @@
+package texpr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRuleBinding struct{}
+
+func (testRuleBinding) Bind() (System, Options) {
+	return sys, Options{RootType: typeContext, ExpectedTypes: []TypeName{typeBool}}
+}
+
+func TestBoundExpr(t *testing.T) {
+	type rule struct {
+		Name string                     `json:"name"`
+		When BoundExpr[testRuleBinding] `json:"when"`
+	}
+
+	decoded := rule{}
+	err := json.Unmarshal([]byte(`{"name": "upper", "when": "user.name.isUpper"}`), &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, "user.name.isUpper", decoded.When.String())
+	assert.Equal(t, TypeName(typeBool), decoded.When.Expr.Last().Type.Name)
+
+	encoded, err := json.Marshal(decoded)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name": "upper", "when": "user.name.isUpper"}`, string(encoded))
+
+	err = json.Unmarshal([]byte(`{"when": "user.name.upper"}`), &decoded)
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+	assert.Equal(t, "user.name.isUpper", decoded.When.Expression)
+
+	err = json.Unmarshal([]byte(`{"when": ""}`), &decoded)
+	assert.NoError(t, err)
+	assert.Nil(t, decoded.When.Expr)
+
+	bound, err := ParseBound[testRuleBinding]("user.name.isLower")
+	assert.NoError(t, err)
+	assert.NotNil(t, bound.Expr)
+
+	value, err := bound.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "user.name.isLower", value)
+
+	scanned := BoundExpr[testRuleBinding]{}
+	assert.NoError(t, scanned.Scan([]byte("user.name.isUpper")))
+	assert.Equal(t, "user.name.isUpper", scanned.Expression)
+	assert.NoError(t, scanned.Scan("user.name.isLower"))
+	assert.Equal(t, "user.name.isLower", scanned.Expression)
+	assert.NoError(t, scanned.Scan(nil))
+	assert.Nil(t, scanned.Expr)
+	value, err = scanned.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	assert.ErrorIs(t, scanned.Scan("user.nam.isUpper"), ErrUnknownValue)
+	assert.EqualError(t, scanned.Scan(12), "an expression can't be scanned from int")
+}
@@ -0,0 +1,141 @@
+package texpr
+
+import (
+	"fmt"
+)
+
+// Builds a linked expression one value at a time, see Build. Each step is linked as it's added so
+// a mistake is reported where it's made, and once a step fails the builder ignores the steps after
+// it and Expr returns the error.
+type Builder struct {
+	sys        System
+	root       *Type
+	parameters map[string]*Type
+	roles      []string
+	first      *Expr
+	last       *Expr
+	err        error
+}
+
+// Returns a builder of expressions in the system, like
+// `Build(sys).Root("context").Value("time").Value("now").Value("hour").Call(">", Const("12"))`.
+// The root type must be given before any values.
+func Build(sys System) *Builder {
+	return &Builder{sys: sys, parameters: make(map[string]*Type)}
+}
+
+// Returns a builder of a constant argument which is parsed as the type of the parameter it's given to.
+func Const(token string) *Builder {
+	constant := &Expr{Token: token, Constant: true}
+	return &Builder{first: constant, last: constant}
+}
+
+// Returns a builder of a bind parameter (`:name`) argument, which must be given by Builder.Parameters.
+func Param(name string) *Builder {
+	bind := &Expr{Token: name, Bind: true}
+	return &Builder{first: bind, last: bind}
+}
+
+// Sets the root type of the expression.
+func (b *Builder) Root(root TypeName) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.first != nil {
+		b.err = NewParseError(nil, "the root type must be given before any values")
+		return b
+	}
+	b.root = b.sys.resolveType(root)
+	if b.root == nil {
+		b.err = NewParseErrorKind(nil, ErrUnknownType, fmt.Sprintf("undefined root type: %s", root))
+	}
+	return b
+}
+
+// Sets the types of the bind parameters the expression can use, see Options.Parameters.
+func (b *Builder) Parameters(parameters map[string]TypeName) *Builder {
+	if b.err != nil {
+		return b
+	}
+	types, err := b.sys.parameterTypes(Options{Parameters: parameters})
+	if err != nil {
+		b.err = err
+		return b
+	}
+	for name, t := range types {
+		b.parameters[name] = t
+	}
+	return b
+}
+
+// Sets the roles of the user the expression is built for, see Options.Roles.
+func (b *Builder) Roles(roles ...string) *Builder {
+	b.roles = roles
+	return b
+}
+
+// Returns a builder of an argument which starts on the root of this builder, like
+// `b.Call("max", b.Arg().Value("limit"))`.
+func (b *Builder) Arg() *Builder {
+	return &Builder{sys: b.sys, root: b.root, parameters: b.parameters, roles: b.roles, err: b.err}
+}
+
+// Adds the value with the path to the expression, which is on the root type for the first value and
+// otherwise on the type of the previous value.
+func (b *Builder) Value(path string) *Builder {
+	return b.Call(path)
+}
+
+// Adds the value with the path given the arguments to the expression, which is on the root type for
+// the first value and otherwise on the type of the previous value.
+func (b *Builder) Call(path string, args ...*Builder) *Builder {
+	if b.err != nil {
+		return b
+	}
+	current := &Expr{Token: path, Prev: b.last, Arguments: make([]*Expr, len(args))}
+	for i, arg := range args {
+		if arg.err != nil {
+			b.err = arg.err
+			return b
+		}
+		if arg.first == nil {
+			b.err = NewParseErrorKind(current, ErrSyntax, fmt.Sprintf("argument %d of %s is empty", i, path))
+			return b
+		}
+		arg.first.Parent = current
+		current.Arguments[i] = arg.first
+	}
+	if b.last != nil {
+		b.last.Next = current
+	} else {
+		b.first = current
+	}
+	b.last = current
+	b.link()
+	return b
+}
+
+// Returns the built expression, or the error of the first step which failed.
+func (b *Builder) Expr() (*Expr, error) {
+	if b.err != nil {
+		return b.first, b.err
+	}
+	if b.first == nil {
+		return nil, ErrNoExpression
+	}
+	return b.first, nil
+}
+
+// Links the expression built so far, unless the builder is an argument which has no root yet.
+func (b *Builder) link() {
+	if b.root == nil {
+		if b.first.Constant || b.first.Bind {
+			return
+		}
+		b.err = ErrNoRoot
+		return
+	}
+	ctx := newLinkContext(b.root, b.parameters)
+	ctx.roles = b.roles
+	b.err = joinParseErrors(b.sys.link(b.first, nil, ctx))
+}
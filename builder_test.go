@@ -0,0 +1,53 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	e, err := Build(sys).Root(typeContext).Value("time").Value("now").Value("hour").Call(">", Const("12")).Expr()
+	assert.NoError(t, err)
+	parsed, err := sys.Parse(Options{RootType: typeContext, Expression: "time.now.hour>(12)"})
+	assert.NoError(t, err)
+	assert.Equal(t, parsed.String(), e.String())
+	assert.Equal(t, TypeName(typeBool), e.Last().Type.Name)
+	assert.Equal(t, 12, e.Last().Arguments[0].Parsed)
+	assert.Same(t, e.Last(), e.Last().Arguments[0].Parent)
+
+	b := Build(sys).Root(typeContext).Parameters(map[string]TypeName{"name": typeText})
+	e, err = b.Value("user").Value("name").Call("contains", Param("name")).Call("or", b.Arg().Value("user").Value("name").Value("isUpper")).Expr()
+	assert.NoError(t, err)
+	assert.Equal(t, "user.name.contains(:name).or(user.name.isUpper)", e.String())
+
+	tests := []struct {
+		name    string
+		builder *Builder
+		kind    error
+		message string
+	}{
+		{name: "unknown value", builder: Build(sys).Root(typeContext).Value("time").Value("nope").Value("hour"), kind: ErrUnknownValue, message: "invalid value nope"},
+		{name: "arity", builder: Build(sys).Root(typeContext).Value("time").Value("now").Value("hour").Call(">"), kind: ErrArity},
+		{name: "constant", builder: Build(sys).Root(typeContext).Value("time").Value("now").Value("hour").Call(">", Const("noon")), kind: ErrTypeMismatch},
+		{name: "parameter", builder: Build(sys).Root(typeContext).Value("user").Value("name").Call("contains", Param("missing")), kind: ErrUnknownValue, message: "undefined parameter :missing"},
+		{name: "root", builder: Build(sys).Root("nope"), kind: ErrUnknownType, message: "undefined root type: nope"},
+		{name: "no root", builder: Build(sys).Value("time"), message: "undefined root type"},
+		{name: "argument", builder: Build(sys).Root(typeContext).Value("user").Value("name").Call("contains", Build(sys).Root(typeContext).Value("user").Value("nope")), kind: ErrUnknownValue, message: "invalid value nope"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.builder.Value("more").Expr()
+			assert.Error(t, err)
+			if test.kind != nil {
+				assert.ErrorIs(t, err, test.kind)
+			}
+			if test.message != "" {
+				assert.EqualError(t, err, test.message)
+			}
+		})
+	}
+
+	_, err = Build(sys).Root(typeContext).Expr()
+	assert.Equal(t, ErrNoExpression, err)
+}
@@ -0,0 +1,211 @@
+package texpr
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The options of a CachedEvaluator.
+type CacheOptions struct {
+	// The maximum number of results cached, the least recently used result is removed when there are
+	// more. When zero there is no limit.
+	MaxEntries int
+	// How long a result is cached. When zero results are cached until they are removed by MaxEntries or Clear.
+	TTL time.Duration
+	// Returns the cache key of a root dependency's value. By default it's the type and Go syntax
+	// representation of the value, which is only suitable for values without pointers.
+	Key func(v any) string
+	// Returns the current time, used for the TTL. By default it's time.Now.
+	Now func() time.Time
+	// Records whether each lookup of a deterministic expression's result was cached.
+	Metrics Metrics
+}
+
+// The number of evaluations a CachedEvaluator did by outcome.
+type CacheStats struct {
+	// The evaluations which returned a cached result.
+	Hits int
+	// The evaluations which were cached after evaluating.
+	Misses int
+	// The evaluations which were not cached because the expression is not deterministic, or it has
+	// bind parameters or placeholders.
+	Bypassed int
+}
+
+// An evaluator which caches the results of deterministic expressions (see Expr.IsDeterministic).
+// Results are keyed by the expression and the values of its root dependencies (see
+// Expr.RootDependencies), which are evaluated with the wrapped evaluator. It's safe for concurrent use.
+type CachedEvaluator struct {
+	evaluator Evaluator
+	options   CacheOptions
+	lock      sync.Mutex
+	entries   map[string]*list.Element
+	order     *list.List
+	stats     CacheStats
+}
+
+// A cached result.
+type cacheEntry struct {
+	key     string
+	result  any
+	expires time.Time
+}
+
+var _ Evaluator = &CachedEvaluator{}
+
+// Returns an evaluator which caches the results of the given evaluator.
+func NewCachedEvaluator(evaluator Evaluator, options CacheOptions) *CachedEvaluator {
+	if options.Key == nil {
+		options.Key = func(v any) string {
+			return fmt.Sprintf("%T:%#v", v, v)
+		}
+	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+	return &CachedEvaluator{
+		evaluator: evaluator,
+		options:   options,
+		entries:   make(map[string]*list.Element),
+		order:     list.New(),
+	}
+}
+
+// Evaluates the expression, returning the cached result if the expression is deterministic and was
+// evaluated with the same root dependencies before. Errors are not cached.
+func (c *CachedEvaluator) Evaluate(e *Expr, root any) (any, error) {
+	if !e.IsDeterministic() || len(e.Placeholders()) > 0 || len(e.Binds()) > 0 {
+		c.lock.Lock()
+		c.stats.Bypassed++
+		c.lock.Unlock()
+		return c.evaluator.Evaluate(e, root)
+	}
+
+	key, err := c.key(e, root)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	if element, exists := c.entries[key]; exists {
+		entry := element.Value.(*cacheEntry)
+		if entry.expires.IsZero() || c.options.Now().Before(entry.expires) {
+			c.order.MoveToFront(element)
+			c.stats.Hits++
+			c.lock.Unlock()
+			if c.options.Metrics != nil {
+				c.options.Metrics.CacheLookup(true)
+			}
+			return entry.result, nil
+		}
+		c.remove(element)
+	}
+	c.lock.Unlock()
+	if c.options.Metrics != nil {
+		c.options.Metrics.CacheLookup(false)
+	}
+
+	result, err := c.evaluator.Evaluate(e, root)
+	if err != nil {
+		return result, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.stats.Misses++
+	entry := &cacheEntry{key: key, result: result}
+	if c.options.TTL > 0 {
+		entry.expires = c.options.Now().Add(c.options.TTL)
+	}
+	if element, exists := c.entries[key]; exists {
+		c.remove(element)
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.options.MaxEntries > 0 && c.order.Len() > c.options.MaxEntries {
+		c.remove(c.order.Back())
+	}
+	return result, nil
+}
+
+// Returns the number of cached results, including expired results which have not been removed.
+func (c *CachedEvaluator) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.order.Len()
+}
+
+// Removes every cached result.
+func (c *CachedEvaluator) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// Returns the number of evaluations by outcome.
+func (c *CachedEvaluator) Stats() CacheStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.stats
+}
+
+// Removes the cached result.
+func (c *CachedEvaluator) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*cacheEntry).key)
+}
+
+// Returns the cache key of the expression evaluated with the root.
+func (c *CachedEvaluator) key(e *Expr, root any) (string, error) {
+	key := strings.Builder{}
+	if e.ParentType != nil {
+		key.WriteString(string(e.ParentType.Name))
+	}
+	key.WriteString("\x00")
+	key.WriteString(e.String())
+	for _, dependency := range e.RootDependencies() {
+		value, err := c.evaluator.Evaluate(dependency, root)
+		if err != nil {
+			return "", err
+		}
+		key.WriteString("\x00")
+		key.WriteString(c.options.Key(value))
+	}
+	return key.String(), nil
+}
+
+// Returns the values on the root the expression depends on, which are copies of the first expression
+// of each chain in the expression and its arguments that starts with a value. Each dependency includes
+// its arguments and has no chain after it. Dependencies are in the order they appear without duplicates.
+func (e *Expr) RootDependencies() []*Expr {
+	dependencies := make([]*Expr, 0)
+	seen := make(map[string]bool)
+	var walk func(e *Expr)
+	walk = func(e *Expr) {
+		if e.Prev == nil && e.Value != nil && !e.Constant && !e.Bind && !e.Placeholder {
+			dependency := &Expr{}
+			*dependency = *e
+			dependency.Next = nil
+			dependency.Parent = nil
+			dependency.Arguments = make([]*Expr, len(e.Arguments))
+			for i, arg := range e.Arguments {
+				dependency.Arguments[i] = arg.Clone()
+				dependency.Arguments[i].Parent = dependency
+			}
+			if key := dependency.String(); !seen[key] {
+				seen[key] = true
+				dependencies = append(dependencies, dependency)
+			}
+		}
+		for _, c := range e.Chain() {
+			for _, arg := range c.Arguments {
+				walk(arg)
+			}
+		}
+	}
+	walk(e)
+	return dependencies
+}
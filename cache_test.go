@@ -0,0 +1,90 @@
+package texpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedEvaluator(t *testing.T) {
+	s := NewSystemRequired([]Type{{
+		Name:  "num",
+		Parse: func(x string) (any, error) { return x, nil },
+		Values: []Value{
+			{Path: "plus", Type: "num", Parameters: []Parameter{{Name: "value", Type: "num"}}},
+		},
+	}, {
+		Name: "root",
+		Values: []Value{
+			{Path: "a", Type: "num"},
+			{Path: "b", Type: "num"},
+			{Path: "now", Type: "num", Volatility: VolatilityTime},
+		},
+	}})
+
+	evaluations := 0
+	inner := EvaluatorFunc(func(e *Expr, root any) (any, error) {
+		values := root.(map[string]int)
+		if e.Next == nil {
+			return values[e.Token], nil
+		}
+		evaluations++
+		return values[e.Token] + values[e.Next.Arguments[0].Token], nil
+	})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cached := NewCachedEvaluator(inner, CacheOptions{
+		MaxEntries: 2,
+		TTL:        time.Minute,
+		Now:        func() time.Time { return now },
+	})
+	parse := func(expression string) *Expr {
+		e, err := s.Parse(Options{RootType: "root", Expression: expression})
+		assert.NoError(t, err)
+		return e
+	}
+
+	aPlusB := parse("a.plus(b)")
+	assert.Equal(t, []string{"a", "b"}, dependencyStrings(aPlusB))
+
+	result, err := cached.Evaluate(aPlusB, map[string]int{"a": 1, "b": 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result)
+	result, _ = cached.Evaluate(aPlusB, map[string]int{"a": 1, "b": 2, "other": 5})
+	assert.Equal(t, 3, result)
+	assert.Equal(t, 1, evaluations)
+
+	result, _ = cached.Evaluate(aPlusB, map[string]int{"a": 2, "b": 2})
+	assert.Equal(t, 4, result)
+	assert.Equal(t, 2, evaluations)
+
+	cached.Evaluate(parse("b.plus(a)"), map[string]int{"a": 1, "b": 2})
+	assert.Equal(t, 3, evaluations)
+	assert.Equal(t, 2, cached.Len())
+	cached.Evaluate(aPlusB, map[string]int{"a": 1, "b": 2})
+	assert.Equal(t, 4, evaluations, "least recently used result was removed")
+
+	now = now.Add(2 * time.Minute)
+	cached.Evaluate(aPlusB, map[string]int{"a": 1, "b": 2})
+	assert.Equal(t, 5, evaluations, "result expired")
+
+	volatile := parse("now.plus(a)")
+	assert.False(t, volatile.IsDeterministic())
+	cached.Evaluate(volatile, map[string]int{"now": 1, "a": 1})
+	cached.Evaluate(volatile, map[string]int{"now": 1, "a": 1})
+	assert.Equal(t, 7, evaluations)
+
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 5, Bypassed: 2}, cached.Stats())
+	cached.Clear()
+	assert.Equal(t, 0, cached.Len())
+}
+
+func dependencyStrings(e *Expr) []string {
+	dependencies := e.RootDependencies()
+	texts := make([]string, len(dependencies))
+	for i, d := range dependencies {
+		texts[i] = d.String()
+	}
+	return texts
+}
@@ -0,0 +1,350 @@
+package texpr
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// An operator generated for a type with capability flags (see Type.Comparable, Type.Ordered, and
+// Type.Numeric) or for an enum type (see SystemOptions.EnumOperators).
+type Operator string
+
+const (
+	OpEquals       Operator = "="
+	OpNotEquals    Operator = "!="
+	OpLess         Operator = "<"
+	OpLessEqual    Operator = "<="
+	OpGreater      Operator = ">"
+	OpGreaterEqual Operator = ">="
+	OpMin          Operator = "min"
+	OpMax          Operator = "max"
+	OpAdd          Operator = "+"
+	OpSubtract     Operator = "-"
+	OpMultiply     Operator = "*"
+	OpDivide       Operator = "/"
+	OpOneOf        Operator = "oneOf"
+	OpNotOneOf     Operator = "notOneOf"
+)
+
+// The operators generated for Comparable types.
+var ComparableOperators = []Operator{OpEquals, OpNotEquals}
+
+// The operators generated for Ordered types.
+var OrderedOperators = []Operator{OpLess, OpLessEqual, OpGreater, OpGreaterEqual, OpMin, OpMax}
+
+// The operators generated for Numeric types.
+var NumericOperators = []Operator{OpAdd, OpSubtract, OpMultiply, OpDivide}
+
+// The operators generated for types with Enums when SystemOptions.EnumOperators is true.
+var EnumOperators = []Operator{OpEquals, OpNotEquals, OpOneOf, OpNotOneOf}
+
+// The runtime behavior of the operators generated for a type. When a type has no Operations
+// StandardOperations is used.
+type Operations interface {
+	// Returns whether the values are equal.
+	Equal(a, b any) (bool, error)
+	// Returns a negative number when a is less than b, zero when they are equal, and a positive number otherwise.
+	Compare(a, b any) (int, error)
+	// Returns the result of the arithmetic operator (+, -, *, or /) on the values.
+	Arithmetic(op Operator, a, b any) (any, error)
+}
+
+// Returns the result of the operator on the value given the argument values using the operations.
+func (op Operator) Apply(ops Operations, value any, args []any) (any, error) {
+	if ops == nil {
+		ops = StandardOperations{}
+	}
+	switch op {
+	case OpMin, OpMax:
+		result := value
+		for _, arg := range args {
+			c, err := ops.Compare(arg, result)
+			if err != nil {
+				return nil, err
+			}
+			if (op == OpMin && c < 0) || (op == OpMax && c > 0) {
+				result = arg
+			}
+		}
+		return result, nil
+	case OpOneOf, OpNotOneOf:
+		found := false
+		for _, arg := range args {
+			equal, err := ops.Equal(value, arg)
+			if err != nil {
+				return nil, err
+			}
+			found = found || equal
+		}
+		return found == (op == OpOneOf), nil
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("operator %s expects 1 argument but was given %d", op, len(args))
+	}
+	other := args[0]
+	switch op {
+	case OpEquals, OpNotEquals:
+		equal, err := ops.Equal(value, other)
+		return equal == (op == OpEquals), err
+	case OpLess, OpLessEqual, OpGreater, OpGreaterEqual:
+		c, err := ops.Compare(value, other)
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case OpLess:
+			return c < 0, nil
+		case OpLessEqual:
+			return c <= 0, nil
+		case OpGreater:
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	case OpAdd, OpSubtract, OpMultiply, OpDivide:
+		return ops.Arithmetic(op, value, other)
+	}
+	return nil, fmt.Errorf("unknown operator %s", op)
+}
+
+// Returns the operator the value was generated for, if it was generated by a capability flag of its
+// type or SystemOptions.EnumOperators.
+func (v Value) Operator() (Operator, bool) {
+	return v.operator, v.operator != ""
+}
+
+// Returns the operations evaluators use for the generated operators of the type: its Operations,
+// EnumOperations when it has Enums, or StandardOperations.
+func (t Type) operations() Operations {
+	switch {
+	case t.Operations != nil:
+		return t.Operations
+	case len(t.Enums) > 0:
+		return EnumOperations{}
+	}
+	return StandardOperations{}
+}
+
+// Operations on Go numbers, strings, booleans, and times. Values of different numeric kinds are
+// compared as floats, and the result of arithmetic has the type of the first value.
+type StandardOperations struct{}
+
+var _ Operations = StandardOperations{}
+
+func (StandardOperations) Equal(a, b any) (bool, error) {
+	if c, err := (StandardOperations{}).Compare(a, b); err == nil {
+		return c == 0, nil
+	}
+	return reflect.DeepEqual(a, b), nil
+}
+
+func (StandardOperations) Compare(a, b any) (int, error) {
+	if at, ok := a.(time.Time); ok {
+		if bt, ok := b.(time.Time); ok {
+			return at.Compare(bt), nil
+		}
+	}
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case isString(av) && isString(bv):
+		return strings.Compare(av.String(), bv.String()), nil
+	case isBool(av) && isBool(bv):
+		ab, bb := av.Bool(), bv.Bool()
+		if ab == bb {
+			return 0, nil
+		} else if !ab {
+			return -1, nil
+		}
+		return 1, nil
+	case isInt(av) && isInt(bv):
+		return compareOrdered(av.Int(), bv.Int()), nil
+	case isUint(av) && isUint(bv):
+		return compareOrdered(av.Uint(), bv.Uint()), nil
+	case isNumber(av) && isNumber(bv):
+		return compareOrdered(toFloat(av), toFloat(bv)), nil
+	}
+	return 0, fmt.Errorf("%v (%T) and %v (%T) can not be compared", a, a, b, b)
+}
+
+func (StandardOperations) Arithmetic(op Operator, a, b any) (any, error) {
+	if at, ok := a.(time.Time); ok {
+		if bd, ok := asDuration(b); ok {
+			switch op {
+			case OpAdd:
+				return bd.AddTo(at), nil
+			case OpSubtract:
+				return bd.Neg().AddTo(at), nil
+			}
+		}
+	}
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !isNumber(av) || !isNumber(bv) {
+		if op == OpAdd && isString(av) && isString(bv) {
+			return reflect.ValueOf(av.String() + bv.String()).Convert(av.Type()).Interface(), nil
+		}
+		return nil, fmt.Errorf("operator %s is not supported for %v (%T) and %v (%T)", op, a, a, b, b)
+	}
+	result := reflect.New(av.Type()).Elem()
+	switch {
+	case isInt(av):
+		x, y := av.Int(), toInt(bv)
+		if op == OpDivide && y == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		result.SetInt(applyArithmetic(op, x, y))
+	case isUint(av):
+		x, y := av.Uint(), uint64(toInt(bv))
+		if op == OpDivide && y == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		result.SetUint(applyArithmetic(op, x, y))
+	default:
+		result.SetFloat(applyArithmetic(op, av.Float(), toFloat(bv)))
+	}
+	return result.Interface(), nil
+}
+
+type ordered interface {
+	~int64 | ~uint64 | ~float64
+}
+
+func compareOrdered[T ordered](a, b T) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+func applyArithmetic[T ordered](op Operator, a, b T) T {
+	switch op {
+	case OpAdd:
+		return a + b
+	case OpSubtract:
+		return a - b
+	case OpMultiply:
+		return a * b
+	}
+	return a / b
+}
+
+func isString(v reflect.Value) bool {
+	return v.Kind() == reflect.String
+}
+
+func isBool(v reflect.Value) bool {
+	return v.Kind() == reflect.Bool
+}
+
+func isInt(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUint(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+func isNumber(v reflect.Value) bool {
+	return isInt(v) || isUint(v) || v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case isInt(v):
+		return float64(v.Int())
+	case isUint(v):
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+func toInt(v reflect.Value) int64 {
+	switch {
+	case isInt(v):
+		return v.Int()
+	case isUint(v):
+		return int64(v.Uint())
+	}
+	return int64(v.Float())
+}
+
+// Adds the operators for the capabilities of the type, and the enum operators to a type with Enums when
+// enumOperators is true, which the type does not already have a value for.
+func addCapabilityOperators(t *Type, boolType TypeName, enumOperators bool) error {
+	enumOperators = enumOperators && len(t.Enums) > 0
+	if (t.Comparable || t.Ordered || enumOperators) && boolType == "" {
+		return SystemError{
+			Message: fmt.Sprintf("type %s has comparison operators but no BoolType was given", t.Name),
+			Type:    t,
+			Kind:    ErrUnknownType,
+		}
+	}
+	add := func(op Operator, resultType TypeName, variadic bool) {
+		if existing, _ := findValue(string(op), *t); existing != nil {
+			return
+		}
+		t.Values = append(t.Values, Value{
+			Path:        string(op),
+			Type:        resultType,
+			Description: operatorDescriptions[op],
+			Variadic:    variadic,
+			Parameters: []Parameter{
+				{Name: "value", Type: t.Name},
+			},
+			operator: op,
+		})
+	}
+	if t.Comparable {
+		for _, op := range ComparableOperators {
+			add(op, boolType, false)
+		}
+	}
+	if t.Ordered {
+		for _, op := range OrderedOperators {
+			if op == OpMin || op == OpMax {
+				add(op, t.Name, true)
+			} else {
+				add(op, boolType, false)
+			}
+		}
+	}
+	if t.Numeric {
+		for _, op := range NumericOperators {
+			add(op, t.Name, false)
+		}
+	}
+	if enumOperators {
+		for _, op := range EnumOperators {
+			add(op, boolType, op == OpOneOf || op == OpNotOneOf)
+		}
+	}
+	return nil
+}
+
+var operatorDescriptions = map[Operator]string{
+	OpEquals:       "Returns whether the values are equal",
+	OpNotEquals:    "Returns whether the values are not equal",
+	OpLess:         "Returns whether the value is less than the given value",
+	OpLessEqual:    "Returns whether the value is less than or equal to the given value",
+	OpGreater:      "Returns whether the value is greater than the given value",
+	OpGreaterEqual: "Returns whether the value is greater than or equal to the given value",
+	OpMin:          "Returns the smallest of the value and the given values",
+	OpMax:          "Returns the largest of the value and the given values",
+	OpAdd:          "Returns the sum of the values",
+	OpSubtract:     "Returns the difference of the values",
+	OpMultiply:     "Returns the product of the values",
+	OpDivide:       "Returns the quotient of the values",
+	OpOneOf:        "Returns whether the value is one of the given values",
+	OpNotOneOf:     "Returns whether the value is none of the given values",
+}
@@ -0,0 +1,117 @@
+package texpr
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type versionOperations struct {
+	StandardOperations
+}
+
+func (versionOperations) Compare(a, b any) (int, error) {
+	return len(a.(string)) - len(b.(string)), nil
+}
+
+type reflectCounter struct {
+	Count   int
+	Version string
+}
+
+func TestCapabilities(t *testing.T) {
+	s, err := NewSystemWithOptions([]Type{{
+		Name:       "int",
+		Parse:      func(x string) (any, error) { return strconv.Atoi(x) },
+		Comparable: true,
+		Ordered:    true,
+		Numeric:    true,
+		Values: []Value{
+			{Path: "+", Type: "int", Description: "custom", Parameters: []Parameter{{Name: "value", Type: "int"}}},
+		},
+	}, {
+		Name:       "bool",
+		Comparable: true,
+	}}, SystemOptions{BoolType: "bool"})
+	assert.NoError(t, err)
+
+	intType := s.Type("int")
+	paths := make([]string, len(intType.Values))
+	for i, v := range intType.Values {
+		paths[i] = v.Path
+	}
+	assert.Equal(t, []string{"+", "=", "!=", "<", "<=", ">", ">=", "min", "max", "-", "*", "/"}, paths)
+	assert.Equal(t, "custom", intType.Value("+").Description)
+	_, isOperator := intType.Value("+").Operator()
+	assert.False(t, isOperator)
+	op, isOperator := intType.Value("<=").Operator()
+	assert.True(t, isOperator)
+	assert.Equal(t, OpLessEqual, op)
+	assert.Equal(t, TypeName("bool"), intType.Value("<").Type)
+	assert.Equal(t, TypeName("int"), intType.Value("max").Type)
+	assert.True(t, intType.Value("max").Variadic)
+	assert.Len(t, s.Type("bool").Values, 2)
+
+	_, err = NewSystem([]Type{{Name: "int", Ordered: true}})
+	assert.ErrorIs(t, err, ErrUnknownType)
+
+	tests := []struct {
+		op       Operator
+		value    any
+		args     []any
+		expected any
+	}{
+		{OpEquals, 1, []any{1.0}, true},
+		{OpNotEquals, "a", []any{"a"}, false},
+		{OpLess, uint(2), []any{uint(3)}, true},
+		{OpGreaterEqual, 2.5, []any{2}, true},
+		{OpMin, 5, []any{3, 8}, 3},
+		{OpMax, "b", []any{"a", "c"}, "c"},
+		{OpAdd, 2, []any{3}, 5},
+		{OpSubtract, 2.5, []any{1}, 1.5},
+		{OpMultiply, int8(4), []any{2}, int8(8)},
+		{OpDivide, 7, []any{2}, 3},
+		{OpAdd, "a", []any{"b"}, "ab"},
+		{OpAdd, time.Unix(0, 0), []any{time.Hour}, time.Unix(0, 0).Add(time.Hour)},
+		{OpLess, time.Unix(0, 0), []any{time.Unix(1, 0)}, true},
+	}
+	for _, test := range tests {
+		result, err := test.op.Apply(nil, test.value, test.args)
+		assert.NoError(t, err, test.op)
+		assert.Equal(t, test.expected, result, test.op)
+	}
+
+	_, err = OpDivide.Apply(nil, 1, []any{0})
+	assert.EqualError(t, err, "division by zero")
+	_, err = OpLess.Apply(nil, 1, []any{"a"})
+	assert.Error(t, err)
+
+	result, err := OpMax.Apply(versionOperations{}, "1.0", []any{"1.10", "1.2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "1.10", result)
+
+	r, err := NewReflect(ReflectOptions{
+		BoolType: "bool",
+		Types: map[reflect.Type]Type{
+			TypeOf[int]():            {Name: "int", Parse: func(x string) (any, error) { return strconv.Atoi(x) }, Ordered: true, Numeric: true},
+			TypeOf[bool]():           {Name: "bool"},
+			TypeOf[string]():         {Name: "string", Parse: func(x string) (any, error) { return x, nil }, ParseOrder: -1, Ordered: true, Operations: versionOperations{}},
+			TypeOf[reflectCounter](): {Name: "counter"},
+		},
+	})
+	assert.NoError(t, err)
+
+	evaluate := func(expression string) any {
+		e, err := r.Parse(Options{RootType: "counter", Expression: expression})
+		assert.NoError(t, err)
+		result, err := r.Evaluate(e, reflectCounter{Count: 4, Version: "1.9"})
+		assert.NoError(t, err)
+		return result
+	}
+	assert.Equal(t, 10, evaluate("count+(2).max(10, 3)"))
+	assert.Equal(t, true, evaluate("count*(2)>(7)"))
+	assert.Equal(t, "1.10", evaluate("version.max('1.10')"))
+}
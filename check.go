@@ -0,0 +1,230 @@
+package texpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// How severe a problem found by System.Check is.
+type Severity int
+
+const (
+	// The problem is likely intended but worth knowing about.
+	SeverityInfo Severity = iota
+	// The problem may cause expressions to behave unexpectedly.
+	SeverityWarning
+	// The problem will cause expressions to fail.
+	SeverityError
+)
+
+// Returns the name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// The kind of problem found by System.Check.
+type CheckKind string
+
+const (
+	CheckUnreachableType   CheckKind = "unreachableType"
+	CheckUnlinkedParameter CheckKind = "unlinkedParameter"
+	CheckEmptyEnums        CheckKind = "emptyEnums"
+	CheckParseOrder        CheckKind = "parseOrder"
+	CheckDuplicateAlias    CheckKind = "duplicateAlias"
+)
+
+// A problem found by System.Check.
+type CheckIssue struct {
+	// How severe the problem is.
+	Severity Severity
+	// What kind of problem it is.
+	Kind CheckKind
+	// A human readable description of the problem.
+	Message string
+	// The type with the problem.
+	Type *Type
+	// The value with the problem, if any.
+	Value *Value
+	// The parameter with the problem, if any.
+	Parameter *Parameter
+}
+
+// Returns the severity and message of the issue.
+func (i CheckIssue) String() string {
+	return i.Severity.String() + ": " + i.Message
+}
+
+// All problems found by System.Check.
+type CheckReport []CheckIssue
+
+// Returns the issues with at least the given severity.
+func (r CheckReport) AtLeast(severity Severity) CheckReport {
+	issues := make(CheckReport, 0, len(r))
+	for _, issue := range r {
+		if issue.Severity >= severity {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// Returns whether the report has any issues with the error severity.
+func (r CheckReport) HasErrors() bool {
+	return len(r.AtLeast(SeverityError)) > 0
+}
+
+// Returns a report of problems in the system which don't prevent it from being built: types that
+// can't be reached, parameters whose types were never linked, empty enums, types with an ambiguous
+// parse order, and aliases given more than once.
+func (sys System) Check() CheckReport {
+	report := make(CheckReport, 0)
+	report = sys.checkReachable(report)
+	for _, t := range sys.types {
+		report = sys.checkType(t, report)
+	}
+	report = sys.checkParseOrder(report)
+	return report
+}
+
+// Reports the types which can't be reached. With a root type every type which can't be reached from
+// the root by values, parameters, and conversions is a warning. Without a root every type which
+// isn't referenced by another type is reported as information, since it could be used as a root.
+func (sys System) checkReachable(report CheckReport) CheckReport {
+	references := func(t *Type) []*Type {
+		refs := make([]*Type, 0)
+		for i := range t.Values {
+			v := &t.Values[i]
+			if v.valueType != nil {
+				refs = append(refs, v.valueType)
+			}
+			for k := range v.Parameters {
+				if p := v.Parameters[k].parameterType; p != nil {
+					refs = append(refs, p)
+				}
+			}
+		}
+		for name := range t.As {
+			if other := sys.Type(name); other != nil {
+				refs = append(refs, other)
+			}
+		}
+		return refs
+	}
+
+	if root := sys.Type(sys.options.Root); root != nil {
+		reached := map[*Type]bool{root: true}
+		queue := []*Type{root}
+		for len(queue) > 0 {
+			t := queue[0]
+			queue = queue[1:]
+			for _, ref := range references(t) {
+				if !reached[ref] {
+					reached[ref] = true
+					queue = append(queue, ref)
+				}
+			}
+		}
+		for _, t := range sys.types {
+			if !reached[t] {
+				report = append(report, CheckIssue{
+					Severity: SeverityWarning,
+					Kind:     CheckUnreachableType,
+					Message:  fmt.Sprintf("type %s can not be reached from root %s", t.Name, root.Name),
+					Type:     t,
+				})
+			}
+		}
+		return report
+	}
+
+	referenced := make(map[*Type]bool)
+	for _, t := range sys.types {
+		for _, ref := range references(t) {
+			if ref != t {
+				referenced[ref] = true
+			}
+		}
+	}
+	for _, t := range sys.types {
+		if !referenced[t] {
+			report = append(report, CheckIssue{
+				Severity: SeverityInfo,
+				Kind:     CheckUnreachableType,
+				Message:  fmt.Sprintf("type %s is not referenced by any other type", t.Name),
+				Type:     t,
+			})
+		}
+	}
+	return report
+}
+
+// Reports the problems with the values, parameters, and enums of a single type.
+func (sys System) checkType(t *Type, report CheckReport) CheckReport {
+	if t.Enums != nil && len(t.Enums) == 0 {
+		report = append(report, CheckIssue{
+			Severity: SeverityWarning,
+			Kind:     CheckEmptyEnums,
+			Message:  fmt.Sprintf("type %s has an empty list of enums", t.Name),
+			Type:     t,
+		})
+	}
+
+	for i := range t.Values {
+		v := &t.Values[i]
+		for k := range v.Parameters {
+			p := &v.Parameters[k]
+			if p.parameterType == nil && !p.Generic {
+				report = append(report, CheckIssue{
+					Severity:  SeverityError,
+					Kind:      CheckUnlinkedParameter,
+					Message:   fmt.Sprintf("parameter %s on %s.%s has type %q which was not linked", p.Name, t.Name, v.Path, p.Type),
+					Type:      t,
+					Value:     v,
+					Parameter: p,
+				})
+			}
+		}
+
+		names := map[string]bool{strings.ToLower(v.Path): true}
+		for _, a := range v.Aliases {
+			key := strings.ToLower(a)
+			if names[key] {
+				report = append(report, CheckIssue{
+					Severity: SeverityInfo,
+					Kind:     CheckDuplicateAlias,
+					Message:  fmt.Sprintf("alias %s on %s.%s is given more than once", a, t.Name, v.Path),
+					Type:     t,
+					Value:    v,
+				})
+			}
+			names[key] = true
+		}
+	}
+
+	return report
+}
+
+// Reports types which are parsed in an order that depends on how the types were given, since
+// they have the same parse order, parse function specificity, and name length.
+func (sys System) checkParseOrder(report CheckReport) CheckReport {
+	for i := 1; i < len(sys.parseOrder); i++ {
+		a, b := sys.parseOrder[i-1], sys.parseOrder[i]
+		if a.ParseOrder == b.ParseOrder && a.parses() == b.parses() && len(a.Name) == len(b.Name) {
+			report = append(report, CheckIssue{
+				Severity: SeverityWarning,
+				Kind:     CheckParseOrder,
+				Message:  fmt.Sprintf("types %s and %s have an ambiguous parse order %d", a.Name, b.Name, a.ParseOrder),
+				Type:     b,
+			})
+		}
+	}
+	return report
+}
@@ -0,0 +1,72 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	newTypes := func() []Type {
+		return []Type{{
+			Name:  "text",
+			Parse: func(x string) (any, error) { return x, nil },
+			Values: []Value{
+				{Path: "lower", Type: "text", Aliases: []string{"lowercase", "LowerCase"}},
+				{Path: "pick", Generic: true, Parameters: []Parameter{
+					{Name: "value", Generic: true},
+					{Name: "fallback", Type: "missing"},
+				}},
+			},
+		}, {
+			Name:  "code",
+			Parse: func(x string) (any, error) { return x, nil },
+		}, {
+			Name:  "kind",
+			Enums: []string{},
+		}, {
+			Name: "user",
+			Values: []Value{
+				{Path: "name", Type: "text"},
+			},
+		}}
+	}
+
+	s, err := NewSystem(newTypes())
+	assert.NoError(t, err)
+
+	messages := func(report CheckReport) []string {
+		out := make([]string, len(report))
+		for i, issue := range report {
+			out[i] = issue.String()
+		}
+		return out
+	}
+
+	report := s.Check()
+	assert.Equal(t, []string{
+		"info: type code is not referenced by any other type",
+		"info: type kind is not referenced by any other type",
+		"info: type user is not referenced by any other type",
+		"info: alias LowerCase on text.lower is given more than once",
+		`error: parameter fallback on text.pick has type "missing" which was not linked`,
+		"warning: type kind has an empty list of enums",
+		"warning: types text and code have an ambiguous parse order 0",
+	}, messages(report))
+	assert.True(t, report.HasErrors())
+	assert.Len(t, report.AtLeast(SeverityWarning), 3)
+
+	s, err = NewSystemWithOptions(newTypes(), SystemOptions{Root: "user"})
+	assert.NoError(t, err)
+
+	unreachable := make([]string, 0)
+	for _, issue := range s.Check() {
+		if issue.Kind == CheckUnreachableType {
+			unreachable = append(unreachable, issue.String())
+		}
+	}
+	assert.Equal(t, []string{
+		"warning: type code can not be reached from root user",
+		"warning: type kind can not be reached from root user",
+	}, unreachable)
+}
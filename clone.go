@@ -0,0 +1,122 @@
+package texpr
+
+// Returns a deep copy of the system. The copy has its own types, values, and parameters built from
+// the types the system was given, so changes to the types of one system are not seen by the other.
+// Types the system resolved lazily (see SystemOptions.Resolver and System.ListOf) are resolved again
+// by the copy when they are first referred to, and constants interned by the system are not copied.
+// An error is returned if the copy could not be built.
+func (sys System) Clone() (System, error) {
+	return NewSystemWithOptions(sys.definitions, sys.options)
+}
+
+// Returns deep copies of the types, without anything a system prepared on them.
+func copyTypes(types []Type) []Type {
+	copies := make([]Type, len(types))
+	for i := range types {
+		copies[i] = copyType(types[i])
+	}
+	return copies
+}
+
+// Returns a deep copy of the type, without anything a system prepared on it.
+func copyType(t Type) Type {
+	copied := t
+	copied.Descriptions = copyMap(t.Descriptions)
+	copied.Values = copyValues(t.Values)
+	copied.As = copyMap(t.As)
+	copied.Enums = copySlice(t.Enums)
+	copied.Mixins = copySlice(t.Mixins)
+	copied.Roles = copySlice(t.Roles)
+	copied.Layouts = copySlice(t.Layouts)
+	if t.EnumOptions != nil {
+		copied.EnumOptions = make(map[string]EnumOption, len(t.EnumOptions))
+		for option, enumOption := range t.EnumOptions {
+			enumOption.Labels = copyMap(enumOption.Labels)
+			enumOption.Descriptions = copyMap(enumOption.Descriptions)
+			copied.EnumOptions[option] = enumOption
+		}
+	}
+	copied.values = nil
+	copied.prefixes = nil
+	copied.as = nil
+	copied.enums = nil
+	copied.enumOptions = nil
+	copied.element = nil
+	return copied
+}
+
+// Returns deep copies of the values.
+func copyValues(values []Value) []Value {
+	if values == nil {
+		return nil
+	}
+	copies := make([]Value, len(values))
+	for i, v := range values {
+		v.Aliases = copySlice(v.Aliases)
+		v.Descriptions = copyMap(v.Descriptions)
+		v.Examples = copySlice(v.Examples)
+		v.Tests = copySlice(v.Tests)
+		v.Roles = copySlice(v.Roles)
+		v.valueType = nil
+		if v.Parameters != nil {
+			v.Parameters = make([]Parameter, len(values[i].Parameters))
+			for k, p := range values[i].Parameters {
+				p.Descriptions = copyMap(p.Descriptions)
+				p.Options = copySlice(p.Options)
+				p.Default = copyPointer(p.Default)
+				p.Min = copyPointer(p.Min)
+				p.Max = copyPointer(p.Max)
+				p.Step = copyPointer(p.Step)
+				p.MinLength = copyPointer(p.MinLength)
+				p.MaxLength = copyPointer(p.MaxLength)
+				p.parameterType = nil
+				p.pattern = nil
+				v.Parameters[k] = p
+			}
+		}
+		copies[i] = v
+	}
+	return copies
+}
+
+// Returns deep copies of the mixins.
+func copyMixins(mixins []Mixin) []Mixin {
+	if mixins == nil {
+		return nil
+	}
+	copies := make([]Mixin, len(mixins))
+	for i, m := range mixins {
+		m.Values = copyValues(m.Values)
+		copies[i] = m
+	}
+	return copies
+}
+
+// Returns a pointer to a copy of the value the pointer points to, or nil.
+func copyPointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	copied := *p
+	return &copied
+}
+
+// Returns a copy of the slice, or nil.
+func copySlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
+
+// Returns a copy of the map, or nil.
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	copied := make(map[K]V, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,98 @@
+package texpr
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemCopiesTypes(t *testing.T) {
+	min := 1.0
+	types := []Type{{
+		Name:       "number",
+		Comparable: true,
+		Mixins:     []string{"named"},
+		Parse:      func(x string) (any, error) { return x, nil },
+		Values: []Value{
+			{Path: "between", Type: "number", Parameters: []Parameter{{Name: "low", Type: "number", Min: &min}}},
+		},
+	}}
+	mixins := []Mixin{{Name: "named", Values: []Value{{Path: "name", Type: "number"}}}}
+	numbers, err := NewSystemWithOptions(types, SystemOptions{Mixins: mixins, BoolType: "number"})
+	assert.NoError(t, err)
+
+	// The types given are not changed by the system.
+	assert.Len(t, types[0].Values, 1)
+	assert.Nil(t, types[0].values)
+	assert.Len(t, numbers.Type("number").Values, 4)
+
+	// Changing the types given does not change the system.
+	types[0].Values[0].Path = "changed"
+	*types[0].Values[0].Parameters[0].Min = 5
+	mixins[0].Values[0].Path = "changed"
+	number := numbers.Type("number")
+	assert.NotNil(t, number.Value("between"))
+	assert.Equal(t, 1.0, *number.Value("between").Parameters[0].Min)
+	assert.NotNil(t, number.Value("name"))
+
+	clone, err := numbers.Clone()
+	assert.NoError(t, err)
+	_, err = clone.Parse(Options{RootType: "number", Expression: "between(2).name"})
+	assert.NoError(t, err)
+	assert.NotSame(t, number, clone.Type("number"))
+	assert.NotSame(t, number.Value("between"), clone.Type("number").Value("between"))
+
+	// Changing a clone does not change the system it was cloned from.
+	clone.Type("number").Description = "changed"
+	*clone.Type("number").Value("between").Parameters[0].Min = 3
+	assert.Equal(t, "", number.Description)
+	assert.Equal(t, 1.0, *number.Value("between").Parameters[0].Min)
+
+	// The error building the copy is returned.
+	broken := numbers
+	broken.options.Root = "missing"
+	_, err = broken.Clone()
+	assert.ErrorIs(t, err, ErrUnknownType)
+}
+
+func TestCloneResolvedTypes(t *testing.T) {
+	resolved := &Type{Name: "id", Parse: func(x string) (any, error) { return x, nil }}
+	withResolver, err := NewSystemWithOptions([]Type{{
+		Name:   "order",
+		Values: []Value{{Path: "id", Type: "id"}},
+	}}, SystemOptions{Resolver: func(name TypeName) (*Type, error) {
+		if name == "id" {
+			return resolved, nil
+		}
+		return nil, nil
+	}})
+	assert.NoError(t, err)
+	clone, err := withResolver.Clone()
+	assert.NoError(t, err)
+
+	assert.NotNil(t, withResolver.resolveType("id"))
+	assert.NotNil(t, clone.resolveType("id"))
+	assert.NotSame(t, withResolver.resolveType("id"), clone.resolveType("id"))
+	assert.Nil(t, resolved.values)
+}
+
+func TestConcurrentParse(t *testing.T) {
+	clone, err := sys.Clone()
+	assert.NoError(t, err)
+	expressions := []string{"user.name.upper", "user.createDate.add(2, day)", "time.today.dayOfWeek.text", "'a'.contains(user.name)"}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for _, expression := range expressions {
+				_, err := clone.Parse(Options{RootType: typeContext, Expression: expression, ExpectedTypes: []TypeName{typeText}})
+				assert.NoError(t, err)
+				_, err = clone.ListOf(typeInt)
+				assert.NoError(t, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -6,7 +6,8 @@ import (
 )
 
 // A compiler is a function that is given an expression, the root type, a previously compiled expression (CE),
-// argument CEs, and returns a CE for the given expression.
+// argument CEs, and returns a CE for the given expression. When Expr.InNullableChain is true the previous
+// value may be absent, and when Expr.Optional is true the rest of the chain should be absent if it is.
 type Compiler[CE any] func(e *Expr, root *Type, previous CE, arguments []CE) (CE, error)
 
 // A helper to the compile function.
@@ -20,6 +21,20 @@ type CompileSource[CE any] interface {
 	GetValueCompiler(e *Expr, root *Type, previous CE) (Compiler[CE], error)
 }
 
+// An optional extension to CompileSource for sources which can compile bind parameters.
+// Compiling an expression with a bind parameter against a source without it returns an error.
+type CompileBindSource[CE any] interface {
+	// Returns a compiled value for a bind parameter expression.
+	GetBindCompiled(e *Expr, root *Type, previous CE) (CE, error)
+}
+
+// An optional extension to CompileSource for sources which can compile list literals.
+// Compiling an expression with a list literal against a source without it returns an error.
+type CompileListSource[CE any] interface {
+	// Returns a compiled value for a list literal expression given its compiled elements.
+	GetListCompiled(e *Expr, root *Type, previous CE, elements []CE) (CE, error)
+}
+
 // A set of compilers mapped by their lowecased paths.
 type ValueCompilers[CE any] map[string]Compiler[CE]
 
@@ -35,9 +50,19 @@ type CompileSourceLookup[CE any] struct {
 	TypeCompilers TypeCompilers[CE]
 	// A compiler for a constant expression.
 	ConstantCompiler Compiler[CE]
+	// A compiler for a bind parameter expression, the name of the parameter is the expression's token.
+	BindCompiler Compiler[CE]
+	// A compiler for a list literal expression, like `[a, b]`, which is given its compiled elements as arguments.
+	ListCompiler Compiler[CE]
+	// A compiler for the operators added to types for their capabilities (see Type.Comparable) and to
+	// enum types (see SystemOptions.EnumOperators). It's used when TypeCompilers has no compiler for
+	// the operator, which is available with Value.Operator.
+	OperatorCompiler Compiler[CE]
 }
 
 var _ CompileSource[int] = CompileSourceLookup[int]{}
+var _ CompileBindSource[int] = CompileSourceLookup[int]{}
+var _ CompileListSource[int] = CompileSourceLookup[int]{}
 
 func (csl CompileSourceLookup[CE]) GetInitial(e *Expr) (CE, error) {
 	return csl.Initial, nil
@@ -45,25 +70,63 @@ func (csl CompileSourceLookup[CE]) GetInitial(e *Expr) (CE, error) {
 func (csl CompileSourceLookup[CE]) GetConstantCompiled(e *Expr, root *Type, previous CE, arguments []CE) (CE, error) {
 	return csl.ConstantCompiler(e, root, previous, arguments)
 }
+func (csl CompileSourceLookup[CE]) GetBindCompiled(e *Expr, root *Type, previous CE) (CE, error) {
+	if csl.BindCompiler == nil {
+		return previous, fmt.Errorf("no bind compiler specified for parameter :%s", e.Token)
+	}
+	return csl.BindCompiler(e, root, previous, nil)
+}
+func (csl CompileSourceLookup[CE]) GetListCompiled(e *Expr, root *Type, previous CE, elements []CE) (CE, error) {
+	if csl.ListCompiler == nil {
+		return previous, fmt.Errorf("no list compiler specified for a list of %d elements", len(elements))
+	}
+	return csl.ListCompiler(e, root, previous, elements)
+}
 func (csl CompileSourceLookup[CE]) GetValueCompiler(e *Expr, root *Type, previous CE) (Compiler[CE], error) {
 	parent := e.ParentType
 	if e.Prev != nil {
 		parent = e.Prev.Type
 	}
 	typeCompiler := csl.TypeCompilers[parent.Name]
+	valueCompiler := typeCompiler[strings.ToLower(e.Value.Path)]
+	if _, isOperator := e.Value.Operator(); valueCompiler == nil && isOperator && csl.OperatorCompiler != nil {
+		return csl.OperatorCompiler, nil
+	}
 	if typeCompiler == nil {
 		return nil, fmt.Errorf("no value compilers specified for %s", parent.Name)
 	}
-	valueCompiler := typeCompiler[strings.ToLower(e.Value.Path)]
 	if valueCompiler == nil {
 		return nil, fmt.Errorf("no value %s specified for %s", e.Value.Path, parent.Name)
 	}
 	return valueCompiler, nil
 }
 
+// Returns whether the lookup has a compiler for the expression, ignoring its arguments. This can be
+// used to split expressions between backends, see SplitPushDown.
+func (csl CompileSourceLookup[CE]) Supports(e *Expr) bool {
+	switch {
+	case e.Constant:
+		return csl.ConstantCompiler != nil
+	case e.Bind:
+		return csl.BindCompiler != nil
+	case e.List:
+		return csl.ListCompiler != nil
+	case e.Placeholder || e.Value == nil:
+		return false
+	}
+	var previous CE
+	_, err := csl.GetValueCompiler(e, e.ParentType, previous)
+	return err == nil
+}
+
 // Compiles the given expression into the desired compiled expression (CE). If there was any error
 // or a type or value compiler was not specified an error will be returned.
 func Compile[CE any](e *Expr, source CompileSource[CE]) (CE, error) {
+	if placeholders := e.Placeholders(); len(placeholders) > 0 {
+		var empty CE
+		return empty, NewParseErrorKind(placeholders[0], ErrPlaceholder, "placeholders must be filled in before compilation")
+	}
+
 	last, err := source.GetInitial(e)
 	if err != nil {
 		return last, err
@@ -78,6 +141,36 @@ func Compile[CE any](e *Expr, source CompileSource[CE]) (CE, error) {
 			if err != nil {
 				break
 			}
+		} else if current.Bind {
+			bindSource, ok := source.(CompileBindSource[CE])
+			if !ok {
+				err = fmt.Errorf("bind parameter :%s is not supported by the compile source", current.Token)
+				break
+			}
+			last, err = bindSource.GetBindCompiled(current, root, last)
+			if err != nil {
+				break
+			}
+		} else if current.List {
+			listSource, ok := source.(CompileListSource[CE])
+			if !ok {
+				err = fmt.Errorf("a list of %d elements is not supported by the compile source", len(current.Arguments))
+				break
+			}
+			elements := make([]CE, len(current.Arguments))
+			for i, element := range current.Arguments {
+				elements[i], err = Compile(element, source)
+				if err != nil {
+					break
+				}
+			}
+			if err != nil {
+				break
+			}
+			last, err = listSource.GetListCompiled(current, root, last, elements)
+			if err != nil {
+				break
+			}
 		} else {
 			valueCompiler, valueErr := source.GetValueCompiler(current, root, last)
 			if valueErr != nil {
@@ -0,0 +1,220 @@
+package texpr
+
+import (
+	"sort"
+	"strings"
+)
+
+// A suggestion for what can be entered at a position in an expression.
+type Completion struct {
+	// The text which would replace the partial token at the position.
+	Text string
+	// What the completion is, either a value or an enum option.
+	Kind DocKind
+	// The value suggested, if any.
+	Value *Value
+	// The type the suggested value is on, if any.
+	ParentType *Type
+	// The type of the suggested value or the type of the enum option.
+	Type *Type
+	// A human readable signature of the suggestion.
+	Signature string
+	// The description of the suggestion.
+	Description string
+	// The label to display for an enum option, see EnumOption.
+	Label string
+	// If the suggested enum option is deprecated.
+	Deprecated bool
+	// The examples of the suggested value.
+	Examples []string
+	// The number of times the suggested value is used, see System.CompleteByUsage.
+	Uses int
+	// The start of the partial token being replaced.
+	Start Position
+	// The end of the partial token being replaced.
+	End Position
+}
+
+// Returns the completions for the expression in the options at the given index. Only the expression
+// up to the index is considered. Values from the type the partial token is on are suggested followed
+// by enum options of the expected types (when the token starts a chain).
+func (sys System) Complete(opts Options, index int) []Completion {
+	completions := make([]Completion, 0)
+	root := sys.resolveType(opts.RootType)
+	if root == nil || index < 0 || index > len(opts.Expression) {
+		return completions
+	}
+
+	expectedTypes := make([]*Type, 0, len(opts.ExpectedTypes))
+	for _, name := range opts.ExpectedTypes {
+		if t := sys.resolveType(name); t != nil {
+			expectedTypes = append(expectedTypes, t)
+		}
+	}
+
+	prefix := opts.Expression[:index]
+	p := newParser(prefix, sys.options.Identifiers)
+	err := error(nil)
+	for p.hasData() && err == nil {
+		_, err = p.parseExpr()
+	}
+	if p.first == nil {
+		return emptyCompletions(root, expectedTypes, opts.Locale, opts.Roles, Position{}, completions)
+	}
+	parameters, _ := sys.parameterTypes(opts)
+	ctx := newLinkContext(root, parameters)
+	ctx.roles = opts.Roles
+	sys.link(p.first, expectedTypes, ctx)
+
+	target := lastExprAt(p.first, index)
+	if target == nil {
+		return completions
+	}
+
+	parentType := root
+	if target.Prev != nil {
+		parentType = target.Prev.Type
+	}
+	if target.Prev == nil && target.Parent != nil {
+		expectedTypes = expectedTypes[:0]
+		if target.Parent.Value != nil {
+			argIndex := 0
+			for i, arg := range target.Parent.Arguments {
+				if arg == target {
+					argIndex = i
+				}
+			}
+			param := target.Parent.Value.Parameter(argIndex)
+			if param != nil && param.parameterType != nil {
+				expectedTypes = append(expectedTypes, param.parameterType)
+			}
+		}
+	}
+
+	locale := opts.Locale
+	token := strings.ToLower(target.Token)
+	if parentType != nil {
+		for i := range parentType.Values {
+			v := &parentType.Values[i]
+			if completionMatches(v, token) && parentType.Allows(v, opts.Roles) {
+				completions = append(completions, Completion{
+					Text:        v.Path,
+					Kind:        DocKindValue,
+					Value:       v,
+					ParentType:  parentType,
+					Type:        v.valueType,
+					Signature:   v.Signature(),
+					Description: v.Describe(locale),
+					Examples:    v.Examples,
+					Start:       target.Start,
+					End:         target.End,
+				})
+			}
+		}
+	}
+	if target.Prev == nil {
+		for _, t := range expectedTypes {
+			for _, enumValue := range t.SortedEnums() {
+				if strings.HasPrefix(strings.ToLower(enumValue), token) {
+					completions = append(completions, enumCompletion(t, enumValue, locale, target.Start, target.End))
+				}
+			}
+		}
+	}
+
+	return completions
+}
+
+// Returns the completions when nothing has been entered yet.
+func emptyCompletions(root *Type, expectedTypes []*Type, locale string, roles []string, at Position, completions []Completion) []Completion {
+	for i := range root.Values {
+		v := &root.Values[i]
+		if !root.Allows(v, roles) {
+			continue
+		}
+		completions = append(completions, Completion{
+			Text:        v.Path,
+			Kind:        DocKindValue,
+			Value:       v,
+			ParentType:  root,
+			Type:        v.valueType,
+			Signature:   v.Signature(),
+			Description: v.Describe(locale),
+			Examples:    v.Examples,
+			Start:       at,
+			End:         at,
+		})
+	}
+	for _, t := range expectedTypes {
+		for _, enumValue := range t.SortedEnums() {
+			completions = append(completions, enumCompletion(t, enumValue, locale, at, at))
+		}
+	}
+	return completions
+}
+
+// Returns the completion for an enum option of the type.
+func enumCompletion(t *Type, enumValue string, locale string, start, end Position) Completion {
+	option, _ := t.EnumOption(enumValue)
+	return Completion{
+		Text:        enumValue,
+		Kind:        DocKindEnum,
+		Type:        t,
+		Signature:   enumValue,
+		Description: t.DescribeEnum(enumValue, locale),
+		Label:       t.EnumLabel(enumValue, locale),
+		Deprecated:  option.Deprecated,
+		Start:       start,
+		End:         end,
+	}
+}
+
+// Returns whether the value's path or one of its aliases starts with the lowercase token.
+func completionMatches(v *Value, token string) bool {
+	if strings.HasPrefix(strings.ToLower(v.Path), token) {
+		return true
+	}
+	for _, a := range v.Aliases {
+		if strings.HasPrefix(strings.ToLower(a), token) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the last expression (in the chain or arguments) which ends at the given index.
+func lastExprAt(e *Expr, index int) *Expr {
+	var found *Expr
+	for _, c := range e.Chain() {
+		if c.End.Index == index && !c.Constant {
+			found = c
+		}
+		for _, arg := range c.Arguments {
+			if inner := lastExprAt(arg, index); inner != nil {
+				found = inner
+			}
+		}
+	}
+	return found
+}
+
+// Returns the completions like Complete with the suggested values ordered from the most used to the
+// least used, where counts are the uses of the value paths keyed by the name of the type they're on (see
+// UsageReport.Counts), so the values commonly used in stored expressions are suggested first. Values
+// with the same usage keep their order, and enum options follow the values.
+func (sys System) CompleteByUsage(opts Options, index int, counts map[TypeName]map[string]int) []Completion {
+	completions := sys.Complete(opts, index)
+	for i := range completions {
+		if c := completions[i]; c.Value != nil && c.ParentType != nil {
+			completions[i].Uses = counts[c.ParentType.Name][c.Value.Path]
+		}
+	}
+	sort.SliceStable(completions, func(i, j int) bool {
+		a, b := completions[i], completions[j]
+		if (a.Kind == DocKindValue) != (b.Kind == DocKindValue) {
+			return a.Kind == DocKindValue
+		}
+		return a.Uses > b.Uses
+	})
+	return completions
+}
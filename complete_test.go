@@ -0,0 +1,102 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       Options
+		index         int
+		expectedTexts []string
+		expectedStart int
+	}{{
+		name:          "empty",
+		options:       Options{RootType: typeContext, Expression: ""},
+		index:         0,
+		expectedTexts: []string{"time", "user"},
+	}, {
+		name:          "after dot",
+		options:       Options{RootType: typeContext, Expression: "user."},
+		index:         5,
+		expectedTexts: []string{"name", "createDate"},
+		expectedStart: 5,
+	}, {
+		name:          "partial",
+		options:       Options{RootType: typeContext, Expression: "user.createDate.m"},
+		index:         17,
+		expectedTexts: []string{"minute", "month"},
+		expectedStart: 16,
+	}, {
+		name:          "alias",
+		options:       Options{RootType: typeContext, Expression: "user.createDate.min.text"},
+		index:         19,
+		expectedTexts: []string{"minute"},
+		expectedStart: 16,
+	}, {
+		name:          "argument enums",
+		options:       Options{RootType: typeContext, Expression: "time.today.add(1, w"},
+		index:         19,
+		expectedTexts: []string{"week"},
+		expectedStart: 18,
+	}, {
+		name:          "expected enums",
+		options:       Options{RootType: typeContext, Expression: "t", ExpectedTypes: []TypeName{typeDayOfWeek}},
+		index:         1,
+		expectedTexts: []string{"time", "tuesday", "thursday"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			completions := sys.Complete(test.options, test.index)
+			texts := make([]string, len(completions))
+			for i, c := range completions {
+				texts[i] = c.Text
+				assert.Equal(t, test.expectedStart, c.Start.Index)
+			}
+			assert.Equal(t, test.expectedTexts, texts)
+		})
+	}
+
+	completions := sys.Complete(Options{RootType: typeContext, Expression: "time.today.a"}, 12)
+	assert.Len(t, completions, 1)
+	assert.Equal(t, []string{"add(2, day)"}, completions[0].Examples)
+}
+
+func TestCompleteByUsage(t *testing.T) {
+	usage := sys.Usage([]StoredExpression{
+		{ID: "a", Options: Options{RootType: typeContext, Expression: "user.createDate.month"}},
+		{ID: "b", Options: Options{RootType: typeContext, Expression: "user.createDate.month.text"}},
+		{ID: "c", Options: Options{RootType: typeContext, Expression: "user.createDate.minute"}},
+		{ID: "d", Options: Options{RootType: typeContext, Expression: "user.createDate.month"}},
+	})
+
+	texts := func(completions []Completion) []string {
+		out := make([]string, len(completions))
+		for i, c := range completions {
+			out[i] = c.Text
+		}
+		return out
+	}
+
+	options := Options{RootType: typeContext, Expression: "user.createDate.m"}
+	assert.Equal(t, []string{"minute", "month"}, texts(sys.Complete(options, 17)))
+	completions := sys.CompleteByUsage(options, 17, usage.Counts())
+	assert.Equal(t, []string{"month", "minute"}, texts(completions))
+	assert.Equal(t, 3, completions[0].Uses)
+	assert.Equal(t, 1, completions[1].Uses)
+
+	completions = sys.CompleteByUsage(Options{RootType: typeContext, Expression: "user."}, 5, usage.Counts())
+	assert.Equal(t, []string{"createDate", "name"}, texts(completions))
+	assert.Equal(t, []string{"name", "createDate"}, texts(sys.CompleteByUsage(Options{RootType: typeContext, Expression: "user."}, 5, nil)))
+
+	cloned, err := sys.Clone()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"month", "minute"}, texts(cloned.CompleteByUsage(options, 17, usage.Counts())))
+	assert.Equal(t, []string{"createDate", "name"}, texts(sys.CompleteByUsage(Options{RootType: typeContext, Expression: "user."}, 5, map[TypeName]map[string]int{
+		"user": {"createDate": 2},
+	})))
+}
@@ -0,0 +1,86 @@
+package texpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Composes two linked expressions into one where the result of a is the root of b. For example
+// composing `user.name` (with context as the root) and `lower.contains(upper)` (with text as the root)
+// produces `user.name.lower.contains(user.name.upper)`. Any chain in the arguments of b that refers
+// to b's root is given a as a prefix, so the composed expression is linked against the root of a with
+// the roles a was parsed with (or b's when a was parsed without roles), see Options.Roles.
+func (sys System) Compose(a, b *Expr) (*Expr, error) {
+	if a == nil || b == nil {
+		return nil, NewParseErrorKind(nil, ErrSyntax, "both expressions are required to compose")
+	}
+	last := a.Last()
+	if last.Type == nil || b.ParentType == nil || last.Type.Name != b.ParentType.Name {
+		return nil, NewParseErrorKind(b, ErrTypeMismatch, fmt.Sprintf("expression %s does not produce the root type of %s", a, b))
+	}
+	if b.Constant || b.Bind || b.Placeholder {
+		return nil, NewParseErrorKind(b, ErrSyntax, fmt.Sprintf("expression %s must start with a value to be composed", b))
+	}
+
+	parameters := make(map[string]*Type)
+	collectBindTypes(a, parameters)
+	collectBindTypes(b, parameters)
+
+	composed := a.Clone()
+	tail := b.Clone()
+	prefixRootArguments(tail, a)
+	joinChains(composed, tail)
+
+	ctx := newLinkContext(a.ParentType, parameters)
+	ctx.roles = a.roles
+	if ctx.roles == nil {
+		ctx.roles = b.roles
+	}
+	errs := sys.link(composed, nil, ctx)
+	switch len(errs) {
+	case 0:
+		return composed, nil
+	case 1:
+		return composed, errs[0]
+	default:
+		return composed, ParseErrors(errs)
+	}
+}
+
+// Adds the type of every bind parameter in the expression to the given map.
+func collectBindTypes(e *Expr, parameters map[string]*Type) {
+	for _, c := range e.Chain() {
+		if c.Bind && c.Type != nil && c.Type != Unknown {
+			parameters[strings.ToLower(c.Token)] = c.Type
+		}
+		for _, arg := range c.Arguments {
+			collectBindTypes(arg, parameters)
+		}
+	}
+}
+
+// Replaces every argument chain which starts with a value on the root with the chain prefixed by a copy of prefix.
+func prefixRootArguments(e *Expr, prefix *Expr) {
+	for _, c := range e.Chain() {
+		for i, arg := range c.Arguments {
+			prefixRootArguments(arg, prefix)
+			if arg.Constant || arg.Bind || arg.Placeholder || arg.Value == nil {
+				continue
+			}
+			prefixed := prefix.Clone()
+			prefixed.Parent = c
+			prefixed.Parameter = arg.Parameter
+			arg.Parent = nil
+			arg.Parameter = nil
+			joinChains(prefixed, arg)
+			c.Arguments[i] = prefixed
+		}
+	}
+}
+
+// Appends the tail chain to the end of the head chain.
+func joinChains(head *Expr, tail *Expr) {
+	last := head.Last()
+	last.Next = tail
+	tail.Prev = last
+}
@@ -0,0 +1,41 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompose(t *testing.T) {
+	a, err := sys.Parse(Options{RootType: typeContext, Expression: "user.name"})
+	assert.NoError(t, err)
+
+	b, err := sys.Parse(Options{RootType: typeText, Expression: "lower.contains(lower.length.text).and(:flag)", Parameters: map[string]TypeName{"flag": typeBool}})
+	assert.NoError(t, err)
+
+	composed, err := sys.Compose(a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, "user.name.lower.contains(user.name.lower.length.text).and(:flag)", composed.String())
+	assert.Equal(t, typeBool, composed.Last().Type.Name)
+	assert.Equal(t, typeContext, composed.Next.Next.Next.Arguments[0].ParentType.Name)
+
+	// the inputs are left untouched
+	assert.Equal(t, "user.name", a.String())
+	assert.Equal(t, "lower.contains(lower.length.text).and(:flag)", b.String())
+
+	c, err := sys.Parse(Options{RootType: typeText, Expression: "length>(3)"})
+	assert.NoError(t, err)
+
+	composed, err = sys.Compose(a, c)
+	assert.NoError(t, err)
+	assert.Equal(t, "user.name.length>('3')", composed.String())
+
+	run, err := Compile[Run](composed, compileOptions)
+	assert.NoError(t, err)
+	result, err := run(map[string]any{"user": map[string]any{"name": "Mason"}})
+	assert.NoError(t, err)
+	assert.Equal(t, true, result)
+
+	_, err = sys.Compose(composed, c)
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+}
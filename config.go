@@ -0,0 +1,212 @@
+package texpr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// The struct tag of fields which hold expressions, see System.ValidateStruct.
+const ExprTag = "expr"
+
+// An expression in a field of a struct is invalid, see System.ValidateStruct.
+type FieldError struct {
+	// The path of the field from the struct given, like Rules[2].When or Limits["daily"].
+	Field string
+	// The expression in the field.
+	Expression string
+	// Why the expression is invalid, usually a ParseError or ParseErrors.
+	Err error
+}
+
+var _ error = FieldError{}
+
+// The field and the error, with one line per error when the expression has more than one.
+func (e FieldError) Error() string {
+	lines := strings.Split(e.Err.Error(), "\n")
+	for i := range lines {
+		lines[i] = e.Field + ": " + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Returns the error so it can be used with errors.Is and errors.As.
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// The invalid expressions found in the fields of a struct.
+type FieldErrors []FieldError
+
+var _ error = FieldErrors{}
+
+// The errors of all the fields, one per line.
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Returns the errors so they can be used with errors.Is and errors.As.
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// The options of a field given by its expr tag.
+type fieldTag struct {
+	expected TypeName
+	root     TypeName
+	required bool
+}
+
+// Parses an expr tag like "bool,root=context,required" where the first part is the expected type.
+func parseFieldTag(tag string) (fieldTag, error) {
+	parts := strings.Split(tag, ",")
+	parsed := fieldTag{expected: TypeName(strings.TrimSpace(parts[0]))}
+	for _, part := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "root":
+			parsed.root = TypeName(value)
+		case "required":
+			parsed.required = true
+		default:
+			return parsed, fmt.Errorf("unknown expr tag option %q", key)
+		}
+	}
+	return parsed, nil
+}
+
+// Parses the expressions in the fields of the struct (or pointer to a struct) tagged with expr, like
+// `expr:"bool,root=context"`, and returns FieldErrors for the invalid ones. The first part of the tag
+// is the expected type of the expression, which can be empty to accept any type. The root option is
+// the root type, which is otherwise the root type of the options, and the required option makes
+// empty expressions invalid; otherwise empty fields are skipped. Tagged fields can be strings, string
+// pointers, or slices and maps of strings, and structs in fields, slices, and maps are validated too.
+// The options are used to parse every expression, like the bind parameters the expressions can use.
+func (sys System) ValidateStruct(v any, options Options) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("ValidateStruct expects a struct but was given %T", v)
+	}
+	errs := sys.validateStruct(value, "", options, make(FieldErrors, 0))
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Decodes a config with the decode function, like the Decode method of a json.Decoder or yaml.Decoder,
+// and validates its expressions with ValidateStruct so configs with invalid rules fail when loaded.
+func (sys System) DecodeStruct(decode func(v any) error, v any, options Options) error {
+	if err := decode(v); err != nil {
+		return err
+	}
+	return sys.ValidateStruct(v, options)
+}
+
+// Validates the tagged fields of the struct and the structs within it.
+func (sys System) validateStruct(value reflect.Value, path string, options Options, errs FieldErrors) FieldErrors {
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		tag, tagged := field.Tag.Lookup(ExprTag)
+		if !tagged {
+			errs = sys.validateNested(value.Field(i), fieldPath, options, errs)
+			continue
+		}
+		parsed, err := parseFieldTag(tag)
+		if err != nil {
+			errs = append(errs, FieldError{Field: fieldPath, Err: err})
+			continue
+		}
+		fieldOptions := options
+		if parsed.expected != "" {
+			fieldOptions.ExpectedTypes = []TypeName{parsed.expected}
+		}
+		if parsed.root != "" {
+			fieldOptions.RootType = parsed.root
+		}
+		errs = sys.validateField(value.Field(i), fieldPath, fieldOptions, parsed.required, errs)
+	}
+	return errs
+}
+
+// Validates the structs in the value, which is a field without an expr tag.
+func (sys System) validateNested(value reflect.Value, path string, options Options, errs FieldErrors) FieldErrors {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !value.IsNil() {
+			errs = sys.validateNested(value.Elem(), path, options, errs)
+		}
+	case reflect.Struct:
+		errs = sys.validateStruct(value, path, options, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			errs = sys.validateNested(value.Index(i), fmt.Sprintf("%s[%d]", path, i), options, errs)
+		}
+	case reflect.Map:
+		for _, key := range sortedMapKeys(value) {
+			errs = sys.validateNested(value.MapIndex(key), fmt.Sprintf("%s[%#v]", path, key.Interface()), options, errs)
+		}
+	}
+	return errs
+}
+
+// Parses the expressions in the value of a tagged field.
+func (sys System) validateField(value reflect.Value, path string, options Options, required bool, errs FieldErrors) FieldErrors {
+	switch value.Kind() {
+	case reflect.String:
+		options.Expression = value.String()
+		if options.Expression == "" && !required {
+			return errs
+		}
+		if _, err := sys.Parse(options); err != nil {
+			errs = append(errs, FieldError{Field: path, Expression: options.Expression, Err: err})
+		}
+	case reflect.Pointer:
+		if !value.IsNil() {
+			errs = sys.validateField(value.Elem(), path, options, required, errs)
+		} else if required {
+			errs = append(errs, FieldError{Field: path, Err: ErrNoExpression})
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			errs = sys.validateField(value.Index(i), fmt.Sprintf("%s[%d]", path, i), options, required, errs)
+		}
+	case reflect.Map:
+		for _, key := range sortedMapKeys(value) {
+			errs = sys.validateField(value.MapIndex(key), fmt.Sprintf("%s[%#v]", path, key.Interface()), options, required, errs)
+		}
+	default:
+		errs = append(errs, FieldError{Field: path, Err: errors.New("expr tag is on a field which is not a string, string pointer, or slice or map of strings")})
+	}
+	return errs
+}
+
+// Returns the keys of the map ordered by how they're formatted, so errors are in a stable order.
+func sortedMapKeys(value reflect.Value) []reflect.Value {
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	return keys
+}
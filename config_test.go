@@ -0,0 +1,84 @@
+package texpr
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type testRule struct {
+	Name  string
+	When  string   `expr:"bool,root=context,required" json:"when" yaml:"when"`
+	Notes []string `expr:",root=user" json:"notes" yaml:"notes"`
+}
+
+type testConfig struct {
+	Rules   []testRule        `json:"rules" yaml:"rules"`
+	Default *string           `expr:"text" json:"default" yaml:"default"`
+	Limits  map[string]string `expr:"int" json:"limits" yaml:"limits"`
+	Nested  *struct {
+		Check string `expr:"bool" json:"check" yaml:"check"`
+	} `json:"nested" yaml:"nested"`
+}
+
+func TestValidateStruct(t *testing.T) {
+	valid := `{
+		"rules": [
+			{"when": "user.name.isUpper", "notes": ["name", "createDate"]},
+			{"when": "time.today.dayOfWeek.oneOf(monday, friday)"}
+		],
+		"default": "user.name",
+		"limits": {"daily": "user.name.len"},
+		"nested": {"check": "user.name.isLower"}
+	}`
+	config := testConfig{}
+	err := sys.DecodeStruct(json.NewDecoder(strings.NewReader(valid)).Decode, &config, Options{RootType: typeContext})
+	assert.NoError(t, err)
+	assert.Len(t, config.Rules, 2)
+
+	invalid := `
+rules:
+  - when: user.nam.isUpper
+    notes: [name, name.age]
+  - when: ""
+default: user
+limits:
+  weekly: user.name
+  daily: user.name.len
+nested:
+  check: user.name.contains(:part)
+`
+	config = testConfig{}
+	err = sys.DecodeStruct(yaml.NewDecoder(strings.NewReader(invalid)).Decode, &config, Options{RootType: typeContext})
+	assert.ErrorIs(t, err, ErrUnknownValue)
+	assert.ErrorIs(t, err, ErrNoExpression)
+
+	var fieldErrors FieldErrors
+	assert.ErrorAs(t, err, &fieldErrors)
+	fields := make([]string, len(fieldErrors))
+	for i, fieldError := range fieldErrors {
+		fields[i] = fieldError.Field
+	}
+	assert.Equal(t, []string{"Rules[0].When", "Rules[0].Notes[1]", "Rules[1].When", "Limits[\"weekly\"]", "Nested.Check"}, fields)
+	assert.Equal(t, "user.nam.isUpper", fieldErrors[0].Expression)
+	assert.Equal(t, "Rules[0].Notes[1]: invalid value age", fieldErrors[1].Error())
+
+	// Conversions to the expected type are allowed, like the text of a user.
+	assert.NotContains(t, fields, "Default")
+
+	err = sys.DecodeStruct(json.NewDecoder(strings.NewReader("{")).Decode, &config, Options{})
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnknownValue)
+
+	err = sys.ValidateStruct("user", Options{})
+	assert.EqualError(t, err, "ValidateStruct expects a struct but was given string")
+
+	err = sys.ValidateStruct(struct {
+		Count int    `expr:"int"`
+		Rule  string `expr:"bool,strict"`
+	}{Rule: "true"}, Options{RootType: typeContext})
+	assert.EqualError(t, err, "Count: expr tag is on a field which is not a string, string pointer, or slice or map of strings\nRule: unknown expr tag option \"strict\"")
+}
@@ -0,0 +1,142 @@
+package texpr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// An argument did not meet the constraints of its parameter, like Parameter.Min.
+var ErrConstraint = errors.New("constraint violated")
+
+// The tolerance used to decide whether a number is a multiple of Parameter.Step.
+const stepEpsilon = 1e-9
+
+// Returns whether the parameter has constraints on the values given to it.
+func (p Parameter) Constrained() bool {
+	return p.Min != nil || p.Max != nil || p.Step != nil || p.Pattern != "" || p.MinLength != nil || p.MaxLength != nil || len(p.Options) > 0
+}
+
+// Returns an error if the value does not meet the constraints of the parameter. Numeric constraints
+// apply to Go numbers, BigInt, and BigDecimal values and text constraints apply to strings, other
+// values are not checked against them.
+func (p Parameter) Check(value any) error {
+	if !p.Constrained() {
+		return nil
+	}
+	if text := reflect.ValueOf(value); isString(text) {
+		return p.checkText(text.String())
+	}
+	r, isNumber := bigRat(value)
+	if !isNumber {
+		return nil
+	}
+	n, _ := r.Float64()
+	if p.Min != nil && n < *p.Min {
+		return fmt.Errorf("%v is less than the minimum %v of %s", value, *p.Min, p.Name)
+	}
+	if p.Max != nil && n > *p.Max {
+		return fmt.Errorf("%v is more than the maximum %v of %s", value, *p.Max, p.Name)
+	}
+	if p.Step != nil && *p.Step > 0 {
+		from := 0.0
+		if p.Min != nil {
+			from = *p.Min
+		}
+		steps := (n - from) / *p.Step
+		if math.Abs(steps-math.Round(steps)) > stepEpsilon*math.Max(1, math.Abs(steps)) {
+			return fmt.Errorf("%v is not a multiple of %v for %s", value, *p.Step, p.Name)
+		}
+	}
+	return nil
+}
+
+// Returns an error if the text does not meet the text constraints of the parameter.
+func (p Parameter) checkText(text string) error {
+	length := utf8.RuneCountInString(text)
+	if p.MinLength != nil && length < *p.MinLength {
+		return fmt.Errorf("%q is shorter than the minimum length %d of %s", text, *p.MinLength, p.Name)
+	}
+	if p.MaxLength != nil && length > *p.MaxLength {
+		return fmt.Errorf("%q is longer than the maximum length %d of %s", text, *p.MaxLength, p.Name)
+	}
+	if len(p.Options) > 0 {
+		found := false
+		for _, option := range p.Options {
+			found = found || strings.EqualFold(option, text)
+		}
+		if !found {
+			return fmt.Errorf("%q is not one of %s for %s", text, strings.Join(p.Options, ", "), p.Name)
+		}
+	}
+	if p.Pattern != "" {
+		pattern := p.pattern
+		if pattern == nil {
+			var err error
+			if pattern, err = regexp.Compile(p.Pattern); err != nil {
+				return err
+			}
+		}
+		if !pattern.MatchString(text) {
+			return fmt.Errorf("%q does not match the pattern %s of %s", text, p.Pattern, p.Name)
+		}
+	}
+	return nil
+}
+
+// Returns an error if the constraints of the parameter can't be met by any value, and compiles its pattern.
+func (p *Parameter) checkConstraints() error {
+	if p.Min != nil && p.Max != nil && *p.Min > *p.Max {
+		return fmt.Errorf("minimum %v is more than the maximum %v", *p.Min, *p.Max)
+	}
+	if p.Step != nil && *p.Step <= 0 {
+		return fmt.Errorf("step %v must be positive", *p.Step)
+	}
+	if p.MinLength != nil && p.MaxLength != nil && *p.MinLength > *p.MaxLength {
+		return fmt.Errorf("minimum length %d is more than the maximum length %d", *p.MinLength, *p.MaxLength)
+	}
+	if p.Pattern != "" {
+		pattern, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return fmt.Errorf("pattern %s is not valid: %w", p.Pattern, err)
+		}
+		p.pattern = pattern
+	}
+	return nil
+}
+
+// Returns an error if the default of the parameter parses but does not meet its constraints.
+func (p Parameter) checkDefault() error {
+	if p.Default == nil || p.parameterType == nil || !p.Constrained() {
+		return nil
+	}
+	parsed, _, err := p.parameterType.ParseLayout(*p.Default)
+	if err != nil {
+		return nil
+	}
+	return p.Check(parsed)
+}
+
+// Returns whether the argument is a constant, which is checked against the constraints of its parameter when linked.
+func isConstantArgument(arg *Expr) bool {
+	return arg.Constant && arg.Next == nil
+}
+
+// Returns a parse error of the ErrConstraint kind for the argument if the value does not meet the
+// constraints of the argument's parameter.
+func checkArgument(arg *Expr, value any) *ParseError {
+	if arg.Parameter == nil {
+		return nil
+	}
+	if err := arg.Parameter.Check(value); err != nil {
+		parseError := NewParseErrorKind(arg, ErrConstraint, err.Error())
+		parseError.Parameter = arg.Parameter
+		parseError.Cause = err
+		return &parseError
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package texpr
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type constraintScore struct {
+	Value int
+	Bonus int
+}
+
+func (s constraintScore) Percent(percent int) int {
+	return s.Value * percent / 100
+}
+
+func (s constraintScore) Round(step int) int {
+	return s.Value / step * step
+}
+
+func TestNumericConstraints(t *testing.T) {
+	zero, five, ten, hundred := 0.0, 5.0, 10.0, 100.0
+	one := 1.0
+	newReflect := func(check bool) *Reflect {
+		r, err := NewReflect(ReflectOptions{
+			CheckParameters: check,
+			Types: map[reflect.Type]Type{
+				TypeOf[int](): {
+					Name: "int",
+					Parse: func(x string) (any, error) {
+						return strconv.Atoi(x)
+					},
+				},
+				TypeOf[constraintScore](): {
+					Values: []Value{
+						{Path: "percent", Parameters: []Parameter{
+							{Name: "percent", Min: &zero, Max: &hundred},
+						}},
+						{Path: "round", Parameters: []Parameter{
+							{Name: "step", Min: &five, Step: &five},
+						}},
+					},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		return r
+	}
+	r := newReflect(true)
+	root := NameOf[constraintScore]()
+	score := constraintScore{Value: 47, Bonus: 120}
+
+	tests := []struct {
+		expression string
+		message    string
+		result     any
+	}{
+		{expression: "percent(50)", result: 23},
+		{expression: "percent(0)", result: 0},
+		{expression: "percent(101)", message: "101 is more than the maximum 100 of percent"},
+		{expression: "percent(-1)", message: "-1 is less than the minimum 0 of percent"},
+		{expression: "round(10)", result: 40},
+		{expression: "round(12)", message: "12 is not a multiple of 5 for step"},
+		{expression: "round(0)", message: "0 is less than the minimum 5 of step"},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := r.Parse(Options{RootType: root, Expression: test.expression})
+			if test.message != "" {
+				assert.EqualError(t, err, test.message)
+				assert.ErrorIs(t, err, ErrConstraint)
+				var parseError ParseError
+				assert.True(t, errors.As(err, &parseError))
+				assert.NotNil(t, parseError.Parameter)
+				return
+			}
+			assert.NoError(t, err)
+			result, err := r.Evaluate(e, score)
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+
+	e, err := r.Parse(Options{RootType: root, Expression: "percent(bonus)"})
+	assert.NoError(t, err)
+	_, err = r.Evaluate(e, score)
+	assert.EqualError(t, err, "120 is more than the maximum 100 of percent")
+	assert.ErrorIs(t, err, ErrConstraint)
+
+	result, err := newReflect(false).Evaluate(e, score)
+	assert.NoError(t, err)
+	assert.Equal(t, 56, result)
+
+	_, err = NewSystem([]Type{{
+		Name: "int",
+		Values: []Value{
+			{Path: "clamp", Type: "int", Parameters: []Parameter{
+				{Name: "to", Type: "int", Min: &ten, Max: &one},
+			}},
+		},
+	}})
+	assert.EqualError(t, err, "constraints on int.clamp (parameter to) are invalid: minimum 10 is more than the maximum 1")
+	assert.ErrorIs(t, err, ErrConstraint)
+
+	defaults := map[string]string{
+		"3":  "default 3 on int.round (parameter to) is invalid: 3 is less than the minimum 5 of to",
+		"12": "default 12 on int.round (parameter to) is invalid: 12 is more than the maximum 10 of to",
+		"6":  "default 6 on int.round (parameter to) is invalid: 6 is not a multiple of 5 for to",
+	}
+	for value, message := range defaults {
+		value := value
+		_, err = NewSystem([]Type{{
+			Name:  "int",
+			Parse: func(x string) (any, error) { return strconv.Atoi(x) },
+			Values: []Value{
+				{Path: "round", Type: "int", Parameters: []Parameter{
+					{Name: "to", Type: "int", Min: &five, Max: &ten, Step: &five, Default: &value},
+				}},
+			},
+		}})
+		assert.EqualError(t, err, message)
+		assert.ErrorIs(t, err, ErrConstraint)
+	}
+}
+
+func TestTextConstraints(t *testing.T) {
+	three, eight := 3, 8
+	newSystem := func(pattern string) (System, error) {
+		return NewSystem([]Type{{
+			Name:  "text",
+			Parse: func(x string) (any, error) { return x, nil },
+		}, {
+			Name: "account",
+			Values: []Value{
+				{Path: "format", Type: "text", Parameters: []Parameter{
+					{Name: "layout", Type: "text", Pattern: pattern},
+				}},
+				{Path: "rename", Type: "text", Parameters: []Parameter{
+					{Name: "id", Type: "text", MinLength: &three, MaxLength: &eight},
+				}},
+				{Path: "sort", Type: "text", Parameters: []Parameter{
+					{Name: "direction", Type: "text", Options: []string{"asc", "desc"}},
+				}},
+			},
+		}})
+	}
+	sys, err := newSystem(`^[#0.,]+$`)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		expression string
+		message    string
+	}{
+		{expression: "format('#,##0.00')"},
+		{expression: "format('#,##0.0x')", message: `"#,##0.0x" does not match the pattern ^[#0.,]+$ of layout`},
+		{expression: "rename('acct_1')"},
+		{expression: "rename('ab')", message: `"ab" is shorter than the minimum length 3 of id`},
+		{expression: "rename('äöüäöüäö')"},
+		{expression: "rename('account_12')", message: `"account_12" is longer than the maximum length 8 of id`},
+		{expression: "sort(DESC)"},
+		{expression: "sort(up)", message: `"up" is not one of asc, desc for direction`},
+		{expression: "sort(format('##'))"},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			_, err := sys.Parse(Options{RootType: "account", Expression: test.expression})
+			if test.message == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.message)
+				assert.ErrorIs(t, err, ErrConstraint)
+			}
+		})
+	}
+
+	_, err = newSystem(`[`)
+	assert.ErrorIs(t, err, ErrConstraint)
+	assert.ErrorContains(t, err, "constraints on account.format (parameter layout) are invalid: pattern [ is not valid")
+
+	assert.NoError(t, Parameter{Name: "id", Pattern: `^\d+$`}.Check("123"))
+	assert.Error(t, Parameter{Name: "id", Pattern: `^\d+$`}.Check("12a"))
+}
@@ -0,0 +1,148 @@
+package texpr
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// An expression stored outside of a system, like a rule saved in a database.
+type StoredExpression struct {
+	// The identifier of the stored expression, used to report on it.
+	ID string
+	// The options the expression is parsed with, including the expression.
+	Options Options
+}
+
+// The options used to validate a corpus of stored expressions, see System.ValidateCorpus.
+type ValidateOptions struct {
+	// The number of expressions parsed at once. When zero the number of CPUs is used.
+	Concurrency int
+	// Applies defaults to the options of every expression, like a root type all stored expressions
+	// share. It's called before the expression is parsed.
+	Prepare func(stored *StoredExpression)
+}
+
+// The result of validating a single stored expression.
+type ValidationResult struct {
+	// The stored expression that was validated.
+	Stored StoredExpression
+	// The parsed expression, even if it's invalid.
+	Expr *Expr
+	// The problems with the expression, if any.
+	Errors []ParseError
+}
+
+// Returns whether the expression is valid.
+func (r ValidationResult) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// The results of validating a corpus of stored expressions.
+type ValidationReport struct {
+	// The result of each expression in the order they were given.
+	Results []ValidationResult
+	// The number of expressions validated.
+	Total int
+	// The number of valid expressions.
+	Valid int
+	// The number of invalid expressions.
+	Invalid int
+	// The number of errors of each kind (ex: ErrUnknownValue). Errors without a kind are counted with a nil kind.
+	Kinds map[error]int
+}
+
+// Returns the results of the invalid expressions.
+func (r ValidationReport) Failures() []ValidationResult {
+	failures := make([]ValidationResult, 0, r.Invalid)
+	for _, result := range r.Results {
+		if !result.Valid() {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// Parses every stored expression concurrently and returns a report of the problems with each expression
+// and a summary. If the context is canceled validation stops, the results of the expressions which were
+// not validated have a nil Expr and no errors, and the context's error is returned with the report.
+func (sys System) ValidateCorpus(ctx context.Context, corpus []StoredExpression, options ValidateOptions) (ValidationReport, error) {
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	results := make([]ValidationResult, len(corpus))
+	indices := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				stored := corpus[i]
+				if options.Prepare != nil {
+					options.Prepare(&stored)
+				}
+				results[i] = sys.validateStored(stored)
+			}
+		}()
+	}
+
+	var err error
+feed:
+	for i := range corpus {
+		select {
+		case indices <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break feed
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	report := ValidationReport{
+		Results: results,
+		Kinds:   make(map[error]int),
+	}
+	for i := range results {
+		if results[i].Expr == nil && len(results[i].Errors) == 0 {
+			results[i].Stored = corpus[i]
+			continue
+		}
+		report.Total++
+		if results[i].Valid() {
+			report.Valid++
+		} else {
+			report.Invalid++
+		}
+		for _, e := range results[i].Errors {
+			report.Kinds[e.Kind]++
+		}
+	}
+	return report, err
+}
+
+// Parses the stored expression and returns its result.
+func (sys System) validateStored(stored StoredExpression) ValidationResult {
+	e, err := sys.Parse(stored.Options)
+	result := ValidationResult{Stored: stored, Expr: e}
+	if err != nil {
+		var parseErrors ParseErrors
+		var parseError ParseError
+		switch {
+		case errors.As(err, &parseErrors):
+			result.Errors = parseErrors
+		case errors.As(err, &parseError):
+			result.Errors = []ParseError{parseError}
+		default:
+			result.Errors = []ParseError{{Message: err.Error(), Expr: e, Cause: err}}
+		}
+	}
+	if e == nil && len(result.Errors) == 0 {
+		result.Errors = []ParseError{NewParseError(nil, "expression could not be parsed")}
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package texpr
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCorpus(t *testing.T) {
+	corpus := make([]StoredExpression, 0)
+	for i := 0; i < 100; i++ {
+		expression := "name.lower"
+		switch i % 10 {
+		case 3:
+			expression = "name.missing"
+		case 7:
+			expression = "name.contains"
+		}
+		corpus = append(corpus, StoredExpression{ID: fmt.Sprint(i), Options: Options{Expression: expression}})
+	}
+
+	report, err := sys.ValidateCorpus(context.Background(), corpus, ValidateOptions{
+		Concurrency: 4,
+		Prepare: func(stored *StoredExpression) {
+			stored.Options.RootType = typeUser
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 100, report.Total)
+	assert.Equal(t, 80, report.Valid)
+	assert.Equal(t, 20, report.Invalid)
+	assert.Equal(t, map[error]int{ErrUnknownValue: 10, ErrArity: 10}, report.Kinds)
+
+	failures := report.Failures()
+	assert.Len(t, failures, 20)
+	assert.Equal(t, "3", failures[0].Stored.ID)
+	assert.Equal(t, typeUser, failures[0].Stored.Options.RootType)
+	assert.Equal(t, "7", failures[1].Stored.ID)
+	assert.True(t, report.Results[0].Valid())
+	assert.Equal(t, "name.lower", report.Results[0].Expr.String())
+
+	report, err = sys.ValidateCorpus(context.Background(), []StoredExpression{{ID: "none", Options: Options{Expression: "name"}}}, ValidateOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Invalid)
+	assert.Equal(t, ErrNoRoot.Message, report.Results[0].Errors[0].Message)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err = sys.ValidateCorpus(canceled, corpus, ValidateOptions{Concurrency: 1})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, report.Total, 100)
+	assert.Len(t, report.Results, 100)
+}
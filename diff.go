@@ -0,0 +1,262 @@
+package texpr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The kind of change found by DiffSystems.
+type DiffKind string
+
+const (
+	DiffAddedType        DiffKind = "addedType"
+	DiffRemovedType      DiffKind = "removedType"
+	DiffAddedValue       DiffKind = "addedValue"
+	DiffRemovedValue     DiffKind = "removedValue"
+	DiffRenamedValue     DiffKind = "renamedValue"
+	DiffRemovedAlias     DiffKind = "removedAlias"
+	DiffChangedType      DiffKind = "changedType"
+	DiffChangedParameter DiffKind = "changedParameter"
+	DiffAddedParameter   DiffKind = "addedParameter"
+	DiffRemovedParameter DiffKind = "removedParameter"
+	DiffAddedEnum        DiffKind = "addedEnum"
+	DiffRemovedEnum      DiffKind = "removedEnum"
+	DiffRemovedAs        DiffKind = "removedAs"
+)
+
+// A change between two systems found by DiffSystems.
+type DiffChange struct {
+	// What kind of change it is.
+	Kind DiffKind
+	// If expressions which are valid in the old system could fail to parse or produce a different
+	// type in the updated system because of this change.
+	Breaking bool
+	// A human readable description of the change.
+	Message string
+	// The name of the type with the change.
+	Type TypeName
+	// The path of the value with the change in the old system, if any.
+	Value string
+}
+
+// Returns the message of the change and whether it's breaking.
+func (c DiffChange) String() string {
+	if c.Breaking {
+		return "breaking: " + c.Message
+	}
+	return c.Message
+}
+
+// All changes found by DiffSystems.
+type SystemDiff []DiffChange
+
+// Returns the changes which break existing expressions.
+func (d SystemDiff) Breaking() SystemDiff {
+	changes := make(SystemDiff, 0, len(d))
+	for _, change := range d {
+		if change.Breaking {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// Returns whether any change breaks existing expressions.
+func (d SystemDiff) HasBreaking() bool {
+	return len(d.Breaking()) > 0
+}
+
+// Returns the changes from the old system to the updated system: types, values, aliases, parameters,
+// enums, and conversions which were added, removed, renamed, or changed. Each change is marked
+// breaking when expressions stored against the old system could fail or change type with the updated
+// system. A value whose old path is an alias of a value in the updated system is renamed, which is not
+// breaking. Changes are ordered by type name and then by the order of values in the old type.
+func DiffSystems(old, updated System) SystemDiff {
+	diff := make(SystemDiff, 0)
+
+	names := make([]TypeName, 0)
+	for _, t := range old.types {
+		names = append(names, t.Name)
+	}
+	for _, t := range updated.types {
+		if old.Type(t.Name) == nil {
+			names = append(names, t.Name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+
+	for _, name := range names {
+		oldType, newType := old.Type(name), updated.Type(name)
+		switch {
+		case newType == nil:
+			diff = append(diff, DiffChange{
+				Kind:     DiffRemovedType,
+				Breaking: true,
+				Message:  fmt.Sprintf("type %s was removed", name),
+				Type:     name,
+			})
+		case oldType == nil:
+			diff = append(diff, DiffChange{
+				Kind:    DiffAddedType,
+				Message: fmt.Sprintf("type %s was added", name),
+				Type:    name,
+			})
+		default:
+			diff = diffType(oldType, newType, diff)
+		}
+	}
+
+	return diff
+}
+
+// Adds the changes between two versions of a type.
+func diffType(oldType, newType *Type, diff SystemDiff) SystemDiff {
+	name := oldType.Name
+	matched := make(map[*Value]bool)
+
+	for i := range oldType.Values {
+		v := &oldType.Values[i]
+		nv := newType.Value(v.Path)
+		if nv == nil {
+			diff = append(diff, DiffChange{
+				Kind:     DiffRemovedValue,
+				Breaking: true,
+				Message:  fmt.Sprintf("value %s.%s was removed", name, v.Path),
+				Type:     name,
+				Value:    v.Path,
+			})
+			continue
+		}
+		matched[nv] = true
+		if !strings.EqualFold(nv.Path, v.Path) {
+			diff = append(diff, DiffChange{
+				Kind:    DiffRenamedValue,
+				Message: fmt.Sprintf("value %s.%s was renamed to %s", name, v.Path, nv.Path),
+				Type:    name,
+				Value:   v.Path,
+			})
+		}
+		for _, alias := range v.Aliases {
+			if newType.Value(alias) != nv {
+				diff = append(diff, DiffChange{
+					Kind:     DiffRemovedAlias,
+					Breaking: true,
+					Message:  fmt.Sprintf("alias %s of %s.%s was removed", alias, name, v.Path),
+					Type:     name,
+					Value:    v.Path,
+				})
+			}
+		}
+		diff = diffValue(name, v, nv, diff)
+	}
+
+	for i := range newType.Values {
+		nv := &newType.Values[i]
+		if !matched[nv] && oldType.Value(nv.Path) == nil {
+			diff = append(diff, DiffChange{
+				Kind:    DiffAddedValue,
+				Message: fmt.Sprintf("value %s.%s was added", name, nv.Path),
+				Type:    name,
+			})
+		}
+	}
+
+	for _, enum := range oldType.Enums {
+		if _, exists := newType.EnumFor(enum); !exists {
+			diff = append(diff, DiffChange{
+				Kind:     DiffRemovedEnum,
+				Breaking: true,
+				Message:  fmt.Sprintf("enum %s of %s was removed", enum, name),
+				Type:     name,
+			})
+		}
+	}
+	for _, enum := range newType.Enums {
+		if _, exists := oldType.EnumFor(enum); !exists {
+			diff = append(diff, DiffChange{
+				Kind:    DiffAddedEnum,
+				Message: fmt.Sprintf("enum %s of %s was added", enum, name),
+				Type:    name,
+			})
+		}
+	}
+
+	targets := make([]TypeName, 0, len(oldType.As))
+	for target := range oldType.As {
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i] < targets[j]
+	})
+	for _, target := range targets {
+		if _, exists := newType.As[target]; !exists {
+			diff = append(diff, DiffChange{
+				Kind:     DiffRemovedAs,
+				Breaking: true,
+				Message:  fmt.Sprintf("conversion of %s to %s was removed", name, target),
+				Type:     name,
+			})
+		}
+	}
+
+	return diff
+}
+
+// Adds the changes between two versions of a value: its type and parameters.
+func diffValue(typeName TypeName, v, nv *Value, diff SystemDiff) SystemDiff {
+	change := func(kind DiffKind, breaking bool, format string, args ...any) {
+		diff = append(diff, DiffChange{
+			Kind:     kind,
+			Breaking: breaking,
+			Message:  fmt.Sprintf("%s.%s ", typeName, v.Path) + fmt.Sprintf(format, args...),
+			Type:     typeName,
+			Value:    v.Path,
+		})
+	}
+
+	if v.Generic != nv.Generic || (!v.Generic && v.Type != nv.Type) {
+		change(DiffChangedType, true, "changed type from %s to %s", diffTypeName(v.Type, v.Generic), diffTypeName(nv.Type, nv.Generic))
+	}
+	if !v.Nullable && nv.Nullable {
+		change(DiffChangedType, true, "is now nullable")
+	}
+
+	for i := range v.Parameters {
+		p := &v.Parameters[i]
+		if i >= len(nv.Parameters) {
+			change(DiffRemovedParameter, true, "parameter %s was removed", p.Name)
+			continue
+		}
+		np := &nv.Parameters[i]
+		if p.Generic != np.Generic || (!p.Generic && p.Type != np.Type) {
+			change(DiffChangedParameter, true, "parameter %s changed type from %s to %s", p.Name, diffTypeName(p.Type, p.Generic), diffTypeName(np.Type, np.Generic))
+		}
+		if p.Default != nil && np.Default == nil {
+			change(DiffChangedParameter, true, "parameter %s is now required", p.Name)
+		}
+	}
+	for i := len(v.Parameters); i < len(nv.Parameters); i++ {
+		np := &nv.Parameters[i]
+		if np.Default == nil {
+			change(DiffAddedParameter, true, "required parameter %s was added", np.Name)
+		} else {
+			change(DiffAddedParameter, false, "optional parameter %s was added", np.Name)
+		}
+	}
+	if v.Variadic && !nv.Variadic {
+		change(DiffChangedParameter, true, "is no longer variadic")
+	}
+
+	return diff
+}
+
+// Returns the type name or generic for a change message.
+func diffTypeName(name TypeName, generic bool) string {
+	if generic {
+		return "generic"
+	}
+	return string(name)
+}
@@ -0,0 +1,75 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSystems(t *testing.T) {
+	optional := "1"
+	old := NewSystemRequired([]Type{{
+		Name: "num",
+	}, {
+		Name:  "color",
+		Enums: []string{"red", "green", "blue"},
+	}, {
+		Name: "legacy",
+	}, {
+		Name: "user",
+		As:   map[TypeName]string{"num": "id"},
+		Values: []Value{
+			{Path: "id", Type: "num"},
+			{Path: "name", Type: "num", Aliases: []string{"title"}},
+			{Path: "favorite", Type: "color"},
+			{Path: "age", Type: "num"},
+			{Path: "score", Type: "num", Parameters: []Parameter{{Name: "scale", Type: "num", Default: &optional}}},
+			{Path: "rank", Type: "num", Parameters: []Parameter{{Name: "by", Type: "num"}}},
+		},
+	}})
+	updated := NewSystemRequired([]Type{{
+		Name: "num",
+	}, {
+		Name: "text",
+	}, {
+		Name:  "color",
+		Enums: []string{"red", "blue", "purple"},
+	}, {
+		Name: "user",
+		Values: []Value{
+			{Path: "id", Type: "num"},
+			{Path: "fullName", Type: "num", Aliases: []string{"name"}},
+			{Path: "favorite", Type: "color", Nullable: true},
+			{Path: "age", Type: "text"},
+			{Path: "score", Type: "num", Parameters: []Parameter{{Name: "scale", Type: "num"}, {Name: "offset", Type: "num", Default: &optional}}},
+			{Path: "rank", Type: "num"},
+			{Path: "email", Type: "text"},
+		},
+	}})
+
+	diff := DiffSystems(old, updated)
+	messages := make([]string, len(diff))
+	for i, change := range diff {
+		messages[i] = change.String()
+	}
+	assert.Equal(t, []string{
+		"breaking: enum green of color was removed",
+		"enum purple of color was added",
+		"breaking: type legacy was removed",
+		"type text was added",
+		"value user.name was renamed to fullName",
+		"breaking: alias title of user.name was removed",
+		"breaking: user.favorite is now nullable",
+		"breaking: user.age changed type from num to text",
+		"breaking: user.score parameter scale is now required",
+		"user.score optional parameter offset was added",
+		"breaking: user.rank parameter by was removed",
+		"value user.email was added",
+		"breaking: conversion of user to num was removed",
+	}, messages)
+	assert.Len(t, diff.Breaking(), 8)
+	assert.True(t, diff.HasBreaking())
+
+	assert.Empty(t, DiffSystems(old, old))
+	assert.False(t, DiffSystems(old, old).HasBreaking())
+}
@@ -0,0 +1,192 @@
+package texpr
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+// The kind of documented element in a DocEntry.
+type DocKind string
+
+const (
+	DocKindType  DocKind = "type"
+	DocKindValue DocKind = "value"
+	DocKindEnum  DocKind = "enum"
+)
+
+// A documentation bundle of a System. It contains every type (with values, parameters, and enums)
+// and a flattened search index which front-ends can use for command palettes and help panels.
+type DocBundle struct {
+	// The types of the system in the order they were given.
+	Types []*Type `json:"types"`
+	// The searchable entries for every type, value, and enum in the system.
+	Index []DocEntry `json:"index"`
+}
+
+// A searchable entry in a DocBundle.
+type DocEntry struct {
+	// What the entry documents.
+	Kind DocKind `json:"kind"`
+	// The type which is documented or contains the documented value or enum.
+	Type TypeName `json:"type"`
+	// The path of the value or the enum option. Empty for types.
+	Path string `json:"path,omitempty"`
+	// A human readable signature of the documented element.
+	Signature string `json:"signature"`
+	// The description of the documented element.
+	Description string `json:"description,omitempty"`
+	// The label to display for a documented enum option, see EnumOption.
+	Label string `json:"label,omitempty"`
+	// If the documented enum option is deprecated.
+	Deprecated bool `json:"deprecated,omitempty"`
+	// The example expressions of a documented value.
+	Examples []string `json:"examples,omitempty"`
+	// The lowercase terms the entry can be found by.
+	Terms []string `json:"terms"`
+}
+
+// Returns the documentation bundle for the system.
+func (s System) Docs() DocBundle {
+	return s.LocalizedDocs("")
+}
+
+// Returns the documentation bundle for the system with index descriptions in the given locale.
+func (s System) LocalizedDocs(locale string) DocBundle {
+	bundle := DocBundle{
+		Types: s.types,
+		Index: make([]DocEntry, 0),
+	}
+	for _, t := range s.types {
+		bundle.Index = append(bundle.Index, DocEntry{
+			Kind:        DocKindType,
+			Type:        t.Name,
+			Signature:   string(t.Name),
+			Description: t.Describe(locale),
+			Terms:       searchTerms([]string{string(t.Name)}, t.Describe(locale)),
+		})
+		for i := range t.Values {
+			v := &t.Values[i]
+			names := append([]string{v.Path}, v.Aliases...)
+			bundle.Index = append(bundle.Index, DocEntry{
+				Kind:        DocKindValue,
+				Type:        t.Name,
+				Path:        v.Path,
+				Signature:   v.Signature(),
+				Description: v.Describe(locale),
+				Examples:    v.Examples,
+				Terms:       searchTerms(names, append([]string{v.Describe(locale)}, v.Examples...)...),
+			})
+		}
+		for _, enumValue := range t.SortedEnums() {
+			description := t.DescribeEnum(enumValue, locale)
+			label := t.EnumLabel(enumValue, locale)
+			option, _ := t.EnumOption(enumValue)
+			bundle.Index = append(bundle.Index, DocEntry{
+				Kind:        DocKindEnum,
+				Type:        t.Name,
+				Path:        enumValue,
+				Signature:   enumValue,
+				Description: description,
+				Label:       label,
+				Deprecated:  option.Deprecated,
+				Terms:       searchTerms([]string{enumValue}, description, label),
+			})
+		}
+	}
+	return bundle
+}
+
+// Returns the documentation bundle for the system encoded as JSON.
+func (s System) DocsJSON() ([]byte, error) {
+	return json.Marshal(s.Docs())
+}
+
+// Returns a human readable signature of the value, ex: `add(amount int, duration duration) date`.
+func (v Value) Signature() string {
+	out := strings.Builder{}
+	out.WriteString(v.Path)
+	if len(v.Parameters) > 0 {
+		out.WriteString("(")
+		for i, p := range v.Parameters {
+			if i > 0 {
+				out.WriteString(", ")
+			}
+			out.WriteString(p.Name)
+			if p.Generic {
+				out.WriteString(" ?")
+			} else {
+				out.WriteString(" " + string(p.Type))
+			}
+			if p.Default != nil {
+				out.WriteString(" = " + *p.Default)
+			}
+			if v.Variadic && i == len(v.Parameters)-1 {
+				out.WriteString("...")
+			}
+		}
+		out.WriteString(")")
+	}
+	if v.Generic {
+		out.WriteString(" ?")
+	} else {
+		out.WriteString(" " + string(v.Type))
+	}
+	return out.String()
+}
+
+// Returns the unique lowercase terms for the given names and texts. Names are included
+// as a whole and split by their camel case words, texts are split into words.
+func searchTerms(names []string, texts ...string) []string {
+	terms := make([]string, 0)
+	seen := make(map[string]bool)
+	add := func(term string) {
+		term = strings.ToLower(term)
+		if term != "" && !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	for _, name := range names {
+		add(name)
+		for _, word := range splitWords(name) {
+			add(word)
+		}
+	}
+	for _, text := range texts {
+		for _, word := range strings.FieldsFunc(text, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			add(word)
+		}
+	}
+	return terms
+}
+
+// Splits a camel case or snake case name into its words.
+func splitWords(name string) []string {
+	words := make([]string, 0)
+	current := strings.Builder{}
+	flush := func() {
+		if current.Len() > 0 {
+			words = append(words, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range name {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			flush()
+		case unicode.IsUpper(r):
+			flush()
+			current.WriteRune(r)
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	if len(words) == 1 {
+		return nil
+	}
+	return words
+}
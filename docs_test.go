@@ -0,0 +1,55 @@
+package texpr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocs(t *testing.T) {
+	docs := sys.Docs()
+
+	assert.Equal(t, len(sys.Types()), len(docs.Types))
+
+	find := func(kind DocKind, typeName TypeName, path string) *DocEntry {
+		for i := range docs.Index {
+			e := &docs.Index[i]
+			if e.Kind == kind && e.Type == typeName && e.Path == path {
+				return e
+			}
+		}
+		return nil
+	}
+
+	dateAdd := find(DocKindValue, typeDate, "add")
+	assert.NotNil(t, dateAdd)
+	assert.Equal(t, "add(amount int, duration duration) date", dateAdd.Signature)
+
+	minute := find(DocKindValue, typeDateTime, "minute")
+	assert.NotNil(t, minute)
+	assert.Contains(t, minute.Terms, "min")
+
+	dayOfMonth := find(DocKindValue, typeDate, "dayOfMonth")
+	assert.NotNil(t, dayOfMonth)
+	assert.Equal(t, []string{"dayofmonth", "day", "of", "month"}, dayOfMonth.Terms)
+
+	then := find(DocKindValue, typeBool, "then")
+	assert.NotNil(t, then)
+	assert.Equal(t, "then(trueValue ?, falseValue ?) ?", then.Signature)
+
+	sunday := find(DocKindValue, typeTimePackage, "sunday")
+	assert.NotNil(t, sunday)
+	assert.Contains(t, sunday.Terms, "unambiguous")
+
+	assert.NotNil(t, find(DocKindEnum, typeDuration, "week"))
+	assert.NotNil(t, find(DocKindType, typeUser, ""))
+
+	encoded, err := sys.DocsJSON()
+	assert.NoError(t, err)
+
+	decoded := map[string]any{}
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Contains(t, decoded, "types")
+	assert.Contains(t, decoded, "index")
+}
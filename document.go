@@ -0,0 +1,326 @@
+package texpr
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// An expression in a document references itself, directly or through other expressions.
+	ErrDependencyCycle = errors.New("dependency cycle")
+	// More than one expression in a document has the same name, or an expression has the name of a parameter.
+	ErrDuplicateName = errors.New("duplicate name")
+)
+
+// A set of named expressions parsed from a document, see System.ParseDocument.
+type Document struct {
+	// The expressions in dependency order, every expression is after the expressions it references.
+	// Expressions in a dependency cycle and expressions with a duplicate name are last.
+	Expressions []*DocumentExpression
+}
+
+// A named expression in a document.
+type DocumentExpression struct {
+	// The name of the expression, which other expressions reference as a bind parameter (`:name`).
+	Name string
+	// The linked expression, its positions are in the document.
+	Expr *Expr
+	// The names of the other expressions in the document this expression references.
+	References []string
+	// The position of the name in the document.
+	Start Position
+
+	// The start and end index of the expression in the document.
+	from, to int
+	// The line the expression starts on and the index of the start of that line.
+	line, lineAt int
+}
+
+// Returns the expression in the document with the given name (case insensitive), or nil if none exists.
+func (d Document) Expression(name string) *DocumentExpression {
+	for _, e := range d.Expressions {
+		if strings.EqualFold(e.Name, name) {
+			return e
+		}
+	}
+	return nil
+}
+
+// Evaluates the expressions in dependency order and returns their results keyed by name. Each expression
+// is compiled with the given function and given the parameters and the results of the expressions it
+// references as bind parameters.
+func (d Document) Evaluate(compile func(e *Expr) BoundRun, root any, params map[string]any) (map[string]any, error) {
+	results := make(map[string]any, len(d.Expressions))
+	values := make(map[string]any, len(params)+len(d.Expressions))
+	for name, value := range params {
+		values[name] = value
+	}
+	for _, e := range d.Expressions {
+		result, err := compile(e.Expr)(root, values)
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", e.Name, err)
+		}
+		results[e.Name] = result
+		values[e.Name] = result
+	}
+	return results, nil
+}
+
+// Parses a document of named expressions, one per line like `name: expression`. An expression continues
+// on the following lines which are indented, and empty lines and lines starting with # are ignored.
+// Expressions reference each other as bind parameters (`:name`) which have the type of the referenced
+// expression, and the options give the root type, the other bind parameters, etc of every expression.
+// Like Parse the document is returned with all the errors found, where the messages are prefixed with
+// the name of the expression they're in.
+func (sys System) ParseDocument(document string, opts Options) (*Document, error) {
+	doc, errs := splitDocument(document, sys.options.Identifiers)
+	names := make(map[string]*DocumentExpression, len(doc.Expressions))
+	parameters := make(map[string]bool, len(opts.Parameters))
+	for name := range opts.Parameters {
+		parameters[strings.ToLower(name)] = true
+	}
+	for _, e := range doc.Expressions {
+		key := strings.ToLower(e.Name)
+		if names[key] != nil {
+			errs = append(errs, documentError(e, ErrDuplicateName, fmt.Sprintf("%s is defined more than once", e.Name)))
+			continue
+		}
+		if parameters[key] {
+			errs = append(errs, documentError(e, ErrDuplicateName, fmt.Sprintf("%s is already the name of a parameter", e.Name)))
+			continue
+		}
+		names[key] = e
+	}
+
+	// Find references by parsing the syntax of each expression.
+	for _, e := range doc.Expressions {
+		p := e.parser(document, sys.options.Identifiers)
+		var err error
+		for p.hasData() && err == nil {
+			_, err = p.parseExpr()
+		}
+		if p.first == nil {
+			continue
+		}
+		for _, bind := range p.first.Binds() {
+			if referenced := names[strings.ToLower(bind)]; referenced != nil {
+				e.References = append(e.References, referenced.Name)
+			}
+		}
+	}
+
+	ordered, cyclic := orderDocument(doc.Expressions, names)
+	for _, cycle := range cyclic {
+		errs = append(errs, documentError(cycle[0], ErrDependencyCycle, fmt.Sprintf("dependency cycle %s", strings.Join(cycleNames(cycle), " -> "))))
+	}
+	doc.Expressions = ordered
+
+	types := make(map[string]*Type, len(doc.Expressions))
+	for _, e := range doc.Expressions {
+		references := make(map[string]*Type, len(e.References))
+		for _, name := range e.References {
+			t := types[strings.ToLower(name)]
+			if t == nil {
+				t = Unknown
+			}
+			references[strings.ToLower(name)] = t
+		}
+		expr, err := sys.parseFrom(opts, e.parser(document, sys.options.Identifiers), references)
+		e.Expr = expr
+		if expr != nil {
+			types[strings.ToLower(e.Name)] = expr.Last().Type
+		}
+		var parseErrors ParseErrors
+		var parseError ParseError
+		if errors.As(err, &parseErrors) {
+			for _, pe := range parseErrors {
+				errs = append(errs, prefixError(e, pe))
+			}
+		} else if errors.As(err, &parseError) {
+			errs = append(errs, prefixError(e, parseError))
+		}
+	}
+
+	switch len(errs) {
+	case 0:
+		return doc, nil
+	case 1:
+		return doc, errs[0]
+	default:
+		return doc, ParseErrors(errs)
+	}
+}
+
+// Splits the document into its named expressions, returning errors for lines which are not expressions.
+func splitDocument(document string, identifiers IdentifierChars) (*Document, []ParseError) {
+	doc := &Document{Expressions: make([]*DocumentExpression, 0)}
+	errs := make([]ParseError, 0)
+	var current *DocumentExpression
+	lineStart := 0
+	for line := 0; lineStart <= len(document); line++ {
+		lineEnd := strings.IndexByte(document[lineStart:], '\n')
+		if lineEnd == -1 {
+			lineEnd = len(document)
+		} else {
+			lineEnd += lineStart
+		}
+		text := document[lineStart:lineEnd]
+		trimmed := strings.TrimSpace(text)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+		case text[0] == ' ' || text[0] == '\t':
+			if current == nil {
+				errs = append(errs, lineError(line, lineStart, "expected an expression name before an indented line"))
+			} else {
+				current.to = lineEnd
+			}
+		default:
+			colon := strings.IndexByte(text, ':')
+			name := ""
+			if colon > 0 {
+				name = strings.TrimSpace(text[:colon])
+			}
+			if !isDocumentName(name, identifiers) {
+				current = nil
+				errs = append(errs, lineError(line, lineStart, fmt.Sprintf("expected name: expression but found %s", trimmed)))
+				break
+			}
+			current = &DocumentExpression{
+				Name:   name,
+				Start:  Position{Index: lineStart, Line: line},
+				from:   lineStart + colon + 1,
+				to:     lineEnd,
+				line:   line,
+				lineAt: lineStart,
+			}
+			doc.Expressions = append(doc.Expressions, current)
+		}
+		lineStart = lineEnd + 1
+	}
+	return doc, errs
+}
+
+// Returns whether the name is a valid expression name, which can be referenced as a bind parameter.
+func isDocumentName(name string, identifiers IdentifierChars) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); {
+		size := identifiers.at(name[i:])
+		if size == 0 {
+			return false
+		}
+		i += size
+	}
+	return true
+}
+
+// Returns a parser of the expression in the document.
+func (e *DocumentExpression) parser(document string, identifiers IdentifierChars) parser {
+	p := newParser(document, identifiers)
+	p.i = e.from
+	p.n = e.to
+	p.line = e.line
+	p.lineReset = e.lineAt
+	return p
+}
+
+// Orders the expressions so each is after the expressions it references and returns the cycles found.
+// The expressions in cycles are added after the other expressions in the order they were given.
+func orderDocument(expressions []*DocumentExpression, names map[string]*DocumentExpression) ([]*DocumentExpression, [][]*DocumentExpression) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[*DocumentExpression]int, len(expressions))
+	ordered := make([]*DocumentExpression, 0, len(expressions))
+	inCycle := make(map[*DocumentExpression]bool)
+	cycles := make([][]*DocumentExpression, 0)
+	path := make([]*DocumentExpression, 0)
+
+	var visit func(e *DocumentExpression) bool
+	visit = func(e *DocumentExpression) bool {
+		switch state[e] {
+		case visited:
+			return !inCycle[e]
+		case visiting:
+			for i := len(path) - 1; i >= 0; i-- {
+				if path[i] == e {
+					cycle := append([]*DocumentExpression(nil), path[i:]...)
+					for _, c := range cycle {
+						inCycle[c] = true
+					}
+					cycles = append(cycles, cycle)
+					break
+				}
+			}
+			return false
+		}
+		state[e] = visiting
+		path = append(path, e)
+		acyclic := true
+		references := append([]string(nil), e.References...)
+		sort.Strings(references)
+		for _, name := range references {
+			if !visit(names[strings.ToLower(name)]) {
+				acyclic = false
+			}
+		}
+		path = path[:len(path)-1]
+		state[e] = visited
+		if !acyclic {
+			inCycle[e] = true
+		} else {
+			ordered = append(ordered, e)
+		}
+		return acyclic
+	}
+
+	for _, e := range expressions {
+		if names[strings.ToLower(e.Name)] == e {
+			visit(e)
+		}
+	}
+	for _, e := range expressions {
+		if inCycle[e] || state[e] == unvisited {
+			ordered = append(ordered, e)
+		}
+	}
+	return ordered, cycles
+}
+
+// Returns the names of the expressions in the cycle, ending with the first expression again.
+func cycleNames(cycle []*DocumentExpression) []string {
+	names := make([]string, 0, len(cycle)+1)
+	for _, e := range cycle {
+		names = append(names, e.Name)
+	}
+	return append(names, cycle[0].Name)
+}
+
+// Returns an error of the kind about the expression located at its name.
+func documentError(e *DocumentExpression, kind error, message string) ParseError {
+	err := NewParseErrorKind(nil, kind, message)
+	start := e.Start
+	end := Position{Index: start.Index + len(e.Name), Line: start.Line, Column: len(e.Name)}
+	err.Start = &start
+	err.End = &end
+	return err
+}
+
+// Returns an error of the ErrSyntax kind located at the start of the line.
+func lineError(line int, index int, message string) ParseError {
+	err := NewParseErrorKind(nil, ErrSyntax, message)
+	start := Position{Index: index, Line: line}
+	err.Start = &start
+	return err
+}
+
+// Returns the error of an expression with its message prefixed with the name of the expression.
+func prefixError(e *DocumentExpression, err ParseError) ParseError {
+	err.Message = e.Name + ": " + err.Message
+	return err
+}
@@ -0,0 +1,90 @@
+package texpr
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type documentOrder struct {
+	Total    int
+	Discount int
+}
+
+func TestParseDocument(t *testing.T) {
+	r, err := NewReflect(ReflectOptions{
+		BoolType: "bool",
+		Types: map[reflect.Type]Type{
+			TypeOf[bool](): {Name: "bool"},
+			TypeOf[int](): {
+				Name:       "int",
+				Comparable: true,
+				Ordered:    true,
+				Numeric:    true,
+				Parse: func(x string) (any, error) {
+					return strconv.Atoi(x)
+				},
+			},
+			TypeOf[documentOrder](): {},
+		},
+	})
+	assert.NoError(t, err)
+	sys := r.System()
+	root := NameOf[documentOrder]()
+
+	document := `# pricing rules
+free: :subtotal.<=(0)
+subtotal: total.-(discount)
+withTax:
+  :subtotal.+(:tax)
+`
+	doc, err := sys.ParseDocument(document, Options{RootType: root, Parameters: map[string]TypeName{"tax": "int"}})
+	assert.NoError(t, err)
+
+	names := make([]string, len(doc.Expressions))
+	for i, e := range doc.Expressions {
+		names[i] = e.Name
+	}
+	assert.Equal(t, []string{"subtotal", "free", "withTax"}, names)
+	assert.Equal(t, []string{"subtotal"}, doc.Expression("FREE").References)
+	assert.Equal(t, TypeName("bool"), doc.Expression("free").Expr.Last().Type.Name)
+	assert.Equal(t, Position{Index: 77, Line: 4, Column: 2}, doc.Expression("withTax").Expr.Start)
+	assert.Equal(t, Position{Index: 16, Line: 1}, doc.Expression("free").Start)
+
+	results, err := doc.Evaluate(func(e *Expr) BoundRun {
+		return BoundRun(r.CompileBound(e))
+	}, documentOrder{Total: 100, Discount: 30}, map[string]any{"tax": 7})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"subtotal": 70, "free": false, "withTax": 77}, results)
+
+	tests := []struct {
+		name     string
+		document string
+		kind     error
+		message  string
+	}{
+		{name: "cycle", document: "a: :b.+(1)\nb: :c\nc: :a\nd: total", kind: ErrDependencyCycle, message: "dependency cycle a -> b -> c -> a"},
+		{name: "self", document: "a: :a", kind: ErrDependencyCycle, message: "dependency cycle a -> a"},
+		{name: "duplicate", document: "a: total\nA: discount", kind: ErrDuplicateName, message: "A is defined more than once"},
+		{name: "parameter", document: "tax: total", kind: ErrDuplicateName, message: "tax is already the name of a parameter"},
+		{name: "syntax", document: "a total", kind: ErrSyntax, message: "expected name: expression but found a total"},
+		{name: "expression", document: "a: total\nb: :a.+(total.nope)", kind: ErrUnknownValue, message: "b: invalid value nope"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := sys.ParseDocument(test.document, Options{RootType: root, Parameters: map[string]TypeName{"tax": "int"}})
+			assert.ErrorIs(t, err, test.kind)
+			assert.ErrorContains(t, err, test.message)
+		})
+	}
+
+	doc, err = sys.ParseDocument("a: :b\nb: :a\nc: total", Options{RootType: root})
+	var parseError ParseError
+	assert.True(t, errors.As(err, &parseError))
+	assert.Equal(t, Position{Index: 0}, *parseError.Start)
+	assert.Equal(t, "c", doc.Expressions[0].Name)
+	assert.Len(t, doc.Expressions, 3)
+}
@@ -0,0 +1,285 @@
+package texpr
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A compound duration of calendar months and days and an exact amount of time, like 1 year 2 months
+// or 3 days 1h30m. Months and days vary in length so they're applied to dates on the calendar, see
+// Duration.AddTo. The methods are the values of the type given by DurationType when used with Reflect.
+type Duration struct {
+	// The number of months, where a year is 12 months.
+	Months int
+	// The number of days, where a week is 7 days.
+	Days int
+	// The exact amount of time.
+	Time time.Duration
+}
+
+var durationUnits = regexp.MustCompile(`^(?i)([+-]?\d+)(years?|yrs?|y|months?|mos?|weeks?|wks?|w|days?|d|hours?|hrs?|minutes?|mins?|seconds?|secs?)$`)
+
+var durationNumber = regexp.MustCompile(`^[+-]?\d+$`)
+
+// Parses a duration made up of any number of parts separated by spaces or commas, where each part
+// is an amount and a unit like `3 days`, `2weeks`, and `1 year`, or a Go duration like `1h30m`.
+func ParseDuration(x string) (Duration, error) {
+	fields := strings.FieldsFunc(x, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+	if len(fields) == 0 {
+		return Duration{}, fmt.Errorf("%q is not a duration", x)
+	}
+	d := Duration{}
+	for i := 0; i < len(fields); i++ {
+		part := fields[i]
+		if durationNumber.MatchString(part) && i+1 < len(fields) {
+			i++
+			part += fields[i]
+		}
+		if match := durationUnits.FindStringSubmatch(part); match != nil {
+			amount, err := strconv.Atoi(match[1])
+			if err != nil {
+				return Duration{}, fmt.Errorf("%q is not a duration: %w", x, err)
+			}
+			switch unit := strings.ToLower(match[2]); {
+			case strings.HasPrefix(unit, "y"):
+				d.Months += amount * 12
+			case strings.HasPrefix(unit, "mo"):
+				d.Months += amount
+			case strings.HasPrefix(unit, "w"):
+				d.Days += amount * 7
+			case strings.HasPrefix(unit, "d"):
+				d.Days += amount
+			case strings.HasPrefix(unit, "h"):
+				d.Time += time.Duration(amount) * time.Hour
+			case strings.HasPrefix(unit, "mi"):
+				d.Time += time.Duration(amount) * time.Minute
+			default:
+				d.Time += time.Duration(amount) * time.Second
+			}
+			continue
+		}
+		t, err := time.ParseDuration(part)
+		if err != nil {
+			return Duration{}, fmt.Errorf("%q is not a duration", x)
+		}
+		d.Time += t
+	}
+	return d, nil
+}
+
+// Returns the duration of calendar parts and time between the times, which added to the start gives
+// the end. When the end is before the start the duration is negative.
+func DurationBetween(start, end time.Time) Duration {
+	if end.Before(start) {
+		return DurationBetween(end, start).Neg()
+	}
+	end = end.In(start.Location())
+	d := Duration{Months: (end.Year()-start.Year())*12 + int(end.Month()-start.Month())}
+	for d.Months > 0 && start.AddDate(0, d.Months, 0).After(end) {
+		d.Months--
+	}
+	monthsLater := start.AddDate(0, d.Months, 0)
+	d.Days = int(end.Sub(monthsLater) / (24 * time.Hour))
+	for d.Days > 0 && monthsLater.AddDate(0, 0, d.Days).After(end) {
+		d.Days--
+	}
+	d.Time = end.Sub(monthsLater.AddDate(0, 0, d.Days))
+	return d
+}
+
+// Returns the duration of the Go duration.
+func DurationOf(d time.Duration) Duration {
+	return Duration{Time: d}
+}
+
+// Returns the duration like `1 year 2 months 3 days 1h30m0s`, or `0s` when it's zero.
+func (d Duration) String() string {
+	parts := make([]string, 0, 4)
+	add := func(amount int, unit string) {
+		if amount == 1 || amount == -1 {
+			parts = append(parts, fmt.Sprintf("%d %s", amount, unit))
+		} else if amount != 0 {
+			parts = append(parts, fmt.Sprintf("%d %ss", amount, unit))
+		}
+	}
+	add(d.Months/12, "year")
+	add(d.Months%12, "month")
+	add(d.Days, "day")
+	if d.Time != 0 || len(parts) == 0 {
+		parts = append(parts, d.Time.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := ParseDuration(string(text))
+	if err == nil {
+		*d = parsed
+	}
+	return err
+}
+
+// Returns the sum of the durations.
+func (d Duration) Add(other Duration) Duration {
+	return Duration{Months: d.Months + other.Months, Days: d.Days + other.Days, Time: d.Time + other.Time}
+}
+
+// Returns the other duration subtracted from the duration.
+func (d Duration) Sub(other Duration) Duration {
+	return d.Add(other.Neg())
+}
+
+// Returns the negation of the duration.
+func (d Duration) Neg() Duration {
+	return Duration{Months: -d.Months, Days: -d.Days, Time: -d.Time}
+}
+
+// Returns whether the duration has no months, days, or time.
+func (d Duration) IsZero() bool {
+	return d == Duration{}
+}
+
+// Returns the time after the duration, adding the months and days on the calendar and then the time.
+func (d Duration) AddTo(t time.Time) time.Time {
+	return t.AddDate(0, d.Months, d.Days).Add(d.Time)
+}
+
+// The options of the duration type, see DurationType.
+type DurationOptions struct {
+	// The name of the type, by default "duration".
+	Name TypeName
+}
+
+// Returns the definition of the Duration type to give to Reflect with TypeOf[Duration](). It's comparable,
+// and it can be added to and subtracted from dates with the values given by DateValues.
+func DurationType(options DurationOptions) Type {
+	if options.Name == "" {
+		options.Name = "duration"
+	}
+	return Type{
+		Name:        options.Name,
+		Description: "An amount of time, like 1h30m or 1 year 2 months 3 days",
+		Comparable:  true,
+		Parse: func(x string) (any, error) {
+			return ParseDuration(x)
+		},
+	}
+}
+
+// An operation of the values given by DateValues and ZoneValues.
+type DateOperation string
+
+const (
+	// Returns the date after the duration.
+	DateAdd DateOperation = "add"
+	// Returns the date before the duration.
+	DateSub DateOperation = "sub"
+	// Returns the duration from the date to the given date.
+	DateBetween DateOperation = "between"
+)
+
+// Returns the add, sub, and between values (see DateOperation) to add to a date or dateTime type,
+// where durations have the given duration type. Reflect evaluates them on time.Time values with
+// Duration or time.Duration arguments.
+func DateValues(date TypeName, duration TypeName) []Value {
+	return []Value{{
+		Path:          string(DateAdd),
+		Type:          date,
+		Description:   "The date after the duration",
+		Parameters:    []Parameter{{Name: "duration", Type: duration}},
+		dateOperation: DateAdd,
+	}, {
+		Path:          string(DateSub),
+		Type:          date,
+		Description:   "The date before the duration",
+		Parameters:    []Parameter{{Name: "duration", Type: duration}},
+		dateOperation: DateSub,
+	}, {
+		Path:          string(DateBetween),
+		Type:          duration,
+		Description:   "The duration from the date to the given date",
+		Parameters:    []Parameter{{Name: "end", Type: date}},
+		dateOperation: DateBetween,
+	}}
+}
+
+// Returns the date operation the value was created for by DateValues or ZoneValues, if any.
+func (v Value) DateOperation() (DateOperation, bool) {
+	return v.dateOperation, v.dateOperation != ""
+}
+
+// Returns the result of the operation on the date given the argument values. Dates are time.Time
+// values, durations are Duration or time.Duration values, and zones are IANA zone names.
+func (op DateOperation) Apply(date any, args []any) (any, error) {
+	t, ok := asTime(date)
+	if !ok {
+		return nil, fmt.Errorf("date operation %s expects a date but was given %T", op, date)
+	}
+	switch op {
+	case DateInZone, DateToUTC, DateZoneName, DateOffsetMinutes:
+		return op.applyZone(t, args)
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("date operation %s expects 1 argument but was given %d", op, len(args))
+	}
+	switch op {
+	case DateAdd, DateSub:
+		d, ok := asDuration(args[0])
+		if !ok {
+			return nil, fmt.Errorf("date operation %s expects a duration but was given %T", op, args[0])
+		}
+		if op == DateSub {
+			d = d.Neg()
+		}
+		return d.AddTo(t), nil
+	case DateBetween:
+		end, ok := asTime(args[0])
+		if !ok {
+			return nil, fmt.Errorf("date operation %s expects a date but was given %T", op, args[0])
+		}
+		return DurationBetween(t, end), nil
+	}
+	return nil, fmt.Errorf("unknown date operation %s", op)
+}
+
+func asTime(v any) (time.Time, bool) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, true
+	case *time.Time:
+		if x == nil {
+			return time.Time{}, false
+		}
+		return *x, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.IsValid() && rv.Type().ConvertibleTo(TypeOf[time.Time]()) {
+		return rv.Convert(TypeOf[time.Time]()).Interface().(time.Time), true
+	}
+	return time.Time{}, false
+}
+
+func asDuration(v any) (Duration, bool) {
+	switch x := v.(type) {
+	case Duration:
+		return x, true
+	case *Duration:
+		if x == nil {
+			return Duration{}, false
+		}
+		return *x, true
+	case time.Duration:
+		return DurationOf(x), true
+	}
+	return Duration{}, false
+}
@@ -0,0 +1,129 @@
+package texpr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type durationBooking struct {
+	Start  time.Time
+	End    time.Time
+	Length Duration
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		duration Duration
+		text     string
+	}{
+		{input: "1h30m", duration: Duration{Time: 90 * time.Minute}, text: "1h30m0s"},
+		{input: "3 days", duration: Duration{Days: 3}, text: "3 days"},
+		{input: "1 year, 2 months", duration: Duration{Months: 14}, text: "1 year 2 months"},
+		{input: "2weeks 1d 1h30m", duration: Duration{Days: 15, Time: 90 * time.Minute}, text: "15 days 1h30m0s"},
+		{input: "5 minutes 10 secs", duration: Duration{Time: 5*time.Minute + 10*time.Second}, text: "5m10s"},
+		{input: "-1 day", duration: Duration{Days: -1}, text: "-1 day"},
+		{input: "0s", duration: Duration{}, text: "0s"},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			d, err := ParseDuration(test.input)
+			assert.NoError(t, err)
+			assert.Equal(t, test.duration, d)
+			assert.Equal(t, test.text, d.String())
+		})
+	}
+
+	for _, input := range []string{"", "3", "3 fortnights", "1h30"} {
+		_, err := ParseDuration(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestDurationBetween(t *testing.T) {
+	start := time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		end      time.Time
+		duration Duration
+	}{
+		{end: time.Date(2024, 1, 31, 11, 30, 0, 0, time.UTC), duration: Duration{Time: 90 * time.Minute}},
+		{end: time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC), duration: Duration{Days: 29}},
+		{end: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), duration: Duration{Days: 29, Time: 23 * time.Hour}},
+		{end: time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC), duration: Duration{Months: 13, Days: 2, Time: 2 * time.Hour}},
+		{end: time.Date(2024, 1, 30, 10, 0, 0, 0, time.UTC), duration: Duration{Days: -1}},
+	}
+	for _, test := range tests {
+		t.Run(test.end.String(), func(t *testing.T) {
+			d := DurationBetween(start, test.end)
+			assert.Equal(t, test.duration, d)
+			assert.Equal(t, test.end, d.AddTo(start))
+		})
+	}
+}
+
+func TestDateValues(t *testing.T) {
+	dateTime := Type{
+		Name: "dateTime",
+		Parse: func(x string) (any, error) {
+			return time.Parse(time.DateTime, x)
+		},
+		Values: DateValues("dateTime", "duration"),
+	}
+	r, err := NewReflect(ReflectOptions{
+		BoolType: "bool",
+		Types: map[reflect.Type]Type{
+			TypeOf[bool]():            {Name: "bool"},
+			TypeOf[time.Time]():       dateTime,
+			TypeOf[Duration]():        DurationType(DurationOptions{}),
+			TypeOf[durationBooking](): {},
+		},
+	})
+	assert.NoError(t, err)
+
+	booking := durationBooking{
+		Start:  time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC),
+		End:    time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+		Length: Duration{Days: 1, Time: time.Hour},
+	}
+	tests := []struct {
+		expression string
+		result     any
+	}{
+		{expression: "start.add('1h30m')", result: time.Date(2024, 1, 31, 11, 30, 0, 0, time.UTC)},
+		{expression: "start.add('1 month 1 day')", result: time.Date(2024, 3, 3, 10, 0, 0, 0, time.UTC)},
+		{expression: "start.sub('2 weeks')", result: time.Date(2024, 1, 17, 10, 0, 0, 0, time.UTC)},
+		{expression: "start.add(length)", result: time.Date(2024, 2, 1, 11, 0, 0, 0, time.UTC)},
+		{expression: "start.between(end)", result: Duration{Days: 30, Time: 150 * time.Minute}},
+		{expression: "start.between(end).=('30 days 2h30m')", result: true},
+		{expression: "length.add('2 days').neg", result: Duration{Days: -3, Time: -time.Hour}},
+		{expression: "length.sub(length).isZero", result: true},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := r.Parse(Options{RootType: NameOf[durationBooking](), Expression: test.expression})
+			if !assert.NoError(t, err) {
+				return
+			}
+			result, err := r.Evaluate(e, booking)
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+
+	op, isDateOperation := r.System().Type("dateTime").Value("between").DateOperation()
+	assert.True(t, isDateOperation)
+	assert.Equal(t, DateBetween, op)
+	_, err = DateAdd.Apply("today", []any{time.Hour})
+	assert.Error(t, err)
+	_, err = DateAdd.Apply((*time.Time)(nil), []any{time.Hour})
+	assert.EqualError(t, err, "date operation add expects a date but was given *time.Time")
+	_, err = DateAdd.Apply(booking.Start, []any{(*Duration)(nil)})
+	assert.Error(t, err)
+
+	sum, err := StandardOperations{}.Arithmetic(OpAdd, booking.Start, Duration{Months: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC), sum)
+}
@@ -0,0 +1,225 @@
+package texpr
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The version of the binary expression encoding written by EncodeExpr.
+const ExprEncodingVersion = 1
+
+// The binary encoding of an expression could not be decoded.
+var ErrInvalidEncoding = errors.New("invalid expression encoding")
+
+// The field numbers of the messages in expr.proto.
+const (
+	encodedExprVersion   protowire.Number = 1
+	encodedExprRootType  protowire.Number = 2
+	encodedExprExpr      protowire.Number = 3
+	encodedChainLinks    protowire.Number = 1
+	encodedLinkToken     protowire.Number = 1
+	encodedLinkKind      protowire.Number = 2
+	encodedLinkArguments protowire.Number = 3
+	encodedLinkCalled    protowire.Number = 4
+	encodedLinkLiteral   protowire.Number = 5
+	encodedLinkOptional  protowire.Number = 6
+)
+
+// The kinds of links in expr.proto.
+const (
+	encodedValue uint64 = iota
+	encodedConstant
+	encodedBind
+	encodedPlaceholder
+	encodedPrefix
+	encodedList
+)
+
+// Encodes the expression as the EncodedExpr protobuf message defined in expr.proto, so it can be stored
+// compactly or embedded in other protobuf messages. Only the tokens and the root type are encoded, the
+// expression is linked again by System.DecodeExpr. Conversions and default arguments added while
+// linking are encoded like they were written.
+func EncodeExpr(e *Expr) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, encodedExprVersion, protowire.VarintType)
+	b = protowire.AppendVarint(b, ExprEncodingVersion)
+	if e.ParentType != nil {
+		b = protowire.AppendTag(b, encodedExprRootType, protowire.BytesType)
+		b = protowire.AppendString(b, string(e.ParentType.Name))
+	}
+	b = protowire.AppendTag(b, encodedExprExpr, protowire.BytesType)
+	return protowire.AppendBytes(b, encodeChain(e))
+}
+
+// Returns the EncodedChain message of the chain.
+func encodeChain(e *Expr) []byte {
+	var chain []byte
+	for c := e; c != nil; c = c.Next {
+		var link []byte
+		if c.Token != "" {
+			link = protowire.AppendTag(link, encodedLinkToken, protowire.BytesType)
+			link = protowire.AppendString(link, c.Token)
+		}
+		kind := encodedValue
+		switch {
+		case c.Constant:
+			kind = encodedConstant
+		case c.Bind:
+			kind = encodedBind
+		case c.Placeholder:
+			kind = encodedPlaceholder
+		case c.Prefix:
+			kind = encodedPrefix
+		case c.List:
+			kind = encodedList
+		}
+		if kind != encodedValue {
+			link = protowire.AppendTag(link, encodedLinkKind, protowire.VarintType)
+			link = protowire.AppendVarint(link, kind)
+		}
+		for _, arg := range c.Arguments {
+			link = protowire.AppendTag(link, encodedLinkArguments, protowire.BytesType)
+			link = protowire.AppendBytes(link, encodeChain(arg))
+		}
+		if c.Called {
+			link = protowire.AppendTag(link, encodedLinkCalled, protowire.VarintType)
+			link = protowire.AppendVarint(link, 1)
+		}
+		if c.Optional {
+			link = protowire.AppendTag(link, encodedLinkOptional, protowire.VarintType)
+			link = protowire.AppendVarint(link, 1)
+		}
+		if c.Literal != "" {
+			link = protowire.AppendTag(link, encodedLinkLiteral, protowire.BytesType)
+			link = protowire.AppendString(link, c.Literal)
+		}
+		chain = protowire.AppendTag(chain, encodedChainLinks, protowire.BytesType)
+		chain = protowire.AppendBytes(chain, link)
+	}
+	return chain
+}
+
+// Decodes an expression encoded by EncodeExpr and links it like Parse does with the options. The root
+// type is the encoded root type unless the options give one. Errors from linking are returned with the
+// expression like Parse, and ErrInvalidEncoding is returned when the data is not an encoded expression.
+func (sys System) DecodeExpr(data []byte, opts Options) (*Expr, error) {
+	var version uint64
+	var rootType string
+	var first *Expr
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error {
+		var err error
+		switch {
+		case num == encodedExprVersion && typ == protowire.VarintType:
+			version = varint
+		case num == encodedExprRootType && typ == protowire.BytesType:
+			rootType = string(value)
+		case num == encodedExprExpr && typ == protowire.BytesType:
+			first, err = decodeChain(value, nil)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if version != ExprEncodingVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidEncoding, version)
+	}
+	if first == nil {
+		return nil, ErrNoExpression
+	}
+	if opts.RootType == "" {
+		opts.RootType = TypeName(rootType)
+	}
+	ctx, expectedTypes, err := sys.linkOptions(opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	errs := sys.link(first, expectedTypes, ctx)
+	if len(errs) == 0 {
+		errs = ctx.checkConstraints(first)
+	}
+	return first, joinParseErrors(errs)
+}
+
+// Decodes an EncodedChain message whose first expression is an argument of the parent, if any.
+func decodeChain(data []byte, parent *Expr) (*Expr, error) {
+	var first, last *Expr
+	err := decodeFields(data, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error {
+		if num != encodedChainLinks || typ != protowire.BytesType {
+			return nil
+		}
+		link := &Expr{Prev: last}
+		err := decodeFields(value, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error {
+			switch {
+			case num == encodedLinkToken && typ == protowire.BytesType:
+				link.Token = string(value)
+			case num == encodedLinkKind && typ == protowire.VarintType:
+				link.Constant = varint == encodedConstant
+				link.Bind = varint == encodedBind
+				link.Placeholder = varint == encodedPlaceholder
+				link.Prefix = varint == encodedPrefix
+				link.List = varint == encodedList
+			case num == encodedLinkArguments && typ == protowire.BytesType:
+				arg, err := decodeChain(value, link)
+				if err != nil {
+					return err
+				}
+				link.Arguments = append(link.Arguments, arg)
+			case num == encodedLinkCalled && typ == protowire.VarintType:
+				link.Called = varint != 0
+			case num == encodedLinkOptional && typ == protowire.VarintType:
+				link.Optional = varint != 0
+			case num == encodedLinkLiteral && typ == protowire.BytesType:
+				link.Literal = string(value)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if last != nil {
+			last.Next = link
+		} else {
+			first = link
+			link.Parent = parent
+		}
+		last = link
+		return nil
+	})
+	if err == nil && first == nil {
+		err = fmt.Errorf("%w: empty chain", ErrInvalidEncoding)
+	}
+	return first, err
+}
+
+// Calls fn with each field of the message, with the bytes of length delimited fields or the varint of
+// varint fields. Fields of other wire types are skipped so newer encodings can add fields.
+func decodeFields(data []byte, fn func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return fmt.Errorf("%w: %v", ErrInvalidEncoding, protowire.ParseError(n))
+		}
+		data = data[n:]
+		var value []byte
+		var varint uint64
+		switch typ {
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(data)
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return fmt.Errorf("%w: %v", ErrInvalidEncoding, protowire.ParseError(n))
+		}
+		data = data[n:]
+		if err := fn(num, typ, value, varint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
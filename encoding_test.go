@@ -0,0 +1,62 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestEncodeExpr(t *testing.T) {
+	opts := Options{RootType: typeContext, Parameters: map[string]TypeName{"part": typeText}}
+	tests := []string{
+		"time.now.hour>(12)",
+		"user.name.contains(:part).or(user.name.isUpper, user.name.lower.contains('a,b'))",
+		"'hello'.upper",
+		"time.now().hour>(12)",
+	}
+	for _, expression := range tests {
+		t.Run(expression, func(t *testing.T) {
+			opts.Expression = expression
+			e, err := sys.Parse(opts)
+			assert.NoError(t, err)
+			decoded, err := sys.DecodeExpr(EncodeExpr(e), Options{Parameters: opts.Parameters})
+			assert.NoError(t, err)
+			assert.Equal(t, e.String(), decoded.String())
+			assert.Equal(t, e.Last().Type, decoded.Last().Type)
+			assert.Equal(t, e.Last().Parsed, decoded.Last().Parsed)
+			assert.Equal(t, e.ParentType, decoded.ParentType)
+		})
+	}
+
+	// Conversions added while linking are encoded.
+	e, err := sys.Parse(Options{RootType: typeContext, Expression: "user", ExpectedTypes: []TypeName{typeText}})
+	assert.NoError(t, err)
+	decoded, err := sys.DecodeExpr(EncodeExpr(e), Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, "user.name", decoded.String())
+	assert.Equal(t, TypeName(typeText), decoded.Last().Type.Name)
+
+	e, err = sys.Parse(Options{RootType: typeContext, Expression: "user.name.lower"})
+	assert.NoError(t, err)
+	encoded := EncodeExpr(e)
+
+	// Unknown fields from newer encodings are skipped.
+	extended := protowire.AppendTag(append([]byte(nil), encoded...), 15, protowire.BytesType)
+	extended = protowire.AppendString(extended, "ignored")
+	extended = protowire.AppendTag(extended, 16, protowire.Fixed32Type)
+	extended = protowire.AppendFixed32(extended, 7)
+	decoded, err = sys.DecodeExpr(extended, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, "user.name.lower", decoded.String())
+
+	_, err = sys.DecodeExpr(encoded[:len(encoded)-2], Options{})
+	assert.ErrorIs(t, err, ErrInvalidEncoding)
+
+	newer := protowire.AppendVarint(protowire.AppendTag(nil, 1, protowire.VarintType), 2)
+	_, err = sys.DecodeExpr(newer, Options{})
+	assert.EqualError(t, err, "invalid expression encoding: unsupported version 2")
+
+	_, err = sys.DecodeExpr(encoded, Options{RootType: typeUser})
+	assert.ErrorIs(t, err, ErrUnknownValue)
+}
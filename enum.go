@@ -0,0 +1,69 @@
+package texpr
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// The metadata of an enum option, used to build enum pickers.
+type EnumOption struct {
+	// The label displayed for the option. When empty the option itself is displayed.
+	Label string `json:"label,omitempty"`
+	// The label of the option in other locales.
+	Labels Localized `json:"labels,omitempty"`
+	// A description of the option.
+	Description string `json:"description,omitempty"`
+	// The description of the option in other locales.
+	Descriptions Localized `json:"descriptions,omitempty"`
+	// If the option should no longer be used. Deprecated options can still be parsed.
+	Deprecated bool `json:"deprecated,omitempty"`
+	// The order the option is listed in, lower orders first. Options with the same order are
+	// listed in the order they appear in Enums.
+	Order int `json:"order,omitempty"`
+}
+
+// Returns the metadata of the enum option, case insensitive. If this type was not given
+// to a system then no metadata is returned.
+func (t Type) EnumOption(enumValue string) (EnumOption, bool) {
+	option, ok := t.enumOptions[strings.ToLower(enumValue)]
+	return option, ok
+}
+
+// Returns the label of the enum option in the given locale, falling back to the option itself.
+func (t Type) EnumLabel(enumValue string, locale string) string {
+	option, _ := t.EnumOption(enumValue)
+	fallback := option.Label
+	if fallback == "" {
+		fallback = enumValue
+	}
+	return option.Labels.Get(locale, fallback)
+}
+
+// Returns the enum options sorted by their order.
+func (t Type) SortedEnums() []string {
+	sorted := make([]string, len(t.Enums))
+	copy(sorted, t.Enums)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, _ := t.EnumOption(sorted[i])
+		b, _ := t.EnumOption(sorted[j])
+		return a.Order < b.Order
+	})
+	return sorted
+}
+
+// Operations on enum values, which are StandardOperations except strings are equal case insensitively
+// like enum options are parsed. It's used by the operators of types with Enums and no Operations.
+type EnumOperations struct {
+	StandardOperations
+}
+
+var _ Operations = EnumOperations{}
+
+func (EnumOperations) Equal(a, b any) (bool, error) {
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	if isString(av) && isString(bv) {
+		return strings.EqualFold(av.String(), bv.String()), nil
+	}
+	return StandardOperations{}.Equal(a, b)
+}
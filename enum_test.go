@@ -0,0 +1,187 @@
+package texpr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnumOptions(t *testing.T) {
+	newTypes := func(options map[string]EnumOption) []Type {
+		return []Type{{
+			Name:        "priority",
+			Enums:       []string{"low", "normal", "high", "urgent"},
+			EnumOptions: options,
+		}, {
+			Name: "task",
+			Values: []Value{
+				{Path: "priority", Type: "priority"},
+			},
+		}}
+	}
+
+	s, err := NewSystem(newTypes(map[string]EnumOption{
+		"high":   {Order: -1, Label: "High", Labels: Localized{"fr": "Haute"}},
+		"Urgent": {Order: -1, Deprecated: true, Description: "Use high instead", Descriptions: Localized{"fr": "Utilisez high"}},
+	}))
+	assert.NoError(t, err)
+
+	priority := s.Type("priority")
+	assert.Equal(t, []string{"high", "urgent", "low", "normal"}, priority.SortedEnums())
+	assert.Equal(t, "High", priority.EnumLabel("HIGH", ""))
+	assert.Equal(t, "Haute", priority.EnumLabel("high", "fr-CA"))
+	assert.Equal(t, "low", priority.EnumLabel("low", "fr"))
+	assert.Equal(t, "Utilisez high", priority.DescribeEnum("urgent", "fr"))
+
+	option, exists := priority.EnumOption("URGENT")
+	assert.True(t, exists)
+	assert.True(t, option.Deprecated)
+
+	completions := s.Complete(Options{RootType: "task", Expression: "", ExpectedTypes: []TypeName{"priority"}}, 0)
+	texts := make([]string, len(completions))
+	for i, c := range completions {
+		texts[i] = c.Text
+	}
+	assert.Equal(t, []string{"priority", "high", "urgent", "low", "normal"}, texts)
+	assert.Equal(t, "High", completions[1].Label)
+	assert.True(t, completions[2].Deprecated)
+	assert.Equal(t, "Use high instead", completions[2].Description)
+
+	docs := s.LocalizedDocs("fr")
+	for _, entry := range docs.Index {
+		if entry.Kind == DocKindEnum && entry.Path == "high" {
+			assert.Equal(t, "Haute", entry.Label)
+			assert.Contains(t, entry.Terms, "haute")
+		}
+		if entry.Kind == DocKindEnum && entry.Path == "urgent" {
+			assert.True(t, entry.Deprecated)
+		}
+	}
+
+	_, err = NewSystem(newTypes(map[string]EnumOption{"critical": {}}))
+	assert.ErrorIs(t, err, ErrUnknownValue)
+	assert.EqualError(t, err, "enum option critical on priority could not be found")
+}
+
+type taskPriority string
+
+type reflectTask struct {
+	Priority taskPriority
+}
+
+func TestEnumOperators(t *testing.T) {
+	s, err := NewSystemWithOptions([]Type{{
+		Name:  "priority",
+		Enums: []string{"low", "normal", "high"},
+		Values: []Value{
+			{Path: "=", Type: "bool", Description: "custom", Parameters: []Parameter{{Name: "value", Type: "priority"}}},
+		},
+	}, {
+		Name: "bool",
+	}, {
+		Name: "task",
+		Values: []Value{
+			{Path: "priority", Type: "priority"},
+		},
+	}}, SystemOptions{BoolType: "bool", EnumOperators: true})
+	assert.NoError(t, err)
+
+	priority := s.Type("priority")
+	paths := make([]string, len(priority.Values))
+	for i, v := range priority.Values {
+		paths[i] = v.Path
+	}
+	assert.Equal(t, []string{"=", "!=", "oneOf", "notOneOf"}, paths)
+	assert.Equal(t, "custom", priority.Value("=").Description)
+
+	_, isOperator := priority.Value("=").Operator()
+	assert.False(t, isOperator)
+	op, isOperator := priority.Value("notOneOf").Operator()
+	assert.True(t, isOperator)
+	assert.Equal(t, OpNotOneOf, op)
+	assert.True(t, priority.Value("oneOf").Variadic)
+
+	source := CompileSourceLookup[Run]{
+		Initial: func(root any) (any, error) { return root, nil },
+		TypeCompilers: TypeCompilers[Run]{
+			"task": {
+				"priority": runCompiler(func(v map[string]any, args []any) (any, error) { return v["priority"], nil }),
+			},
+		},
+		ConstantCompiler: func(e *Expr, root *Type, previous Run, arguments []Run) (Run, error) {
+			return func(root any) (any, error) { return e.Parsed, nil }, nil
+		},
+		OperatorCompiler: func(e *Expr, root *Type, previous Run, arguments []Run) (Run, error) {
+			op, _ := e.Value.Operator()
+			return func(root any) (any, error) {
+				value, err := previous(root)
+				if err != nil {
+					return nil, err
+				}
+				args := make([]any, len(arguments))
+				for i, arg := range arguments {
+					if args[i], err = arg(root); err != nil {
+						return nil, err
+					}
+				}
+				return op.Apply(e.Prev.Type.operations(), value, args)
+			}, nil
+		},
+	}
+
+	tests := map[string]bool{
+		"priority!=(low)":             true,
+		"priority.oneOf(low, HIGH)":   true,
+		"priority.notOneOf(low,high)": false,
+	}
+	for expression, expected := range tests {
+		e, err := s.Parse(Options{RootType: "task", Expression: expression})
+		assert.NoError(t, err)
+		run, err := Compile[Run](e, source)
+		assert.NoError(t, err)
+		result, err := run(map[string]any{"priority": "high"})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, expression)
+	}
+
+	e, err := s.Parse(Options{RootType: "task", Expression: "priority=(low)"})
+	assert.NoError(t, err)
+	_, err = Compile[Run](e, source)
+	assert.EqualError(t, err, "no value compilers specified for priority")
+
+	_, err = OpEquals.Apply(EnumOperations{}, "low", []any{"low", "high"})
+	assert.Error(t, err)
+
+	found, err := OpOneOf.Apply(EnumOperations{}, []int{1, 2}, []any{nil, []int{2}, []int{1, 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, true, found)
+	found, err = OpEquals.Apply(EnumOperations{}, map[string]int{"a": 1}, []any{1})
+	assert.NoError(t, err)
+	assert.Equal(t, false, found)
+
+	_, err = NewSystemWithOptions([]Type{{Name: "priority", Enums: []string{"low"}}}, SystemOptions{EnumOperators: true})
+	assert.ErrorIs(t, err, ErrUnknownType)
+
+	r, err := NewReflect(ReflectOptions{
+		BoolType:      "bool",
+		EnumOperators: true,
+		Conversions: map[reflect.Type]ReflectConversion{
+			TypeOf[bool](): {Type: "bool"},
+		},
+		Types: map[reflect.Type]Type{
+			TypeOf[bool](): {Name: "bool"},
+			TypeOf[taskPriority](): {Name: "priority", Enums: []string{"low", "normal", "high"}, Parse: func(x string) (any, error) {
+				return taskPriority(x), nil
+			}},
+			TypeOf[reflectTask](): {Name: "task"},
+		},
+	})
+	assert.NoError(t, err)
+
+	e, err = r.Parse(Options{RootType: "task", Expression: "priority.oneOf(normal, high)"})
+	assert.NoError(t, err)
+	result, err := r.Evaluate(e, reflectTask{Priority: "HIGH"})
+	assert.NoError(t, err)
+	assert.Equal(t, true, result)
+}
@@ -0,0 +1,281 @@
+package texpr
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseErrorKinds(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		expected   []TypeName
+		kind       error
+	}{
+		{name: "unknown value", expression: "time.sun", kind: ErrUnknownValue},
+		{name: "too few", expression: "time.today.add", kind: ErrArity},
+		{name: "too many", expression: "time.today.add(1, day, 2)", kind: ErrArity},
+		{name: "constant mismatch", expression: "time.today.add(one, day)", kind: ErrTypeMismatch},
+		{name: "expected mismatch", expression: "time.today", expected: []TypeName{typeInt}, kind: ErrTypeMismatch},
+		{name: "unterminated", expression: "user.name.contains('Ma", kind: ErrUnterminatedConstant},
+		{name: "unexpected parenthesis", expression: "user.name)", kind: ErrSyntax},
+		{name: "missing parenthesis", expression: "user.name.contains('Ma'", kind: ErrSyntax},
+		{name: "missing value", expression: "user.", kind: ErrSyntax},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := sys.Parse(Options{
+				RootType:      typeContext,
+				Expression:    test.expression,
+				ExpectedTypes: test.expected,
+			})
+			assert.ErrorIs(t, err, test.kind)
+
+			var parseError ParseError
+			assert.True(t, errors.As(err, &parseError))
+			assert.Equal(t, test.kind, parseError.Kind)
+		})
+	}
+
+	_, err := sys.Parse(Options{RootType: "missing", Expression: "x"})
+	assert.ErrorIs(t, err, ErrUnknownType)
+}
+
+func TestParseErrorCause(t *testing.T) {
+	_, err := sys.Parse(Options{RootType: typeContext, Expression: "time.today.add(one, day)"})
+
+	var numError *strconv.NumError
+	assert.True(t, errors.As(err, &numError))
+	assert.Equal(t, "one", numError.Num)
+}
+
+func TestConstantErrorPosition(t *testing.T) {
+	months := NewSystemRequired([]Type{{
+		Name: "month",
+		Parse: func(x string) (any, error) {
+			if len(x) != 7 || x[4] != '-' {
+				return nil, NewConstantError(0, len(x), "invalid month: expected yyyy-mm")
+			}
+			month, err := strconv.Atoi(x[5:])
+			if err != nil || month < 1 || month > 12 {
+				e := NewConstantError(5, 2, "invalid month: bad month")
+				e.Cause = err
+				return nil, e
+			}
+			return x, nil
+		},
+	}, {
+		Name: "report",
+		Values: []Value{
+			{Path: "for", Type: "report", Parameters: []Parameter{
+				{Name: "month", Type: "month"},
+			}},
+		},
+	}})
+
+	_, err := months.Parse(Options{RootType: "report", Expression: "for('2024-13')"})
+	assert.EqualError(t, err, "constant 2024-13 did not match expected type(s) month: invalid month: bad month at (index: 10, line: 0, column: 10)")
+
+	var parseError ParseError
+	assert.True(t, errors.As(err, &parseError))
+	assert.Equal(t, Position{Index: 10, Column: 10}, *parseError.Start)
+	assert.Equal(t, Position{Index: 12, Column: 12}, *parseError.End)
+	var constantError ConstantError
+	assert.True(t, errors.As(err, &constantError))
+	assert.Equal(t, 5, constantError.Offset)
+
+	_, err = months.Parse(Options{RootType: "report", Expression: `for('\x32024-13')`})
+	assert.True(t, errors.As(err, &parseError))
+	assert.Equal(t, Position{Index: 13, Column: 13}, *parseError.Start)
+	assert.Equal(t, Position{Index: 15, Column: 15}, *parseError.End)
+
+	literals, err := NewSystemWithOptions(months.definitions, SystemOptions{Literals: map[string]TypeName{"month": "month"}})
+	assert.NoError(t, err)
+	_, err = literals.Parse(Options{RootType: "report", Expression: "for(month'2024-13')"})
+	assert.True(t, errors.As(err, &parseError))
+	assert.Equal(t, Position{Index: 15, Column: 15}, *parseError.Start)
+	assert.Equal(t, Position{Index: 17, Column: 17}, *parseError.End)
+
+	_, err = months.Parse(Options{RootType: "report", Expression: "for(\n  2024)"})
+	assert.True(t, errors.As(err, &parseError))
+	assert.Equal(t, Position{Index: 7, Line: 1, Column: 2}, *parseError.Start)
+	assert.Equal(t, Position{Index: 11, Line: 1, Column: 6}, *parseError.End)
+
+	_, err = months.Parse(Options{RootType: "report", Expression: "for('24')"})
+	assert.True(t, errors.As(err, &parseError))
+	assert.Equal(t, Position{Index: 5, Column: 5}, *parseError.Start)
+	assert.Equal(t, Position{Index: 7, Column: 7}, *parseError.End)
+}
+
+func TestSystemErrorKinds(t *testing.T) {
+	_, err := NewSystem([]Type{{
+		Name:   typeText,
+		Values: []Value{{Path: "a.b", Type: typeText}},
+	}})
+	assert.ErrorIs(t, err, ErrInvalidPath)
+
+	_, err = NewSystem([]Type{{
+		Name:   typeText,
+		Values: []Value{{Path: "upper", Type: typeInt}},
+	}})
+	assert.ErrorIs(t, err, ErrUnknownType)
+
+	_, err = NewSystem([]Type{{
+		Name:   typeText,
+		Values: []Value{{Path: "pick", Generic: true}},
+	}})
+	assert.ErrorIs(t, err, ErrInvalidGeneric)
+
+	_, err = NewSystem([]Type{{
+		Name:   typeText,
+		Values: []Value{{Path: "upper", Type: typeText, Examples: []string{"upper.lower"}}},
+	}})
+	assert.ErrorIs(t, err, ErrInvalidExample)
+	assert.ErrorIs(t, err, ErrUnknownValue)
+
+	var systemError SystemError
+	assert.True(t, errors.As(err, &systemError))
+	assert.Equal(t, "upper", systemError.Value.Path)
+}
+
+func TestSystemErrorDuplicatePaths(t *testing.T) {
+	newSystem := func(values ...Value) error {
+		_, err := NewSystem([]Type{{Name: typeText, Values: values}})
+		return err
+	}
+
+	err := newSystem(Value{Path: "upper", Type: typeText}, Value{Path: "UPPER", Type: typeText})
+	assert.ErrorIs(t, err, ErrDuplicatePath)
+	assert.EqualError(t, err, "path UPPER on text.UPPER collides with the path of text.upper")
+
+	err = newSystem(Value{Path: "upper", Type: typeText}, Value{Path: "caps", Type: typeText, Aliases: []string{"upper"}})
+	assert.EqualError(t, err, "alias upper on text.caps collides with the path of text.upper")
+
+	err = newSystem(Value{Path: "upper", Type: typeText, Aliases: []string{"caps"}}, Value{Path: "caps", Type: typeText})
+	assert.EqualError(t, err, "path caps on text.caps collides with the alias of text.upper")
+
+	err = newSystem(Value{Path: "upper", Type: typeText, Aliases: []string{"caps"}}, Value{Path: "shout", Type: typeText, Aliases: []string{"Caps"}})
+	assert.EqualError(t, err, "alias Caps on text.shout collides with the alias of text.upper")
+
+	err = newSystem(Value{Path: "upper", Type: typeText, Aliases: []string{"caps", "CAPS", "upper"}})
+	assert.NoError(t, err)
+}
+
+func TestParseErrorRecovery(t *testing.T) {
+	e, err := sys.Parse(Options{
+		RootType:   typeContext,
+		Expression: "user.nme.upper.contains(user.name.lower, time.sun).or(user.name.len>(3), time.today.add(1).year>(2000))",
+	})
+
+	var errs ParseErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Len(t, errs, 3)
+	assert.Equal(t, "invalid value nme\ninvalid value sun\nadd.date expects at least 2 parameters", err.Error())
+	assert.ErrorIs(t, err, ErrUnknownValue)
+	assert.ErrorIs(t, err, ErrArity)
+
+	nme := e.Next
+	assert.Equal(t, Unknown, nme.Type)
+	assert.Equal(t, Unknown, nme.Next.Type)
+
+	contains := nme.Next.Next
+	assert.Equal(t, Unknown, contains.Type)
+	assert.Equal(t, typeText, contains.Arguments[0].Last().Type.Name)
+	assert.Equal(t, typeTimePackage, contains.Arguments[1].Type.Name)
+	assert.Equal(t, Unknown, contains.Arguments[1].Next.Type)
+
+	or := contains.Next
+	assert.Equal(t, typeBool, or.Arguments[0].Last().Type.Name)
+	assert.Equal(t, typeBool, or.Arguments[1].Last().Type.Name)
+}
+
+func TestSystemErrorConversionCycles(t *testing.T) {
+	newType := func(name TypeName, as ...TypeName) Type {
+		t := Type{Name: name, As: map[TypeName]string{}}
+		for _, other := range as {
+			t.Values = append(t.Values, Value{Path: "to" + string(other), Type: other})
+			t.As[other] = "to" + string(other)
+		}
+		return t
+	}
+
+	_, err := NewSystem([]Type{newType("a", "b"), newType("b", "c"), newType("c")})
+	assert.NoError(t, err)
+
+	_, err = NewSystem([]Type{newType("a", "b"), newType("b", "a")})
+	assert.ErrorIs(t, err, ErrConversionCycle)
+	assert.EqualError(t, err, "conversion cycle a as b as a")
+
+	_, err = NewSystem([]Type{newType("a", "b"), newType("b", "c"), newType("c", "d", "b"), newType("d")})
+	assert.EqualError(t, err, "conversion cycle b as c as b")
+
+	_, err = NewSystem([]Type{newType("a", "a")})
+	assert.EqualError(t, err, "conversion cycle a as a")
+}
+
+func TestCalledValues(t *testing.T) {
+	tests := []struct {
+		expression string
+		kind       error
+		message    string
+	}{
+		{expression: "user.name()"},
+		{expression: "time.now().hour>('12')"},
+		{expression: "user.name.contains()", kind: ErrArity, message: "contains was called with no arguments but expects at least 1 parameters"},
+		{expression: "()", kind: ErrSyntax, message: "parentheses must group one expression, found 0"},
+		{expression: "(user, time)", kind: ErrSyntax, message: "parentheses must group one expression, found 2"},
+		{expression: "user.name()()", kind: ErrSyntax},
+		{expression: "'a'()", kind: ErrSyntax, message: "a is not a value and can't be called"},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := sys.Parse(Options{RootType: typeContext, Expression: test.expression})
+			if test.kind == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expression, e.String())
+				return
+			}
+			assert.ErrorIs(t, err, test.kind)
+			if test.message != "" {
+				assert.EqualError(t, err, test.message)
+			}
+		})
+	}
+}
+
+func TestTrailingCommas(t *testing.T) {
+	tests := []struct {
+		expression string
+		trailing   bool
+		kind       error
+		message    string
+	}{
+		{expression: "time.today.add(1, day,)", trailing: true},
+		{expression: "time.today.add(1, day , )", trailing: true},
+		{expression: "time.today.add(1, day)"},
+		{expression: "user.name.contains(,)", kind: ErrArity, message: "parameter value at 0 was not given a value or a default value"},
+		{expression: "user.name.contains(,'a')", kind: ErrArity, message: "contains.text expects no more than 1 parameters"},
+		{expression: "time.today.add(1,, day)", kind: ErrArity, message: "parameter duration at 1 was not given a value or a default value"},
+		{expression: "[,1]", kind: ErrSyntax, message: "unexpected , at (index: 1, line: 0, column: 1), expecting an argument before it"},
+		{expression: "time.today.add(1,", kind: ErrSyntax, message: "expression missing 1 terminating parenthesis"},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := sys.Parse(Options{RootType: typeContext, Expression: test.expression})
+			if test.message != "" {
+				assert.ErrorIs(t, err, test.kind)
+				assert.ErrorContains(t, err, test.message)
+				return
+			}
+			assert.NoError(t, err)
+			add := e.Next.Next
+			assert.Equal(t, test.trailing, add.TrailingComma)
+			assert.Len(t, add.Arguments, 2)
+			assert.Equal(t, "time.today.add('1','day')", e.String())
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package texpr
+
+// Evaluates linked expressions against a root value.
+type Evaluator interface {
+	// Evaluates the expression with the given root value and returns the result.
+	Evaluate(e *Expr, root any) (any, error)
+}
+
+// A function which implements Evaluator.
+type EvaluatorFunc func(e *Expr, root any) (any, error)
+
+var _ Evaluator = EvaluatorFunc(nil)
+
+func (f EvaluatorFunc) Evaluate(e *Expr, root any) (any, error) {
+	return f(e, root)
+}
@@ -0,0 +1,209 @@
+package texpr
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// An operation of the values given by LogicValues.
+type LogicOperation string
+
+const (
+	// Returns whether the value and all the given values are true.
+	LogicAnd LogicOperation = "and"
+	// Returns whether the value or any of the given values are true.
+	LogicOr LogicOperation = "or"
+	// Returns whether the value is false.
+	LogicNot LogicOperation = "not"
+)
+
+// Returns the and, or, and not values (see LogicOperation) to add to a boolean type. Reflect evaluates
+// them on bool values, and Reflect.Explain explains which of their operands determined the outcome.
+func LogicValues(boolType TypeName) []Value {
+	return []Value{{
+		Path:           string(LogicAnd),
+		Type:           boolType,
+		Description:    "Whether the value and all the given values are true",
+		Variadic:       true,
+		Parameters:     []Parameter{{Name: "values", Type: boolType}},
+		logicOperation: LogicAnd,
+	}, {
+		Path:           string(LogicOr),
+		Type:           boolType,
+		Description:    "Whether the value or any of the given values are true",
+		Variadic:       true,
+		Parameters:     []Parameter{{Name: "values", Type: boolType}},
+		logicOperation: LogicOr,
+	}, {
+		Path:           string(LogicNot),
+		Type:           boolType,
+		Description:    "Whether the value is false",
+		logicOperation: LogicNot,
+	}}
+}
+
+// Returns the logic operation the value was created for by LogicValues, if any.
+func (v Value) LogicOperation() (LogicOperation, bool) {
+	return v.logicOperation, v.logicOperation != ""
+}
+
+// Returns the result of the operation on the boolean value given the boolean argument values.
+func (op LogicOperation) Apply(value any, args []any) (any, error) {
+	operands := append([]any{value}, args...)
+	result := op == LogicAnd
+	for _, operand := range operands {
+		b, ok := operand.(bool)
+		if !ok {
+			return nil, fmt.Errorf("logic operation %s expects a boolean but was given %T", op, operand)
+		}
+		switch op {
+		case LogicAnd:
+			result = result && b
+		case LogicOr:
+			result = result || b
+		case LogicNot:
+			return !b, nil
+		default:
+			return nil, fmt.Errorf("unknown logic operation %s", op)
+		}
+	}
+	return result, nil
+}
+
+// Why a boolean expression has its result, see Reflect.Explain.
+type Explanation struct {
+	// The first expression of the chain which was evaluated.
+	Expr *Expr
+	// The last expression of the chain which was evaluated, the chain after it is not part of this explanation.
+	Last *Expr
+	// The result of the chain from Expr to Last.
+	Result bool
+	// The logic operation of Last, or empty if the result was evaluated directly.
+	Operation LogicOperation
+	// The explanations of the operands of the operation which determined the result. For and this is
+	// every operand when true, otherwise the first operand which was false. For or this is the first
+	// operand which was true when true, otherwise every operand. For not this is the operand.
+	Causes []*Explanation
+}
+
+// Returns the explanations without an operation which determined the result, in the order they were evaluated.
+func (x *Explanation) Reasons() []*Explanation {
+	if x.Operation == "" {
+		return []*Explanation{x}
+	}
+	reasons := make([]*Explanation, 0, len(x.Causes))
+	for _, cause := range x.Causes {
+		reasons = append(reasons, cause.Reasons()...)
+	}
+	return reasons
+}
+
+// Returns the chain from Expr to Last as a string, like `user.createDate.hour>('12')`.
+func (x *Explanation) String() string {
+	return x.prefix().String()
+}
+
+// Evaluates the boolean expression against the root and bind parameters and explains which operands of
+// the values given by LogicValues determined the result. Operands are evaluated in order and stop like
+// their operation does, so `a.and(b)` does not evaluate b when a is false.
+func (r Reflect) Explain(e *Expr, root any, params map[string]any) (*Explanation, error) {
+	env := reflectEnv{
+		root:   reflect.ValueOf(root),
+		params: make(map[string]any, len(params)),
+	}
+	for name, value := range params {
+		env.params[strings.ToLower(name)] = value
+	}
+	return r.explain(env, e, e.Last())
+}
+
+// Explains the chain from the first to the last expression.
+func (r Reflect) explain(env reflectEnv, first *Expr, last *Expr) (*Explanation, error) {
+	x := &Explanation{Expr: first, Last: last}
+	op, operands := logicOperands(first, last)
+	if op == "" {
+		chain := first
+		if last.Next != nil {
+			chain = x.prefix()
+		}
+		result, err := r.run(env, chain)
+		if err != nil {
+			return nil, err
+		}
+		b, ok := result.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s returned %v (%T) instead of a boolean", x.String(), result, result)
+		}
+		x.Result = b
+		return x, nil
+	}
+
+	x.Operation = op
+	x.Result = op == LogicAnd
+	for _, operand := range operands {
+		cause, err := r.explain(env, operand.first, operand.last)
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case LogicNot:
+			x.Result = !cause.Result
+			x.Causes = []*Explanation{cause}
+			return x, nil
+		case LogicAnd:
+			if !cause.Result {
+				x.Result = false
+				x.Causes = []*Explanation{cause}
+				return x, nil
+			}
+		case LogicOr:
+			if cause.Result {
+				x.Result = true
+				x.Causes = []*Explanation{cause}
+				return x, nil
+			}
+		}
+		x.Causes = append(x.Causes, cause)
+	}
+	return x, nil
+}
+
+// Returns a copy of the chain from Expr to Last which ends at Last.
+func (x *Explanation) prefix() *Expr {
+	return chainPrefix(x.Expr, x.Last)
+}
+
+// A part of a chain from the first to the last expression.
+type chainPart struct {
+	first, last *Expr
+}
+
+// Returns the logic operation of the last expression and its operands, which are the chain before
+// it and its arguments. Returns an empty operation when the last expression is not a logic value.
+func logicOperands(first *Expr, last *Expr) (LogicOperation, []chainPart) {
+	if last.Value == nil || last.Prev == nil {
+		return "", nil
+	}
+	op, isLogic := last.Value.LogicOperation()
+	if !isLogic {
+		return "", nil
+	}
+	operands := make([]chainPart, 0, len(last.Arguments)+1)
+	operands = append(operands, chainPart{first, last.Prev})
+	for _, arg := range last.Arguments {
+		operands = append(operands, chainPart{arg, arg.Last()})
+	}
+	return op, operands
+}
+
+// Returns a copy of the chain from the first expression which ends at the last.
+func chainPrefix(first *Expr, last *Expr) *Expr {
+	clone := first.Clone()
+	for c, o := clone, first; c != nil; c, o = c.Next, o.Next {
+		if o == last {
+			c.Next = nil
+		}
+	}
+	return clone
+}
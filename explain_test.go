@@ -0,0 +1,80 @@
+package texpr
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type explainUser struct {
+	Age    int
+	Hour   int
+	Active bool
+}
+
+func TestExplain(t *testing.T) {
+	r, err := NewReflect(ReflectOptions{
+		BoolType: "bool",
+		Types: map[reflect.Type]Type{
+			TypeOf[bool](): {Name: "bool", Comparable: true, Values: LogicValues("bool")},
+			TypeOf[int](): {
+				Name:       "int",
+				Comparable: true,
+				Ordered:    true,
+				Parse: func(x string) (any, error) {
+					return strconv.Atoi(x)
+				},
+			},
+			TypeOf[explainUser](): {},
+		},
+	})
+	assert.NoError(t, err)
+	root := NameOf[explainUser]()
+
+	tests := []struct {
+		expression string
+		user       explainUser
+		result     bool
+		reasons    []string
+	}{
+		{expression: "age.>=(18).and(hour.>(12), active)", user: explainUser{Age: 20, Hour: 9, Active: true}, result: false, reasons: []string{"hour>('12')"}},
+		{expression: "age.>=(18).and(hour.>(12), active)", user: explainUser{Age: 20, Hour: 13, Active: true}, result: true, reasons: []string{"age>=('18')", "hour>('12')", "active"}},
+		{expression: "age.<(13).or(active.not)", user: explainUser{Age: 20, Active: false}, result: true, reasons: []string{"active"}},
+		{expression: "age.<(13).or(active.not)", user: explainUser{Age: 20, Active: true}, result: false, reasons: []string{"age<('13')", "active"}},
+		{expression: "active.not.=(hour.>(12))", user: explainUser{Hour: 13}, result: true, reasons: []string{"active.not=(hour>('12'))"}},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := r.Parse(Options{RootType: root, Expression: test.expression})
+			assert.NoError(t, err)
+			result, err := r.Evaluate(e, test.user)
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+
+			x, err := r.Explain(e, test.user, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, x.Result)
+			reasons := make([]string, 0)
+			for _, reason := range x.Reasons() {
+				reasons = append(reasons, reason.String())
+			}
+			assert.Equal(t, test.reasons, reasons)
+		})
+	}
+
+	e, err := r.Parse(Options{RootType: root, Expression: "active.and(hour.<(6)).not"})
+	assert.NoError(t, err)
+	x, err := r.Explain(e, explainUser{Active: true, Hour: 3}, nil)
+	assert.NoError(t, err)
+	assert.False(t, x.Result)
+	assert.Equal(t, LogicNot, x.Operation)
+	assert.Equal(t, LogicAnd, x.Causes[0].Operation)
+	assert.Equal(t, "active.and(hour<('6'))", x.Causes[0].String())
+	assert.Same(t, e.Next, x.Causes[0].Last)
+
+	result, err := LogicAnd.Apply(true, []any{1})
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "logic operation and expects a boolean but was given int")
+}
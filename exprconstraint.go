@@ -0,0 +1,74 @@
+package texpr
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// An expression did not meet the constraints in Options.Constraints.
+var ErrExprConstraint = errors.New("expression constraint violated")
+
+// What a parsed expression must reference, so hosts can require saved rules to be data driven. The
+// constraints are checked after an expression is linked without errors, see Options.Constraints.
+type ExprConstraints struct {
+	// If the expression must reference at least one value on the root type, like user in user.name.
+	RequireRootValue bool
+	// If the expression must not be constant-only, it must reference a value on the root type or a bind
+	// parameter so its result is not the same every time it's evaluated.
+	NoConstantOnly bool
+	// The values the expression must use keyed by the name of the type they're on. Values are given by
+	// path or alias and are case insensitive.
+	RequireValues map[TypeName][]string
+}
+
+// A value the expression must use, resolved from ExprConstraints.RequireValues.
+type requiredValue struct {
+	t *Type
+	v *Value
+}
+
+// Returns the values the constraints require ordered by type name, or an error if a type or value does not exist.
+func (sys System) requiredValues(c ExprConstraints) ([]requiredValue, error) {
+	typeNames := make([]TypeName, 0, len(c.RequireValues))
+	for typeName := range c.RequireValues {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Slice(typeNames, func(i, j int) bool {
+		return typeNames[i] < typeNames[j]
+	})
+	required := make([]requiredValue, 0)
+	for _, typeName := range typeNames {
+		paths := c.RequireValues[typeName]
+		t := sys.resolveType(typeName)
+		if t == nil {
+			return nil, NewParseErrorKind(nil, ErrUnknownType, fmt.Sprintf("undefined type %s for required values", typeName))
+		}
+		for _, path := range paths {
+			v := t.Value(path)
+			if v == nil {
+				return nil, NewParseErrorKind(nil, ErrUnknownValue, fmt.Sprintf("undefined required value %s.%s", typeName, path))
+			}
+			required = append(required, requiredValue{t: t, v: v})
+		}
+	}
+	return required, nil
+}
+
+// Returns the errors for the constraints of the context the linked expression does not meet.
+func (ctx *linkContext) checkConstraints(e *Expr) []ParseError {
+	errs := make([]ParseError, 0)
+	rootValues := len(e.RootDependencies()) > 0
+	if ctx.constraints.RequireRootValue && !rootValues {
+		errs = append(errs, NewParseErrorKind(e, ErrExprConstraint, fmt.Sprintf("expression must reference at least one %s value", ctx.root.Name)))
+	}
+	if ctx.constraints.NoConstantOnly && !rootValues && len(e.Binds()) == 0 {
+		errs = append(errs, NewParseErrorKind(e, ErrExprConstraint, "expression must not be constant-only, it must reference a value or parameter"))
+	}
+	for _, required := range ctx.required {
+		if !e.Uses(required.v) {
+			errs = append(errs, NewParseErrorKind(e, ErrExprConstraint, fmt.Sprintf("expression must use %s.%s", required.t.Name, required.v.Path)))
+		}
+	}
+	return errs
+}
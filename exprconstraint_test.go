@@ -0,0 +1,55 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExprConstraints(t *testing.T) {
+	parameters := map[string]TypeName{"name": typeText}
+	tests := []struct {
+		name        string
+		expression  string
+		constraints ExprConstraints
+		err         string
+	}{
+		{name: "root value", expression: "user.name.isUpper", constraints: ExprConstraints{RequireRootValue: true}},
+		{name: "root value in argument", expression: "'a'.contains(user.name)", constraints: ExprConstraints{RequireRootValue: true}},
+		{name: "no root value", expression: "'a'.isUpper", constraints: ExprConstraints{RequireRootValue: true}, err: "expression must reference at least one context value"},
+		{name: "parameter is not a root value", expression: ":name.isUpper", constraints: ExprConstraints{RequireRootValue: true}, err: "expression must reference at least one context value"},
+		{name: "parameter is not constant-only", expression: ":name.isUpper", constraints: ExprConstraints{NoConstantOnly: true}},
+		{name: "root value is not constant-only", expression: "time.today", constraints: ExprConstraints{NoConstantOnly: true}},
+		{name: "constant-only", expression: "'a'.upper.contains('A')", constraints: ExprConstraints{NoConstantOnly: true}, err: "expression must not be constant-only, it must reference a value or parameter"},
+		{name: "required value", expression: "user.name.isUpper", constraints: ExprConstraints{RequireValues: map[TypeName][]string{typeUser: {"NAME"}}}},
+		{name: "required value in argument", expression: "'a'.contains(user.name)", constraints: ExprConstraints{RequireValues: map[TypeName][]string{typeUser: {"name"}}}},
+		{name: "required alias", expression: "user.createDate.min", constraints: ExprConstraints{RequireValues: map[TypeName][]string{typeDateTime: {"minute"}}}},
+		{name: "missing required value", expression: "user.name.isUpper", constraints: ExprConstraints{RequireValues: map[TypeName][]string{typeUser: {"createDate"}}}, err: "expression must use user.createDate"},
+		{name: "every constraint", expression: "'a'.isUpper", constraints: ExprConstraints{RequireRootValue: true, NoConstantOnly: true, RequireValues: map[TypeName][]string{typeUser: {"name"}}}, err: "expression must reference at least one context value\nexpression must not be constant-only, it must reference a value or parameter\nexpression must use user.name"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := sys.Parse(Options{RootType: typeContext, Expression: test.expression, Parameters: parameters, Constraints: test.constraints})
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+
+	_, err := sys.Parse(Options{RootType: typeContext, Expression: "'a'", Constraints: ExprConstraints{NoConstantOnly: true}})
+	assert.ErrorIs(t, err, ErrExprConstraint)
+
+	// Constraints are only checked when the expression has no other errors.
+	_, err = sys.Parse(Options{RootType: typeContext, Expression: "usr.name", Constraints: ExprConstraints{RequireRootValue: true}})
+	assert.ErrorIs(t, err, ErrUnknownValue)
+	assert.NotErrorIs(t, err, ErrExprConstraint)
+
+	_, err = sys.Parse(Options{RootType: typeContext, Expression: "user", Constraints: ExprConstraints{RequireValues: map[TypeName][]string{typeUser: {"age"}}}})
+	assert.ErrorIs(t, err, ErrUnknownValue)
+	assert.EqualError(t, err, "undefined required value user.age")
+
+	_, err = sys.Parse(Options{RootType: typeContext, Expression: "user", Constraints: ExprConstraints{RequireValues: map[TypeName][]string{"missing": {"age"}}}})
+	assert.ErrorIs(t, err, ErrUnknownType)
+}
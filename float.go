@@ -0,0 +1,116 @@
+package texpr
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// The epsilon used by FloatType when the options have none.
+const DefaultEpsilon = 1e-9
+
+// A floating point number whose equality and comparisons allow for rounding error, see FloatType.
+// The methods are the values of the type when used with Reflect.
+type Float float64
+
+// Returns whether the numbers are within the epsilon of each other, see FloatOperations.
+func (f Float) Near(other Float, epsilon Float) bool {
+	return floatNear(float64(f), float64(other), float64(epsilon))
+}
+
+// Returns the absolute value of the number.
+func (f Float) Abs() Float {
+	return Float(math.Abs(float64(f)))
+}
+
+// Returns the greatest integer less than or equal to the number.
+func (f Float) Floor() Float {
+	return Float(math.Floor(float64(f)))
+}
+
+// Returns the least integer greater than or equal to the number.
+func (f Float) Ceil() Float {
+	return Float(math.Ceil(float64(f)))
+}
+
+// Returns the nearest integer to the number, rounding half away from zero.
+func (f Float) Round() Float {
+	return Float(math.Round(float64(f)))
+}
+
+// The options of the float type, see FloatType.
+type FloatOptions struct {
+	// The name of the type, by default "float".
+	Name TypeName
+	// The tolerance of equality and comparisons, by default DefaultEpsilon.
+	Epsilon float64
+}
+
+// Returns the definition of the Float type to give to Reflect with TypeOf[Float](). It's comparable,
+// ordered, and numeric with FloatOperations using the epsilon of the options. The `near` value compares
+// with an epsilon given to it instead.
+func FloatType(options FloatOptions) Type {
+	if options.Name == "" {
+		options.Name = "float"
+	}
+	if options.Epsilon == 0 {
+		options.Epsilon = DefaultEpsilon
+	}
+	return Type{
+		Name:        options.Name,
+		Description: "A floating point number",
+		Comparable:  true,
+		Ordered:     true,
+		Numeric:     true,
+		Operations:  FloatOperations{Epsilon: options.Epsilon},
+		Parse: func(x string) (any, error) {
+			f, err := strconv.ParseFloat(x, 64)
+			return Float(f), err
+		},
+		Values: []Value{{
+			Path:        "Near",
+			Description: "Returns whether the number differs from the value by at most the epsilon, relative to the larger number when it's over 1",
+		}},
+	}
+}
+
+// Operations on numbers where values within the epsilon of each other are equal. The epsilon is
+// absolute for numbers up to 1 and relative to the larger number otherwise, so it's meaningful for
+// small and large numbers alike. Values which are not numbers use StandardOperations.
+type FloatOperations struct {
+	Epsilon float64
+}
+
+var _ Operations = FloatOperations{}
+
+func (o FloatOperations) Equal(a, b any) (bool, error) {
+	if c, err := o.Compare(a, b); err == nil {
+		return c == 0, nil
+	}
+	return StandardOperations{}.Equal(a, b)
+}
+
+func (o FloatOperations) Compare(a, b any) (int, error) {
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !isNumber(av) || !isNumber(bv) {
+		return StandardOperations{}.Compare(a, b)
+	}
+	x, y := toFloat(av), toFloat(bv)
+	if floatNear(x, y, o.Epsilon) {
+		return 0, nil
+	}
+	return compareOrdered(x, y), nil
+}
+
+func (o FloatOperations) Arithmetic(op Operator, a, b any) (any, error) {
+	return StandardOperations{}.Arithmetic(op, a, b)
+}
+
+// Returns whether the numbers are within the epsilon of each other, relative to the larger number when it's over 1.
+func floatNear(a, b, epsilon float64) bool {
+	if a == b {
+		return true
+	}
+	scale := math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+	return math.Abs(a-b) <= epsilon*scale
+}
@@ -0,0 +1,95 @@
+package texpr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type floatReading struct {
+	Total Float
+	Large Float
+}
+
+func TestFloat(t *testing.T) {
+	newReflect := func(options FloatOptions) *Reflect {
+		r, err := NewReflect(ReflectOptions{
+			BoolType: "bool",
+			Types: map[reflect.Type]Type{
+				TypeOf[bool]():         {Name: "bool"},
+				TypeOf[Float]():        FloatType(options),
+				TypeOf[floatReading](): {},
+			},
+		})
+		assert.NoError(t, err)
+		return r
+	}
+
+	reading := floatReading{Total: 0.1 + 0.2, Large: 1e12}
+	tests := []struct {
+		options    FloatOptions
+		expression string
+		infix      bool
+		decimals   bool
+		result     any
+	}{
+		{expression: "total.=('0.3')", result: true},
+		{expression: "total.!=('0.3')", result: false},
+		{expression: "total.<=('0.3')", result: true},
+		{expression: "total.<('0.3')", result: false},
+		{expression: "total.>('0.29')", result: true},
+		{expression: "total.=('0.300001')", result: false},
+		{expression: "large.=('1000000000000.0001')", result: true},
+		{expression: "large.+(1).=(large)", result: true},
+		{expression: "large.+(10000).=(large)", result: false},
+		{options: FloatOptions{Epsilon: 1e-3}, expression: "total.=('0.3001')", result: true},
+		{options: FloatOptions{Epsilon: 1e-3}, expression: "total.=('0.302')", result: false},
+		{expression: "total.near('0.31', '0.1')", result: true},
+		{expression: "total.near('0.31', '0.001')", result: false},
+		{expression: "total.*(10).round", result: Float(3)},
+		{expression: "total.-(1).abs.floor", result: Float(0)},
+		{expression: "total.ceil", result: Float(1)},
+		{expression: "total.=(0.3)", decimals: true, result: true},
+		{expression: "large.=(1e12)", result: true},
+		{expression: "large.=(1.0E12)", result: true},
+		{expression: "large.=(-1.5e6.abs)", result: false},
+		{expression: "total.*(1e1).-(3.0e-0).abs.<(2E-3)", result: true},
+		{expression: "total.+(1_000.5).>(1.0005e+3)", decimals: true, result: true},
+		{expression: "total * 10 = 3.0", infix: true, decimals: true, result: true},
+		{expression: "large - 1.5e12 < -4.99e11", infix: true, result: true},
+		{expression: "0.25 < total", infix: true, decimals: true, result: true},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			r := newReflect(test.options)
+			opts := Options{RootType: NameOf[floatReading](), Expression: test.expression, Decimals: test.decimals}
+			if test.infix {
+				opts.Infix = DefaultPrecedence
+			}
+			e, err := r.Parse(opts)
+			if !assert.NoError(t, err) {
+				return
+			}
+			result, err := r.Evaluate(e, reading)
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+
+	r := newReflect(FloatOptions{})
+	_, err := r.Parse(Options{RootType: NameOf[floatReading](), Expression: "total.=(0.3)"})
+	assert.ErrorIs(t, err, ErrUnknownValue)
+
+	r = newReflect(FloatOptions{Name: "decimal"})
+	assert.NotNil(t, r.System().Type("decimal"))
+	assert.Equal(t, TypeName("bool"), r.System().Type("decimal").Value("near").Type)
+	assert.Len(t, r.System().Type("decimal").Value("near").Parameters, 2)
+
+	equal, err := FloatOperations{Epsilon: 0.5}.Equal(1, 1.4)
+	assert.NoError(t, err)
+	assert.True(t, equal)
+	equal, err = FloatOperations{Epsilon: 0.5}.Equal("a", "a")
+	assert.NoError(t, err)
+	assert.True(t, equal)
+}
@@ -0,0 +1,92 @@
+package texpr
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// A value which is computed asynchronously, like a lookup over the network. The fields and methods of
+// types given to Reflect can be futures of a supported type, and their values are awaited when they're
+// needed. The arguments of a value are all evaluated before any of them are awaited, so the latencies
+// of futures given to the same value overlap.
+type Future[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// Returns a future of the result of the function, which is ran in a new goroutine.
+func NewFuture[T any](fn func() (T, error)) *Future[T] {
+	return FutureOf(func(resolve func(T, error)) {
+		go func() {
+			resolve(fn())
+		}()
+	})
+}
+
+// Returns a future which is resolved when the callback given to start is called. Only the first
+// call to the callback resolves the future.
+func FutureOf[T any](start func(resolve func(T, error))) *Future[T] {
+	f := &Future[T]{done: make(chan struct{})}
+	once := sync.Once{}
+	start(func(value T, err error) {
+		once.Do(func() {
+			f.value, f.err = value, err
+			close(f.done)
+		})
+	})
+	return f
+}
+
+// Returns a future which already has the value.
+func ResolvedFuture[T any](value T) *Future[T] {
+	return FutureOf(func(resolve func(T, error)) {
+		resolve(value, nil)
+	})
+}
+
+// Waits for the future to be resolved and returns its value.
+func (f *Future[T]) Await() (T, error) {
+	<-f.done
+	return f.value, f.err
+}
+
+func (f *Future[T]) await() (any, error) {
+	return f.Await()
+}
+
+func (f *Future[T]) futureType() reflect.Type {
+	return TypeOf[T]()
+}
+
+// A future of any type.
+type awaitable interface {
+	await() (any, error)
+	futureType() reflect.Type
+}
+
+// Returns the value of the future if the given value is a future, otherwise the value is returned.
+// Evaluators built with compilers can use this to support values which return futures.
+func Await(v any) (any, error) {
+	if f, ok := v.(awaitable); ok {
+		if reflect.ValueOf(f).IsNil() {
+			return nil, fmt.Errorf("future of %v is nil", f.futureType())
+		}
+		return f.await()
+	}
+	return v, nil
+}
+
+// Returns the type of the values of a future type.
+func futureElem(rt reflect.Type) (reflect.Type, bool) {
+	if rt.Kind() == reflect.Pointer && rt.Implements(TypeOf[awaitable]()) {
+		return reflect.New(rt.Elem()).Interface().(awaitable).futureType(), true
+	}
+	return nil, false
+}
+
+// Returns whether the value is a future.
+func isFuture(v reflect.Value) bool {
+	return v.IsValid() && v.Type().Implements(TypeOf[awaitable]())
+}
@@ -0,0 +1,132 @@
+package texpr
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type futureStore struct {
+	Stock   *Future[Int]
+	pending *sync.WaitGroup
+}
+
+// Returns the price of the item, which is only resolved once every pending price was requested.
+func (s futureStore) Price(sku Int) *Future[Int] {
+	return NewFuture(func() (Int, error) {
+		s.pending.Done()
+		waited := make(chan struct{})
+		go func() {
+			s.pending.Wait()
+			close(waited)
+		}()
+		select {
+		case <-waited:
+			return sku * 10, nil
+		case <-time.After(time.Second):
+			return 0, errors.New("prices were not requested together")
+		}
+	})
+}
+
+func (s futureStore) Sum(a Int, b Int) Int {
+	return a + b
+}
+
+func (s futureStore) Missing() *Future[Int] {
+	return nil
+}
+
+func TestFuture(t *testing.T) {
+	r, err := NewReflect(ReflectOptions{
+		Types: map[reflect.Type]Type{
+			TypeOf[Int](): {Parse: func(x string) (any, error) {
+				i, err := strconv.Atoi(x)
+				return Int(i), err
+			}},
+			TypeOf[futureStore](): {},
+		},
+	})
+	assert.NoError(t, err)
+
+	store := r.System().Type(NameOf[futureStore]())
+	assert.NotNil(t, store)
+	assert.Equal(t, NameOf[Int](), store.Value("stock").Type)
+	assert.Equal(t, NameOf[Int](), store.Value("price").Type)
+
+	tests := []struct {
+		expression string
+		root       futureStore
+		result     any
+		err        string
+	}{
+		{
+			expression: "sum(price(1), price(2))",
+			root:       futureStore{pending: waitGroup(2)},
+			result:     Int(30),
+		},
+		{
+			expression: "price(4)",
+			root:       futureStore{pending: waitGroup(1)},
+			result:     Int(40),
+		},
+		{
+			expression: "stock",
+			root: futureStore{Stock: FutureOf(func(resolve func(Int, error)) {
+				time.AfterFunc(time.Millisecond, func() {
+					resolve(5, nil)
+					resolve(6, nil)
+				})
+			})},
+			result: Int(5),
+		},
+		{
+			expression: "sum(stock, 3)",
+			root:       futureStore{Stock: ResolvedFuture[Int](2)},
+			result:     Int(5),
+		},
+		{
+			expression: "sum(missing, 3)",
+			err:        "future of texpr.Int is nil",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := r.Parse(Options{RootType: NameOf[futureStore](), Expression: test.expression})
+			assert.NoError(t, err)
+
+			result, err := r.Evaluate(e, test.root)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.result, result)
+			}
+		})
+	}
+}
+
+func TestAwait(t *testing.T) {
+	value, err := Await(Int(3))
+	assert.NoError(t, err)
+	assert.Equal(t, Int(3), value)
+
+	value, err = Await(NewFuture(func() (string, error) { return "done", nil }))
+	assert.NoError(t, err)
+	assert.Equal(t, "done", value)
+
+	_, err = Await(NewFuture(func() (string, error) { return "", errors.New("failed") }))
+	assert.EqualError(t, err, "failed")
+}
+
+func waitGroup(n int) *sync.WaitGroup {
+	wg := &sync.WaitGroup{}
+	wg.Add(n)
+	return wg
+}
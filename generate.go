@@ -0,0 +1,285 @@
+package texpr
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// The options of System.GenerateBuilders.
+type GenerateOptions struct {
+	// The package of the generated code.
+	Package string
+	// The types which get a constructor to start an expression on, by default every type.
+	Roots []TypeName
+}
+
+// The names of the methods generated for operators.
+var generatedOperatorNames = map[string]string{
+	"=":  "Equals",
+	"!=": "NotEquals",
+	"<":  "Less",
+	"<=": "LessEqual",
+	">":  "Greater",
+	">=": "GreaterEqual",
+	"+":  "Add",
+	"-":  "Subtract",
+	"*":  "Multiply",
+	"/":  "Divide",
+}
+
+// Generates Go source with a typed builder for each type of the system, so expressions composed in Go
+// have their paths and parameter types checked by the compiler. For a type user with a value name of
+// type text it generates:
+//
+//	type UserExpr struct{ b *texpr.Builder }
+//	func NewUser(b *texpr.Builder) UserExpr
+//	func UserConst(token string) UserExpr
+//	func UserParam(name string) UserExpr
+//	func (e UserExpr) Name() TextExpr
+//	func (e UserExpr) Expr() (*texpr.Expr, error)
+//
+// Values are methods which take their parameters as the builders of the parameter types, where variadic
+// parameters are variadic. Generic values and values whose names can't be made into Go identifiers are
+// skipped. List types (see ListTypeName) the values refer to get builders too, and when two types would
+// have the same Go identifier an error is returned. Like Builder, each builder is meant to be used once. The source is usually written by a
+// program ran with go:generate which builds the system and writes the result to a file.
+func (sys System) GenerateBuilders(options GenerateOptions) ([]byte, error) {
+	if options.Package == "" {
+		return nil, fmt.Errorf("a package is required to generate builders")
+	}
+	types, err := sys.generatedTypes()
+	if err != nil {
+		return nil, err
+	}
+	roots := make(map[TypeName]bool)
+	for _, root := range options.Roots {
+		if sys.resolveType(root) == nil {
+			return nil, fmt.Errorf("undefined root type: %s", root)
+		}
+		roots[sys.resolveType(root).Name] = true
+	}
+
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "// Code generated by texpr. DO NOT EDIT.\n\npackage %s\n\n", options.Package)
+	fmt.Fprintf(out, "import \"github.com/ClickerMonkey/texpr\"\n")
+	for _, t := range types {
+		name := generatedIdentifier(string(t.Name))
+		expr := name + "Expr"
+		fmt.Fprintf(out, "\n// Builds an expression whose result is a %s.\ntype %s struct {\n\tb *texpr.Builder\n}\n", t.Name, expr)
+		if len(options.Roots) == 0 || roots[t.Name] {
+			fmt.Fprintf(out, "\n// Starts an expression on the %s root type with the builder.\nfunc New%s(b *texpr.Builder) %s {\n\treturn %s{b.Root(%q)}\n}\n", t.Name, name, expr, expr, t.Name)
+		}
+		if t.parses() {
+			fmt.Fprintf(out, "\n// Returns a %s constant argument.\nfunc %sConst(token string) %s {\n\treturn %s{texpr.Const(token)}\n}\n", t.Name, name, expr, expr)
+		}
+		fmt.Fprintf(out, "\n// Returns a %s bind parameter argument.\nfunc %sParam(name string) %s {\n\treturn %s{texpr.Param(name)}\n}\n", t.Name, name, expr, expr)
+		fmt.Fprintf(out, "\n// Returns the built expression, or the error of the first step which failed.\nfunc (e %s) Expr() (*texpr.Expr, error) {\n\treturn e.b.Expr()\n}\n", expr)
+
+		methods := map[string]bool{"Expr": true}
+		for i := range t.Values {
+			generateBuilderValue(out, expr, &t.Values[i], methods)
+		}
+	}
+
+	return format.Source([]byte(out.String()))
+}
+
+// Generates Go source with constants for the type names, value paths, and enum members of the system,
+// so host code can refer to them without string literals. For a type user with a value name and a type
+// role with the enum admin it generates:
+//
+//	TypeUser texpr.TypeName = "user"
+//	UserName                = "name"
+//	RoleEnumAdmin           = "admin"
+//
+// Operators are named like the methods of GenerateBuilders (ex: IntGreater for >). Names which can't be
+// made into Go identifiers and names already generated are skipped, and types are the types
+// GenerateBuilders generates builders for. The Roots option is not used.
+func (sys System) GenerateConstants(options GenerateOptions) ([]byte, error) {
+	if options.Package == "" {
+		return nil, fmt.Errorf("a package is required to generate constants")
+	}
+	types, err := sys.generatedTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	constant := func(out *strings.Builder, name string, value string) {
+		if !names[name] {
+			names[name] = true
+			fmt.Fprintf(out, "\t%s = %q\n", name, value)
+		}
+	}
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "// Code generated by texpr. DO NOT EDIT.\n\npackage %s\n\n", options.Package)
+	fmt.Fprintf(out, "import \"github.com/ClickerMonkey/texpr\"\n\n// The names of the types.\nconst (\n")
+	for _, t := range types {
+		name := "Type" + generatedIdentifier(string(t.Name))
+		if !names[name] {
+			names[name] = true
+			fmt.Fprintf(out, "\t%s texpr.TypeName = %q\n", name, t.Name)
+		}
+	}
+	fmt.Fprintf(out, ")\n")
+	for _, t := range types {
+		name := generatedIdentifier(string(t.Name))
+		if len(t.Values) > 0 {
+			fmt.Fprintf(out, "\n// The paths of the values of %s.\nconst (\n", t.Name)
+			for _, v := range t.Values {
+				path := generatedOperatorNames[v.Path]
+				if path == "" {
+					path = generatedIdentifier(v.Path)
+				}
+				if path != "" {
+					constant(out, name+path, v.Path)
+				}
+			}
+			fmt.Fprintf(out, ")\n")
+		}
+		if len(t.Enums) > 0 {
+			fmt.Fprintf(out, "\n// The enum members of %s.\nconst (\n", t.Name)
+			for _, enum := range t.Enums {
+				if member := generatedIdentifier(enum); member != "" {
+					constant(out, name+"Enum"+member, enum)
+				}
+			}
+			fmt.Fprintf(out, ")\n")
+		}
+	}
+
+	return format.Source([]byte(out.String()))
+}
+
+// Returns the types code is generated for sorted by name, which are the types of the system and the types
+// their values and parameters refer to with names that can be made into Go identifiers. Returns an error
+// if two of the types have the same identifier.
+func (sys System) generatedTypes() ([]*Type, error) {
+	types := make([]*Type, 0, len(sys.types))
+	identifiers := make(map[string]TypeName)
+	var add func(t *Type) error
+	add = func(t *Type) error {
+		if t == nil || t.Name == "" {
+			return nil
+		}
+		identifier := generatedIdentifier(string(t.Name))
+		if identifier == "" {
+			return nil
+		}
+		if other, exists := identifiers[identifier]; exists {
+			if other == t.Name {
+				return nil
+			}
+			return fmt.Errorf("types %s and %s both generate the identifier %s", other, t.Name, identifier)
+		}
+		identifiers[identifier] = t.Name
+		types = append(types, t)
+		for i := range t.Values {
+			v := &t.Values[i]
+			if err := add(v.ValueType()); err != nil {
+				return err
+			}
+			for _, p := range v.Parameters {
+				if err := add(p.parameterType); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	for _, t := range sys.types {
+		if err := add(t); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Name < types[j].Name
+	})
+	return types, nil
+}
+
+// Writes the builder method of the value, unless it can't be generated or its name is already used.
+func generateBuilderValue(out *strings.Builder, expr string, v *Value, methods map[string]bool) {
+	method := generatedOperatorNames[v.Path]
+	if method == "" {
+		method = generatedIdentifier(v.Path)
+	}
+	if method == "" || methods[method] || v.Generic || v.ValueType() == nil || generatedIdentifier(string(v.ValueType().Name)) == "" {
+		return
+	}
+	params := make([]string, len(v.Parameters))
+	for i, p := range v.Parameters {
+		if p.Generic || p.parameterType == nil || generatedIdentifier(string(p.parameterType.Name)) == "" || generatedIdentifier(p.Name) == "" {
+			return
+		}
+		params[i] = fmt.Sprintf("%s %s%sExpr", generatedParameter(p.Name), generatedVariadic(v, i), generatedIdentifier(string(p.parameterType.Name)))
+	}
+	methods[method] = true
+
+	result := generatedIdentifier(string(v.ValueType().Name)) + "Expr"
+	description, _, _ := strings.Cut(strings.TrimSpace(v.Description), "\n")
+	if description == "" {
+		description = fmt.Sprintf("Adds the %s value to the expression.", v.Path)
+	}
+	fmt.Fprintf(out, "\n// %s\nfunc (e %s) %s(%s) %s {\n", description, expr, method, strings.Join(params, ", "), result)
+	args := make([]string, 0, len(v.Parameters))
+	for i, p := range v.Parameters {
+		name := generatedParameter(p.Name)
+		if generatedVariadic(v, i) != "" {
+			if len(args) > 0 {
+				fmt.Fprintf(out, "\targs := append(make([]*texpr.Builder, 0, %d+len(%s)), %s)\n", len(args), name, strings.Join(args, ", "))
+			} else {
+				fmt.Fprintf(out, "\targs := make([]*texpr.Builder, 0, len(%s))\n", name)
+			}
+			fmt.Fprintf(out, "\tfor _, arg := range %s {\n\t\targs = append(args, arg.b)\n\t}\n", name)
+			fmt.Fprintf(out, "\treturn %s{e.b.Call(%q, args...)}\n}\n", result, v.Path)
+			return
+		}
+		args = append(args, name+".b")
+	}
+	fmt.Fprintf(out, "\treturn %s{e.b.Call(%q%s)}\n}\n", result, v.Path, strings.Join(append([]string{""}, args...), ", "))
+}
+
+// Returns "..." if the parameter at the index is the variadic parameter of the value.
+func generatedVariadic(v *Value, i int) string {
+	if v.Variadic && i == len(v.Parameters)-1 {
+		return "..."
+	}
+	return ""
+}
+
+// Returns the name of a parameter which doesn't conflict with the receiver, the generated variables, or Go keywords.
+func generatedParameter(name string) string {
+	identifier := generatedIdentifier(name)
+	parameter := strings.ToLower(identifier[:1]) + identifier[1:]
+	if token.IsKeyword(parameter) || parameter == "e" || parameter == "args" || parameter == "arg" {
+		parameter += "Value"
+	}
+	return parameter
+}
+
+// Returns the name as an exported Go identifier, like dateTime as DateTime and day_of_week as DayOfWeek.
+// Returns an empty string if the name has no letters or digits.
+func generatedIdentifier(name string) string {
+	out := strings.Builder{}
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if out.Len() == 0 && unicode.IsDigit(r) {
+			out.WriteString("V")
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
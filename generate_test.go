@@ -0,0 +1,104 @@
+package texpr
+
+import (
+	"go/ast"
+	"go/importer"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Imports this package from source for type checking generated code, shared so it's only checked once.
+var generatedImporter = importer.ForCompiler(token.NewFileSet(), "source", nil)
+
+// Returns an error if the generated source does not type check against this package.
+func typeCheckGenerated(source []byte) error {
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, "generated.go", source, 0)
+	if err != nil {
+		return err
+	}
+	config := types.Config{Importer: generatedImporter}
+	_, err = config.Check("exprs", fset, []*ast.File{file}, nil)
+	return err
+}
+
+func TestGenerateBuilders(t *testing.T) {
+	source, err := sys.GenerateBuilders(GenerateOptions{Package: "exprs", Roots: []TypeName{typeContext}})
+	assert.NoError(t, err)
+	assert.NoError(t, typeCheckGenerated(source))
+
+	code := string(source)
+	for _, expected := range []string{
+		"// Code generated by texpr. DO NOT EDIT.\n\npackage exprs\n",
+		"func NewContext(b *texpr.Builder) ContextExpr {\n\treturn ContextExpr{b.Root(\"context\")}\n}",
+		"func (e UserExpr) Name() TextExpr {\n\treturn TextExpr{e.b.Call(\"name\")}\n}",
+		"func (e TextExpr) Contains(value TextExpr) BoolExpr {\n\treturn BoolExpr{e.b.Call(\"contains\", value.b)}\n}",
+		"func (e IntExpr) Greater(value IntExpr) BoolExpr {",
+		"func (e BoolExpr) And(values ...BoolExpr) BoolExpr {\n\targs := make([]*texpr.Builder, 0, len(values))",
+		"func TextConst(token string) TextExpr {\n\treturn TextExpr{texpr.Const(token)}\n}",
+		"func UserParam(name string) UserExpr {",
+		"// An unambiguous way to refer to Sunday\nfunc (e TimePackageExpr) Sunday() DayOfWeekExpr {",
+	} {
+		assert.Contains(t, code, expected)
+	}
+	assert.NotContains(t, code, "func NewUser(")
+	assert.NotContains(t, code, "func UserConst(")
+	assert.NotContains(t, code, ") Then(")
+
+	_, err = sys.GenerateBuilders(GenerateOptions{})
+	assert.EqualError(t, err, "a package is required to generate builders")
+	_, err = sys.GenerateBuilders(GenerateOptions{Package: "exprs", Roots: []TypeName{"nope"}})
+	assert.EqualError(t, err, "undefined root type: nope")
+
+	lists := NewSystemRequired([]Type{{
+		Name:  "text",
+		Parse: func(x string) (any, error) { return x, nil },
+	}, {
+		Name: "order",
+		Values: []Value{
+			{Path: "tags", Type: ListTypeName("text")},
+		},
+	}})
+	source, err = lists.GenerateBuilders(GenerateOptions{Package: "exprs"})
+	assert.NoError(t, err)
+	assert.NoError(t, typeCheckGenerated(source))
+	assert.Contains(t, string(source), "func (e OrderExpr) Tags() ListTextExpr {")
+	assert.Contains(t, string(source), "func (e ListTextExpr) First() TextExpr {")
+
+	colliding := NewSystemRequired([]Type{
+		{Name: "day_of_week"},
+		{Name: "dayOfWeek"},
+	})
+	_, err = colliding.GenerateBuilders(GenerateOptions{Package: "exprs"})
+	assert.EqualError(t, err, "types day_of_week and dayOfWeek both generate the identifier DayOfWeek")
+
+	assert.Equal(t, "DayOfWeek", generatedIdentifier("day_of_week"))
+	assert.Equal(t, "V2fa", generatedIdentifier("2fa"))
+	assert.Equal(t, "typeValue", generatedParameter("type"))
+}
+
+func TestGenerateConstants(t *testing.T) {
+	source, err := sys.GenerateConstants(GenerateOptions{Package: "exprs"})
+	assert.NoError(t, err)
+	assert.NoError(t, typeCheckGenerated(source))
+
+	code := string(source)
+	for _, expected := range []string{
+		"// Code generated by texpr. DO NOT EDIT.\n\npackage exprs\n",
+		"\tTypeContext     texpr.TypeName = \"context\"\n",
+		"\tTypeDayOfWeek   texpr.TypeName = \"dayOfWeek\"\n",
+		"// The paths of the values of user.\nconst (\n\tUserName       = \"name\"\n\tUserCreateDate = \"createDate\"\n)",
+		"\tIntGreater ",
+		"\tTextLength ",
+		"\tBoolEnumTrue  = \"true\"\n",
+	} {
+		assert.Contains(t, code, expected)
+	}
+
+	_, err = sys.GenerateConstants(GenerateOptions{})
+	assert.EqualError(t, err, "a package is required to generate constants")
+}
@@ -0,0 +1,653 @@
+package texpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The options used to build types from GraphQL type definitions, see GraphQLTypes.
+type GraphQLOptions struct {
+	// The types used for scalars (ex: "Int", "DateTime") which are given to the system separately.
+	// Built-in scalars which are not mapped are generated with a parse function, and custom scalars
+	// which are not mapped are generated as strings.
+	Scalars map[string]TypeName
+}
+
+// The parse functions and parse orders of the built-in scalars a type is generated for.
+var graphQLScalars = map[string]Type{
+	"Int": {Parse: func(x string) (any, error) {
+		i, err := strconv.ParseInt(x, 10, 32)
+		return int32(i), err
+	}},
+	"Float": {ParseOrder: -1, Parse: func(x string) (any, error) {
+		return strconv.ParseFloat(x, 64)
+	}},
+	"Boolean": {Parse: func(x string) (any, error) {
+		return strconv.ParseBool(x)
+	}},
+	"String": {ParseOrder: -2, Parse: func(x string) (any, error) {
+		return x, nil
+	}},
+	"ID": {ParseOrder: -2, Parse: func(x string) (any, error) {
+		return x, nil
+	}},
+}
+
+// A reference to a type in a GraphQL definition, ex: [String!]!
+type graphQLTypeRef struct {
+	name    string
+	list    *graphQLTypeRef
+	nonNull bool
+}
+
+// An argument or field of a GraphQL definition.
+type graphQLField struct {
+	name         string
+	description  string
+	ref          graphQLTypeRef
+	defaultValue *string
+	deprecated   bool
+	arguments    []graphQLField
+}
+
+// A type, interface, input, enum, scalar, or union definition.
+type graphQLDefinition struct {
+	kind        string
+	name        string
+	description string
+	fields      []graphQLField
+}
+
+// The tokens of a GraphQL document and the position being parsed.
+type graphQLParser struct {
+	tokens []graphQLToken
+	index  int
+}
+
+// A name, punctuator, string, or number in a GraphQL document.
+type graphQLToken struct {
+	text   string
+	str    bool
+	line   int
+	column int
+}
+
+// Returns the types in the GraphQL type definitions (SDL). Object types, interfaces, and input types
+// are types with a value for each field, fields with arguments are values with parameters, enums are
+// types with Enums, lists are list types (see System.ListOf), and scalars are types with parse functions.
+// Unions are types with a Nullable value for each member type, like an inline fragment (... on Member).
+// Fields without a non-null wrapper (!) are Nullable values, and arguments with a default value are
+// optional. Descriptions and @deprecated are kept on values and enum options, type extensions are
+// merged into the type they extend, and schema and directive definitions are skipped.
+func GraphQLTypes(document string, options GraphQLOptions) ([]Type, error) {
+	tokens, err := graphQLTokenize(document)
+	if err != nil {
+		return nil, err
+	}
+	p := &graphQLParser{tokens: tokens}
+	definitions, err := p.document()
+	if err != nil {
+		return nil, err
+	}
+
+	types := make([]Type, 0, len(definitions))
+	declared := make(map[string]bool)
+	for _, d := range definitions {
+		declared[d.name] = true
+	}
+	generated := make(map[TypeName]bool)
+	typeOf := func(ref graphQLTypeRef) (TypeName, error) {
+		var resolve func(r graphQLTypeRef) (TypeName, error)
+		resolve = func(r graphQLTypeRef) (TypeName, error) {
+			if r.list != nil {
+				element, err := resolve(*r.list)
+				return ListTypeName(element), err
+			}
+			if mapped := options.Scalars[r.name]; mapped != "" {
+				return mapped, nil
+			}
+			if scalar, builtIn := graphQLScalars[r.name]; builtIn {
+				name := TypeName(r.name)
+				if !generated[name] {
+					generated[name] = true
+					scalar.Name = name
+					scalar.Description = fmt.Sprintf("A GraphQL %s", r.name)
+					types = append(types, scalar)
+				}
+				return name, nil
+			}
+			if !declared[r.name] {
+				return "", fmt.Errorf("type %s is not defined", r.name)
+			}
+			return TypeName(r.name), nil
+		}
+		return resolve(ref)
+	}
+
+	for _, d := range definitions {
+		name := TypeName(d.name)
+		switch d.kind {
+		case "scalar":
+			if options.Scalars[d.name] == "" && graphQLScalars[d.name].Parse == nil {
+				types = append(types, Type{
+					Name:        name,
+					Description: d.description,
+					ParseOrder:  -2,
+					Parse: func(x string) (any, error) {
+						return x, nil
+					},
+				})
+			}
+		case "enum":
+			t := Type{Name: name, Description: d.description, Enums: make([]string, len(d.fields))}
+			for i, f := range d.fields {
+				t.Enums[i] = f.name
+				if f.description != "" || f.deprecated {
+					if t.EnumOptions == nil {
+						t.EnumOptions = make(map[string]EnumOption)
+					}
+					t.EnumOptions[f.name] = EnumOption{Description: f.description, Deprecated: f.deprecated}
+				}
+			}
+			types = append(types, t)
+		case "union":
+			t := Type{Name: name, Description: d.description, Values: make([]Value, len(d.fields))}
+			for i, f := range d.fields {
+				memberType, err := typeOf(graphQLTypeRef{name: f.name})
+				if err != nil {
+					return nil, fmt.Errorf("union %s: %w", d.name, err)
+				}
+				t.Values[i] = Value{
+					Path:        f.name,
+					Type:        memberType,
+					Description: fmt.Sprintf("The %s when it's a %s", d.name, f.name),
+					Nullable:    true,
+				}
+			}
+			types = append(types, t)
+		case "type", "interface", "input":
+			t := Type{Name: name, Description: d.description, Values: make([]Value, len(d.fields))}
+			for i, f := range d.fields {
+				fieldType, err := typeOf(f.ref)
+				if err != nil {
+					return nil, fmt.Errorf("field %s.%s: %w", d.name, f.name, err)
+				}
+				v := Value{Path: f.name, Type: fieldType, Description: f.description, Nullable: !f.ref.nonNull}
+				for _, a := range f.arguments {
+					argumentType, err := typeOf(a.ref)
+					if err != nil {
+						return nil, fmt.Errorf("argument %s.%s(%s): %w", d.name, f.name, a.name, err)
+					}
+					v.Parameters = append(v.Parameters, Parameter{
+						Name:        a.name,
+						Type:        argumentType,
+						Description: a.description,
+						Default:     a.defaultValue,
+					})
+				}
+				t.Values[i] = v
+			}
+			types = append(types, t)
+		}
+	}
+	return types, nil
+}
+
+// Returns the definitions in the document, merging extensions into the definitions they extend.
+func (p *graphQLParser) document() ([]graphQLDefinition, error) {
+	definitions := make([]graphQLDefinition, 0)
+	indices := make(map[string]int)
+	extensions := make([]graphQLDefinition, 0)
+
+	for !p.done() {
+		description := p.description()
+		extend := p.accept("extend")
+		if p.done() {
+			return nil, fmt.Errorf("expected a definition at the end of the document")
+		}
+		keyword := p.next()
+		switch keyword.text {
+		case "type", "interface", "input", "enum", "scalar", "union":
+			d, err := p.definition(keyword.text)
+			if err != nil {
+				return nil, err
+			}
+			d.description = description
+			if extend {
+				extensions = append(extensions, d)
+			} else if _, exists := indices[d.name]; exists {
+				return nil, fmt.Errorf("type %s is defined more than once", d.name)
+			} else {
+				indices[d.name] = len(definitions)
+				definitions = append(definitions, d)
+			}
+		case "schema":
+			p.directives()
+			if err := p.skipBlock(); err != nil {
+				return nil, err
+			}
+		case "directive":
+			if err := p.skipDirectiveDefinition(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, keyword.errorf("unexpected %q, expected a definition", keyword.text)
+		}
+	}
+
+	for _, e := range extensions {
+		index, exists := indices[e.name]
+		if !exists {
+			return nil, fmt.Errorf("extended type %s is not defined", e.name)
+		}
+		definitions[index].fields = append(definitions[index].fields, e.fields...)
+	}
+	return definitions, nil
+}
+
+// Parses the definition after its keyword.
+func (p *graphQLParser) definition(kind string) (graphQLDefinition, error) {
+	name, err := p.name()
+	if err != nil {
+		return graphQLDefinition{}, err
+	}
+	d := graphQLDefinition{kind: kind, name: name}
+	if p.accept("implements") {
+		p.accept("&")
+		for {
+			if _, err := p.name(); err != nil {
+				return d, err
+			}
+			if !p.accept("&") && !p.accept(",") {
+				break
+			}
+		}
+	}
+	p.directives()
+
+	switch kind {
+	case "union":
+		if p.accept("=") {
+			p.accept("|")
+			for {
+				member, err := p.name()
+				if err != nil {
+					return d, err
+				}
+				d.fields = append(d.fields, graphQLField{name: member})
+				if !p.accept("|") {
+					break
+				}
+			}
+		}
+	case "enum":
+		if !p.accept("{") {
+			return d, nil
+		}
+		for !p.accept("}") {
+			if p.done() {
+				return d, fmt.Errorf("enum %s is missing a closing }", name)
+			}
+			f := graphQLField{description: p.description()}
+			if f.name, err = p.name(); err != nil {
+				return d, err
+			}
+			f.deprecated = p.directives()
+			d.fields = append(d.fields, f)
+		}
+	case "type", "interface", "input":
+		if !p.accept("{") {
+			return d, nil
+		}
+		for !p.accept("}") {
+			if p.done() {
+				return d, fmt.Errorf("type %s is missing a closing }", name)
+			}
+			f, err := p.field(true)
+			if err != nil {
+				return d, err
+			}
+			d.fields = append(d.fields, f)
+		}
+	}
+	return d, nil
+}
+
+// Parses a field or an argument definition.
+func (p *graphQLParser) field(arguments bool) (graphQLField, error) {
+	f := graphQLField{description: p.description()}
+	name, err := p.name()
+	if err != nil {
+		return f, err
+	}
+	f.name = name
+	if arguments && p.accept("(") {
+		for !p.accept(")") {
+			if p.done() {
+				return f, fmt.Errorf("field %s is missing a closing )", name)
+			}
+			a, err := p.field(false)
+			if err != nil {
+				return f, err
+			}
+			f.arguments = append(f.arguments, a)
+			p.accept(",")
+		}
+	}
+	if err := p.expect(":"); err != nil {
+		return f, err
+	}
+	if f.ref, err = p.typeRef(); err != nil {
+		return f, err
+	}
+	if p.accept("=") {
+		value, err := p.value()
+		if err != nil {
+			return f, err
+		}
+		f.defaultValue = &value
+	}
+	f.deprecated = p.directives()
+	p.accept(",")
+	return f, nil
+}
+
+// Parses a type reference, ex: [Int!]!
+func (p *graphQLParser) typeRef() (graphQLTypeRef, error) {
+	ref := graphQLTypeRef{}
+	if p.accept("[") {
+		element, err := p.typeRef()
+		if err != nil {
+			return ref, err
+		}
+		if err := p.expect("]"); err != nil {
+			return ref, err
+		}
+		ref.list = &element
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return ref, err
+		}
+		ref.name = name
+	}
+	ref.nonNull = p.accept("!")
+	return ref, nil
+}
+
+// Parses a constant value and returns it in the form a type can parse. Strings are unquoted, and
+// lists and objects are returned in their GraphQL form.
+func (p *graphQLParser) value() (string, error) {
+	if p.done() {
+		return "", fmt.Errorf("expected a value")
+	}
+	token := p.next()
+	switch {
+	case token.str:
+		return token.text, nil
+	case token.text == "[" || token.text == "{":
+		closing := map[string]string{"[": "]", "{": "}"}[token.text]
+		parts := []string{token.text}
+		for !p.accept(closing) {
+			if p.done() {
+				return "", token.errorf("value is missing a closing %s", closing)
+			}
+			part, err := p.value()
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(append(parts, closing), " "), nil
+	}
+	return token.text, nil
+}
+
+// Skips the directives at the current position and returns whether one of them was @deprecated.
+func (p *graphQLParser) directives() bool {
+	deprecated := false
+	for p.accept("@") {
+		name, _ := p.name()
+		deprecated = deprecated || name == "deprecated"
+		if p.accept("(") {
+			for depth := 1; depth > 0 && !p.done(); {
+				switch p.next().text {
+				case "(":
+					depth++
+				case ")":
+					depth--
+				}
+			}
+		}
+	}
+	return deprecated
+}
+
+// Skips a block enclosed by braces.
+func (p *graphQLParser) skipBlock() error {
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for depth := 1; depth > 0; {
+		if p.done() {
+			return fmt.Errorf("block is missing a closing }")
+		}
+		switch p.next().text {
+		case "{":
+			depth++
+		case "}":
+			depth--
+		}
+	}
+	return nil
+}
+
+// Skips a directive definition, ex: directive @auth(role: String) repeatable on FIELD | OBJECT
+func (p *graphQLParser) skipDirectiveDefinition() error {
+	if err := p.expect("@"); err != nil {
+		return err
+	}
+	if _, err := p.name(); err != nil {
+		return err
+	}
+	if p.accept("(") {
+		for !p.accept(")") {
+			if p.done() {
+				return fmt.Errorf("directive is missing a closing )")
+			}
+			if _, err := p.field(false); err != nil {
+				return err
+			}
+		}
+	}
+	p.accept("repeatable")
+	if err := p.expect("on"); err != nil {
+		return err
+	}
+	p.accept("|")
+	for {
+		if _, err := p.name(); err != nil {
+			return err
+		}
+		if !p.accept("|") {
+			return nil
+		}
+	}
+}
+
+// Returns the description at the current position, if any.
+func (p *graphQLParser) description() string {
+	if !p.done() && p.tokens[p.index].str {
+		return p.next().text
+	}
+	return ""
+}
+
+// Returns whether every token was parsed.
+func (p *graphQLParser) done() bool {
+	return p.index >= len(p.tokens)
+}
+
+// Returns the current token and moves to the next.
+func (p *graphQLParser) next() graphQLToken {
+	token := p.tokens[p.index]
+	p.index++
+	return token
+}
+
+// Moves past the current token if it's the given punctuator or name.
+func (p *graphQLParser) accept(text string) bool {
+	if !p.done() && !p.tokens[p.index].str && p.tokens[p.index].text == text {
+		p.index++
+		return true
+	}
+	return false
+}
+
+// Moves past the current token or returns an error if it's not the given punctuator.
+func (p *graphQLParser) expect(text string) error {
+	if p.done() {
+		return fmt.Errorf("expected %q at the end of the document", text)
+	}
+	if token := p.tokens[p.index]; token.str || token.text != text {
+		return token.errorf("unexpected %q, expected %q", token.text, text)
+	}
+	p.index++
+	return nil
+}
+
+// Returns the name at the current position or an error if there isn't one.
+func (p *graphQLParser) name() (string, error) {
+	if p.done() {
+		return "", fmt.Errorf("expected a name at the end of the document")
+	}
+	token := p.tokens[p.index]
+	if token.str || !isGraphQLName(token.text) {
+		return "", token.errorf("unexpected %q, expected a name", token.text)
+	}
+	p.index++
+	return token.text, nil
+}
+
+// Returns an error with the position of the token.
+func (t graphQLToken) errorf(format string, args ...any) error {
+	return fmt.Errorf("%d:%d: %s", t.line, t.column, fmt.Sprintf(format, args...))
+}
+
+// Returns whether the text is a GraphQL name.
+func isGraphQLName(text string) bool {
+	if text == "" || unicode.IsDigit(rune(text[0])) {
+		return false
+	}
+	for _, c := range text {
+		if c != '_' && !(c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c))) {
+			return false
+		}
+	}
+	return true
+}
+
+// Splits the GraphQL document into tokens, ignoring whitespace, commas, and comments.
+func graphQLTokenize(document string) ([]graphQLToken, error) {
+	tokens := make([]graphQLToken, 0)
+	runes := []rune(document)
+	line, lineStart := 1, 0
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		token := graphQLToken{line: line, column: i - lineStart + 1}
+		switch {
+		case c == '\n':
+			line, lineStart = line+1, i+1
+			i++
+		case unicode.IsSpace(c) || c == ',' || c == '\uFEFF':
+			i++
+		case c == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			if strings.HasPrefix(string(runes[i:]), `"""`) {
+				end := i + 3
+				for end < len(runes) && !(strings.HasPrefix(string(runes[end:]), `"""`) && runes[end-1] != '\\') {
+					if runes[end] == '\n' {
+						line, lineStart = line+1, end+1
+					}
+					end++
+				}
+				if end >= len(runes) {
+					return nil, token.errorf("block string is missing a closing \"\"\"")
+				}
+				token.text, token.str = graphQLBlockString(string(runes[i+3:end])), true
+				i = end + 3
+			} else {
+				end := i + 1
+				for end < len(runes) && runes[end] != '"' && runes[end] != '\n' {
+					if runes[end] == '\\' {
+						end++
+					}
+					end++
+				}
+				if end >= len(runes) || runes[end] != '"' {
+					return nil, token.errorf("string is missing a closing \"")
+				}
+				unquoted, err := strconv.Unquote(string(runes[i : end+1]))
+				if err != nil {
+					return nil, token.errorf("invalid string: %v", err)
+				}
+				token.text, token.str = unquoted, true
+				i = end + 1
+			}
+			tokens = append(tokens, token)
+		case strings.ContainsRune("{}()[]!:=@|&", c):
+			token.text = string(c)
+			tokens = append(tokens, token)
+			i++
+		case c == '.' && strings.HasPrefix(string(runes[i:]), "..."):
+			token.text = "..."
+			tokens = append(tokens, token)
+			i += 3
+		case c == '_' || c == '-' || unicode.IsLetter(c) || unicode.IsDigit(c):
+			end := i + 1
+			for end < len(runes) && (runes[end] == '_' || runes[end] == '.' || runes[end] == '+' || runes[end] == '-' || unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end])) {
+				end++
+			}
+			token.text = string(runes[i:end])
+			tokens = append(tokens, token)
+			i = end
+		default:
+			return nil, token.errorf("unexpected character %q", c)
+		}
+	}
+	return tokens, nil
+}
+
+// Returns the value of a block string with its common indentation and blank leading and trailing lines removed.
+func graphQLBlockString(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(raw, `\"""`, `"""`), "\n")
+	indent := -1
+	for i, l := range lines {
+		if i == 0 {
+			continue
+		}
+		trimmed := strings.TrimLeft(l, " \t")
+		if trimmed != "" && (indent == -1 || len(l)-len(trimmed) < indent) {
+			indent = len(l) - len(trimmed)
+		}
+	}
+	for i := 1; i < len(lines) && indent > 0; i++ {
+		if len(lines[i]) >= indent {
+			lines[i] = lines[i][indent:]
+		} else {
+			lines[i] = strings.TrimLeft(lines[i], " \t")
+		}
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,136 @@
+package texpr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQLTypes(t *testing.T) {
+	document := `
+		schema { query: Query }
+
+		directive @auth(role: String = "admin") on FIELD_DEFINITION | OBJECT
+
+		scalar DateTime
+
+		"""
+		A customer order.
+		"""
+		type Order implements Node & Timestamped @auth {
+			id: ID!
+			"The order status"
+			status: Status!
+			placed: DateTime
+			lines(sku: String!, first: Int = 10): [Line!]!
+			total: Float @deprecated(reason: "use lines")
+		}
+
+		# Lines are never queried directly.
+		type Line {
+			sku: String!
+			quantity: Int!
+		}
+
+		enum Status {
+			PENDING
+			"Sent to the customer"
+			SHIPPED
+			LOST @deprecated
+		}
+
+		interface Node { id: ID! }
+		interface Timestamped { placed: DateTime }
+		union Result = Order | Line
+
+		extend type Line {
+			tags: [[String]]
+		}
+	`
+
+	types, err := GraphQLTypes(document, GraphQLOptions{})
+	assert.NoError(t, err)
+
+	described := make([]string, len(types))
+	for i, t := range types {
+		values := make([]string, len(t.Values))
+		for k, v := range t.Values {
+			values[k] = v.Path + " " + string(v.Type)
+			for _, p := range v.Parameters {
+				values[k] += " " + p.Name + ":" + string(p.Type)
+			}
+		}
+		described[i] = fmt.Sprintf("%s: %s%s", t.Name, strings.Join(values, ", "), strings.Join(t.Enums, "|"))
+	}
+	assert.Equal(t, []string{
+		"DateTime: ",
+		"ID: ",
+		"String: ",
+		"Int: ",
+		"Float: ",
+		"Order: id ID, status Status, placed DateTime, lines list<Line> sku:String first:Int, total Float",
+		"Line: sku String, quantity Int, tags list<list<String>>",
+		"Status: PENDING|SHIPPED|LOST",
+		"Node: id ID",
+		"Timestamped: placed DateTime",
+		"Result: Order Order, Line Line",
+	}, described)
+
+	s, err := NewSystemWithOptions(types, SystemOptions{IntType: "Int"})
+	assert.NoError(t, err)
+
+	order := s.Type("Order")
+	assert.Equal(t, "A customer order.", order.Description)
+	assert.Equal(t, "The order status", order.Value("status").Description)
+	assert.Equal(t, 1, order.Value("lines").MinParameters())
+	assert.Equal(t, "10", *order.Value("lines").Parameters[1].Default)
+	assert.False(t, order.Value("id").Nullable)
+	assert.True(t, order.Value("placed").Nullable)
+
+	shipped, _ := s.Type("Status").EnumOption("shipped")
+	assert.Equal(t, "Sent to the customer", shipped.Description)
+	lost, _ := s.Type("Status").EnumOption("LOST")
+	assert.True(t, lost.Deprecated)
+
+	e, err := s.Parse(Options{RootType: "Order", Expression: "lines('A1').first.quantity"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Int", string(e.Last().Type.Name))
+
+	types, err = GraphQLTypes(`type Event { at: DateTime!, count: Int }`, GraphQLOptions{
+		Scalars: map[string]TypeName{"DateTime": "time", "Int": "int"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, types, 1)
+	assert.Equal(t, TypeName("time"), types[0].Values[0].Type)
+	assert.Equal(t, TypeName("int"), types[0].Values[1].Type)
+
+	types, err = GraphQLTypes(`
+		type Query { pet: Pet }
+		type Cat { lives: Int! }
+		type Dog { name: String! }
+		union Pet = Cat | Dog
+	`, GraphQLOptions{})
+	assert.NoError(t, err)
+	s, err = NewSystemWithOptions(types, SystemOptions{IntType: "Int"})
+	assert.NoError(t, err)
+	e, err = s.Parse(Options{RootType: "Query", Expression: "pet?.Cat?.lives"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Int", string(e.Last().Type.Name))
+
+	_, err = GraphQLTypes(`union Pet = Cat`, GraphQLOptions{})
+	assert.EqualError(t, err, "union Pet: type Cat is not defined")
+
+	_, err = GraphQLTypes(`type A { b: B }`, GraphQLOptions{})
+	assert.EqualError(t, err, "field A.b: type B is not defined")
+
+	_, err = GraphQLTypes(`type A {
+		b: String
+		c String
+	}`, GraphQLOptions{})
+	assert.EqualError(t, err, `3:5: unexpected "String", expected ":"`)
+
+	_, err = GraphQLTypes(`extend type A { b: String }`, GraphQLOptions{})
+	assert.EqualError(t, err, "extended type A is not defined")
+}
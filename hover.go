@@ -0,0 +1,88 @@
+package texpr
+
+// Information about the part of an expression at an index, for editor tooltips, see System.HoverAt.
+type Hover struct {
+	// The expression at the index.
+	Expr *Expr
+	// What the expression is, a value, an enum option, or a type for other constants and bind parameters.
+	Kind DocKind
+	// The value of the expression, if any.
+	Value *Value
+	// The type of the expression. For generic values this is the type determined from the arguments.
+	Type *Type
+	// The type the expression is on, like the type a value is on.
+	ParentType *Type
+	// The parameter the expression is an argument for, if it's the start of an argument.
+	Parameter *Parameter
+	// A human readable signature, the signature of the value or the token and type otherwise.
+	Signature string
+	// The description of the value, enum option, or type in the locale of the options.
+	Description string
+	// The description of the parameter in the locale of the options.
+	ParameterDescription string
+	// The aliases of the value.
+	Aliases []string
+	// The examples of the value.
+	Examples []string
+	// The start of the expression.
+	Start Position
+	// The end of the expression.
+	End Position
+}
+
+// Returns information about the part of the expression in the options at the given index, or nil if
+// there is nothing at the index. The whole expression is parsed and linked, and errors are ignored so
+// the parts which could be linked are still described. An index at the end of a token is on the token.
+func (sys System) HoverAt(opts Options, index int) *Hover {
+	if index < 0 || index > len(opts.Expression) {
+		return nil
+	}
+	e, _ := sys.Parse(opts)
+	if e == nil {
+		return nil
+	}
+	target := e.At(index)
+	if target == nil {
+		return nil
+	}
+
+	hover := &Hover{
+		Expr:       target,
+		Kind:       DocKindType,
+		Type:       target.Type,
+		ParentType: target.ParentType,
+		Parameter:  target.Parameter,
+		Start:      target.Start,
+		End:        target.End,
+	}
+	if target.Parameter != nil {
+		hover.ParameterDescription = target.Parameter.Describe(opts.Locale)
+	}
+	typeName := ""
+	if target.Type != nil {
+		typeName = string(target.Type.Name)
+		hover.Description = target.Type.Describe(opts.Locale)
+	}
+	switch {
+	case target.Value != nil:
+		hover.Kind = DocKindValue
+		hover.Value = target.Value
+		hover.Signature = target.Value.Signature()
+		hover.Description = target.Value.Describe(opts.Locale)
+		hover.Aliases = target.Value.Aliases
+		hover.Examples = target.Value.Examples
+	case target.Bind:
+		hover.Signature = ":" + target.Token + " " + typeName
+	case target.Constant && target.Type != nil && len(target.Type.Enums) > 0:
+		enumValue, _ := target.Type.EnumFor(target.Token)
+		if enumValue == "" {
+			enumValue = target.Token
+		}
+		hover.Kind = DocKindEnum
+		hover.Signature = enumValue + " " + typeName
+		hover.Description = target.Type.DescribeEnum(enumValue, opts.Locale)
+	default:
+		hover.Signature = target.Token + " " + typeName
+	}
+	return hover
+}
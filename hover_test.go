@@ -0,0 +1,63 @@
+package texpr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHoverAt(t *testing.T) {
+	expression := "user.createDate.min.>(:limit).then('yes', 'no').=(user.name.upper).and(time.today.add(1, week).=(time.today))"
+	opts := Options{RootType: typeContext, Expression: expression, Parameters: map[string]TypeName{"limit": typeInt}}
+
+	tests := []struct {
+		name        string
+		at          string
+		kind        DocKind
+		token       string
+		typ         TypeName
+		signature   string
+		description string
+		aliases     []string
+		parameter   string
+	}{
+		{name: "root value", at: "ser.", kind: DocKindValue, token: "user", typ: typeUser, signature: "user user"},
+		{name: "end of token", at: ".min", kind: DocKindValue, token: "createDate", typ: typeDateTime, signature: "createDate dateTime"},
+		{name: "alias", at: "in.>", kind: DocKindValue, token: "min", typ: typeInt, signature: "minute int", aliases: []string{"min"}},
+		{name: "bind", at: "imit", kind: DocKindType, token: "limit", typ: typeInt, signature: ":limit int", description: "A whole number", parameter: "value"},
+		{name: "generic", at: "hen", kind: DocKindValue, token: "then", typ: typeText, signature: "then(trueValue ?, falseValue ?) ?"},
+		{name: "argument value", at: "pper)", kind: DocKindValue, token: "upper", typ: typeText},
+		{name: "constant", at: "1,", kind: DocKindType, token: "1", typ: typeInt, signature: "1 int", description: "A whole number", parameter: "amount"},
+		{name: "enum", at: "eek", kind: DocKindEnum, token: "week", typ: typeDuration, signature: "week duration", parameter: "duration"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hover := sys.HoverAt(opts, strings.Index(expression, test.at))
+			if !assert.NotNil(t, hover) {
+				return
+			}
+			assert.Equal(t, test.kind, hover.Kind)
+			assert.Equal(t, test.token, hover.Expr.Token)
+			assert.Equal(t, test.typ, hover.Type.Name)
+			if test.signature != "" {
+				assert.Equal(t, test.signature, hover.Signature)
+			}
+			if test.description != "" {
+				assert.Equal(t, test.description, hover.Description)
+			}
+			assert.Equal(t, test.aliases, hover.Aliases)
+			if test.parameter != "" && assert.NotNil(t, hover.Parameter) {
+				assert.Equal(t, test.parameter, hover.Parameter.Name)
+			}
+			assert.Equal(t, hover.Expr.Start, hover.Start)
+		})
+	}
+
+	assert.Nil(t, sys.HoverAt(opts, -1))
+	assert.Nil(t, sys.HoverAt(Options{RootType: typeContext, Expression: "user.name"}, 20))
+
+	hover := sys.HoverAt(Options{RootType: typeContext, Expression: "user.nope.upper"}, 7)
+	assert.Equal(t, Unknown, hover.Type)
+	assert.Equal(t, "nope <unknown>", hover.Signature)
+}
@@ -0,0 +1,137 @@
+package texpr
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// The precedence of operators which can be written infix keyed by operator (see Options.Infix). Operators
+// with a higher precedence are applied first and operators with the same precedence are applied left to
+// right, so with DefaultPrecedence `a + b * c` is `a.+(b.*(c))` and `a - b - c` is `a.-(b).-(c)`.
+type Precedence map[string]int
+
+// The precedence of the common comparison, arithmetic, and logical operators, from `or` which is applied
+// last to `*`, `/`, and `%` which are applied first.
+var DefaultPrecedence = Precedence{
+	"or":  1,
+	"and": 2,
+	"=":   3,
+	"!=":  3,
+	"<":   4,
+	"<=":  4,
+	">":   4,
+	">=":  4,
+	"+":   5,
+	"-":   5,
+	"*":   6,
+	"/":   6,
+	"%":   6,
+}
+
+// Returns the precedence keyed by lowercase operator, or nil when operators can't be written infix.
+func (p Precedence) lowered() Precedence {
+	if p == nil {
+		return nil
+	}
+	lowered := make(Precedence, len(p))
+	for operator, precedence := range p {
+		lowered[strings.ToLower(operator)] = precedence
+	}
+	return lowered
+}
+
+// Returns the length of the infix operator at the current character, or 0 if there isn't one. An
+// operator is only infix when it follows an operand, so `-1`, `a.>(1)`, `a > -1`, and `!-a` keep their meaning.
+// The longest operator is used, so `>=` is not `>` followed by `=`.
+func (p *parser) operatorAt() int {
+	if p.operators == nil || p.prev == nil || p.prev.Infix || p.prev.Prefix || p.dotted {
+		return 0
+	}
+	longest := 0
+	for operator := range p.operators {
+		n := len(operator)
+		if n <= longest || p.i+n > p.n || !strings.EqualFold(p.e[p.i:p.i+n], operator) {
+			continue
+		}
+		// A word operator must be the whole word, `andy` is not `and`.
+		if p.identifierAt(p.i) > 0 && p.identifierAt(p.i+n) > 0 {
+			continue
+		}
+		longest = n
+	}
+	return longest
+}
+
+// Rewrites the infix operators in the chain starting with the expression and in its arguments into
+// the values they call ordered by precedence, moves prefixes after their operands, and removes grouping
+// parentheses. So `a > 1 and !(b or c)` becomes `a.>(1).and(b.or(c).!)`. Returns the first expression
+// of the rewritten chain.
+func (p *parser) rewriteOperators(e *Expr, errs *[]ParseError) *Expr {
+	operands := []*Expr{e}
+	operators := make([]*Expr, 0)
+	for c := e; c != nil; {
+		for i, argument := range c.Arguments {
+			c.Arguments[i] = p.rewriteOperators(argument, errs)
+			c.Arguments[i].Parent = c
+		}
+		next := c.Next
+		if c.Infix {
+			c.Prev.Next = nil
+			c.Prev = nil
+			c.Next = nil
+			operators = append(operators, c)
+			if next == nil {
+				missing := &Expr{Start: c.End, End: c.End}
+				*errs = append(*errs, NewParseErrorKind(missing, ErrSyntax, fmt.Sprintf("expression expecting a value after %s but found nothing", c.Token)))
+				operands = append(operands, missing)
+			} else {
+				next.Prev = nil
+				operands = append(operands, next)
+			}
+		}
+		c = next
+	}
+	for i, operand := range operands {
+		operands[i] = unprefix(operand, errs)
+	}
+
+	applied := 0
+	var apply func(minimum int) *Expr
+	apply = func(minimum int) *Expr {
+		left := operands[applied]
+		for applied < len(operators) && p.operators[strings.ToLower(operators[applied].Token)] >= minimum {
+			operator := operators[applied]
+			applied++
+			right := apply(p.operators[strings.ToLower(operator.Token)] + 1)
+			last := left.Last()
+			last.Next = operator
+			operator.Prev = last
+			operator.Called = true
+			operator.Arguments = []*Expr{right}
+			right.Parent = operator
+		}
+		return left
+	}
+	return apply(math.MinInt)
+}
+
+// Returns the chain grouped by the parentheses followed by the rest of the chain after them, or the
+// expression if it's not grouping parentheses.
+func ungroup(e *Expr, errs *[]ParseError) *Expr {
+	if !e.group {
+		return e
+	}
+	if len(e.Arguments) != 1 {
+		*errs = append(*errs, NewParseErrorKind(e, ErrSyntax, fmt.Sprintf("parentheses must group one expression, found %d", len(e.Arguments))))
+		return e
+	}
+	grouped := e.Arguments[0]
+	grouped.Parent = nil
+	if e.Next != nil {
+		last := grouped.Last()
+		last.Next = e.Next
+		e.Next.Prev = last
+	}
+	return grouped
+}
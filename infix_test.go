@@ -0,0 +1,98 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInfix(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   string
+		err        error
+	}{
+		{"time.now.hour > 12 and user.name.contains('Ma')", "time.now.hour>('12').and(user.name.contains('Ma'))", nil},
+		{"1 + 2 * 3 > 4 - 1 - 1", "'1'+('2'*('3'))>('4'-('1')-('1'))", nil},
+		{"(1 + 2) * 3 = 9 or false", "'1'+('2')*('3')=('9').or('false')", nil},
+		{"(user.name + 'x').length > 2", "user.name+('x').length>('2')", nil},
+		{"time.now.hour>=-1", "time.now.hour>=('-1')", nil},
+		{"time.now.hour.>(1) AND true", "time.now.hour>('1').AND('true')", nil},
+		{"time.today.add(1 + 1, day).dayOfMonth != 2", "time.today.add('1'+('1'),'day').dayOfMonth!=('2')", nil},
+		{"user.name = 'Ma' and true or false", "user.name=('Ma').and('true').or('false')", nil},
+		{"true or false and false", "'true'.or('false'.and('false'))", nil},
+		{"time.now.hour >", "time.now.hour>()", ErrSyntax},
+		{"() > 1", "''()>('1')", ErrSyntax},
+		{"user.name + 1", "user.name+('1')", nil},
+		{"time.now.hour + 'a'", "time.now.hour+('a')", ErrTypeMismatch},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := sys.Parse(Options{RootType: typeContext, Expression: test.expression, Infix: DefaultPrecedence})
+			if test.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.err)
+			}
+			assert.Equal(t, test.expected, e.String())
+		})
+	}
+}
+
+func TestInfixLinksLikeCalls(t *testing.T) {
+	infix, err := sys.Parse(Options{RootType: typeContext, Expression: "time.now.hour > 12 and user.name.contains('Ma')", Infix: DefaultPrecedence})
+	assert.NoError(t, err)
+	called, err := sys.Parse(Options{RootType: typeContext, Expression: "time.now.hour.>(12).and(user.name.contains('Ma'))"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, called.String(), infix.String())
+	assert.Equal(t, TypeName(typeBool), infix.Last().Type.Name)
+	and := infix.Last()
+	assert.True(t, and.Infix)
+	assert.Equal(t, "and", and.Value.Path)
+	assert.Same(t, and, and.Arguments[0].Parent)
+	assert.Equal(t, &Position{Index: 19, Column: 19}, &and.Start)
+
+	result, err := Compile[Run](infix, compileOptions)
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+
+	// Without a precedence table operators must be called
+	_, err = sys.Parse(Options{RootType: typeContext, Expression: "time.now.hour > 12"})
+	assert.Error(t, err)
+
+	// Operators with the same precedence apply left to right
+	e, err := sys.Parse(Options{RootType: typeContext, Expression: "1 + 2 * 3 > 8", Infix: Precedence{"+": 1, "*": 1, ">": 0}})
+	assert.NoError(t, err)
+	assert.Equal(t, "'1'+('2')*('3')>('8')", e.String())
+}
+
+func TestGroupedChains(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   string
+		err        error
+	}{
+		{"(user.name).contains('a')", "user.name.contains('a')", nil},
+		{"((time.today)).add(1, day)", "time.today.add('1','day')", nil},
+		{"(user.name.isLower.or(user.name.isUpper)).then('x', 'y')", "user.name.isLower.or(user.name.isUpper).then('x','y')", nil},
+		{"user.name.contains((user.name).lower)", "user.name.contains(user.name.lower)", nil},
+		{"(user.name)(1)", "", ErrSyntax},
+		{"(user.name, time)", "", ErrSyntax},
+		{"(user.name", "", ErrSyntax},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := sys.Parse(Options{RootType: typeContext, Expression: test.expression})
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.expected, e.String())
+			}
+		})
+	}
+}
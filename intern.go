@@ -0,0 +1,81 @@
+package texpr
+
+import (
+	"sync"
+)
+
+// A constant token parsed as a type.
+type constantKey struct {
+	parser *Type
+	token  string
+}
+
+// The result of parsing a constant token, shared by every constant with the same token and type.
+type internedConstant struct {
+	token  string
+	parsed any
+	layout string
+}
+
+// The constants interned by a system, shared by all copies of the system. See SystemOptions.InternConstants.
+type constantCache struct {
+	// Guards constants.
+	lock sync.RWMutex
+	// The maximum number of constants interned, once reached new constants are no longer interned.
+	limit     int
+	constants map[constantKey]internedConstant
+}
+
+// Returns the constant with the token parsed as the type, if it has been interned.
+func (c *constantCache) get(key constantKey) (internedConstant, bool) {
+	if c == nil {
+		return internedConstant{}, false
+	}
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	constant, exists := c.constants[key]
+	return constant, exists
+}
+
+// Interns the constant if the cache has not reached its limit.
+func (c *constantCache) put(key constantKey, constant internedConstant) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.constants) < c.limit {
+		c.constants[key] = constant
+	}
+}
+
+// Returns the number of constants interned by the system.
+func (sys System) InternedConstants() int {
+	if sys.constants == nil {
+		return 0
+	}
+	sys.constants.lock.RLock()
+	defer sys.constants.lock.RUnlock()
+	return len(sys.constants.constants)
+}
+
+// Parses the token as the type, reusing the token and parsed value of an identical constant from
+// earlier in the parse or from the system's cache.
+func (sys System) parseConstant(parser *Type, token string, ctx *linkContext) (internedConstant, error) {
+	key := constantKey{parser: parser, token: token}
+	if constant, exists := ctx.constants[key]; exists {
+		return constant, nil
+	}
+	if constant, exists := sys.constants.get(key); exists {
+		ctx.constants[key] = constant
+		return constant, nil
+	}
+	parsed, layout, err := parser.ParseLayout(token)
+	if err != nil {
+		return internedConstant{}, err
+	}
+	constant := internedConstant{token: token, parsed: parsed, layout: layout}
+	ctx.constants[key] = constant
+	sys.constants.put(key, constant)
+	return constant, nil
+}
@@ -0,0 +1,56 @@
+package texpr
+
+import (
+	"strconv"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInternConstants(t *testing.T) {
+	parses := 0
+	newSystem := func(options SystemOptions) System {
+		sys, err := NewSystemWithOptions([]Type{{
+			Name: "num",
+			Parse: func(x string) (any, error) {
+				parses++
+				return strconv.Atoi(x)
+			},
+			Values: []Value{
+				{Path: "plus", Type: "num", Variadic: true, Parameters: []Parameter{
+					{Name: "values", Type: "num"},
+				}},
+			},
+		}, {
+			Name: "order",
+			Values: []Value{
+				{Path: "count", Type: "num"},
+			},
+		}}, options)
+		assert.NoError(t, err)
+		return sys
+	}
+
+	sys := newSystem(SystemOptions{})
+	e, err := sys.Parse(Options{RootType: "order", Expression: "count.plus(10, 10, 20, 10)"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, parses)
+	args := e.Next.Arguments
+	assert.Equal(t, 10, args[3].Parsed)
+	assert.Same(t, unsafe.StringData(args[0].Token), unsafe.StringData(args[3].Token))
+	assert.Equal(t, 0, sys.InternedConstants())
+
+	_, err = sys.Parse(Options{RootType: "order", Expression: "count.plus(10)"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, parses)
+
+	parses = 0
+	sys = newSystem(SystemOptions{InternConstants: 2})
+	for i := 0; i < 3; i++ {
+		_, err = sys.Parse(Options{RootType: "order", Expression: "count.plus(10, 20, 30)"})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, sys.InternedConstants())
+	assert.Equal(t, 5, parses)
+}
@@ -0,0 +1,208 @@
+package texpr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The JavaScript of the values of a type keyed by their paths. Each is a JavaScript expression where
+// $this is replaced by the value before it, $0, $1, etc by its arguments, and $args by all of its
+// arguments separated by commas, like `Math.abs($this)` or `$this.includes($0)`.
+type JavaScriptValues map[string]string
+
+// The options of NewJavaScript.
+type JavaScriptOptions struct {
+	// The JavaScript of the values of each type. Types which are given no JavaScript for their
+	// capability operators (see Type.Comparable) and enum operators use the JavaScript operators,
+	// except types with Operations or Layouts (like BigIntType and dates) don't compare like JavaScript
+	// values and fail to compile. FloatJavaScript is the JavaScript of FloatType, and durations are
+	// written as their text (see Duration.String) so the JavaScript operators compare them like Go does.
+	Types map[TypeName]JavaScriptValues
+	// Returns the JavaScript of a constant, by default its parsed value (or its token when it has none)
+	// written as JSON.
+	Constant func(e *Expr) (string, error)
+}
+
+// The functions every compiled JavaScript function defines for the generated JavaScript. eq compares
+// values like enum operators do, strings case insensitively, and near compares numbers with an epsilon
+// like FloatOperations.
+const javaScriptRuntime = `const eq = (a, b) => typeof a === 'string' && typeof b === 'string' ? a.toLowerCase() === b.toLowerCase() : a === b;
+  const near = (a, b, e) => a === b || Math.abs(a - b) <= e * Math.max(1, Math.abs(a), Math.abs(b));`
+
+// The JavaScript of the capability operators when the type gives none.
+var javaScriptOperators = map[Operator]string{
+	OpEquals:       "($this === $0)",
+	OpNotEquals:    "($this !== $0)",
+	OpLess:         "($this < $0)",
+	OpLessEqual:    "($this <= $0)",
+	OpGreater:      "($this > $0)",
+	OpGreaterEqual: "($this >= $0)",
+	OpMin:          "Math.min($this, $args)",
+	OpMax:          "Math.max($this, $args)",
+	OpAdd:          "($this + $0)",
+	OpSubtract:     "($this - $0)",
+	OpMultiply:     "($this * $0)",
+	OpDivide:       "($this / $0)",
+}
+
+// The JavaScript of the operators of enum types when the type gives none, which compare like EnumOperations.
+var javaScriptEnumOperators = map[Operator]string{
+	OpEquals:    "eq($this, $0)",
+	OpNotEquals: "!eq($this, $0)",
+	OpOneOf:     "[$args].some(o => eq($this, o))",
+	OpNotOneOf:  "![$args].some(o => eq($this, o))",
+}
+
+// Returns a compile source which compiles linked expressions into JavaScript expressions of `root` and
+// the bind parameters in `params`, see CompileJavaScript. The value before an expression in a nullable
+// chain (see Expr.InNullableChain) is checked so null is propagated instead of evaluating the rest of
+// the chain.
+func NewJavaScript(options JavaScriptOptions) CompileSourceLookup[string] {
+	constant := options.Constant
+	if constant == nil {
+		constant = javaScriptConstant
+	}
+	types := make(TypeCompilers[string], len(options.Types))
+	for name, values := range options.Types {
+		compilers := make(ValueCompilers[string], len(values))
+		for path, js := range values {
+			compilers[strings.ToLower(path)] = javaScriptCompiler(js)
+		}
+		types[name] = compilers
+	}
+	return CompileSourceLookup[string]{
+		Initial:       "root",
+		TypeCompilers: types,
+		ConstantCompiler: func(e *Expr, root *Type, previous string, arguments []string) (string, error) {
+			return constant(e)
+		},
+		BindCompiler: func(e *Expr, root *Type, previous string, arguments []string) (string, error) {
+			return "params[" + strconv.Quote(e.Token) + "]", nil
+		},
+		ListCompiler: func(e *Expr, root *Type, previous string, elements []string) (string, error) {
+			return "[" + strings.Join(elements, ", ") + "]", nil
+		},
+		OperatorCompiler: func(e *Expr, root *Type, previous string, arguments []string) (string, error) {
+			op, _ := e.Value.Operator()
+			parent := e.ParentType
+			if e.Prev != nil {
+				parent = e.Prev.Type
+			}
+			js := javaScriptOperators[op]
+			if enumJS, exists := javaScriptEnumOperators[op]; exists && len(parent.Enums) > 0 && parent.Operations == nil {
+				js = enumJS
+			} else if parent.Operations != nil || len(parent.Layouts) > 0 {
+				return "", fmt.Errorf("no JavaScript specified for %s on %s, which does not compare like JavaScript values", e.Token, parent.Name)
+			}
+			return javaScriptCompiler(js)(e, root, previous, arguments)
+		},
+	}
+}
+
+// Compiles the linked expression into the source of a JavaScript function which is given the root and
+// the bind parameters by name, like `function(root, params) { ... }`, so the same expression can be
+// evaluated in a browser as it is on the server. The source is usually from NewJavaScript.
+func CompileJavaScript(e *Expr, source CompileSource[string]) (string, error) {
+	js, err := Compile(e, source)
+	if err != nil {
+		return "", err
+	}
+	return "function(root, params) {\n  " + javaScriptRuntime + "\n  return " + js + ";\n}", nil
+}
+
+// Returns the JavaScript of the constant's parsed value, or its token when it has none.
+func javaScriptConstant(e *Expr) (string, error) {
+	value := e.Parsed
+	if value == nil && e.Type != Null {
+		value = e.Token
+	}
+	js, err := json.Marshal(value)
+	if err != nil {
+		return "", NewParseErrorKind(e, ErrTypeMismatch, fmt.Sprintf("constant %s can't be written in JavaScript: %v", e.Token, err))
+	}
+	return string(js), nil
+}
+
+// Returns a compiler which replaces $this, $args, and $0, $1, etc in the JavaScript. When the value
+// before the expression may be null the JavaScript is only evaluated when it's not.
+func javaScriptCompiler(js string) Compiler[string] {
+	return func(e *Expr, root *Type, previous string, arguments []string) (string, error) {
+		if js == "" {
+			return "", fmt.Errorf("no JavaScript specified for %s", e.Token)
+		}
+		if !e.InNullableChain() {
+			return replaceJavaScript(js, previous, arguments)
+		}
+		replaced, err := replaceJavaScript(js, "$v", arguments)
+		if err != nil {
+			return "", err
+		}
+		return "(($v) => $v == null ? null : " + replaced + ")(" + previous + ")", nil
+	}
+}
+
+// Replaces $this with the previous value and $args, $0, $1, etc with the arguments in the JavaScript.
+func replaceJavaScript(js string, previous string, arguments []string) (string, error) {
+	out := strings.Builder{}
+	for i := 0; i < len(js); i++ {
+		if js[i] != '$' {
+			out.WriteByte(js[i])
+			continue
+		}
+		rest := js[i+1:]
+		switch {
+		case strings.HasPrefix(rest, "this"):
+			out.WriteString(previous)
+			i += len("this")
+		case strings.HasPrefix(rest, "args"):
+			out.WriteString(strings.Join(arguments, ", "))
+			i += len("args")
+		default:
+			digits := 0
+			for digits < len(rest) && rest[digits] >= '0' && rest[digits] <= '9' {
+				digits++
+			}
+			if digits == 0 {
+				out.WriteByte('$')
+				continue
+			}
+			index, _ := strconv.Atoi(rest[:digits])
+			if index >= len(arguments) {
+				return "", fmt.Errorf("JavaScript %s refers to argument %d but was given %d", js, index, len(arguments))
+			}
+			out.WriteString(arguments[index])
+			i += digits
+		}
+	}
+	return out.String(), nil
+}
+
+// Returns the JavaScript of the values of FloatType with the options, which compare with the epsilon
+// and round half away from zero like Float does. The other operators are the JavaScript operators.
+func FloatJavaScript(options FloatOptions) JavaScriptValues {
+	if options.Epsilon == 0 {
+		options.Epsilon = DefaultEpsilon
+	}
+	epsilon := strconv.FormatFloat(options.Epsilon, 'g', -1, 64)
+	return JavaScriptValues{
+		"near":  "near($this, $0, $1)",
+		"abs":   "Math.abs($this)",
+		"floor": "Math.floor($this)",
+		"ceil":  "Math.ceil($this)",
+		"round": "((f) => Math.sign(f) * Math.round(Math.abs(f)))($this)",
+		"=":     "near($this, $0, " + epsilon + ")",
+		"!=":    "!near($this, $0, " + epsilon + ")",
+		"<":     "((a, b) => a < b && !near(a, b, " + epsilon + "))($this, $0)",
+		"<=":    "((a, b) => a < b || near(a, b, " + epsilon + "))($this, $0)",
+		">":     "((a, b) => a > b && !near(a, b, " + epsilon + "))($this, $0)",
+		">=":    "((a, b) => a > b || near(a, b, " + epsilon + "))($this, $0)",
+		"min":   javaScriptOperators[OpMin],
+		"max":   javaScriptOperators[OpMax],
+		"+":     javaScriptOperators[OpAdd],
+		"-":     javaScriptOperators[OpSubtract],
+		"*":     javaScriptOperators[OpMultiply],
+		"/":     javaScriptOperators[OpDivide],
+	}
+}
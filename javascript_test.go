@@ -0,0 +1,95 @@
+package texpr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type jsReading struct {
+	total Float
+	unit  jsUnit
+	next  *jsReading
+	wait  Duration
+	big   BigInt
+}
+
+func (r *jsReading) Total() Float     { return r.total }
+func (r *jsReading) Count() Float     { return 1 }
+func (r *jsReading) Unit() jsUnit     { return r.unit }
+func (r *jsReading) Next() *jsReading { return r.next }
+func (r *jsReading) Wait() Duration   { return r.wait }
+func (r *jsReading) Big() BigInt      { return r.big }
+
+type jsUnit string
+
+func TestCompileJavaScript(t *testing.T) {
+	r, err := NewReflect(ReflectOptions{
+		BoolType: "bool",
+		Types: map[reflect.Type]Type{
+			TypeOf[bool]():       {Name: "bool"},
+			TypeOf[Float]():      FloatType(FloatOptions{}),
+			TypeOf[jsUnit]():     {Name: "unit", Enums: []string{"cm", "in"}},
+			TypeOf[Duration]():   DurationType(DurationOptions{}),
+			TypeOf[BigInt]():     BigIntType(BigNumberOptions{}),
+			TypeOf[BigDecimal](): BigDecimalType(BigNumberOptions{}),
+			TypeOf[*jsReading](): {Name: "reading"},
+		},
+		EnumOperators: true,
+	})
+	assert.NoError(t, err)
+
+	js := NewJavaScript(JavaScriptOptions{
+		Types: map[TypeName]JavaScriptValues{
+			"float":   FloatJavaScript(FloatOptions{}),
+			"reading": {"total": "$this.total", "unit": "$this.unit", "next": "$this.next", "wait": "$this.wait", "big": "$this.big"},
+		},
+	})
+
+	tests := []struct {
+		expression string
+		expected   string
+		err        string
+	}{
+		{expression: "total", expected: "root.total"},
+		{expression: "total.*(10).round", expected: "((f) => Math.sign(f) * Math.round(Math.abs(f)))((root.total * 10))"},
+		{expression: "total.=('0.3')", expected: "near(root.total, 0.3, 1e-09)"},
+		{expression: "total.max(1, :limit)", expected: "Math.max(root.total, 1, params[\"limit\"])"},
+		{expression: "total.near('0.31', '0.1')", expected: "near(root.total, 0.31, 0.1)"},
+		{expression: "unit.oneOf(cm, in)", expected: "[\"cm\", \"in\"].some(o => eq(root.unit, o))"},
+		{expression: "next.total.abs", expected: "Math.abs(root.next.total)"},
+		{expression: "next?.total.abs", expected: "(($v) => $v == null ? null : Math.abs($v))((($v) => $v == null ? null : $v.total)(root.next))"},
+		{expression: "[total, 1]", expected: "[root.total, 1]"},
+		{expression: "null", expected: "null"},
+		{expression: "wait.!=('1 day 60m')", expected: "(root.wait !== \"1 day 1h0m0s\")"},
+		{expression: "big.>('1')", err: "no JavaScript specified for > on bigint, which does not compare like JavaScript values"},
+		{expression: "next.count", err: "no value Count specified for reading"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := r.Parse(Options{RootType: "reading", Expression: test.expression, Parameters: map[string]TypeName{"limit": "float"}})
+			if !assert.NoError(t, err) {
+				return
+			}
+			compiled, err := Compile[string](e, js)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.expected, compiled)
+			}
+		})
+	}
+
+	e, err := r.Parse(Options{RootType: "reading", Expression: "total.abs"})
+	assert.NoError(t, err)
+	source, err := CompileJavaScript(e, js)
+	assert.NoError(t, err)
+	assert.Equal(t, "function(root, params) {\n  "+javaScriptRuntime+"\n  return Math.abs(root.total);\n}", source)
+
+	_, err = replaceJavaScript("$this.at($1)", "x", []string{"0"})
+	assert.ErrorContains(t, err, "refers to argument 1 but was given 1")
+}
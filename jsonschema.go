@@ -0,0 +1,399 @@
+package texpr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The options used to build types from a JSON Schema document, see JSONSchemaTypes.
+type JSONSchemaOptions struct {
+	// The name of the type of the root schema. When empty the title of the schema is used, and when
+	// there is no title the name "root" is used.
+	Root TypeName
+	// The names of the types used for JSON types ("string", "integer", "number", "boolean") and formats
+	// (ex: "date-time", "email") which are given to the system separately. JSON types and formats which
+	// are not mapped are generated with a parse function.
+	Types map[string]TypeName
+}
+
+// The parse functions of the formats a type is generated for.
+var jsonSchemaFormats = map[string]func(x string) (any, error){
+	"date": func(x string) (any, error) {
+		return time.Parse(time.DateOnly, x)
+	},
+	"date-time": func(x string) (any, error) {
+		return time.Parse(time.RFC3339, x)
+	},
+	"time": func(x string) (any, error) {
+		return time.Parse(time.TimeOnly, x)
+	},
+	"email": func(x string) (any, error) {
+		address, err := mail.ParseAddress(x)
+		if err != nil {
+			return nil, err
+		}
+		return address.Address, nil
+	},
+	"uri": func(x string) (any, error) {
+		return url.ParseRequestURI(x)
+	},
+	"ipv4": func(x string) (any, error) {
+		ip := net.ParseIP(x)
+		if ip == nil || ip.To4() == nil {
+			return nil, fmt.Errorf("%s is not an ipv4 address", x)
+		}
+		return ip, nil
+	},
+	"ipv6": func(x string) (any, error) {
+		ip := net.ParseIP(x)
+		if ip == nil || ip.To4() != nil {
+			return nil, fmt.Errorf("%s is not an ipv6 address", x)
+		}
+		return ip, nil
+	},
+	"uuid": func(x string) (any, error) {
+		if !uuidPattern.MatchString(x) {
+			return nil, fmt.Errorf("%s is not a uuid", x)
+		}
+		return strings.ToLower(x), nil
+	},
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// The parse functions and parse orders of the JSON types a type is generated for.
+var jsonSchemaPrimitives = map[string]Type{
+	"string": {ParseOrder: -1, Parse: func(x string) (any, error) {
+		return x, nil
+	}},
+	"integer": {Parse: func(x string) (any, error) {
+		return strconv.ParseInt(x, 10, 64)
+	}},
+	"number": {ParseOrder: -1, Parse: func(x string) (any, error) {
+		return strconv.ParseFloat(x, 64)
+	}},
+	"boolean": {Parse: func(x string) (any, error) {
+		return strconv.ParseBool(x)
+	}},
+}
+
+// Builds types from JSON Schema documents.
+type jsonSchemaImporter struct {
+	options     JSONSchemaOptions
+	definitions map[string]map[string]any
+	types       []Type
+	named       map[string]TypeName
+	generated   map[TypeName]bool
+}
+
+// Returns the types described by the JSON Schema document. Objects are types and their properties are
+// values, string enums are types with Enums, arrays are list types (see System.ListOf), and JSON types
+// and formats are types with parse functions. References to definitions (#/definitions/x or #/$defs/x)
+// are types named by the definition.
+func JSONSchemaTypes(document []byte, options JSONSchemaOptions) ([]Type, error) {
+	schema := make(map[string]any)
+	if err := json.Unmarshal(document, &schema); err != nil {
+		return nil, err
+	}
+	importer := newJSONSchemaImporter(options)
+	for _, key := range []string{"definitions", "$defs"} {
+		importer.addDefinitions("#/"+key+"/", schema[key])
+	}
+
+	root := options.Root
+	if root == "" {
+		if title, ok := schema["title"].(string); ok && title != "" {
+			root = TypeName(title)
+		} else {
+			root = "root"
+		}
+	}
+	if _, err := importer.typeOf(schema, root); err != nil {
+		return nil, err
+	}
+	return importer.finish()
+}
+
+// Returns a new importer with the given options.
+func newJSONSchemaImporter(options JSONSchemaOptions) *jsonSchemaImporter {
+	return &jsonSchemaImporter{
+		options:     options,
+		definitions: make(map[string]map[string]any),
+		named:       make(map[string]TypeName),
+		generated:   make(map[TypeName]bool),
+	}
+}
+
+// Adds the schemas in the definitions map which can be referenced by the prefix and their name.
+func (im *jsonSchemaImporter) addDefinitions(prefix string, definitions any) {
+	if definitionMap, ok := definitions.(map[string]any); ok {
+		for name, definition := range definitionMap {
+			if definitionSchema, ok := definition.(map[string]any); ok {
+				im.definitions[prefix+name] = definitionSchema
+			}
+		}
+	}
+}
+
+// Adds the types of the definitions which were not referenced and returns all types.
+func (im *jsonSchemaImporter) finish() ([]Type, error) {
+	refs := make([]string, 0, len(im.definitions))
+	for ref := range im.definitions {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		if _, err := im.reference(ref); err != nil {
+			return nil, err
+		}
+	}
+	return im.types, nil
+}
+
+// Returns the type of the schema, adding any types it needs. The name is used when the schema
+// needs a new type and it has no title.
+func (im *jsonSchemaImporter) typeOf(schema map[string]any, name TypeName) (TypeName, error) {
+	if ref, ok := schema["$ref"].(string); ok {
+		return im.reference(ref)
+	}
+	if title, ok := schema["title"].(string); ok && title != "" {
+		name = TypeName(title)
+	}
+
+	jsonType, _ := schema["type"].(string)
+	if types, ok := schema["type"].([]any); ok {
+		for _, t := range types {
+			if s, ok := t.(string); ok && s != "null" {
+				jsonType = s
+				break
+			}
+		}
+	}
+	if jsonType == "" {
+		if _, hasProperties := schema["properties"]; hasProperties {
+			jsonType = "object"
+		}
+	}
+
+	if allOf, ok := schema["allOf"].([]any); ok && len(allOf) > 0 {
+		merged, err := im.mergeAllOf(schema, allOf, make(map[string]bool))
+		if err != nil {
+			return "", err
+		}
+		schema, jsonType = merged, "object"
+	}
+
+	if enums, ok := schema["enum"].([]any); ok && len(enums) > 0 {
+		return im.enumType(schema, name, enums)
+	}
+
+	switch jsonType {
+	case "object":
+		return im.objectType(schema, name)
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		if items == nil {
+			return "", fmt.Errorf("array %s must have an items schema", name)
+		}
+		element, err := im.typeOf(items, name+"Item")
+		if err != nil {
+			return "", err
+		}
+		return ListTypeName(element), nil
+	case "string", "integer", "number", "boolean":
+		if format, ok := schema["format"].(string); ok {
+			if _, known := jsonSchemaFormats[format]; known || im.options.Types[format] != "" {
+				return im.formatType(format), nil
+			}
+		}
+		return im.primitiveType(jsonType), nil
+	}
+	return "", fmt.Errorf("schema %s has an unsupported type %q", name, jsonType)
+}
+
+// Returns the type of the referenced definition, adding it the first time it's referenced.
+func (im *jsonSchemaImporter) reference(ref string) (TypeName, error) {
+	if name, exists := im.named[ref]; exists {
+		return name, nil
+	}
+	definition := im.definitions[ref]
+	if definition == nil {
+		return "", fmt.Errorf("reference %s could not be found", ref)
+	}
+	name := TypeName(ref[strings.LastIndex(ref, "/")+1:])
+	if title, ok := definition["title"].(string); ok && title != "" {
+		name = TypeName(title)
+	}
+	im.named[ref] = name
+	resolved, err := im.typeOf(definition, name)
+	if err != nil {
+		return "", err
+	}
+	im.named[ref] = resolved
+	return resolved, nil
+}
+
+// Returns an object schema with the properties of the schema and every schema it's composed of.
+// The references being merged are tracked so a schema composed of itself is an error.
+func (im *jsonSchemaImporter) mergeAllOf(schema map[string]any, allOf []any, merging map[string]bool) (map[string]any, error) {
+	properties := make(map[string]any)
+	merge := func(s map[string]any) {
+		if p, ok := s["properties"].(map[string]any); ok {
+			for name, property := range p {
+				properties[name] = property
+			}
+		}
+	}
+	for _, part := range allOf {
+		partSchema, ok := part.(map[string]any)
+		if !ok {
+			continue
+		}
+		ref, isRef := partSchema["$ref"].(string)
+		if isRef {
+			if partSchema = im.definitions[ref]; partSchema == nil {
+				return nil, fmt.Errorf("reference %s could not be found", ref)
+			}
+			if merging[ref] {
+				return nil, fmt.Errorf("reference %s is composed of itself with allOf", ref)
+			}
+			merging[ref] = true
+		}
+		if nested, ok := partSchema["allOf"].([]any); ok {
+			nestedSchema, err := im.mergeAllOf(partSchema, nested, merging)
+			if err != nil {
+				return nil, err
+			}
+			partSchema = nestedSchema
+		}
+		if isRef {
+			delete(merging, ref)
+		}
+		merge(partSchema)
+	}
+	merge(schema)
+	return map[string]any{
+		"type":        "object",
+		"description": schema["description"],
+		"properties":  properties,
+	}, nil
+}
+
+// Adds a type for the object schema with a value for each property.
+func (im *jsonSchemaImporter) objectType(schema map[string]any, name TypeName) (TypeName, error) {
+	if im.generated[name] {
+		return name, nil
+	}
+	im.generated[name] = true
+	index := len(im.types)
+	im.types = append(im.types, Type{Name: name, Description: jsonSchemaDescription(schema)})
+
+	properties, _ := schema["properties"].(map[string]any)
+	paths := make([]string, 0, len(properties))
+	for path := range properties {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	values := make([]Value, 0, len(paths))
+	for _, path := range paths {
+		property, ok := properties[path].(map[string]any)
+		if !ok {
+			continue
+		}
+		propertyType, err := im.typeOf(property, name+TypeName(strings.ToUpper(path[:1])+path[1:]))
+		if err != nil {
+			return "", err
+		}
+		values = append(values, Value{
+			Path:        path,
+			Type:        propertyType,
+			Description: jsonSchemaDescription(property),
+			Nullable:    jsonSchemaNullable(property),
+		})
+	}
+	im.types[index].Values = values
+	return name, nil
+}
+
+// Adds a type for the enum schema.
+func (im *jsonSchemaImporter) enumType(schema map[string]any, name TypeName, enums []any) (TypeName, error) {
+	if im.generated[name] {
+		return name, nil
+	}
+	im.generated[name] = true
+	options := make([]string, 0, len(enums))
+	for _, enum := range enums {
+		if enum != nil {
+			options = append(options, fmt.Sprint(enum))
+		}
+	}
+	im.types = append(im.types, Type{
+		Name:        name,
+		Description: jsonSchemaDescription(schema),
+		Enums:       options,
+	})
+	return name, nil
+}
+
+// Returns the type of the JSON type, adding it if it's not mapped.
+func (im *jsonSchemaImporter) primitiveType(jsonType string) TypeName {
+	if mapped := im.options.Types[jsonType]; mapped != "" {
+		return mapped
+	}
+	name := TypeName(jsonType)
+	if !im.generated[name] {
+		im.generated[name] = true
+		t := jsonSchemaPrimitives[jsonType]
+		t.Name = name
+		t.Description = fmt.Sprintf("A JSON %s", jsonType)
+		im.types = append(im.types, t)
+	}
+	return name
+}
+
+// Returns the type of the format, adding it if it's not mapped.
+func (im *jsonSchemaImporter) formatType(format string) TypeName {
+	if mapped := im.options.Types[format]; mapped != "" {
+		return mapped
+	}
+	name := TypeName(format)
+	if !im.generated[name] {
+		im.generated[name] = true
+		im.types = append(im.types, Type{
+			Name:        name,
+			Description: fmt.Sprintf("A string in the %s format", format),
+			Parse:       jsonSchemaFormats[format],
+		})
+	}
+	return name
+}
+
+// Returns whether the schema allows null, by a type of null or the OpenAPI nullable keyword.
+func jsonSchemaNullable(schema map[string]any) bool {
+	if nullable, ok := schema["nullable"].(bool); ok && nullable {
+		return true
+	}
+	if types, ok := schema["type"].([]any); ok {
+		for _, t := range types {
+			if t == "null" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns the description of the schema.
+func jsonSchemaDescription(schema map[string]any) string {
+	description, _ := schema["description"].(string)
+	return description
+}
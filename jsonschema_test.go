@@ -0,0 +1,97 @@
+package texpr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONSchemaTypes(t *testing.T) {
+	document := `{
+		"title": "order",
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"status": {"type": "string", "enum": ["pending", "shipped"], "description": "The order status"},
+			"total": {"type": "number"},
+			"placed": {"type": "string", "format": "date-time"},
+			"customer": {"$ref": "#/$defs/customer"},
+			"lines": {"type": "array", "items": {
+				"type": "object",
+				"properties": {
+					"sku": {"type": "string"},
+					"quantity": {"type": "integer"}
+				}
+			}}
+		},
+		"$defs": {
+			"customer": {
+				"type": "object",
+				"properties": {
+					"email": {"type": "string", "format": "email"},
+					"referrer": {"$ref": "#/$defs/customer"},
+					"vip": {"type": ["boolean", "null"]}
+				}
+			},
+			"unused": {"type": "object", "properties": {"name": {"type": "string"}}}
+		}
+	}`
+
+	types, err := JSONSchemaTypes([]byte(document), JSONSchemaOptions{})
+	assert.NoError(t, err)
+
+	described := make([]string, len(types))
+	for i, t := range types {
+		values := make([]string, len(t.Values))
+		for k, v := range t.Values {
+			values[k] = v.Path + " " + string(v.Type)
+		}
+		described[i] = fmt.Sprintf("%s: %s%s", t.Name, strings.Join(values, ", "), strings.Join(t.Enums, "|"))
+	}
+	assert.Equal(t, []string{
+		"order: customer customer, id uuid, lines list<orderLinesItem>, placed date-time, status orderStatus, total number",
+		"customer: email email, referrer customer, vip boolean",
+		"email: ",
+		"boolean: ",
+		"uuid: ",
+		"orderLinesItem: quantity integer, sku string",
+		"integer: ",
+		"string: ",
+		"date-time: ",
+		"orderStatus: pending|shipped",
+		"number: ",
+		"unused: name string",
+	}, described)
+
+	s, err := NewSystemWithOptions(types, SystemOptions{IntType: "integer", BoolType: "boolean"})
+	assert.NoError(t, err)
+
+	e, err := s.Parse(Options{RootType: "order", Expression: "lines.first.quantity"})
+	assert.NoError(t, err)
+	assert.Equal(t, "integer", string(e.Last().Type.Name))
+
+	_, err = s.Type("uuid").ParseInput("not-a-uuid")
+	assert.Error(t, err)
+	parsed, err := s.Type("email").ParseInput("Bob <BOB@example.com>")
+	assert.NoError(t, err)
+	assert.Equal(t, "BOB@example.com", parsed)
+	assert.Equal(t, "The order status", s.Type("orderStatus").Description)
+
+	types, err = JSONSchemaTypes([]byte(`{"type": "object", "properties": {"n": {"type": "integer"}, "at": {"type": "string", "format": "date"}}}`), JSONSchemaOptions{
+		Root:  "thing",
+		Types: map[string]TypeName{"integer": "int", "date": "day"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, types, 1)
+	assert.Equal(t, TypeName("thing"), types[0].Name)
+	assert.Equal(t, TypeName("day"), types[0].Values[0].Type)
+	assert.Equal(t, TypeName("int"), types[0].Values[1].Type)
+
+	_, err = JSONSchemaTypes([]byte(`{"properties": {"x": {"$ref": "#/definitions/missing"}}}`), JSONSchemaOptions{})
+	assert.EqualError(t, err, "reference #/definitions/missing could not be found")
+
+	_, err = JSONSchemaTypes([]byte(`{"type": "array"}`), JSONSchemaOptions{})
+	assert.EqualError(t, err, "array root must have an items schema")
+}
@@ -0,0 +1,122 @@
+package texpr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// An evaluation used more resources than its ResourceLimits allow.
+var ErrResourceLimit = errors.New("resource limit exceeded")
+
+// A resource which is limited during evaluation.
+type ResourceLimit string
+
+const (
+	// The length in bytes of a string produced by an expression.
+	LimitStringLength ResourceLimit = "string length"
+	// The number of elements in a list (slice, array, or map) produced by an expression.
+	LimitListLength ResourceLimit = "list length"
+	// The number of expressions executed by one evaluation.
+	LimitExecutions ResourceLimit = "executions"
+)
+
+// The limits on the resources one evaluation can use, so a pathological expression can't exhaust
+// a shared service. A zero limit is unlimited.
+type ResourceLimits struct {
+	// The maximum length in bytes of each string produced while evaluating.
+	MaxStringLength int
+	// The maximum number of elements of each list produced while evaluating.
+	MaxListLength int
+	// The maximum number of expressions executed by one evaluation, counting every value, constant,
+	// and parameter each time it's evaluated.
+	MaxExecutions int
+}
+
+// The error returned when an evaluation exceeds one of its ResourceLimits.
+type ResourceLimitError struct {
+	// The resource which exceeded its limit.
+	Limit ResourceLimit
+	// The limit of the resource.
+	Max int
+	// The amount of the resource used.
+	Actual int
+	// The expression which exceeded the limit.
+	Expr *Expr
+}
+
+func (e ResourceLimitError) Error() string {
+	msg := fmt.Sprintf("%v: %s of %d is over the limit of %d", ErrResourceLimit, e.Limit, e.Actual, e.Max)
+	if e.Expr != nil {
+		msg += " at " + e.Expr.Token
+	}
+	return msg
+}
+
+// Returns ErrResourceLimit so the error can be checked with errors.Is.
+func (e ResourceLimitError) Unwrap() error {
+	return ErrResourceLimit
+}
+
+// Returns whether there are no limits.
+func (l ResourceLimits) Unlimited() bool {
+	return l.MaxStringLength <= 0 && l.MaxListLength <= 0 && l.MaxExecutions <= 0
+}
+
+// Returns a ResourceLimitError if the value the expression produced is a string or list over the limits.
+// Evaluators can use this to enforce the limits on intermediate results.
+func (l ResourceLimits) Check(e *Expr, value any) error {
+	return l.checkValue(e, reflect.ValueOf(value))
+}
+
+// Returns a ResourceLimitError if the value is a string or list over the limits.
+func (l ResourceLimits) checkValue(e *Expr, rv reflect.Value) error {
+	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		if l.MaxStringLength > 0 && rv.Len() > l.MaxStringLength {
+			return ResourceLimitError{Limit: LimitStringLength, Max: l.MaxStringLength, Actual: rv.Len(), Expr: e}
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if l.MaxListLength > 0 && rv.Len() > l.MaxListLength {
+			return ResourceLimitError{Limit: LimitListLength, Max: l.MaxListLength, Actual: rv.Len(), Expr: e}
+		}
+	}
+	return nil
+}
+
+// Returns a ResourceLimitError if the number of expressions executed is over the limit.
+func (l ResourceLimits) CheckExecutions(e *Expr, executions int) error {
+	if l.MaxExecutions > 0 && executions > l.MaxExecutions {
+		return ResourceLimitError{Limit: LimitExecutions, Max: l.MaxExecutions, Actual: executions, Expr: e}
+	}
+	return nil
+}
+
+// The resources used by one evaluation.
+type resourceUsage struct {
+	limits     ResourceLimits
+	executions int
+}
+
+// Counts the execution of the expression and returns an error if it's over the limit.
+func (u *resourceUsage) execute(e *Expr) error {
+	if u == nil {
+		return nil
+	}
+	u.executions++
+	return u.limits.CheckExecutions(e, u.executions)
+}
+
+// Returns an error if the value the expression produced is over the limits.
+func (u *resourceUsage) check(e *Expr, v reflect.Value) error {
+	if u == nil || !v.IsValid() {
+		return nil
+	}
+	return u.limits.checkValue(e, v)
+}
@@ -0,0 +1,145 @@
+package texpr
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type limitsText string
+
+func (t limitsText) Repeat(n int) limitsText {
+	return limitsText(strings.Repeat(string(t), n))
+}
+
+func (t limitsText) Append(other limitsText) limitsText {
+	return t + other
+}
+
+func (t limitsText) Length() int {
+	return len(t)
+}
+
+type limitsDoc struct {
+	Title limitsText
+	Tags  []limitsText
+}
+
+func TestResourceLimits(t *testing.T) {
+	newReflect := func(limits ResourceLimits) *Reflect {
+		r, err := NewReflect(ReflectOptions{
+			IntType: "int",
+			Limits:  limits,
+			Types: map[reflect.Type]Type{
+				TypeOf[int]():        {Name: "int", Parse: func(x string) (any, error) { return strconv.Atoi(x) }},
+				TypeOf[limitsText](): {Name: "text", ParseOrder: -1, Parse: func(x string) (any, error) { return limitsText(x), nil }},
+				TypeOf[limitsDoc]():  {Name: "doc"},
+			},
+		})
+		assert.NoError(t, err)
+		return r
+	}
+	doc := limitsDoc{Title: "a b c", Tags: []limitsText{"x", "y", "z"}}
+
+	tests := []struct {
+		name       string
+		limits     ResourceLimits
+		expression string
+		result     any
+		err        *ResourceLimitError
+	}{
+		{
+			name:       "unlimited",
+			expression: "title.repeat(1000).length",
+			result:     5000,
+		},
+		{
+			name:       "string under",
+			limits:     ResourceLimits{MaxStringLength: 10},
+			expression: "title.repeat(2)",
+			result:     limitsText("a b ca b c"),
+		},
+		{
+			name:       "string over",
+			limits:     ResourceLimits{MaxStringLength: 10},
+			expression: "title.repeat(3).length",
+			err:        &ResourceLimitError{Limit: LimitStringLength, Max: 10, Actual: 15},
+		},
+		{
+			name:       "string argument over",
+			limits:     ResourceLimits{MaxStringLength: 5},
+			expression: "tags.first.append(tags.at(1).repeat(6))",
+			err:        &ResourceLimitError{Limit: LimitStringLength, Max: 5, Actual: 6},
+		},
+		{
+			name:       "list under",
+			limits:     ResourceLimits{MaxListLength: 3},
+			expression: "tags.at(2).repeat(2)",
+			result:     limitsText("zz"),
+		},
+		{
+			name:       "list field",
+			limits:     ResourceLimits{MaxListLength: 2},
+			expression: "tags.count",
+			err:        &ResourceLimitError{Limit: LimitListLength, Max: 2, Actual: 3},
+		},
+		{
+			name:       "executions under",
+			limits:     ResourceLimits{MaxExecutions: 4},
+			expression: "title.repeat(2).length",
+			result:     10,
+		},
+		{
+			name:       "executions over",
+			limits:     ResourceLimits{MaxExecutions: 3},
+			expression: "title.repeat(2).length",
+			err:        &ResourceLimitError{Limit: LimitExecutions, Max: 3, Actual: 4},
+		},
+		{
+			name:       "executions of arguments",
+			limits:     ResourceLimits{MaxExecutions: 3},
+			expression: "title.repeat(tags.count)",
+			err:        &ResourceLimitError{Limit: LimitExecutions, Max: 3, Actual: 4},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newReflect(test.limits)
+			e, err := r.Parse(Options{RootType: "doc", Expression: test.expression})
+			assert.NoError(t, err)
+
+			result, err := r.Evaluate(e, doc)
+			if test.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, test.result, result)
+				return
+			}
+			assert.ErrorIs(t, err, ErrResourceLimit)
+			limitErr := ResourceLimitError{}
+			assert.ErrorAs(t, err, &limitErr)
+			assert.Equal(t, test.err.Limit, limitErr.Limit)
+			assert.Equal(t, test.err.Max, limitErr.Max)
+			assert.Equal(t, test.err.Actual, limitErr.Actual)
+			assert.NotNil(t, limitErr.Expr)
+		})
+	}
+
+	r := newReflect(ResourceLimits{MaxExecutions: 3})
+	e, err := r.Parse(Options{RootType: "doc", Expression: "title.repeat(2).length"})
+	assert.NoError(t, err)
+	_, err = r.Evaluate(e, doc)
+	assert.EqualError(t, err, "resource limit exceeded: executions of 4 is over the limit of 3 at length")
+	partial, err := r.CompilePartial(e, map[string]any{"title": limitsText("a")})
+	assert.NoError(t, err)
+	result, err := partial(doc, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result)
+
+	assert.NoError(t, ResourceLimits{}.Check(nil, strings.Repeat("a", 100)))
+	assert.ErrorIs(t, ResourceLimits{MaxListLength: 1}.Check(nil, []int{1, 2}), ErrResourceLimit)
+	assert.NoError(t, ResourceLimits{MaxListLength: 1}.Check(nil, (*[]int)(nil)))
+}
@@ -0,0 +1,195 @@
+package texpr
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// An operation of the values added to list types, see System.ListOf.
+type ListOperation string
+
+const (
+	// Returns the number of elements in the list. Requires SystemOptions.IntType.
+	ListCount ListOperation = "count"
+	// Returns whether the list has no elements. Requires SystemOptions.BoolType.
+	ListIsEmpty ListOperation = "isEmpty"
+	// Returns the first element of the list.
+	ListFirst ListOperation = "first"
+	// Returns the last element of the list.
+	ListLast ListOperation = "last"
+	// Returns the element at the given index. Requires SystemOptions.IntType.
+	ListAt ListOperation = "at"
+	// Returns whether the list has an element equal to the given value. Requires SystemOptions.BoolType.
+	ListContains ListOperation = "contains"
+)
+
+// Returns the name of the list type of the given element type.
+func ListTypeName(element TypeName) TypeName {
+	return "list<" + element + ">"
+}
+
+// Returns the list type for the element type, creating it the first time it's requested. Values and
+// parameters given to a system can also use the name of a list type (see ListTypeName). List types
+// have count, isEmpty, first, last, at, and contains values (see ListOperation) where the values which
+// return a number or boolean are only added when SystemOptions.IntType or SystemOptions.BoolType are given.
+func (sys System) ListOf(element TypeName) (*Type, error) {
+	list, err := sys.ResolveType(ListTypeName(element))
+	if err != nil {
+		return nil, err
+	}
+	if list.ElementType() == nil {
+		return nil, SystemError{
+			Message: fmt.Sprintf("list type %s is already defined", list.Name),
+			Type:    list,
+			Kind:    ErrDuplicatePath,
+		}
+	}
+	return list, nil
+}
+
+// Returns a new list type of the element type. The types of its values are linked when the type is resolved.
+func (sys System) newListType(elementType *Type) *Type {
+	list := &Type{
+		Name:        ListTypeName(elementType.Name),
+		Description: fmt.Sprintf("A list of %s", elementType.Name),
+		Values:      make([]Value, 0, 6),
+		element:     elementType,
+	}
+	intType, boolType := sys.options.IntType, sys.options.BoolType
+	add := func(op ListOperation, valueType TypeName, description string, parameters ...Parameter) {
+		if valueType != "" {
+			list.Values = append(list.Values, Value{
+				Path:          string(op),
+				Type:          valueType,
+				Description:   description,
+				Parameters:    parameters,
+				listOperation: op,
+			})
+		}
+	}
+	add(ListCount, intType, "The number of elements in the list")
+	add(ListIsEmpty, boolType, "Whether the list has no elements")
+	add(ListFirst, elementType.Name, "The first element in the list")
+	add(ListLast, elementType.Name, "The last element in the list")
+	if intType != "" {
+		add(ListAt, elementType.Name, "The element at the given index", Parameter{Name: "index", Type: intType})
+	}
+	add(ListContains, boolType, "Whether the list has an element equal to the given value", Parameter{Name: "value", Type: elementType.Name})
+	return list
+}
+
+// Links the elements of the list literal and sets its type to the list type of its elements. The element
+// type is the element type of the expected list type the first element is or converts to, otherwise the
+// type of the first element, and the other elements are converted to it like arguments.
+func (sys System) linkList(list *Expr, expectedTypes []*Type, ctx *linkContext) []ParseError {
+	errs := make([]ParseError, 0)
+	list.Type = Unknown
+	if list.Called {
+		errs = append(errs, NewParseErrorKind(list, ErrSyntax, "a list is not a value and can't be called"))
+	}
+	elementTypes := make([]*Type, 0, len(expectedTypes))
+	for _, expectedType := range expectedTypes {
+		if elementType := expectedType.ElementType(); elementType != nil {
+			elementTypes = append(elementTypes, elementType)
+		}
+	}
+
+	var elementType *Type
+	for _, element := range list.Arguments {
+		errs = append(errs, sys.link(element, elementTypes, ctx)...)
+		if elementType == nil {
+			elementType = element.Last().Type
+			elementTypes = []*Type{elementType}
+		}
+		if elementType == Unknown {
+			elementTypes = nil
+		}
+	}
+	if elementType == nil && len(elementTypes) > 0 {
+		elementType = elementTypes[0]
+	}
+	if elementType == nil {
+		return append(errs, NewParseErrorKind(list, ErrTypeMismatch, "the element type of an empty list can't be determined without an expected list type"))
+	}
+	if elementType == Unknown {
+		return errs
+	}
+
+	listType, err := sys.ListOf(elementType.Name)
+	if err != nil {
+		parseError := NewParseErrorKind(list, ErrUnknownType, err.Error())
+		parseError.Cause = err
+		return append(errs, parseError)
+	}
+	list.Type = listType
+	ctx.tracef(TraceEvent{Kind: TraceList, Expr: list, Type: listType}, "list of %d elements is %s", len(list.Arguments), listType.Name)
+	return errs
+}
+
+// Returns the element type name of a list type name.
+func listElement(name TypeName) (TypeName, bool) {
+	s := string(name)
+	if strings.HasPrefix(s, "list<") && strings.HasSuffix(s, ">") {
+		return TypeName(s[5 : len(s)-1]), true
+	}
+	return "", false
+}
+
+// Returns the element type of a list type created by System.ListOf, or nil if this is not a list type.
+func (t Type) ElementType() *Type {
+	return t.element
+}
+
+// Returns the list operation the value was added to a list type for, if any.
+func (v Value) ListOperation() (ListOperation, bool) {
+	return v.listOperation, v.listOperation != ""
+}
+
+// Returns the result of the operation on the list given the argument values. The list can be any
+// slice or array, elements are compared with StandardOperations.
+func (op ListOperation) Apply(list any, args []any) (any, error) {
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("list operation %s expects a list but was given %T", op, list)
+	}
+	n := rv.Len()
+	switch op {
+	case ListCount:
+		return n, nil
+	case ListIsEmpty:
+		return n == 0, nil
+	case ListFirst, ListLast:
+		if n == 0 {
+			return nil, fmt.Errorf("list operation %s on an empty list", op)
+		}
+		if op == ListFirst {
+			return rv.Index(0).Interface(), nil
+		}
+		return rv.Index(n - 1).Interface(), nil
+	case ListAt:
+		if len(args) != 1 {
+			return nil, fmt.Errorf("list operation %s expects 1 argument but was given %d", op, len(args))
+		}
+		index := reflect.ValueOf(args[0])
+		if !isInt(index) && !isUint(index) {
+			return nil, fmt.Errorf("list operation %s expects an index but was given %T", op, args[0])
+		}
+		i := toInt(index)
+		if i < 0 || i >= int64(n) {
+			return nil, fmt.Errorf("list index %d is out of range for a list of %d elements", i, n)
+		}
+		return rv.Index(int(i)).Interface(), nil
+	case ListContains:
+		if len(args) != 1 {
+			return nil, fmt.Errorf("list operation %s expects 1 argument but was given %d", op, len(args))
+		}
+		for i := 0; i < n; i++ {
+			if equal, _ := (StandardOperations{}).Equal(rv.Index(i).Interface(), args[0]); equal {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return nil, fmt.Errorf("unknown list operation %s", op)
+}
@@ -0,0 +1,249 @@
+package texpr
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reflectOrder struct {
+	Items []string
+}
+
+func TestListOf(t *testing.T) {
+	s, err := NewSystemWithOptions([]Type{{
+		Name:  "int",
+		Parse: func(x string) (any, error) { return strconv.Atoi(x) },
+	}, {
+		Name: "bool",
+	}, {
+		Name:  "text",
+		Parse: func(x string) (any, error) { return x, nil },
+	}, {
+		Name: "order",
+		Values: []Value{
+			{Path: "tags", Type: "list<text>"},
+			{Path: "matrix", Type: "list<list<int>>"},
+		},
+	}}, SystemOptions{IntType: "int", BoolType: "bool"})
+	assert.NoError(t, err)
+
+	list, err := s.ListOf("text")
+	assert.NoError(t, err)
+	assert.Equal(t, TypeName("list<text>"), list.Name)
+	assert.Equal(t, s.Type("text"), list.ElementType())
+	assert.Same(t, list, s.Type("list<text>"))
+	assert.Same(t, list, s.Type("order").Value("tags").ValueType())
+
+	again, err := s.ListOf("text")
+	assert.NoError(t, err)
+	assert.Same(t, list, again)
+
+	paths := make([]string, len(list.Values))
+	for i, v := range list.Values {
+		paths[i] = v.Path
+	}
+	assert.Equal(t, []string{"count", "isEmpty", "first", "last", "at", "contains"}, paths)
+
+	e, err := s.Parse(Options{RootType: "order", Expression: "matrix.at(1).first"})
+	assert.NoError(t, err)
+	assert.Equal(t, "int", string(e.Last().Type.Name))
+
+	e, err = s.Parse(Options{RootType: "order", Expression: "tags.contains(a)"})
+	assert.NoError(t, err)
+	assert.Equal(t, "bool", string(e.Last().Type.Name))
+	op, isListOperation := e.Last().Value.ListOperation()
+	assert.True(t, isListOperation)
+	assert.Equal(t, ListContains, op)
+
+	e, err = s.Parse(Options{RootType: "order", Expression: "tags.isEmpty"})
+	assert.NoError(t, err)
+	assert.Equal(t, "bool", string(e.Last().Type.Name))
+
+	_, err = s.ListOf("missing")
+	assert.ErrorIs(t, err, ErrUnknownType)
+
+	minimal, err := NewSystem([]Type{{Name: "text"}})
+	assert.NoError(t, err)
+	list, err = minimal.ListOf("text")
+	assert.NoError(t, err)
+	assert.Len(t, list.Values, 2)
+
+	wg := sync.WaitGroup{}
+	lists := make([]*Type, 8)
+	for i := range lists {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lists[i], _ = s.ListOf("int")
+		}()
+	}
+	wg.Wait()
+	for _, l := range lists {
+		assert.Same(t, lists[0], l)
+	}
+
+	results := []struct {
+		op       ListOperation
+		args     []any
+		expected any
+	}{
+		{ListCount, nil, 3},
+		{ListIsEmpty, nil, false},
+		{ListFirst, nil, "a"},
+		{ListLast, nil, "c"},
+		{ListAt, []any{1}, "b"},
+		{ListContains, []any{"c"}, true},
+		{ListContains, []any{"d"}, false},
+	}
+	for _, test := range results {
+		result, err := test.op.Apply([]string{"a", "b", "c"}, test.args)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, result, test.op)
+	}
+	_, err = ListAt.Apply([]string{}, []any{0})
+	assert.EqualError(t, err, "list index 0 is out of range for a list of 0 elements")
+	_, err = ListFirst.Apply(3, nil)
+	assert.Error(t, err)
+
+	r, err := NewReflect(ReflectOptions{
+		IntType:  "int",
+		BoolType: "bool",
+		Types: map[reflect.Type]Type{
+			TypeOf[int]():          {Name: "int", Parse: func(x string) (any, error) { return strconv.Atoi(x) }},
+			TypeOf[bool]():         {Name: "bool"},
+			TypeOf[string]():       {Name: "text", Parse: func(x string) (any, error) { return x, nil }, ParseOrder: -1},
+			TypeOf[reflectOrder](): {Name: "order"},
+		},
+	})
+	assert.NoError(t, err)
+
+	e, err = r.Parse(Options{RootType: "order", Expression: "items.at(1)"})
+	assert.NoError(t, err)
+	result, err := r.Evaluate(e, reflectOrder{Items: []string{"x", "y"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "y", result)
+}
+
+func TestListLiterals(t *testing.T) {
+	s, err := NewSystemWithOptions([]Type{{
+		Name:  "int",
+		Parse: func(x string) (any, error) { return strconv.Atoi(x) },
+		As:    map[TypeName]string{"text": "text"},
+		Values: []Value{
+			{Path: "text", Type: "text"},
+		},
+	}, {
+		Name: "bool",
+	}, {
+		Name:       "text",
+		Parse:      func(x string) (any, error) { return x, nil },
+		ParseOrder: -1,
+	}, {
+		Name: "order",
+		Values: []Value{
+			{Path: "tags", Type: "list<text>"},
+			{Path: "quantity", Type: "int"},
+			{Path: "tagged", Type: "bool", Parameters: []Parameter{{Name: "tags", Type: "list<text>"}}},
+		},
+	}}, SystemOptions{IntType: "int", BoolType: "bool"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		expression string
+		expected   []TypeName
+		serialized string
+		listType   TypeName
+		err        error
+	}{
+		{expression: "[1, 2, 3]", serialized: "['1','2','3']", listType: "list<int>"},
+		{expression: "[1, 2].contains(quantity)", serialized: "['1','2'].contains(quantity)", listType: "list<int>"},
+		{expression: "tagged([a, quantity,])", serialized: "tagged(['a',quantity.text])", listType: "list<text>"},
+		{expression: "[]", expected: []TypeName{"list<text>"}, serialized: "[]", listType: "list<text>"},
+		{expression: "[quantity, 2]", expected: []TypeName{"list<text>"}, serialized: "[quantity.text,'2']", listType: "list<text>"},
+		{expression: "[[1], [2, 3]].first", serialized: "[['1'],['2','3']].first", listType: "list<list<int>>"},
+		{expression: "[]", err: ErrTypeMismatch},
+		{expression: "[quantity, tags]", err: ErrTypeMismatch},
+		{expression: "[1, 2", err: ErrSyntax},
+		{expression: "[1, 2)", err: ErrSyntax},
+		{expression: "quantity[1]", err: ErrSyntax},
+		{expression: "[1](2)", err: ErrSyntax},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := s.Parse(Options{RootType: "order", Expression: test.expression, ExpectedTypes: test.expected})
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.serialized, e.String())
+			list := e
+			if !list.List {
+				list = e.Arguments[0]
+			}
+			assert.True(t, list.List)
+			assert.Equal(t, test.listType, list.Type.Name)
+
+			decoded, err := s.DecodeExpr(EncodeExpr(e), Options{ExpectedTypes: test.expected})
+			assert.NoError(t, err)
+			assert.Equal(t, test.serialized, decoded.String())
+		})
+	}
+
+	_, err = s.Parse(Options{RootType: "order", Expression: "[1, 2"})
+	assert.EqualError(t, err, "expression missing terminating ]")
+
+	r, err := NewReflect(ReflectOptions{
+		IntType:  "int",
+		BoolType: "bool",
+		Types: map[reflect.Type]Type{
+			TypeOf[int]():          {Name: "int", Parse: func(x string) (any, error) { return strconv.Atoi(x) }},
+			TypeOf[bool]():         {Name: "bool"},
+			TypeOf[string]():       {Name: "text", Parse: func(x string) (any, error) { return x, nil }, ParseOrder: -1},
+			TypeOf[reflectOrder](): {Name: "order"},
+		},
+	})
+	assert.NoError(t, err)
+
+	evaluations := []struct {
+		expression string
+		expected   any
+	}{
+		{"[3, 4]", []int{3, 4}},
+		{"[items.last, z].at(1)", "z"},
+		{"[items.first, z].contains(x)", true},
+		{"[[1], [2, 3]].last.count", 2},
+	}
+	for _, test := range evaluations {
+		e, err := r.Parse(Options{RootType: "order", Expression: test.expression})
+		assert.NoError(t, err)
+		result, err := r.Evaluate(e, reflectOrder{Items: []string{"x", "y"}})
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, result, test.expression)
+	}
+
+	e, err := s.Parse(Options{RootType: "order", Expression: "[1, quantity]"})
+	assert.NoError(t, err)
+	compiled, err := Compile[string](e, CompileSourceLookup[string]{
+		ConstantCompiler: func(e *Expr, root *Type, previous string, arguments []string) (string, error) {
+			return e.Token, nil
+		},
+		ListCompiler: func(e *Expr, root *Type, previous string, elements []string) (string, error) {
+			return "ARRAY[" + strings.Join(elements, ", ") + "]", nil
+		},
+		TypeCompilers: TypeCompilers[string]{"order": {"quantity": func(e *Expr, root *Type, previous string, arguments []string) (string, error) {
+			return "quantity", nil
+		}}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ARRAY[1, quantity]", compiled)
+
+	_, err = Compile[Run](e, requiredSource{compileOptions})
+	assert.EqualError(t, err, "a list of 2 elements is not supported by the compile source")
+}
@@ -0,0 +1,64 @@
+package texpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedLiterals(t *testing.T) {
+	literals, err := NewSystemWithOptions(sys.definitions, SystemOptions{Literals: map[string]TypeName{"date": typeDate, "d": typeDuration}})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		expression string
+		expected   string
+		typeName   TypeName
+		infix      bool
+		err        error
+	}{
+		{expression: "date'2023-01-05'", expected: "date'2023-01-05'", typeName: typeDate},
+		{expression: "DATE\"2023-01-05\".add(1, d'day')", expected: "DATE'2023-01-05'.add('1',d'day')", typeName: typeDate},
+		{expression: "time.today.add(1, d'''week''')", expected: "time.today.add('1',d'week')", typeName: typeDate},
+		{expression: "time.now.hour > 1 and date'2023-01-05'.dayOfMonth = 5", expected: "time.now.hour>('1').and(date'2023-01-05'.dayOfMonth=('5'))", typeName: typeBool, infix: true},
+		{expression: "date'tomorrow'", err: ErrTypeMismatch},
+		{expression: "time.today.add(d'2', day)", err: ErrTypeMismatch},
+		{expression: "date'2023-01-05", err: ErrUnterminatedConstant},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			opts := Options{RootType: typeContext, Expression: test.expression}
+			if test.infix {
+				opts.Infix = DefaultPrecedence
+			}
+			e, err := literals.Parse(opts)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.expected, e.String())
+				assert.Equal(t, test.typeName, e.Last().Type.Name)
+
+				decoded, err := literals.DecodeExpr(EncodeExpr(e), Options{})
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, decoded.String())
+			}
+		})
+	}
+
+	e, err := literals.Parse(Options{RootType: typeContext, Expression: "date'2023-01-05'"})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC), e.Parsed)
+	assert.Equal(t, "date", e.Literal)
+	assert.Equal(t, Position{}, e.Start)
+
+	_, err = NewSystemWithOptions(sys.definitions, SystemOptions{Literals: map[string]TypeName{"da te": typeDate}})
+	assert.ErrorIs(t, err, ErrInvalidPath)
+	_, err = NewSystemWithOptions(sys.definitions, SystemOptions{Literals: map[string]TypeName{"x": "missing"}})
+	assert.ErrorIs(t, err, ErrUnknownType)
+	_, err = NewSystemWithOptions(sys.definitions, SystemOptions{Literals: map[string]TypeName{"d": typeDate, "D": typeDuration}})
+	assert.ErrorIs(t, err, ErrDuplicatePath)
+}
@@ -0,0 +1,59 @@
+package texpr
+
+import (
+	"strings"
+)
+
+// Text keyed by locale, ex: "en", "en-US", "fr".
+type Localized map[string]string
+
+// Returns the text for the given locale. Locales are compared case insensitively and "_" is treated
+// like "-". If there is no text for the locale the base language is tried (en-US -> en), and if
+// that does not exist the fallback is returned.
+func (l Localized) Get(locale string, fallback string) string {
+	if len(l) == 0 || locale == "" {
+		return fallback
+	}
+	target := normalizeLocale(locale)
+	base, _, _ := strings.Cut(target, "-")
+	baseText, baseExists := "", false
+	for key, text := range l {
+		normalized := normalizeLocale(key)
+		if normalized == target {
+			return text
+		}
+		if normalized == base {
+			baseText, baseExists = text, true
+		}
+	}
+	if baseExists {
+		return baseText
+	}
+	return fallback
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// Returns the description of the type in the given locale, falling back to Description.
+func (t Type) Describe(locale string) string {
+	return t.Descriptions.Get(locale, t.Description)
+}
+
+// Returns the description of the enum option (see Type.EnumOptions) in the given locale, falling back
+// to its Description. If there is no description for the enum option an empty string is returned.
+func (t Type) DescribeEnum(enumValue string, locale string) string {
+	option, _ := t.EnumOption(enumValue)
+	return option.Descriptions.Get(locale, option.Description)
+}
+
+// Returns the description of the value in the given locale, falling back to Description.
+func (v Value) Describe(locale string) string {
+	return v.Descriptions.Get(locale, v.Description)
+}
+
+// Returns the description of the parameter in the given locale, falling back to Description.
+func (p Parameter) Describe(locale string) string {
+	return p.Descriptions.Get(locale, p.Description)
+}
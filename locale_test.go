@@ -0,0 +1,63 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalized(t *testing.T) {
+	l := Localized{"en": "Hello", "fr-CA": "Allô", "es": "Hola"}
+
+	assert.Equal(t, "Hello", l.Get("en", "x"))
+	assert.Equal(t, "Hello", l.Get("en-US", "x"))
+	assert.Equal(t, "Hello", l.Get("EN_gb", "x"))
+	assert.Equal(t, "Allô", l.Get("fr_ca", "x"))
+	assert.Equal(t, "x", l.Get("fr", "x"))
+	assert.Equal(t, "x", l.Get("", "x"))
+	assert.Equal(t, "x", Localized(nil).Get("en", "x"))
+}
+
+func TestDescribe(t *testing.T) {
+	localized := NewSystemRequired([]Type{{
+		Name:         typeDayOfWeek,
+		Description:  "A day of the week",
+		Descriptions: Localized{"es": "Un día de la semana"},
+		Enums:        []string{"monday", "tuesday"},
+		EnumOptions: map[string]EnumOption{
+			"monday": {Description: "The first work day", Descriptions: Localized{"es": "Lunes"}},
+		},
+		Values: []Value{{
+			Path:         "is",
+			Type:         typeDayOfWeek,
+			Description:  "Is the given day",
+			Descriptions: Localized{"es": "Es el día"},
+			Parameters: []Parameter{{
+				Name:         "day",
+				Type:         typeDayOfWeek,
+				Description:  "The day",
+				Descriptions: Localized{"es": "El día"},
+			}},
+		}},
+	}})
+
+	day := localized.Type(typeDayOfWeek)
+	assert.Equal(t, "Un día de la semana", day.Describe("es-MX"))
+	assert.Equal(t, "A day of the week", day.Describe("de"))
+	assert.Equal(t, "Lunes", day.DescribeEnum("Monday", "es"))
+	assert.Equal(t, "The first work day", day.DescribeEnum("monday", "de"))
+	assert.Equal(t, "", day.DescribeEnum("tuesday", "es"))
+
+	is := day.Value("is")
+	assert.Equal(t, "Es el día", is.Describe("es"))
+	assert.Equal(t, "Is the given day", is.Describe(""))
+	assert.Equal(t, "El día", is.Parameters[0].Describe("es"))
+
+	completions := localized.Complete(Options{RootType: typeDayOfWeek, Expression: "i", Locale: "es"}, 1)
+	assert.Len(t, completions, 1)
+	assert.Equal(t, "Es el día", completions[0].Description)
+
+	docs := localized.LocalizedDocs("es")
+	assert.Equal(t, "Un día de la semana", docs.Index[0].Description)
+	assert.Equal(t, "Lunes", docs.Index[2].Description)
+}
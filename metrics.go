@@ -0,0 +1,121 @@
+package texpr
+
+import (
+	"errors"
+	"expvar"
+	"time"
+)
+
+// Records the work done on expressions so services can monitor their rule workloads, see
+// SystemOptions.Metrics, CompileWithMetrics, NewMeasuredEvaluator, and CacheOptions.Metrics. The
+// methods are called concurrently. ExpvarMetrics and PrometheusMetrics implement it.
+type Metrics interface {
+	// Called after an expression is parsed with how long it took and the error returned, if any.
+	Parsed(duration time.Duration, err error)
+	// Called after an expression is compiled with how long it took and the error returned, if any.
+	Compiled(duration time.Duration, err error)
+	// Called after an expression is evaluated with how long it took and the error returned, if any.
+	Evaluated(duration time.Duration, err error)
+	// Called when a CachedEvaluator looks up a result with whether the result was cached.
+	CacheLookup(hit bool)
+}
+
+// Returns the kinds of the errors, like "unknown value" for ErrUnknownValue, used to count errors by kind.
+// Errors without a kind are "other" and ResourceLimitErrors are "resource limit exceeded".
+func ErrorKinds(err error) []string {
+	if err == nil {
+		return nil
+	}
+	var parseErrors ParseErrors
+	if errors.As(err, &parseErrors) {
+		kinds := make([]string, 0, len(parseErrors))
+		for _, parseError := range parseErrors {
+			kinds = append(kinds, ErrorKinds(parseError)...)
+		}
+		return kinds
+	}
+	var parseError ParseError
+	if errors.As(err, &parseError) && parseError.Kind != nil {
+		return []string{parseError.Kind.Error()}
+	}
+	if errors.Is(err, ErrResourceLimit) {
+		return []string{ErrResourceLimit.Error()}
+	}
+	return []string{"other"}
+}
+
+// Compiles the expression like Compile and records how long it took with the metrics.
+func CompileWithMetrics[CE any](e *Expr, source CompileSource[CE], metrics Metrics) (CE, error) {
+	start := time.Now()
+	compiled, err := Compile(e, source)
+	metrics.Compiled(time.Since(start), err)
+	return compiled, err
+}
+
+// Returns an evaluator which records how long each evaluation of the given evaluator takes with the metrics.
+func NewMeasuredEvaluator(evaluator Evaluator, metrics Metrics) Evaluator {
+	return EvaluatorFunc(func(e *Expr, root any) (any, error) {
+		start := time.Now()
+		result, err := evaluator.Evaluate(e, root)
+		metrics.Evaluated(time.Since(start), err)
+		return result, err
+	})
+}
+
+// Metrics published with the expvar package, so they're served by its /debug/vars handler as a map
+// of counters: parses, compiles, and evaluations, their errors by kind (like parseErrors), their
+// total durations in nanoseconds (like parseNanos), and cacheHits and cacheMisses.
+type ExpvarMetrics struct {
+	vars *expvar.Map
+}
+
+var _ Metrics = &ExpvarMetrics{}
+
+// Returns metrics published with the expvar package under the given name. Like expvar.NewMap it
+// panics if the name is already published.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	vars := expvar.NewMap(name)
+	for _, operation := range []string{"parse", "compile", "evaluation"} {
+		vars.Set(operation+"Errors", new(expvar.Map))
+	}
+	return &ExpvarMetrics{vars: vars}
+}
+
+// Returns the published map of counters.
+func (m *ExpvarMetrics) Map() *expvar.Map {
+	return m.vars
+}
+
+func (m *ExpvarMetrics) Parsed(duration time.Duration, err error) {
+	m.record("parse", duration, err)
+}
+
+func (m *ExpvarMetrics) Compiled(duration time.Duration, err error) {
+	m.record("compile", duration, err)
+}
+
+func (m *ExpvarMetrics) Evaluated(duration time.Duration, err error) {
+	m.record("evaluation", duration, err)
+}
+
+func (m *ExpvarMetrics) CacheLookup(hit bool) {
+	if hit {
+		m.vars.Add("cacheHits", 1)
+	} else {
+		m.vars.Add("cacheMisses", 1)
+	}
+}
+
+// Counts the operation, its duration, and the kinds of its errors.
+func (m *ExpvarMetrics) record(operation string, duration time.Duration, err error) {
+	m.vars.Add(operation+"s", 1)
+	m.vars.Add(operation+"Nanos", duration.Nanoseconds())
+	kinds := ErrorKinds(err)
+	if len(kinds) == 0 {
+		return
+	}
+	errs := m.vars.Get(operation + "Errors").(*expvar.Map)
+	for _, kind := range kinds {
+		errs.Add(kind, 1)
+	}
+}
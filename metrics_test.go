@@ -0,0 +1,112 @@
+package texpr
+
+import (
+	"errors"
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Metrics which record the calls made to them.
+type recordedMetrics struct {
+	lock  sync.Mutex
+	calls []string
+	errs  []error
+}
+
+func (m *recordedMetrics) record(call string, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.calls = append(m.calls, call)
+	m.errs = append(m.errs, err)
+}
+
+func (m *recordedMetrics) Parsed(duration time.Duration, err error)    { m.record("parsed", err) }
+func (m *recordedMetrics) Compiled(duration time.Duration, err error)  { m.record("compiled", err) }
+func (m *recordedMetrics) Evaluated(duration time.Duration, err error) { m.record("evaluated", err) }
+func (m *recordedMetrics) CacheLookup(hit bool) {
+	if hit {
+		m.record("hit", nil)
+	} else {
+		m.record("miss", nil)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	metrics := &recordedMetrics{}
+	measured, err := NewSystemWithOptions([]Type{{
+		Name:  "num",
+		Parse: func(x string) (any, error) { return x, nil },
+	}, {
+		Name:   "root",
+		Values: []Value{{Path: "a", Type: "num"}},
+	}}, SystemOptions{Metrics: metrics})
+	assert.NoError(t, err)
+
+	e, err := measured.Parse(Options{RootType: "root", Expression: "a"})
+	assert.NoError(t, err)
+	_, err = measured.Parse(Options{RootType: "root", Expression: "a.b"})
+	assert.ErrorIs(t, err, ErrUnknownValue)
+
+	compiled, err := CompileWithMetrics[string](e, CompileSourceLookup[string]{
+		TypeCompilers: TypeCompilers[string]{"root": {"a": func(e *Expr, root *Type, previous string, arguments []string) (string, error) {
+			return "a", nil
+		}}},
+	}, metrics)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", compiled)
+	_, err = CompileWithMetrics[string](e, CompileSourceLookup[string]{}, metrics)
+	assert.Error(t, err)
+
+	evaluator := NewMeasuredEvaluator(NewCachedEvaluator(EvaluatorFunc(func(e *Expr, root any) (any, error) {
+		return root.(map[string]int)[e.Token], nil
+	}), CacheOptions{Metrics: metrics}), metrics)
+	result, err := evaluator.Evaluate(e, map[string]int{"a": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result)
+	result, err = evaluator.Evaluate(e, map[string]int{"a": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result)
+
+	assert.Equal(t, []string{"parsed", "parsed", "compiled", "compiled", "miss", "evaluated", "hit", "evaluated"}, metrics.calls)
+	assert.NoError(t, metrics.errs[0])
+	assert.ErrorIs(t, metrics.errs[1], ErrUnknownValue)
+	assert.NoError(t, metrics.errs[2])
+	assert.Error(t, metrics.errs[3])
+}
+
+func TestErrorKinds(t *testing.T) {
+	_, err := sys.Parse(Options{RootType: typeContext, Expression: "time.today.add(x)"})
+	assert.Equal(t, []string{"invalid number of arguments", "type mismatch"}, ErrorKinds(err))
+	assert.Equal(t, []string{"resource limit exceeded"}, ErrorKinds(ResourceLimitError{Limit: "steps"}))
+	assert.Equal(t, []string{"other"}, ErrorKinds(errors.New("failed")))
+	assert.Equal(t, []string{"other"}, ErrorKinds(ErrNoRoot))
+	assert.Nil(t, ErrorKinds(nil))
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	metrics := NewExpvarMetrics("texprTestMetrics")
+	assert.Same(t, metrics.Map(), expvar.Get("texprTestMetrics"))
+
+	metrics.Parsed(time.Millisecond, nil)
+	_, err := sys.Parse(Options{RootType: typeContext, Expression: "time.today.add(x)"})
+	metrics.Parsed(2*time.Millisecond, err)
+	metrics.Evaluated(time.Microsecond, errors.New("failed"))
+	metrics.CacheLookup(true)
+	metrics.CacheLookup(false)
+	metrics.CacheLookup(true)
+
+	vars := metrics.Map()
+	assert.Equal(t, "2", vars.Get("parses").String())
+	assert.Equal(t, "3000000", vars.Get("parseNanos").String())
+	assert.JSONEq(t, `{"invalid number of arguments": 1, "type mismatch": 1}`, vars.Get("parseErrors").String())
+	assert.Equal(t, "1", vars.Get("evaluations").String())
+	assert.JSONEq(t, `{"other": 1}`, vars.Get("evaluationErrors").String())
+	assert.Nil(t, vars.Get("compiles"))
+	assert.JSONEq(t, `{}`, vars.Get("compileErrors").String())
+	assert.Equal(t, "2", vars.Get("cacheHits").String())
+	assert.Equal(t, "1", vars.Get("cacheMisses").String())
+}
@@ -0,0 +1,128 @@
+package texpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The renames of a schema change which are applied to stored expressions, see Migration.Migrate.
+type Migration struct {
+	// The new type names keyed by the old type names.
+	Types map[TypeName]TypeName
+	// The new value paths keyed by the old type name and then the old value path. Paths are case insensitive.
+	Values map[TypeName]map[string]string
+}
+
+// Returns the new name of the type, which is the given name if the type was not renamed.
+func (m Migration) Type(name TypeName) TypeName {
+	if renamed, exists := m.Types[name]; exists {
+		return renamed
+	}
+	return name
+}
+
+// Returns the new path of the value on the old type, if it was renamed.
+func (m Migration) Value(typeName TypeName, path string) (string, bool) {
+	paths := m.Values[typeName]
+	if renamed, exists := paths[path]; exists {
+		return renamed, true
+	}
+	for oldPath, renamed := range paths {
+		if strings.EqualFold(oldPath, path) {
+			return renamed, true
+		}
+	}
+	return "", false
+}
+
+// Returns a copy of the linked expression where renamed values use their new path, however they
+// were referred to (by path or alias). Constants and bind parameters are left as is, conversions added
+// by the system to meet expected types are removed, and the types of the copy are the types of the old
+// system. Use Migrate to get an expression of the new system.
+func (m Migration) Rewrite(e *Expr) *Expr {
+	rewritten := e.Clone()
+	m.rewrite(rewritten)
+	return rewritten
+}
+
+// Renames the values in the chain and its arguments and removes conversions added by the system.
+func (m Migration) rewrite(e *Expr) {
+	for _, c := range e.Chain() {
+		if c.Next != nil && c.Next.converted {
+			c.Next = nil
+		}
+		if c.Value != nil && !c.Constant && !c.Bind {
+			parent := c.ParentType
+			if c.Prev != nil {
+				parent = c.Prev.Type
+			}
+			if parent != nil {
+				if path, renamed := m.Value(parent.Name, c.Value.Path); renamed {
+					c.Token = path
+				}
+			}
+		}
+		for _, arg := range c.Arguments {
+			m.rewrite(arg)
+		}
+	}
+}
+
+// Parses the expression with the old system, rewrites its renamed values, and returns the text of the
+// rewritten expression after making sure it parses with the new system. The root, expected, and
+// parameter types of the options are renamed for the new system. Conversions the old system added to
+// meet expected types are not included in the text. An error is returned if the expression doesn't
+// parse with either system or a value would become a constant in the new system. Renamed paths which
+// need quoting are written surrounded with backticks.
+func (m Migration) Migrate(old, updated System, opts Options) (string, error) {
+	e, err := old.Parse(opts)
+	if err != nil {
+		return "", err
+	}
+	text := m.Rewrite(e).String()
+
+	updatedOpts := opts
+	updatedOpts.Expression = text
+	updatedOpts.RootType = m.Type(opts.RootType)
+	if len(opts.Parameters) > 0 {
+		updatedOpts.Parameters = make(map[string]TypeName, len(opts.Parameters))
+		for name, parameterType := range opts.Parameters {
+			updatedOpts.Parameters[name] = m.Type(parameterType)
+		}
+	}
+	if len(opts.ExpectedTypes) > 0 {
+		updatedOpts.ExpectedTypes = make([]TypeName, len(opts.ExpectedTypes))
+		for i, expectedType := range opts.ExpectedTypes {
+			updatedOpts.ExpectedTypes[i] = m.Type(expectedType)
+		}
+	}
+	migrated, err := updated.Parse(updatedOpts)
+	if err != nil {
+		return text, err
+	}
+	return text, migrationMatches(e, migrated)
+}
+
+// Returns an error if a value in the old expression is a constant in the migrated expression, which
+// happens when a value was not renamed and the new root type parses any text.
+func migrationMatches(old, migrated *Expr) error {
+	o, m := old, migrated
+	for o != nil && m != nil {
+		if o.Value != nil && !o.Constant && !o.Bind && m.Constant {
+			return NewParseErrorKind(m, ErrUnknownValue, fmt.Sprintf("value %s is a constant after migration", m.Token))
+		}
+		for i := 0; i < len(o.Arguments) && i < len(m.Arguments); i++ {
+			if err := migrationMatches(o.Arguments[i], m.Arguments[i]); err != nil {
+				return err
+			}
+		}
+		o, m = o.Next, m.Next
+		for o != nil && o.converted {
+			o = o.Next
+		}
+		for m != nil && m.converted {
+			m = m.Next
+		}
+	}
+	return nil
+}
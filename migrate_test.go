@@ -0,0 +1,113 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigration(t *testing.T) {
+	text := func(x string) (any, error) { return x, nil }
+	old := NewSystemRequired([]Type{{
+		Name:  "text",
+		Parse: text,
+		As:    map[TypeName]string{"flag": "isEmpty"},
+		Values: []Value{
+			{Path: "lower", Aliases: []string{"lowercase"}, Type: "text"},
+			{Path: "contains", Type: "flag", Parameters: []Parameter{{Name: "value", Type: "text"}}},
+			{Path: "isEmpty", Type: "flag"},
+		},
+	}, {
+		Name: "flag",
+	}, {
+		Name: "person",
+		Values: []Value{
+			{Path: "name", Type: "text"},
+			{Path: "nickname", Type: "text"},
+			{Path: "manager", Type: "person"},
+		},
+	}})
+	updated := NewSystemRequired([]Type{{
+		Name:  "string",
+		Parse: text,
+		As:    map[TypeName]string{"bool": "empty"},
+		Values: []Value{
+			{Path: "toLower", Type: "string"},
+			{Path: "includes", Type: "bool", Parameters: []Parameter{{Name: "value", Type: "string"}}},
+			{Path: "empty", Type: "bool"},
+		},
+	}, {
+		Name: "bool",
+	}, {
+		Name: "user",
+		Values: []Value{
+			{Path: "fullName", Type: "string"},
+			{Path: "nickname", Type: "string"},
+			{Path: "manager", Type: "user"},
+		},
+	}})
+
+	migration := Migration{
+		Types: map[TypeName]TypeName{"text": "string", "flag": "bool", "person": "user"},
+		Values: map[TypeName]map[string]string{
+			"text":   {"lower": "toLower", "Contains": "includes", "isEmpty": "empty"},
+			"person": {"name": "fullName"},
+		},
+	}
+
+	tests := []struct {
+		options  Options
+		expected string
+	}{
+		{
+			options:  Options{RootType: "person", Expression: "manager.NAME.lowercase.contains(name)"},
+			expected: "manager.fullName.toLower.includes(fullName)",
+		},
+		{
+			options:  Options{RootType: "person", Expression: "nickname.contains('name\\'s')"},
+			expected: "nickname.includes('name\\'s')",
+		},
+		{
+			options:  Options{RootType: "person", Expression: "nickname.contains(:query)", Parameters: map[string]TypeName{"query": "text"}},
+			expected: "nickname.includes(:query)",
+		},
+		{
+			options:  Options{RootType: "person", Expression: "name", ExpectedTypes: []TypeName{"flag"}},
+			expected: "fullName",
+		},
+	}
+
+	for _, test := range tests {
+		migrated, err := migration.Migrate(old, updated, test.options)
+		assert.NoError(t, err, test.options.Expression)
+		assert.Equal(t, test.expected, migrated, test.options.Expression)
+	}
+
+	e, err := old.Parse(Options{RootType: "person", Expression: "name.lower"})
+	assert.NoError(t, err)
+	assert.Equal(t, "fullName.toLower", migration.Rewrite(e).String())
+	assert.Equal(t, "name.lower", e.String())
+	assert.Equal(t, TypeName("user"), migration.Type("person"))
+	assert.Equal(t, TypeName("other"), migration.Type("other"))
+
+	_, err = Migration{Types: migration.Types}.Migrate(old, updated, Options{RootType: "person", Expression: "name"})
+	assert.ErrorIs(t, err, ErrUnknownValue)
+
+	_, err = migration.Migrate(old, updated, Options{RootType: "person", Expression: "manager.missing"})
+	assert.ErrorIs(t, err, ErrUnknownValue)
+
+	spaced := Migration{Types: migration.Types, Values: map[TypeName]map[string]string{"person": {"name": "full name"}}}
+	quoted := NewSystemRequired([]Type{{
+		Name:  "string",
+		Parse: text,
+	}, {
+		Name: "user",
+		Values: []Value{
+			{Path: "full name", Type: "string"},
+			{Path: "manager", Type: "user"},
+		},
+	}})
+	migrated, err := spaced.Migrate(old, quoted, Options{RootType: "person", Expression: "manager.name"})
+	assert.NoError(t, err)
+	assert.Equal(t, "manager.`full name`", migrated)
+}
@@ -0,0 +1,59 @@
+package texpr
+
+import (
+	"fmt"
+)
+
+// The type name used in mixin values and parameters which is replaced by the type the mixin is applied to.
+const SelfType TypeName = "<self>"
+
+// A named set of values which can be applied to many types, see Type.Mixins. Values and parameters
+// with the SelfType are given the type the mixin is applied to.
+type Mixin struct {
+	// The name of the mixin, should be unique.
+	Name string `json:"name"`
+	// A description of the mixin.
+	Description string `json:"description,omitempty"`
+	// The values which are added to every type the mixin is applied to.
+	Values []Value `json:"values,omitempty"`
+}
+
+// Adds the values of the mixins the type uses. Values the type already has are not added, and
+// when mixins have the same value the first mixin listed by the type is used.
+func applyMixins(t *Type, mixins map[string]*Mixin) error {
+	for _, name := range t.Mixins {
+		mixin := mixins[name]
+		if mixin == nil {
+			return SystemError{
+				Message: fmt.Sprintf("mixin %s on %s could not be found", name, t.Name),
+				Type:    t,
+				Kind:    ErrUnknownMixin,
+			}
+		}
+		for _, v := range mixin.Values {
+			if existing, _ := findValue(v.Path, *t); existing != nil {
+				continue
+			}
+			t.Values = append(t.Values, v.withSelf(t.Name))
+		}
+	}
+	return nil
+}
+
+// Returns a copy of the value where the SelfType is replaced with the given type.
+func (v Value) withSelf(self TypeName) Value {
+	replace := func(name TypeName) TypeName {
+		if name == SelfType {
+			return self
+		}
+		return name
+	}
+	v.Type = replace(v.Type)
+	v.Aliases = append([]string(nil), v.Aliases...)
+	v.Examples = append([]string(nil), v.Examples...)
+	v.Parameters = append([]Parameter(nil), v.Parameters...)
+	for i := range v.Parameters {
+		v.Parameters[i].Type = replace(v.Parameters[i].Type)
+	}
+	return v
+}
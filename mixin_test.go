@@ -0,0 +1,71 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMixins(t *testing.T) {
+	mixins := []Mixin{{
+		Name: "equatable",
+		Values: []Value{
+			{Path: "=", Type: "bool", Parameters: []Parameter{{Name: "value", Type: SelfType}}},
+			{Path: "!=", Type: "bool", Parameters: []Parameter{{Name: "value", Type: SelfType}}},
+		},
+	}, {
+		Name: "printable",
+		Values: []Value{
+			{Path: "text", Type: "text", Aliases: []string{"string"}},
+			{Path: "=", Type: "text", Parameters: []Parameter{{Name: "value", Type: "text"}}},
+		},
+	}, {
+		Name: "self",
+		Values: []Value{
+			{Path: "self", Type: SelfType},
+		},
+	}}
+
+	newTypes := func(intMixins ...string) []Type {
+		return []Type{{
+			Name:   "bool",
+			Mixins: []string{"equatable"},
+		}, {
+			Name:   "text",
+			Parse:  func(x string) (any, error) { return x, nil },
+			Mixins: []string{"printable", "equatable"},
+		}, {
+			Name:   "int",
+			Parse:  func(x string) (any, error) { return x, nil },
+			Mixins: intMixins,
+			Values: []Value{
+				{Path: "self", Type: "text"},
+			},
+		}}
+	}
+
+	s, err := NewSystemWithOptions(newTypes("self", "equatable"), SystemOptions{Mixins: mixins})
+	assert.NoError(t, err)
+
+	intType := s.Type("int")
+	assert.Len(t, intType.Values, 3)
+	assert.Equal(t, TypeName("text"), intType.Value("self").Type)
+	assert.Equal(t, TypeName("int"), intType.Value("=").Parameters[0].Type)
+	assert.Equal(t, "int", string(intType.Value("!=").Parameters[0].ParameterType().Name))
+
+	textType := s.Type("text")
+	assert.Equal(t, TypeName("text"), textType.Value("=").Type)
+	assert.Equal(t, textType.Value("text"), textType.Value("string"))
+
+	boolType := s.Type("bool")
+	assert.Equal(t, TypeName("bool"), boolType.Value("=").Parameters[0].Type)
+	assert.Equal(t, SelfType, mixins[0].Values[0].Parameters[0].Type)
+
+	e, err := s.Parse(Options{RootType: "int", Expression: "=(3)"})
+	assert.NoError(t, err)
+	assert.Equal(t, "bool", string(e.Type.Name))
+
+	_, err = NewSystemWithOptions(newTypes("missing"), SystemOptions{Mixins: mixins})
+	assert.ErrorIs(t, err, ErrUnknownMixin)
+	assert.EqualError(t, err, "mixin missing on int could not be found")
+}
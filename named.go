@@ -0,0 +1,99 @@
+package texpr
+
+import "fmt"
+
+// Returns whether the : at the current character names the argument being parsed, like `add(amount: 3)`.
+// The name is the word directly before it at the start of an argument, so `add(x :y)` is still a bind.
+// The name is removed from the arguments and given to the next one (see Expr.ArgumentName).
+func (p *parser) namesArgument() bool {
+	name := p.prev
+	if name == nil || name.Prev != nil || name.Parent == nil || name.Parent.List || name.Parent.group {
+		return false
+	}
+	if name.Constant || name.Bind || name.Placeholder || name.Called || name.Infix || name.Prefix || name.ArgumentName != "" {
+		return false
+	}
+	if name.End.Index != p.i || p.identifierAt(name.Start.Index) == 0 {
+		return false
+	}
+	parent := name.Parent
+	parent.Arguments = parent.Arguments[:len(parent.Arguments)-1]
+	name.Parent = nil
+	p.argumentName = name.Token
+	p.prev = nil
+	return true
+}
+
+// The token of an argument which skips its parameter so it's given its default, like `format(_, 'UTC')`.
+// An empty argument like `format(, 'UTC')` is parsed as one.
+const skipToken = "_"
+
+// Returns whether the argument skips the parameter. An empty argument always skips, and _ only skips
+// optional parameters so `contains(_)` is still given the text _.
+func isSkipped(arg *Expr, param *Parameter) bool {
+	if arg.Token != skipToken || arg.Constant || arg.Bind || arg.Placeholder || arg.Prefix || arg.List || arg.Called || arg.Next != nil {
+		return false
+	}
+	return arg.End.Index == arg.Start.Index || (param != nil && param.Default != nil)
+}
+
+// Returns whether any of the arguments are named.
+func namedAfter(args []*Expr) bool {
+	for _, arg := range args {
+		if arg != nil && arg.ArgumentName != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Moves the named arguments of the expression to the positions of their parameters. The parameters
+// which were skipped over or skipped with _ are left nil so they're given their defaults when the
+// arguments are linked.
+func (sys System) orderArguments(current *Expr) []ParseError {
+	errs := make([]ParseError, 0)
+	named := false
+	ordered := make([]*Expr, 0, len(current.Arguments))
+	for _, arg := range current.Arguments {
+		if arg.ArgumentName == "" {
+			if named {
+				errs = append(errs, NewParseErrorKind(arg, ErrSyntax, fmt.Sprintf("argument of %s must be named since it follows a named argument", current.Token)))
+				continue
+			}
+			if isSkipped(arg, current.Value.Parameter(len(ordered))) {
+				ordered = append(ordered, nil)
+			} else {
+				ordered = append(ordered, arg)
+			}
+			continue
+		}
+		named = true
+		index := -1
+		for i, param := range current.Value.Parameters {
+			if param.Name == arg.ArgumentName {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			errs = append(errs, NewParseErrorKind(arg, ErrUnknownValue, fmt.Sprintf("%s has no parameter named %s", current.Token, arg.ArgumentName)))
+			continue
+		}
+		for len(ordered) <= index {
+			ordered = append(ordered, nil)
+		}
+		given := arg
+		if isSkipped(arg, &current.Value.Parameters[index]) {
+			given = nil
+		}
+		if ordered[index] != nil {
+			err := NewParseErrorKind(arg, ErrArity, fmt.Sprintf("parameter %s of %s was given more than once", arg.ArgumentName, current.Token))
+			err.Parameter = &current.Value.Parameters[index]
+			errs = append(errs, err)
+			continue
+		}
+		ordered[index] = given
+	}
+	current.Arguments = ordered
+	return errs
+}
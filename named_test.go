@@ -0,0 +1,190 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamedArguments(t *testing.T) {
+	three, day := "3", "day"
+	named := NewSystemRequired([]Type{{
+		Name:  "num",
+		Parse: func(x string) (any, error) { return x, nil },
+	}, {
+		Name:  "unit",
+		Enums: []string{"day", "week"},
+	}, {
+		Name: "root",
+		Values: []Value{
+			{Path: "add", Type: "num", Parameters: []Parameter{
+				{Name: "amount", Type: "num"},
+				{Name: "scale", Type: "num", Default: &three},
+				{Name: "unit", Type: "unit", Default: &day},
+			}},
+		},
+	}})
+
+	tests := []struct {
+		name       string
+		system     System
+		rootType   TypeName
+		expression string
+		expected   string
+		infix      bool
+		errKind    error
+		errMessage string
+	}{
+		{
+			name:       "out of order",
+			system:     sys,
+			rootType:   typeContext,
+			expression: "time.today.add(duration: day, amount: 3)",
+			expected:   "time.today.add(amount:'3',duration:'day')",
+		},
+		{
+			name:       "positional then named",
+			system:     sys,
+			rootType:   typeContext,
+			expression: "time.today.add(3, duration: day)",
+			expected:   "time.today.add('3',duration:'day')",
+		},
+		{
+			name:       "skipped defaults",
+			system:     named,
+			rootType:   "root",
+			expression: "add(unit: week, amount: 1)",
+			expected:   "add(amount:'1',scale:'3',unit:'week')",
+		},
+		{
+			name:       "trailing defaults",
+			system:     named,
+			rootType:   "root",
+			expression: "add(amount: 1)",
+			expected:   "add(amount:'1',scale:'3',unit:'day')",
+		},
+		{
+			name:       "empty slot",
+			system:     named,
+			rootType:   "root",
+			expression: "add(1, , week)",
+			expected:   "add('1','3','week')",
+		},
+		{
+			name:       "skipped slot",
+			system:     named,
+			rootType:   "root",
+			expression: "add(1, _, week)",
+			expected:   "add('1','3','week')",
+		},
+		{
+			name:       "skipped before named",
+			system:     named,
+			rootType:   "root",
+			expression: "add(1, _, unit: week)",
+			expected:   "add('1',scale:'3',unit:'week')",
+		},
+		{
+			name:       "skipped in infix",
+			system:     named,
+			rootType:   "root",
+			expression: "add(1, _, week)",
+			expected:   "add('1','3','week')",
+			infix:      true,
+		},
+		{
+			name:       "underscore for required",
+			system:     named,
+			rootType:   "root",
+			expression: "add(_, _)",
+			expected:   "add('_','3','day')",
+		},
+		{
+			name:       "skipped required",
+			system:     named,
+			rootType:   "root",
+			expression: "add(, 2)",
+			errKind:    ErrArity,
+			errMessage: "parameter amount at 0 was not given a value or a default value",
+		},
+		{
+			name:       "named in infix",
+			system:     sys,
+			rootType:   typeContext,
+			expression: "time.today.add(duration: day, amount: 1 + 2)",
+			expected:   "time.today.add(amount:'1'+('2'),duration:'day')",
+			infix:      true,
+		},
+		{
+			name:       "bind is not a name",
+			system:     named,
+			rootType:   "root",
+			expression: "add(1, scale :s)",
+			errKind:    ErrSyntax,
+			errMessage: "parameter :s must be at the start of an expression",
+		},
+		{
+			name:       "unknown name",
+			system:     sys,
+			rootType:   typeContext,
+			expression: "time.today.add(amount: 3, days: day)",
+			errKind:    ErrUnknownValue,
+			errMessage: "add has no parameter named days",
+		},
+		{
+			name:       "given twice",
+			system:     sys,
+			rootType:   typeContext,
+			expression: "time.today.add(3, amount: 4, duration: day)",
+			errKind:    ErrArity,
+			errMessage: "parameter amount of add was given more than once",
+		},
+		{
+			name:       "positional after named",
+			system:     sys,
+			rootType:   typeContext,
+			expression: "time.today.add(amount: 3, day)",
+			errKind:    ErrSyntax,
+			errMessage: "argument of add must be named since it follows a named argument",
+		},
+		{
+			name:       "named skips required",
+			system:     named,
+			rootType:   "root",
+			expression: "add(scale: 2)",
+			errKind:    ErrArity,
+		},
+		{
+			name:       "missing value",
+			system:     sys,
+			rootType:   typeContext,
+			expression: "time.today.add(amount:, duration: day)",
+			errKind:    ErrSyntax,
+			errMessage: "unexpected , at (index: 22, line: 0, column: 22), expecting a value for amount",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := Options{RootType: test.rootType, Expression: test.expression}
+			if test.infix {
+				opts.Infix = DefaultPrecedence
+			}
+			e, err := test.system.Parse(opts)
+			if test.errKind != nil {
+				assert.ErrorIs(t, err, test.errKind)
+				if test.errMessage != "" {
+					assert.ErrorContains(t, err, test.errMessage)
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.expected, e.String())
+
+				again, err := test.system.Parse(Options{RootType: test.rootType, Expression: e.String()})
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, again.String())
+			}
+		})
+	}
+}
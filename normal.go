@@ -0,0 +1,239 @@
+package texpr
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A normal form of a boolean expression, see Normalize.
+type NormalForm string
+
+const (
+	// An or of terms which are each an and of literals, like `a.and(b).or(c)`.
+	NormalDisjunctive NormalForm = "dnf"
+	// An and of clauses which are each an or of literals, like `a.or(c).and(b.or(c))`.
+	NormalConjunctive NormalForm = "cnf"
+)
+
+// The options of NormalTerms and Normalize.
+type NormalOptions struct {
+	// The normal form, by default NormalDisjunctive.
+	Form NormalForm
+	// The most terms the normal form can have, since normalizing can grow an expression exponentially.
+	// By default 256.
+	MaxTerms int
+}
+
+// A condition of a normal form which may be negated.
+type NormalLiteral struct {
+	// The condition, which is not an and, or, or not.
+	Condition TruthCondition
+	// If the literal is the negation of the condition.
+	Negated bool
+}
+
+// Returns the terms of the normal form of the boolean expression built with the values given by LogicValues.
+// For NormalDisjunctive the expression is true when all literals of any term are true, and for
+// NormalConjunctive when any literal of every term is true. Duplicate literals and terms are removed, as
+// are terms with a condition and its negation. When no terms remain the expression is always false for
+// NormalDisjunctive and always true for NormalConjunctive. Conditions with the same text are the same condition.
+func NormalTerms(e *Expr, options NormalOptions) ([][]NormalLiteral, error) {
+	if options.Form == "" {
+		options.Form = NormalDisjunctive
+	}
+	if options.MaxTerms == 0 {
+		options.MaxTerms = 256
+	}
+	if options.Form != NormalDisjunctive && options.Form != NormalConjunctive {
+		return nil, fmt.Errorf("unknown normal form %s", options.Form)
+	}
+	root, conditions, err := truthTree(e)
+	if err != nil {
+		return nil, err
+	}
+	// The operation of the form which combines terms, the other combines literals within a term.
+	outer := LogicOr
+	if options.Form == NormalConjunctive {
+		outer = LogicAnd
+	}
+
+	var normalize func(node *truthNode, negated bool) ([][]normalLiteral, error)
+	normalize = func(node *truthNode, negated bool) ([][]normalLiteral, error) {
+		op := node.operation
+		switch {
+		case op == "":
+			return [][]normalLiteral{{{index: node.index, negated: negated}}}, nil
+		case op == LogicNot:
+			return normalize(node.operands[0], !negated)
+		case negated && op == LogicAnd:
+			op = LogicOr
+		case negated && op == LogicOr:
+			op = LogicAnd
+		}
+		terms := [][]normalLiteral{}
+		if op != outer {
+			terms = [][]normalLiteral{{}}
+		}
+		for _, operand := range node.operands {
+			operandTerms, err := normalize(operand, negated)
+			if err != nil {
+				return nil, err
+			}
+			if op == outer {
+				terms = append(terms, operandTerms...)
+			} else {
+				product := make([][]normalLiteral, 0, len(terms)*len(operandTerms))
+				for _, term := range terms {
+					for _, operandTerm := range operandTerms {
+						combined := append(append(make([]normalLiteral, 0, len(term)+len(operandTerm)), term...), operandTerm...)
+						product = append(product, combined)
+					}
+				}
+				terms = product
+			}
+			terms = simplifyTerms(terms)
+			if len(terms) > options.MaxTerms {
+				return nil, fmt.Errorf("%w: %s has more than %d terms", ErrTooManyConditions, options.Form, options.MaxTerms)
+			}
+		}
+		return terms, nil
+	}
+	terms, err := normalize(root, false)
+	if err != nil {
+		return nil, err
+	}
+
+	normal := make([][]NormalLiteral, len(terms))
+	for i, term := range terms {
+		normal[i] = make([]NormalLiteral, len(term))
+		for j, literal := range term {
+			normal[i][j] = NormalLiteral{Condition: conditions[literal.index], Negated: literal.negated}
+		}
+	}
+	return normal, nil
+}
+
+// Returns a copy of the boolean expression in the normal form (see NormalTerms) built with the values
+// given by LogicValues on its type. When there are no terms the expression is a true or false constant.
+func Normalize(e *Expr, options NormalOptions) (*Expr, error) {
+	terms, err := NormalTerms(e, options)
+	if err != nil {
+		return nil, err
+	}
+	outer, inner := LogicOr, LogicAnd
+	if options.Form == NormalConjunctive {
+		outer, inner = LogicAnd, LogicOr
+	}
+	boolType := e.Last().Type
+
+	var normal *Expr
+	if len(terms) == 0 {
+		value := outer == LogicAnd
+		normal = &Expr{
+			Token:      strconv.FormatBool(value),
+			Constant:   true,
+			Parsed:     value,
+			ParentType: e.ParentType,
+			Type:       boolType,
+			System:     e.System,
+		}
+	} else {
+		chains := make([]*Expr, len(terms))
+		for i, term := range terms {
+			literals := make([]*Expr, len(term))
+			for j, literal := range term {
+				literals[j] = chainPrefix(literal.Condition.Expr, literal.Condition.Last)
+				if literal.Negated {
+					if literals[j], err = appendLogic(literals[j], LogicNot, nil); err != nil {
+						return nil, err
+					}
+				}
+			}
+			if chains[i], err = appendLogic(literals[0], inner, literals[1:]); err != nil {
+				return nil, err
+			}
+		}
+		if normal, err = appendLogic(chains[0], outer, chains[1:]); err != nil {
+			return nil, err
+		}
+	}
+	normal.Parent = nil
+	normal.Parameter = e.Parameter
+	return normal, nil
+}
+
+// A literal of a term being normalized, where index is the index of the condition.
+type normalLiteral struct {
+	index   int
+	negated bool
+}
+
+// Removes duplicate literals from each term, terms with a literal and its negation, and duplicate terms.
+func simplifyTerms(terms [][]normalLiteral) [][]normalLiteral {
+	simplified := make([][]normalLiteral, 0, len(terms))
+	seen := make(map[string]bool)
+	for _, term := range terms {
+		literals := make(map[int]bool)
+		unique := make([]normalLiteral, 0, len(term))
+		complementary := false
+		for _, literal := range term {
+			negated, exists := literals[literal.index]
+			if exists {
+				complementary = complementary || negated != literal.negated
+				continue
+			}
+			literals[literal.index] = literal.negated
+			unique = append(unique, literal)
+		}
+		if complementary {
+			continue
+		}
+		sort.Slice(unique, func(i, j int) bool {
+			return unique[i].index < unique[j].index
+		})
+		key := strings.Builder{}
+		for _, literal := range unique {
+			fmt.Fprintf(&key, "%d:%t,", literal.index, literal.negated)
+		}
+		if !seen[key.String()] {
+			seen[key.String()] = true
+			simplified = append(simplified, unique)
+		}
+	}
+	return simplified
+}
+
+// Returns the chain with the logic value of its type appended which is given the arguments. When
+// there are no arguments for and or or the chain is returned as is.
+func appendLogic(chain *Expr, op LogicOperation, args []*Expr) (*Expr, error) {
+	if op != LogicNot && len(args) == 0 {
+		return chain, nil
+	}
+	last := chain.Last()
+	var value *Value
+	for i := range last.Type.Values {
+		if valueOp, isLogic := last.Type.Values[i].LogicOperation(); isLogic && valueOp == op {
+			value = &last.Type.Values[i]
+		}
+	}
+	if value == nil {
+		return nil, fmt.Errorf("%s has no %s value, see LogicValues", last.Type.Name, op)
+	}
+	link := &Expr{
+		Token:      value.Path,
+		Value:      value,
+		ParentType: last.Type,
+		Type:       value.ValueType(),
+		Arguments:  args,
+		Prev:       last,
+		System:     last.System,
+	}
+	for i, arg := range args {
+		arg.Parent = link
+		arg.Parameter = value.Parameter(i)
+	}
+	last.Next = link
+	return chain, nil
+}
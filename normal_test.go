@@ -0,0 +1,79 @@
+package texpr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type normalFlags struct {
+	A, B, C bool
+}
+
+func TestNormalize(t *testing.T) {
+	r, err := NewReflect(ReflectOptions{
+		BoolType: "bool",
+		Types: map[reflect.Type]Type{
+			TypeOf[bool]():        {Name: "bool", Values: LogicValues("bool")},
+			TypeOf[normalFlags](): {},
+		},
+	})
+	assert.NoError(t, err)
+	root := NameOf[normalFlags]()
+
+	tests := []struct {
+		expression string
+		dnf        string
+		cnf        string
+	}{
+		{expression: "a.or(b).and(c)", dnf: "a.and(c).or(b.and(c))", cnf: "a.or(b).and(c)"},
+		{expression: "a.and(b).or(c)", dnf: "a.and(b).or(c)", cnf: "a.or(c).and(b.or(c))"},
+		{expression: "a.and(b).not", dnf: "a.not.or(b.not)", cnf: "a.not.or(b.not)"},
+		{expression: "a.or(b.not).not.or(c)", dnf: "a.not.and(b).or(c)", cnf: "a.not.or(c).and(b.or(c))"},
+		{expression: "a.and(a, b.or(b))", dnf: "a.and(b)", cnf: "a.and(b)"},
+		{expression: "a.and(a.not)", dnf: "false", cnf: "a.and(a.not)"},
+		{expression: "a.or(a.not)", dnf: "a.or(a.not)", cnf: "true"},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := r.Parse(Options{RootType: root, Expression: test.expression})
+			assert.NoError(t, err)
+			dnf, err := Normalize(e, NormalOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, test.dnf, trimConstantQuotes(dnf.String()))
+			cnf, err := Normalize(e, NormalOptions{Form: NormalConjunctive})
+			assert.NoError(t, err)
+			assert.Equal(t, test.cnf, trimConstantQuotes(cnf.String()))
+
+			for i := 0; i < 8; i++ {
+				flags := normalFlags{A: i&4 != 0, B: i&2 != 0, C: i&1 != 0}
+				expected, err := r.Evaluate(e, flags)
+				assert.NoError(t, err)
+				for _, normal := range []*Expr{dnf, cnf} {
+					actual, err := r.Evaluate(normal, flags)
+					assert.NoError(t, err)
+					assert.Equal(t, expected, actual, "%s with %+v", normal, flags)
+				}
+			}
+		})
+	}
+
+	e, err := r.Parse(Options{RootType: root, Expression: "a.or(b).and(a.or(c), b.or(c))"})
+	assert.NoError(t, err)
+	terms, err := NormalTerms(e, NormalOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, terms, 4)
+	assert.Equal(t, "a", terms[0][0].Condition.Text)
+	_, err = NormalTerms(e, NormalOptions{MaxTerms: 3})
+	assert.ErrorIs(t, err, ErrTooManyConditions)
+	assert.EqualError(t, err, "too many conditions: dnf has more than 3 terms")
+}
+
+// Returns the string of an expression without the quotes around a constant.
+func trimConstantQuotes(s string) string {
+	if len(s) > 1 && s[0] == '\'' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
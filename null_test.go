@@ -0,0 +1,72 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNull(t *testing.T) {
+	nullable := NewSystemRequired([]Type{{
+		Name:  "text",
+		Parse: func(x string) (any, error) { return x, nil },
+	}, {
+		Name: "root",
+		Values: []Value{
+			{Path: "name", Type: "text", Parameters: []Parameter{{Name: "fallback", Type: "text", Nullable: true}}},
+		},
+	}})
+
+	tests := []struct {
+		name       string
+		system     System
+		rootType   TypeName
+		expression string
+		expected   []TypeName
+		nullable   bool
+		typeName   TypeName
+		err        error
+	}{
+		{name: "constant", system: sys, rootType: typeContext, expression: "null", typeName: "null"},
+		{name: "any case", system: sys, rootType: typeContext, expression: "NULL", typeName: "null"},
+		{name: "quoted", system: sys, rootType: typeContext, expression: "'null'", typeName: typeText},
+		{name: "raw", system: sys, rootType: typeContext, expression: "`null`", typeName: typeText},
+		{name: "generic", system: sys, rootType: typeContext, expression: "user.name.isLower.then('yes', null)", typeName: typeText},
+		{name: "only null", system: sys, rootType: typeContext, expression: "user.name.isLower.then(null, null)", typeName: "null"},
+		{name: "nullable parameter", system: nullable, rootType: "root", expression: "name(null)", typeName: "text"},
+		{name: "nullable expected", system: sys, rootType: typeContext, expression: "null", expected: []TypeName{typeText}, nullable: true, typeName: "null"},
+		{name: "parameter", system: sys, rootType: typeContext, expression: "user.name.contains(null)", err: ErrTypeMismatch},
+		{name: "expected", system: sys, rootType: typeContext, expression: "null", expected: []TypeName{typeText}, err: ErrTypeMismatch},
+		{name: "no values", system: sys, rootType: typeContext, expression: "null.length", err: ErrUnknownValue},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := test.system.Parse(Options{RootType: test.rootType, Expression: test.expression, ExpectedTypes: test.expected, Nullable: test.nullable})
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.typeName, e.Last().Type.Name)
+				reparsed, err := test.system.Parse(Options{RootType: test.rootType, Expression: e.String(), ExpectedTypes: test.expected, Nullable: test.nullable})
+				assert.NoError(t, err)
+				assert.Equal(t, test.typeName, reparsed.Last().Type.Name)
+			}
+		})
+	}
+
+	e, err := sys.Parse(Options{RootType: typeContext, Expression: "'Ann'.contains('x').then('yes', null)"})
+	assert.NoError(t, err)
+	assert.Equal(t, "'Ann'.contains('x').then('yes',null)", e.String())
+	assert.Same(t, Null, e.Last().Arguments[1].Type)
+	result, err := e.Result()
+	assert.NoError(t, err)
+	assert.Equal(t, ResultSchema{Type: typeText, Nullable: true}, result)
+
+	run, err := Compile[Run](e, compileOptions)
+	assert.NoError(t, err)
+	value, err := run(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+}
@@ -0,0 +1,92 @@
+package texpr
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The options used to build a system from an OpenAPI document, see OpenAPISystem.
+type OpenAPIOptions struct {
+	// The name of the component schema which is the root of expressions.
+	Root string
+	// The types used for JSON types and formats, see JSONSchemaOptions.Types.
+	Types map[string]TypeName
+	// The options of the system built. The root is set to the type of the root schema, and when
+	// the int and bool types are not given the integer and boolean types are used.
+	System SystemOptions
+}
+
+// Returns the types of the component schemas in the OpenAPI document, which can be JSON or YAML.
+// Each component schema is a type named by the schema (or its title), see JSONSchemaTypes.
+func OpenAPITypes(document []byte, options OpenAPIOptions) ([]Type, error) {
+	types, _, err := openAPITypes(document, options)
+	return types, err
+}
+
+// Returns a system of the component schemas in the OpenAPI document whose root is the Root schema,
+// so expressions can be written against API payloads.
+func OpenAPISystem(document []byte, options OpenAPIOptions) (System, error) {
+	types, root, err := openAPITypes(document, options)
+	if err != nil {
+		return System{}, err
+	}
+	systemOptions := options.System
+	systemOptions.Root = root
+	if systemOptions.IntType == "" {
+		systemOptions.IntType = openAPIType(types, options, "integer")
+	}
+	if systemOptions.BoolType == "" {
+		systemOptions.BoolType = openAPIType(types, options, "boolean")
+	}
+	return NewSystemWithOptions(types, systemOptions)
+}
+
+// Returns the types of the component schemas and the type name of the root schema.
+func openAPITypes(document []byte, options OpenAPIOptions) ([]Type, TypeName, error) {
+	spec := make(map[string]any)
+	if err := json.Unmarshal(document, &spec); err != nil {
+		if yamlErr := yaml.Unmarshal(document, &spec); yamlErr != nil {
+			return nil, "", fmt.Errorf("openapi document is neither JSON (%v) nor YAML (%v)", err, yamlErr)
+		}
+	}
+
+	importer := newJSONSchemaImporter(JSONSchemaOptions{Types: options.Types})
+	if components, ok := spec["components"].(map[string]any); ok {
+		importer.addDefinitions("#/components/schemas/", components["schemas"])
+	}
+	importer.addDefinitions("#/definitions/", spec["definitions"])
+
+	root := TypeName("")
+	if options.Root != "" {
+		ref := "#/components/schemas/" + options.Root
+		if importer.definitions[ref] == nil {
+			ref = "#/definitions/" + options.Root
+		}
+		if importer.definitions[ref] == nil {
+			return nil, "", fmt.Errorf("root schema %s could not be found", options.Root)
+		}
+		resolved, err := importer.reference(ref)
+		if err != nil {
+			return nil, "", fmt.Errorf("root schema %s: %w", options.Root, err)
+		}
+		root = resolved
+	}
+
+	types, err := importer.finish()
+	return types, root, err
+}
+
+// Returns the type used for the JSON type if there is one.
+func openAPIType(types []Type, options OpenAPIOptions, jsonType string) TypeName {
+	if mapped := options.Types[jsonType]; mapped != "" {
+		return mapped
+	}
+	for _, t := range types {
+		if t.Name == TypeName(jsonType) {
+			return t.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPISystem(t *testing.T) {
+	document := `
+openapi: 3.0.0
+info:
+  title: Orders
+  version: "1"
+paths: {}
+components:
+  schemas:
+    Entity:
+      type: object
+      properties:
+        id:
+          type: integer
+    Order:
+      allOf:
+        - $ref: '#/components/schemas/Entity'
+        - type: object
+          properties:
+            status:
+              type: string
+              enum: [open, closed]
+            tags:
+              type: array
+              items:
+                type: string
+            archived:
+              type: boolean
+`
+
+	s, err := OpenAPISystem([]byte(document), OpenAPIOptions{Root: "Order"})
+	assert.NoError(t, err)
+
+	order := s.Type("Order")
+	assert.NotNil(t, order)
+	assert.Equal(t, "integer", string(order.Value("id").ValueType().Name))
+	assert.Equal(t, []string{"open", "closed"}, order.Value("status").ValueType().Enums)
+	assert.NotNil(t, s.Type("Entity"))
+
+	e, err := s.Parse(Options{RootType: "Order", Expression: "tags.count"})
+	assert.NoError(t, err)
+	assert.Equal(t, "integer", string(e.Last().Type.Name))
+
+	e, err = s.Parse(Options{RootType: "Order", Expression: "tags.isEmpty"})
+	assert.NoError(t, err)
+	assert.Equal(t, "boolean", string(e.Last().Type.Name))
+
+	types, err := OpenAPITypes([]byte(`{"components": {"schemas": {"Pet": {"type": "object", "properties": {"name": {"type": "string"}}}}}}`), OpenAPIOptions{
+		Types: map[string]TypeName{"string": "text"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, types, 1)
+	assert.Equal(t, TypeName("text"), types[0].Values[0].Type)
+
+	_, err = OpenAPISystem([]byte(document), OpenAPIOptions{Root: "Missing"})
+	assert.EqualError(t, err, "root schema Missing could not be found")
+
+	_, err = OpenAPISystem([]byte(`{"components": {"schemas": {"Order": {"type": "object", "properties": {"owner": {"$ref": "#/components/schemas/Owner"}}}}}}`), OpenAPIOptions{Root: "Order"})
+	assert.EqualError(t, err, "root schema Order: reference #/components/schemas/Owner could not be found")
+
+	_, err = OpenAPISystem([]byte(`{"components": {"schemas": {
+		"A": {"allOf": [{"$ref": "#/components/schemas/B"}]},
+		"B": {"allOf": [{"$ref": "#/components/schemas/A"}]}
+	}}}`), OpenAPIOptions{Root: "A"})
+	assert.EqualError(t, err, "root schema A: reference #/components/schemas/B is composed of itself with allOf")
+
+	_, err = OpenAPITypes([]byte("{not: [valid"), OpenAPIOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package texpr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type optionalPerson struct {
+	name    string
+	manager *optionalPerson
+}
+
+func (p *optionalPerson) Name() string             { return p.name }
+func (p *optionalPerson) Manager() *optionalPerson { return p.manager }
+func (p *optionalPerson) Named(name string) bool   { return p.name == name }
+
+func TestOptionalChaining(t *testing.T) {
+	e, err := sys.Parse(Options{RootType: typeContext, Expression: "user?.name.contains('a')"})
+	assert.NoError(t, err)
+	assert.Equal(t, "user?.name.contains('a')", e.String())
+	assert.False(t, e.InNullableChain())
+	assert.True(t, e.Next.Optional)
+	assert.True(t, e.Next.InNullableChain())
+	assert.True(t, e.Last().InNullableChain())
+	result, err := e.Result()
+	assert.NoError(t, err)
+	assert.True(t, result.Nullable)
+
+	decoded, err := sys.DecodeExpr(EncodeExpr(e), Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, "user?.name.contains('a')", decoded.String())
+
+	e, err = sys.Parse(Options{RootType: typeContext, Expression: "time?.now.hour > 12 and ?", Infix: DefaultPrecedence, ExpectedTypes: []TypeName{typeBool}})
+	assert.NoError(t, err)
+	assert.Equal(t, "time?.now.hour>('12').and(?)", e.String())
+
+	r, err := NewReflect(ReflectOptions{
+		Types: map[reflect.Type]Type{
+			TypeOf[bool](): {Name: "bool"},
+			TypeOf[string](): {
+				Name:  "text",
+				Parse: func(x string) (any, error) { return x, nil },
+			},
+			TypeOf[*optionalPerson](): {
+				Name:   "person",
+				Values: []Value{{Path: "Manager", Nullable: true}},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	e, err = r.Parse(Options{RootType: "person", Expression: "manager?.manager?.name"})
+	assert.NoError(t, err)
+	assert.False(t, e.InNullableChain())
+	assert.True(t, e.Next.InNullableChain())
+	run := r.Compile(e)
+
+	boss := &optionalPerson{name: "Boss"}
+	value, err := run(&optionalPerson{name: "Ann", manager: &optionalPerson{name: "Bob", manager: boss}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Boss", value)
+
+	value, err = run(&optionalPerson{name: "Ann", manager: boss})
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	value, err = run(&optionalPerson{name: "Ann"})
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	partial, err := r.CompilePartial(e, map[string]any{"manager": boss})
+	assert.NoError(t, err)
+	value, err = partial(&optionalPerson{name: "Ann"}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	e, err = r.Parse(Options{RootType: "person", Expression: "manager.name"})
+	assert.NoError(t, err)
+	value, err = r.Compile(e)(&optionalPerson{name: "Ann"})
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	for _, expression := range []string{"named(manager?.name)", "named(manager.name)"} {
+		e, err = r.Parse(Options{RootType: "person", Expression: expression})
+		assert.NoError(t, err)
+		run = r.Compile(e)
+		value, err = run(&optionalPerson{name: "Ann", manager: boss})
+		assert.NoError(t, err)
+		assert.Equal(t, false, value)
+		_, err = run(&optionalPerson{name: ""})
+		assert.ErrorContains(t, err, "is absent", expression)
+	}
+}
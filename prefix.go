@@ -0,0 +1,60 @@
+package texpr
+
+import "fmt"
+
+// Returns the length of the prefix symbol at the current character, or 0 if there isn't one. A prefix
+// is only at the start of an operand and must be followed by what it's applied to, so `a.!` and `! a`
+// are not prefixes, and a symbol followed by a digit is a number like `-1`. The longest symbol is used.
+func (p *parser) prefixAt() int {
+	if len(p.prefixes) == 0 || (p.prev != nil && !p.prev.Infix && !p.prev.Prefix) {
+		return 0
+	}
+	longest := 0
+	for symbol := range p.prefixes {
+		n := len(symbol)
+		if n <= longest || p.i+n >= p.n || p.e[p.i:p.i+n] != symbol {
+			continue
+		}
+		next := p.e[p.i+n]
+		if spaceChars[next] || (stopChars[next] && !(next == '(' && p.operators != nil)) || (next >= '0' && next <= '9') {
+			continue
+		}
+		longest = n
+	}
+	return longest
+}
+
+// Returns the operand with the prefixes written before it moved after it, so `!-a.b` becomes `a.b.-.!`,
+// and with grouping parentheses removed.
+func unprefix(e *Expr, errs *[]ParseError) *Expr {
+	prefixes := make([]*Expr, 0)
+	for e != nil && e.Prefix {
+		prefixes = append(prefixes, e)
+		e = e.Next
+	}
+	if len(prefixes) == 0 {
+		return ungroup(e, errs)
+	}
+	last := prefixes[len(prefixes)-1]
+	if e == nil {
+		e = &Expr{Start: last.End, End: last.End}
+		*errs = append(*errs, NewParseErrorKind(e, ErrSyntax, fmt.Sprintf("expression expecting a value after %s but found nothing", last.Token)))
+	} else {
+		e.Prev = nil
+		e = ungroup(e, errs)
+	}
+	for _, prefix := range prefixes {
+		prefix.Prev = nil
+		prefix.Next = nil
+	}
+	e.Parent = prefixes[0].Parent
+	prefixes[0].Parent = nil
+
+	end := e.Last()
+	for i := len(prefixes) - 1; i >= 0; i-- {
+		end.Next = prefixes[i]
+		prefixes[i].Prev = end
+		end = prefixes[i]
+	}
+	return e
+}
@@ -0,0 +1,106 @@
+package texpr
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefix(t *testing.T) {
+	prefixed, err := NewSystem([]Type{{
+		Name: "bool",
+		Values: []Value{
+			{Path: "not", Type: "bool", Prefix: "!"},
+			{Path: "and", Type: "bool", Parameters: []Parameter{{Name: "value", Type: "bool"}}},
+		},
+		Parse: func(x string) (any, error) { return strconv.ParseBool(x) },
+	}, {
+		Name: "num",
+		Values: []Value{
+			{Path: "negate", Type: "num", Prefix: "-"},
+			{Path: "-", Type: "num", Parameters: []Parameter{{Name: "value", Type: "num"}}},
+			{Path: ">", Type: "bool", Parameters: []Parameter{{Name: "value", Type: "num"}}},
+		},
+		Parse: func(x string) (any, error) { return strconv.Atoi(x) },
+	}, {
+		Name: "root",
+		Values: []Value{
+			{Path: "active", Type: "bool"},
+			{Path: "age", Type: "num"},
+		},
+	}})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		expression string
+		infix      bool
+		expected   string
+		err        error
+	}{
+		{"!active", false, "active.not", nil},
+		{"!!active", false, "active.not.not", nil},
+		{"!age.>(-1)", false, "age>('-1').not", nil},
+		{"active.and(!active)", false, "active.and(active.not)", nil},
+		{"!age", false, "!age", ErrUnknownValue},
+		{"!active and -age > 3", true, "active.not.and(age.negate>('3'))", nil},
+		{"!(age > 3) and active", true, "age>('3').not.and(active)", nil},
+		{"age - -age > 1", true, "age-(age.negate)>('1')", nil},
+		{"age - 1 > 0", true, "age-('1')>('0')", nil},
+		{"!!active", true, "active.not.not", nil},
+		{"--age > 1", true, "age.negate.negate>('1')", nil},
+		{"!active and !(-age > 1)", true, "active.not.and(age.negate>('1').not)", nil},
+		{"!-age", true, "!age.negate", ErrUnknownValue},
+		{"!active and !-age > 1", true, "active.not.and(!age.negate>('1'))", ErrUnknownValue},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			opts := Options{RootType: "root", Expression: test.expression}
+			if test.infix {
+				opts.Infix = DefaultPrecedence
+			}
+			e, err := prefixed.Parse(opts)
+			if test.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.err)
+			}
+			assert.Equal(t, test.expected, e.String())
+		})
+	}
+
+	e, err := prefixed.Parse(Options{RootType: "root", Expression: "!active"})
+	assert.NoError(t, err)
+	not := e.Last()
+	assert.True(t, not.Prefix)
+	assert.Equal(t, "!", not.Token)
+	assert.Equal(t, "not", not.Value.Path)
+	assert.Equal(t, Position{}, not.Start)
+	assert.Same(t, prefixed.Type("bool").Value("not"), prefixed.Type("bool").PrefixValue("!"))
+
+	decoded, err := prefixed.DecodeExpr(EncodeExpr(e), Options{})
+	assert.NoError(t, err)
+	assert.True(t, decoded.Last().Prefix)
+	assert.Equal(t, "active.not", decoded.String())
+}
+
+func TestPrefixErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []Value
+		err    error
+	}{
+		{"word", []Value{{Path: "not", Type: "bool", Prefix: "no"}}, ErrInvalidPath},
+		{"parentheses", []Value{{Path: "not", Type: "bool", Prefix: "!("}}, ErrInvalidPath},
+		{"parameters", []Value{{Path: "not", Type: "bool", Prefix: "!", Parameters: []Parameter{{Name: "x", Type: "bool"}}}}, ErrInvalidPath},
+		{"duplicate", []Value{{Path: "not", Type: "bool", Prefix: "!"}, {Path: "isFalse", Type: "bool", Prefix: "!"}}, ErrDuplicatePath},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewSystem([]Type{{Name: "bool", Values: test.values}})
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
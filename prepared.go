@@ -0,0 +1,138 @@
+package texpr
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A bind parameter value was missing, unexpected, or not of the declared type.
+var ErrInvalidParameter = errors.New("invalid parameter")
+
+// A compiled expression which is given the root and the values of the bind parameters by name.
+type BoundRun func(root any, params map[string]any) (any, error)
+
+// A compiled expression paired with the bind parameters it declares. The parameter values
+// given to Execute are checked against the declared types before the expression is ran.
+type Prepared struct {
+	// The expression that was prepared.
+	Expr *Expr
+
+	run        BoundRun
+	parameters map[string]*Type
+	names      map[string]string
+}
+
+// Returns a prepared expression which executes the given compiled expression. The declared
+// parameters are the bind parameters referenced in the expression.
+func NewPrepared(e *Expr, run BoundRun) *Prepared {
+	prepared := &Prepared{
+		Expr:       e,
+		run:        run,
+		parameters: make(map[string]*Type),
+		names:      make(map[string]string),
+	}
+	var walk func(e *Expr)
+	walk = func(e *Expr) {
+		for _, c := range e.Chain() {
+			if c.Bind {
+				key := strings.ToLower(c.Token)
+				prepared.parameters[key] = c.Type
+				prepared.names[key] = c.Token
+			}
+			for _, arg := range c.Arguments {
+				walk(arg)
+			}
+		}
+	}
+	walk(e)
+	return prepared
+}
+
+// Returns the declared parameters and their types keyed by name.
+func (p *Prepared) Parameters() map[string]*Type {
+	parameters := make(map[string]*Type, len(p.parameters))
+	for key, t := range p.parameters {
+		parameters[p.names[key]] = t
+	}
+	return parameters
+}
+
+// Checks the parameter values against the declared parameters and executes the expression.
+// Every declared parameter must be given a value accepted by its type and no other values may be given.
+func (p *Prepared) Execute(root any, params map[string]any) (any, error) {
+	if err := p.Check(params); err != nil {
+		return nil, err
+	}
+	return p.run(root, params)
+}
+
+// Returns a prepared expression where the given parameters are already bound. The returned
+// prepared expression only declares the remaining parameters.
+func (p *Prepared) Bind(params map[string]any) (*Prepared, error) {
+	bound := make(map[string]any, len(params))
+	remaining := &Prepared{
+		Expr:       p.Expr,
+		parameters: make(map[string]*Type, len(p.parameters)),
+		names:      p.names,
+	}
+	for key, t := range p.parameters {
+		remaining.parameters[key] = t
+	}
+	if err := p.checkGiven(params); err != nil {
+		return nil, err
+	}
+	for name, value := range params {
+		bound[name] = value
+		delete(remaining.parameters, strings.ToLower(name))
+	}
+	remaining.run = func(root any, params map[string]any) (any, error) {
+		all := make(map[string]any, len(bound)+len(params))
+		for name, value := range bound {
+			all[name] = value
+		}
+		for name, value := range params {
+			all[name] = value
+		}
+		return p.run(root, all)
+	}
+	return remaining, nil
+}
+
+// Checks the parameter values against the declared parameters.
+func (p *Prepared) Check(params map[string]any) error {
+	if err := p.checkGiven(params); err != nil {
+		return err
+	}
+	missing := make([]string, 0)
+	for key := range p.parameters {
+		found := false
+		for name := range params {
+			found = found || strings.EqualFold(name, key)
+		}
+		if !found {
+			missing = append(missing, ":"+p.names[key])
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("%w: missing %s", ErrInvalidParameter, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Checks the given parameter values are declared and accepted by their types.
+func (p *Prepared) checkGiven(params map[string]any) error {
+	for name, value := range params {
+		key := strings.ToLower(name)
+		t, declared := p.parameters[key]
+		if !declared {
+			return fmt.Errorf("%w: :%s is not declared", ErrInvalidParameter, name)
+		}
+		if t.Accepts != nil && !t.Accepts(value) {
+			return fmt.Errorf("%w: :%s expects %s but was given %v (%T)", ErrInvalidParameter, name, t.Name, value, value)
+		}
+	}
+	return nil
+}
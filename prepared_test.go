@@ -0,0 +1,71 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepared(t *testing.T) {
+	checked := NewSystemRequired([]Type{{
+		Name:    typeText,
+		Accepts: func(v any) bool { _, ok := v.(string); return ok },
+		Values: []Value{
+			{Path: "contains", Type: typeBool, Parameters: []Parameter{{Name: "value", Type: typeText}}},
+		},
+	}, {
+		Name: typeBool,
+		Values: []Value{
+			{Path: "and", Type: typeBool, Variadic: true, Parameters: []Parameter{{Name: "values", Type: typeBool}}},
+		},
+	}, {
+		Name: typeUser,
+		Values: []Value{
+			{Path: "name", Type: typeText},
+		},
+	}})
+
+	e, err := checked.Parse(Options{
+		RootType:   typeUser,
+		Expression: "name.contains(:Search).and(:flag)",
+		Parameters: map[string]TypeName{"search": typeText, "flag": typeBool},
+	})
+	assert.NoError(t, err)
+
+	prepared := NewPrepared(e, func(root any, params map[string]any) (any, error) {
+		return params["Search"], nil
+	})
+
+	parameters := prepared.Parameters()
+	assert.Len(t, parameters, 2)
+	assert.Equal(t, typeText, parameters["Search"].Name)
+	assert.Equal(t, typeBool, parameters["flag"].Name)
+
+	result, err := prepared.Execute(nil, map[string]any{"Search": "Ma", "flag": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "Ma", result)
+
+	_, err = prepared.Execute(nil, map[string]any{"Search": 1, "flag": true})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+	assert.EqualError(t, err, "invalid parameter: :Search expects text but was given 1 (int)")
+
+	_, err = prepared.Execute(nil, map[string]any{"search": "x"})
+	assert.EqualError(t, err, "invalid parameter: missing :flag")
+
+	_, err = prepared.Execute(nil, map[string]any{"search": "x", "flag": true, "other": 2})
+	assert.EqualError(t, err, "invalid parameter: :other is not declared")
+
+	bound, err := prepared.Bind(map[string]any{"flag": false})
+	assert.NoError(t, err)
+	assert.Len(t, bound.Parameters(), 1)
+
+	result, err = bound.Execute(nil, map[string]any{"Search": "Jo"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Jo", result)
+
+	_, err = bound.Execute(nil, map[string]any{"Search": "Jo", "flag": true})
+	assert.EqualError(t, err, "invalid parameter: :flag is not declared")
+
+	_, err = prepared.Bind(map[string]any{"search": 3})
+	assert.ErrorIs(t, err, ErrInvalidParameter)
+}
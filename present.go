@@ -0,0 +1,182 @@
+package texpr
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The ANSI escape codes used by ErrorPresenter.
+const (
+	ansiReset = "\x1b[0m"
+	ansiRed   = "\x1b[1;31m"
+	ansiBlue  = "\x1b[1;34m"
+	ansiCyan  = "\x1b[36m"
+	ansiBold  = "\x1b[1m"
+)
+
+// The default number of similar values suggested by ErrorPresenter.
+const defaultSuggestions = 3
+
+// Formats errors from parsing and evaluating expressions for terminals and logs, with the line of the
+// expression the error is on and a caret under the part of the line with the problem:
+//
+//	error[unknown value]: invalid value nam
+//	 --> line 1, column 6
+//	  |
+//	1 | user.nam.upper
+//	  |      ^^^
+//	  = did you mean name?
+type ErrorPresenter struct {
+	// If the output is colored with ANSI escape codes.
+	Color bool
+	// The most values suggested for an unknown value, by how similar they are to what was written.
+	// When zero 3 values are suggested and when negative none are.
+	Suggestions int
+}
+
+// Returns the error formatted for the expression it came from. ParseErrors are formatted one after
+// another, errors with an expression (like ResourceLimitError) point to the expression, and other
+// errors are formatted with only their message.
+func (p ErrorPresenter) Present(expression string, err error) string {
+	if err == nil {
+		return ""
+	}
+	var parseErrors ParseErrors
+	if errors.As(err, &parseErrors) {
+		presented := make([]string, len(parseErrors))
+		for i, parseError := range parseErrors {
+			presented[i] = p.Present(expression, parseError)
+		}
+		return strings.Join(presented, "\n")
+	}
+
+	out := strings.Builder{}
+	var start, end *Position
+	var expr *Expr
+	var parseError ParseError
+	var limitError ResourceLimitError
+	switch {
+	case errors.As(err, &parseError):
+		start, end, expr = parseError.Start, parseError.End, parseError.Expr
+		if parseError.Kind != nil {
+			out.WriteString(p.paint(ansiRed, "error["+parseError.Kind.Error()+"]"))
+		} else {
+			out.WriteString(p.paint(ansiRed, "error"))
+		}
+		out.WriteString(p.paint(ansiBold, ": "+parseError.Message))
+	case errors.As(err, &limitError) && limitError.Expr != nil:
+		start, end = &limitError.Expr.Start, &limitError.Expr.End
+		out.WriteString(p.paint(ansiRed, "error"))
+		out.WriteString(p.paint(ansiBold, ": "+err.Error()))
+	default:
+		out.WriteString(p.paint(ansiRed, "error"))
+		out.WriteString(p.paint(ansiBold, ": "+err.Error()))
+	}
+	out.WriteString("\n")
+
+	if start == nil {
+		return out.String()
+	}
+	lines := strings.Split(expression, "\n")
+	if start.Line < 0 || start.Line >= len(lines) {
+		return out.String()
+	}
+	line := lines[start.Line]
+	number := strconv.Itoa(start.Line + 1)
+	gutter := strings.Repeat(" ", len(number))
+
+	from := clampIndex(start.Column, len(line))
+	to := len(line)
+	if end != nil && end.Line == start.Line {
+		to = clampIndex(end.Column, len(line))
+	}
+	carets := utf8.RuneCountInString(line[from:to])
+	if carets < 1 {
+		carets = 1
+	}
+
+	out.WriteString(gutter + p.paint(ansiBlue, "--> ") + fmt.Sprintf("line %d, column %d\n", start.Line+1, start.Column+1))
+	out.WriteString(gutter + p.paint(ansiBlue, " |") + "\n")
+	out.WriteString(p.paint(ansiBlue, number+" |") + " " + line + "\n")
+	out.WriteString(gutter + p.paint(ansiBlue, " |") + " " + caretIndent(line[:from]) + p.paint(ansiRed, strings.Repeat("^", carets)) + "\n")
+
+	if suggestions := p.suggest(parseError, expr); len(suggestions) > 0 {
+		out.WriteString(gutter + p.paint(ansiBlue, " = ") + p.paint(ansiCyan, "did you mean "+strings.Join(suggestions, ", ")+"?") + "\n")
+	}
+	return out.String()
+}
+
+// Returns the text wrapped in the ANSI code if the presenter is colored.
+func (p ErrorPresenter) paint(code string, text string) string {
+	if !p.Color {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// Returns the paths of the values on the parent type most similar to an unknown value, if any.
+func (p ErrorPresenter) suggest(err ParseError, expr *Expr) []string {
+	limit := p.Suggestions
+	if limit == 0 {
+		limit = defaultSuggestions
+	}
+	if limit < 0 || expr == nil || expr.Token == "" || !errors.Is(err.Kind, ErrUnknownValue) {
+		return nil
+	}
+	parentType := expr.ParentType
+	if parentType == nil || parentType == Unknown {
+		return nil
+	}
+	type suggestion struct {
+		path  string
+		score int
+	}
+	suggestions := make([]suggestion, 0)
+	word := strings.ToLower(expr.Token)
+	for i := range parentType.Values {
+		v := &parentType.Values[i]
+		if score := searchScore(word, append([]string{v.Path}, v.Aliases...), ""); score > 0 {
+			suggestions = append(suggestions, suggestion{path: v.Path, score: score})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].score > suggestions[j].score
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	paths := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		paths[i] = s.path
+	}
+	return paths
+}
+
+// Returns the index limited to the length.
+func clampIndex(index, length int) int {
+	if index < 0 {
+		return 0
+	}
+	if index > length {
+		return length
+	}
+	return index
+}
+
+// Returns the whitespace which lines a caret up under the character after the text, keeping tabs so
+// the caret lines up however wide tabs are displayed.
+func caretIndent(text string) string {
+	indent := strings.Builder{}
+	for _, r := range text {
+		if r == '\t' {
+			indent.WriteRune('\t')
+		} else {
+			indent.WriteRune(' ')
+		}
+	}
+	return indent.String()
+}
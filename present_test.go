@@ -0,0 +1,93 @@
+package texpr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorPresenter(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		presenter  ErrorPresenter
+		presented  string
+	}{
+		{
+			name:       "suggestion",
+			expression: "user.nam.upper",
+			presented: "error[unknown value]: invalid value nam\n" +
+				" --> line 1, column 6\n" +
+				"  |\n" +
+				"1 | user.nam.upper\n" +
+				"  |      ^^^\n" +
+				"  = did you mean name?\n",
+		},
+		{
+			name:       "no suggestions",
+			expression: "user.nam.upper",
+			presenter:  ErrorPresenter{Suggestions: -1},
+			presented: "error[unknown value]: invalid value nam\n" +
+				" --> line 1, column 6\n" +
+				"  |\n" +
+				"1 | user.nam.upper\n" +
+				"  |      ^^^\n",
+		},
+		{
+			name:       "multiple lines",
+			expression: "user.name.contains(\n\tuser.nme\n)",
+			presented: "error[unknown value]: invalid value nme\n" +
+				" --> line 2, column 7\n" +
+				"  |\n" +
+				"2 | \tuser.nme\n" +
+				"  | \t     ^^^\n" +
+				"  = did you mean name?\n",
+		},
+		{
+			name:       "multiple errors",
+			expression: "time.today.add(x)",
+			presented: "error[invalid number of arguments]: add.date expects at least 2 parameters\n" +
+				" --> line 1, column 12\n" +
+				"  |\n" +
+				"1 | time.today.add(x)\n" +
+				"  |            ^^^\n" +
+				"\n" +
+				"error[type mismatch]: constant x did not match expected type(s) int\n" +
+				" --> line 1, column 16\n" +
+				"  |\n" +
+				"1 | time.today.add(x)\n" +
+				"  |                ^\n",
+		},
+		{
+			name:       "color",
+			expression: "user.nam",
+			presenter:  ErrorPresenter{Color: true},
+			presented: "\x1b[1;31merror[unknown value]\x1b[0m\x1b[1m: invalid value nam\x1b[0m\n" +
+				" \x1b[1;34m--> \x1b[0mline 1, column 6\n" +
+				" \x1b[1;34m |\x1b[0m\n" +
+				"\x1b[1;34m1 |\x1b[0m user.nam\n" +
+				" \x1b[1;34m |\x1b[0m      \x1b[1;31m^^^\x1b[0m\n" +
+				" \x1b[1;34m = \x1b[0m\x1b[36mdid you mean name?\x1b[0m\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := sys.Parse(Options{RootType: typeContext, Expression: test.expression})
+			assert.Equal(t, test.presented, test.presenter.Present(test.expression, err))
+		})
+	}
+
+	presenter := ErrorPresenter{}
+	assert.Equal(t, "", presenter.Present("user", nil))
+	assert.Equal(t, "error: undefined root type\n", presenter.Present("user", ErrNoRoot))
+	assert.Equal(t, "error: runtime failure\n", presenter.Present("user", errors.New("runtime failure")))
+
+	e, _ := sys.Parse(Options{RootType: typeContext, Expression: "user.name"})
+	limited := ResourceLimitError{Limit: ResourceLimit("steps"), Max: 1, Actual: 2, Expr: e.Next}
+	assert.Equal(t, "error: "+limited.Error()+"\n"+
+		" --> line 1, column 6\n"+
+		"  |\n"+
+		"1 | user.name\n"+
+		"  |      ^^^^\n", presenter.Present("user.name", limited))
+}
@@ -0,0 +1,56 @@
+package texpr
+
+import (
+	"errors"
+	"strings"
+)
+
+// Parses a program of expressions separated by semicolons, like `user.age.>(18); user.name.length.<(20)`,
+// which are all parsed with the options. Semicolons in quoted constants and paths don't separate
+// expressions, and empty expressions like the one after a trailing semicolon are ignored. Like Parse
+// the expressions are returned with all the errors found in any of them, positioned in the program.
+func (sys System) ParseProgram(program string, opts Options) ([]*Expr, error) {
+	if len(sys.Types()) == 0 {
+		return nil, ErrNoTypes
+	}
+	if _, _, err := sys.linkOptions(opts, nil); err != nil {
+		return nil, err
+	}
+
+	exprs := make([]*Expr, 0)
+	errs := make([]ParseError, 0)
+	line, lineAt := 0, 0
+	for from := 0; from <= len(program); {
+		to := indexUnquoted(program, from, ';')
+		if to == -1 {
+			to = len(program)
+		}
+		if strings.TrimSpace(program[from:to]) != "" {
+			p := newParser(program, sys.options.Identifiers)
+			p.i = from
+			p.n = to
+			p.line = line
+			p.lineReset = lineAt
+			expr, err := sys.parseFrom(opts, p, nil)
+			var parseErrors ParseErrors
+			var parseError ParseError
+			if errors.As(err, &parseErrors) {
+				errs = append(errs, parseErrors...)
+			} else if errors.As(err, &parseError) {
+				errs = append(errs, parseError)
+			}
+			exprs = append(exprs, expr)
+		}
+		for i := from; i < to; i++ {
+			if program[i] == '\n' {
+				line++
+				lineAt = i + 1
+			}
+		}
+		from = to + 1
+	}
+	if len(exprs) == 0 {
+		return nil, ErrNoExpression
+	}
+	return exprs, joinParseErrors(errs)
+}
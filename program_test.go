@@ -0,0 +1,39 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProgram(t *testing.T) {
+	exprs, err := sys.ParseProgram("user.name.contains('a;b');\n  time.now.hour.>(12) ;;\n", Options{RootType: typeContext})
+	assert.NoError(t, err)
+	if assert.Len(t, exprs, 2) {
+		assert.Equal(t, "user.name.contains('a;b')", exprs[0].String())
+		assert.Equal(t, "time.now.hour>('12')", exprs[1].String())
+		assert.Equal(t, Position{Index: 29, Line: 1, Column: 2}, exprs[1].Start)
+	}
+
+	exprs, err = sys.ParseProgram("user.name.contains('''it's; ok''');user.name", Options{RootType: typeContext})
+	assert.NoError(t, err)
+	if assert.Len(t, exprs, 2) {
+		assert.Equal(t, "user.name", exprs[1].String())
+	}
+
+	exprs, err = sys.ParseProgram("user.nope;\ntime.today.add(1);user.name", Options{RootType: typeContext})
+	assert.Len(t, exprs, 3)
+	var parseErrors ParseErrors
+	if assert.ErrorAs(t, err, &parseErrors) && assert.Len(t, parseErrors, 2) {
+		assert.ErrorIs(t, parseErrors[0], ErrUnknownValue)
+		assert.Equal(t, &Position{Index: 5, Column: 5}, parseErrors[0].Start)
+		assert.ErrorIs(t, parseErrors[1], ErrArity)
+		assert.Equal(t, 1, parseErrors[1].Start.Line)
+	}
+	assert.Equal(t, "user.name", exprs[2].String())
+
+	_, err = sys.ParseProgram(" ; ", Options{RootType: typeContext})
+	assert.Equal(t, ErrNoExpression, err)
+	_, err = sys.ParseProgram("user", Options{})
+	assert.Equal(t, ErrNoRoot, err)
+}
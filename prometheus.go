@@ -0,0 +1,167 @@
+package texpr
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The default histogram buckets of PrometheusMetrics in seconds, from 10µs to 1s since most
+// expressions are parsed, compiled, and evaluated in well under a millisecond.
+var DefaultPrometheusBuckets = []float64{.00001, .00005, .0001, .0005, .001, .005, .01, .05, .1, .5, 1}
+
+// The options of NewPrometheusMetrics.
+type PrometheusOptions struct {
+	// The prefix of the metric names, by default "texpr".
+	Namespace string
+	// The upper bounds of the duration histogram buckets in seconds, by default DefaultPrometheusBuckets.
+	Buckets []float64
+}
+
+// Metrics served in the Prometheus text exposition format, so they can be scraped without a client
+// library. For each of parse, compile, and evaluation there's a duration histogram (like
+// texpr_parse_duration_seconds, whose count is the number of operations) and a counter of errors
+// by kind (like texpr_parse_errors_total{kind="unknown value"}), and texpr_cache_lookups_total counts
+// cache lookups by result (hit or miss). It's an http.Handler which serves the metrics.
+type PrometheusMetrics struct {
+	options    PrometheusOptions
+	lock       sync.Mutex
+	histograms map[string]*durationHistogram
+	errors     map[string]map[string]uint64
+	cache      map[string]uint64
+}
+
+// The durations of an operation.
+type durationHistogram struct {
+	// The number of durations in each bucket, not including the durations of smaller buckets.
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// The operations measured by PrometheusMetrics in the order they're written.
+var prometheusOperations = []string{"parse", "compile", "evaluation"}
+
+var _ Metrics = &PrometheusMetrics{}
+var _ http.Handler = &PrometheusMetrics{}
+
+// Returns metrics which are served in the Prometheus text exposition format.
+func NewPrometheusMetrics(options PrometheusOptions) *PrometheusMetrics {
+	if options.Namespace == "" {
+		options.Namespace = "texpr"
+	}
+	if options.Buckets == nil {
+		options.Buckets = DefaultPrometheusBuckets
+	}
+	options.Buckets = append([]float64(nil), options.Buckets...)
+	sort.Float64s(options.Buckets)
+
+	m := &PrometheusMetrics{
+		options:    options,
+		histograms: make(map[string]*durationHistogram, len(prometheusOperations)),
+		errors:     make(map[string]map[string]uint64, len(prometheusOperations)),
+		cache:      map[string]uint64{"hit": 0, "miss": 0},
+	}
+	for _, operation := range prometheusOperations {
+		m.histograms[operation] = &durationHistogram{buckets: make([]uint64, len(options.Buckets))}
+		m.errors[operation] = make(map[string]uint64)
+	}
+	return m
+}
+
+func (m *PrometheusMetrics) Parsed(duration time.Duration, err error) {
+	m.record("parse", duration, err)
+}
+
+func (m *PrometheusMetrics) Compiled(duration time.Duration, err error) {
+	m.record("compile", duration, err)
+}
+
+func (m *PrometheusMetrics) Evaluated(duration time.Duration, err error) {
+	m.record("evaluation", duration, err)
+}
+
+func (m *PrometheusMetrics) CacheLookup(hit bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if hit {
+		m.cache["hit"]++
+	} else {
+		m.cache["miss"]++
+	}
+}
+
+// Adds the duration to the histogram of the operation and counts the kinds of its errors.
+func (m *PrometheusMetrics) record(operation string, duration time.Duration, err error) {
+	kinds := ErrorKinds(err)
+	seconds := duration.Seconds()
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	histogram := m.histograms[operation]
+	histogram.count++
+	histogram.sum += seconds
+	if i := sort.SearchFloat64s(m.options.Buckets, seconds); i < len(histogram.buckets) {
+		histogram.buckets[i]++
+	}
+	for _, kind := range kinds {
+		m.errors[operation][kind]++
+	}
+}
+
+// Writes the metrics in the Prometheus text exposition format.
+func (m *PrometheusMetrics) Write(w io.Writer) error {
+	m.lock.Lock()
+	out := strings.Builder{}
+	namespace := m.options.Namespace
+	for _, operation := range prometheusOperations {
+		name := namespace + "_" + operation + "_duration_seconds"
+		histogram := m.histograms[operation]
+		fmt.Fprintf(&out, "# HELP %s How long each %s took.\n", name, operation)
+		fmt.Fprintf(&out, "# TYPE %s histogram\n", name)
+		cumulative := uint64(0)
+		for i, bound := range m.options.Buckets {
+			cumulative += histogram.buckets[i]
+			fmt.Fprintf(&out, "%s_bucket{le=\"%s\"} %d\n", name, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(&out, "%s_bucket{le=\"+Inf\"} %d\n", name, histogram.count)
+		fmt.Fprintf(&out, "%s_sum %s\n", name, strconv.FormatFloat(histogram.sum, 'g', -1, 64))
+		fmt.Fprintf(&out, "%s_count %d\n", name, histogram.count)
+
+		name = namespace + "_" + operation + "_errors_total"
+		fmt.Fprintf(&out, "# HELP %s The errors of each %s by kind.\n", name, operation)
+		fmt.Fprintf(&out, "# TYPE %s counter\n", name)
+		kinds := make([]string, 0, len(m.errors[operation]))
+		for kind := range m.errors[operation] {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			fmt.Fprintf(&out, "%s{kind=\"%s\"} %d\n", name, prometheusLabel(kind), m.errors[operation][kind])
+		}
+	}
+	name := namespace + "_cache_lookups_total"
+	fmt.Fprintf(&out, "# HELP %s The cache lookups of cached evaluators by result.\n", name)
+	fmt.Fprintf(&out, "# TYPE %s counter\n", name)
+	fmt.Fprintf(&out, "%s{result=\"hit\"} %d\n", name, m.cache["hit"])
+	fmt.Fprintf(&out, "%s{result=\"miss\"} %d\n", name, m.cache["miss"])
+	m.lock.Unlock()
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// Serves the metrics in the Prometheus text exposition format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.Write(w)
+}
+
+// Returns the label value escaped for the Prometheus text exposition format.
+func prometheusLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,71 @@
+package texpr
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	metrics := NewPrometheusMetrics(PrometheusOptions{Namespace: "rules", Buckets: []float64{0.01, 0.001}})
+	metrics.Parsed(500*time.Microsecond, nil)
+	_, err := sys.Parse(Options{RootType: typeContext, Expression: "time.today.add(x)"})
+	metrics.Parsed(5*time.Millisecond, err)
+	metrics.Compiled(time.Second, nil)
+	metrics.Evaluated(time.Millisecond, errors.New("a \"quoted\" failure"))
+	metrics.CacheLookup(true)
+	metrics.CacheLookup(false)
+	metrics.CacheLookup(true)
+
+	response := httptest.NewRecorder()
+	metrics.ServeHTTP(response, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", response.Header().Get("Content-Type"))
+	assert.Equal(t, strings.Join([]string{
+		"# HELP rules_parse_duration_seconds How long each parse took.",
+		"# TYPE rules_parse_duration_seconds histogram",
+		`rules_parse_duration_seconds_bucket{le="0.001"} 1`,
+		`rules_parse_duration_seconds_bucket{le="0.01"} 2`,
+		`rules_parse_duration_seconds_bucket{le="+Inf"} 2`,
+		"rules_parse_duration_seconds_sum 0.0055",
+		"rules_parse_duration_seconds_count 2",
+		"# HELP rules_parse_errors_total The errors of each parse by kind.",
+		"# TYPE rules_parse_errors_total counter",
+		`rules_parse_errors_total{kind="invalid number of arguments"} 1`,
+		`rules_parse_errors_total{kind="type mismatch"} 1`,
+		"# HELP rules_compile_duration_seconds How long each compile took.",
+		"# TYPE rules_compile_duration_seconds histogram",
+		`rules_compile_duration_seconds_bucket{le="0.001"} 0`,
+		`rules_compile_duration_seconds_bucket{le="0.01"} 0`,
+		`rules_compile_duration_seconds_bucket{le="+Inf"} 1`,
+		"rules_compile_duration_seconds_sum 1",
+		"rules_compile_duration_seconds_count 1",
+		"# HELP rules_compile_errors_total The errors of each compile by kind.",
+		"# TYPE rules_compile_errors_total counter",
+		"# HELP rules_evaluation_duration_seconds How long each evaluation took.",
+		"# TYPE rules_evaluation_duration_seconds histogram",
+		`rules_evaluation_duration_seconds_bucket{le="0.001"} 1`,
+		`rules_evaluation_duration_seconds_bucket{le="0.01"} 1`,
+		`rules_evaluation_duration_seconds_bucket{le="+Inf"} 1`,
+		"rules_evaluation_duration_seconds_sum 0.001",
+		"rules_evaluation_duration_seconds_count 1",
+		"# HELP rules_evaluation_errors_total The errors of each evaluation by kind.",
+		"# TYPE rules_evaluation_errors_total counter",
+		`rules_evaluation_errors_total{kind="other"} 1`,
+		"# HELP rules_cache_lookups_total The cache lookups of cached evaluators by result.",
+		"# TYPE rules_cache_lookups_total counter",
+		`rules_cache_lookups_total{result="hit"} 2`,
+		`rules_cache_lookups_total{result="miss"} 1`,
+		"",
+	}, "\n"), response.Body.String())
+
+	assert.Equal(t, `a \"b\" \\ \n`, prometheusLabel("a \"b\" \\ \n"))
+
+	defaults := NewPrometheusMetrics(PrometheusOptions{})
+	out := strings.Builder{}
+	assert.NoError(t, defaults.Write(&out))
+	assert.Contains(t, out.String(), `texpr_parse_duration_seconds_bucket{le="1e-05"} 0`)
+}
@@ -0,0 +1,186 @@
+package texpr
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// The options used to build types from protobuf message descriptors, see ProtoTypes.
+type ProtoOptions struct {
+	// The types used for scalar kinds which are given to the system separately. Scalar kinds which are
+	// not mapped are generated with a parse function, named by their kind group: int32, int64, uint32,
+	// uint64, float, double, bool, string, and bytes.
+	Kinds map[protoreflect.Kind]TypeName
+	// Returns the type name of a message. By default the full name of the message is used.
+	MessageType func(message protoreflect.MessageDescriptor) TypeName
+	// Returns the type name of an enum. By default the full name of the enum is used.
+	EnumType func(enum protoreflect.EnumDescriptor) TypeName
+}
+
+// The generated scalar types by kind.
+var protoScalars = map[protoreflect.Kind]Type{
+	protoreflect.Int32Kind:    {Name: "int32", Parse: protoParseInt(32)},
+	protoreflect.Sint32Kind:   {Name: "int32", Parse: protoParseInt(32)},
+	protoreflect.Sfixed32Kind: {Name: "int32", Parse: protoParseInt(32)},
+	protoreflect.Int64Kind:    {Name: "int64", Parse: protoParseInt(64)},
+	protoreflect.Sint64Kind:   {Name: "int64", Parse: protoParseInt(64)},
+	protoreflect.Sfixed64Kind: {Name: "int64", Parse: protoParseInt(64)},
+	protoreflect.Uint32Kind:   {Name: "uint32", Parse: protoParseUint(32)},
+	protoreflect.Fixed32Kind:  {Name: "uint32", Parse: protoParseUint(32)},
+	protoreflect.Uint64Kind:   {Name: "uint64", Parse: protoParseUint(64)},
+	protoreflect.Fixed64Kind:  {Name: "uint64", Parse: protoParseUint(64)},
+	protoreflect.FloatKind: {Name: "float", ParseOrder: -1, Parse: func(x string) (any, error) {
+		f, err := strconv.ParseFloat(x, 32)
+		return float32(f), err
+	}},
+	protoreflect.DoubleKind: {Name: "double", ParseOrder: -1, Parse: func(x string) (any, error) {
+		return strconv.ParseFloat(x, 64)
+	}},
+	protoreflect.BoolKind: {Name: "bool", Parse: func(x string) (any, error) {
+		return strconv.ParseBool(x)
+	}},
+	protoreflect.StringKind: {Name: "string", ParseOrder: -2, Parse: func(x string) (any, error) {
+		return x, nil
+	}},
+	protoreflect.BytesKind: {Name: "bytes", ParseOrder: -3, Parse: func(x string) (any, error) {
+		return base64.StdEncoding.DecodeString(x)
+	}},
+}
+
+func protoParseInt(bits int) func(x string) (any, error) {
+	return func(x string) (any, error) {
+		i, err := strconv.ParseInt(x, 10, bits)
+		if bits == 32 {
+			return int32(i), err
+		}
+		return i, err
+	}
+}
+
+func protoParseUint(bits int) func(x string) (any, error) {
+	return func(x string) (any, error) {
+		i, err := strconv.ParseUint(x, 10, bits)
+		if bits == 32 {
+			return uint32(i), err
+		}
+		return i, err
+	}
+}
+
+// Builds types from protobuf descriptors.
+type protoImporter struct {
+	options   ProtoOptions
+	types     []Type
+	generated map[TypeName]bool
+}
+
+// Returns the types of the messages and every message and enum they refer to. Messages are types with
+// a value for each field, enums are types with Enums of the enum value names, repeated fields are list
+// types (see System.ListOf), and scalar fields are types with parse functions. Fields declared optional
+// are Nullable values, and map fields are skipped.
+func ProtoTypes(options ProtoOptions, messages ...protoreflect.MessageDescriptor) ([]Type, error) {
+	if options.MessageType == nil {
+		options.MessageType = func(message protoreflect.MessageDescriptor) TypeName {
+			return TypeName(message.FullName())
+		}
+	}
+	if options.EnumType == nil {
+		options.EnumType = func(enum protoreflect.EnumDescriptor) TypeName {
+			return TypeName(enum.FullName())
+		}
+	}
+	importer := &protoImporter{
+		options:   options,
+		generated: make(map[TypeName]bool),
+	}
+	for _, message := range messages {
+		if _, err := importer.messageType(message); err != nil {
+			return nil, err
+		}
+	}
+	return importer.types, nil
+}
+
+// Adds the type of the message and returns its name.
+func (im *protoImporter) messageType(message protoreflect.MessageDescriptor) (TypeName, error) {
+	name := im.options.MessageType(message)
+	if im.generated[name] {
+		return name, nil
+	}
+	im.generated[name] = true
+	index := len(im.types)
+	im.types = append(im.types, Type{
+		Name:        name,
+		Description: fmt.Sprintf("The %s message", message.FullName()),
+	})
+
+	fields := message.Fields()
+	values := make([]Value, 0, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if field.IsMap() {
+			continue
+		}
+		fieldType, err := im.fieldType(field)
+		if err != nil {
+			return "", err
+		}
+		if field.IsList() {
+			fieldType = ListTypeName(fieldType)
+		}
+		values = append(values, Value{
+			Path:        string(field.Name()),
+			Type:        fieldType,
+			Description: fmt.Sprintf("The %s field (%d)", field.Name(), field.Number()),
+			Nullable:    field.HasOptionalKeyword(),
+		})
+	}
+	im.types[index].Values = values
+	return name, nil
+}
+
+// Returns the type of a single value of the field.
+func (im *protoImporter) fieldType(field protoreflect.FieldDescriptor) (TypeName, error) {
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return im.messageType(field.Message())
+	case protoreflect.EnumKind:
+		return im.enumType(field.Enum()), nil
+	}
+	if mapped := im.options.Kinds[field.Kind()]; mapped != "" {
+		return mapped, nil
+	}
+	scalar, exists := protoScalars[field.Kind()]
+	if !exists {
+		return "", fmt.Errorf("field %s has an unsupported kind %s", field.FullName(), field.Kind())
+	}
+	if !im.generated[scalar.Name] {
+		im.generated[scalar.Name] = true
+		scalar.Description = fmt.Sprintf("A protobuf %s", scalar.Name)
+		im.types = append(im.types, scalar)
+	}
+	return scalar.Name, nil
+}
+
+// Adds the type of the enum and returns its name.
+func (im *protoImporter) enumType(enum protoreflect.EnumDescriptor) TypeName {
+	name := im.options.EnumType(enum)
+	if im.generated[name] {
+		return name
+	}
+	im.generated[name] = true
+	values := enum.Values()
+	enums := make([]string, values.Len())
+	for i := range enums {
+		enums[i] = string(values.Get(i).Name())
+	}
+	im.types = append(im.types, Type{
+		Name:        name,
+		Description: fmt.Sprintf("The %s enum", enum.FullName()),
+		Enums:       enums,
+	})
+	return name
+}
@@ -0,0 +1,112 @@
+package texpr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestProtoTypes(t *testing.T) {
+	field := func(name string, number int32, kind descriptorpb.FieldDescriptorProto_Type, typeName string, repeated bool) *descriptorpb.FieldDescriptorProto {
+		label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+		if repeated {
+			label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+		}
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Type:     kind.Enum(),
+			Label:    label.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("shop.proto"),
+		Package: proto.String("shop"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("PENDING"), Number: proto.Int32(0)},
+				{Name: proto.String("SHIPPED"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Order"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", false),
+				field("status", 2, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".shop.Status", false),
+				field("lines", 3, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".shop.Order.Line", true),
+				field("tags", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".shop.Order.TagsEntry", true),
+				field("paid", 5, descriptorpb.FieldDescriptorProto_TYPE_BOOL, "", false),
+			},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("Line"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("sku", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", false),
+					field("quantity", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64, "", false),
+				},
+			}, {
+				Name:    proto.String("TagsEntry"),
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", false),
+					field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", false),
+				},
+			}},
+		}},
+	}, nil)
+	assert.NoError(t, err)
+
+	order := file.Messages().ByName("Order")
+	types, err := ProtoTypes(ProtoOptions{}, order)
+	assert.NoError(t, err)
+
+	described := make([]string, len(types))
+	for i, t := range types {
+		values := make([]string, len(t.Values))
+		for k, v := range t.Values {
+			values[k] = v.Path + " " + string(v.Type)
+		}
+		described[i] = fmt.Sprintf("%s: %s%s", t.Name, strings.Join(values, ", "), strings.Join(t.Enums, "|"))
+	}
+	assert.Equal(t, []string{
+		"shop.Order: id string, status shop.Status, lines list<shop.Order.Line>, paid bool",
+		"string: ",
+		"shop.Status: PENDING|SHIPPED",
+		"shop.Order.Line: sku string, quantity int64",
+		"int64: ",
+		"bool: ",
+	}, described)
+
+	s, err := NewSystemWithOptions(types, SystemOptions{IntType: "int64", BoolType: "bool"})
+	assert.NoError(t, err)
+
+	e, err := s.Parse(Options{RootType: "shop.Order", Expression: "lines.first.quantity"})
+	assert.NoError(t, err)
+	assert.Equal(t, "int64", string(e.Last().Type.Name))
+
+	parsed, err := s.Type("int64").ParseInput("42")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), parsed)
+
+	types, err = ProtoTypes(ProtoOptions{
+		Kinds:       map[protoreflect.Kind]TypeName{protoreflect.StringKind: "text"},
+		MessageType: func(message protoreflect.MessageDescriptor) TypeName { return TypeName(message.Name()) },
+	}, order.Messages().ByName("Line"))
+	assert.NoError(t, err)
+	assert.Len(t, types, 2)
+	assert.Equal(t, TypeName("Line"), types[0].Name)
+	assert.Equal(t, TypeName("text"), types[0].Values[0].Type)
+}
@@ -0,0 +1,43 @@
+package texpr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// An expensive value was not computed because its limiter refused it.
+var ErrRateLimited = errors.New("rate limited")
+
+// Decides whether an expensive value can be computed and returns an error if it can't, like when a
+// tenant has used its quota of external lookups. The limiter is given the expression which uses the
+// value and the root it's evaluated against, which is nil when the value is precomputed by
+// Reflect.CompilePartial. Limiters can wait for capacity before returning.
+type Limiter func(e *Expr, root any) error
+
+// Returns the expressions in the chain and its arguments which use an expensive value, in the order
+// they appear.
+func (e *Expr) ExpensiveValues() []*Expr {
+	expensive := make([]*Expr, 0)
+	for _, c := range e.Chain() {
+		if c.Value != nil && !c.Constant && !c.Bind && c.Value.Expensive {
+			expensive = append(expensive, c)
+		}
+		for _, arg := range c.Arguments {
+			expensive = append(expensive, arg.ExpensiveValues()...)
+		}
+	}
+	return expensive
+}
+
+// Consults the limiter if the expression uses an expensive value. Errors returned by the limiter
+// which are not ErrRateLimited are wrapped with it.
+func (l Limiter) limit(e *Expr, root any) error {
+	if l == nil || e.Value == nil || e.Constant || e.Bind || !e.Value.Expensive {
+		return nil
+	}
+	err := l(e, root)
+	if err != nil && !errors.Is(err, ErrRateLimited) {
+		err = fmt.Errorf("%w: %s: %w", ErrRateLimited, e.Token, err)
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+package texpr
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rateTenant struct {
+	Name String
+}
+
+func (t rateTenant) Lookup(sku Int) Int {
+	return sku * 2
+}
+
+func (t rateTenant) Double(n Int) Int {
+	return n * 2
+}
+
+func TestLimiter(t *testing.T) {
+	quotas := map[String]int{"acme": 2}
+	calls := make([]string, 0)
+	limiter := func(e *Expr, root any) error {
+		calls = append(calls, e.String())
+		if root == nil {
+			return nil
+		}
+		tenant := root.(rateTenant)
+		if quotas[tenant.Name] <= 0 {
+			return errors.New("quota of " + string(tenant.Name) + " used")
+		}
+		quotas[tenant.Name]--
+		return nil
+	}
+
+	r, err := NewReflect(ReflectOptions{
+		Limiter: limiter,
+		Types: map[reflect.Type]Type{
+			TypeOf[Int](): {Parse: func(x string) (any, error) {
+				i, err := strconv.Atoi(x)
+				return Int(i), err
+			}},
+			TypeOf[String](): {ParseOrder: -1, Parse: func(x string) (any, error) { return String(x), nil }},
+			TypeOf[rateTenant](): {Values: []Value{
+				{Path: "Lookup", Expensive: true},
+			}},
+		},
+	})
+	assert.NoError(t, err)
+
+	e, err := r.Parse(Options{RootType: NameOf[rateTenant](), Expression: "double(lookup(lookup(1)))"})
+	assert.NoError(t, err)
+	assert.Len(t, e.ExpensiveValues(), 2)
+	assert.Equal(t, "lookup(lookup('1'))", e.ExpensiveValues()[0].String())
+	assert.Equal(t, "lookup('1')", e.ExpensiveValues()[1].String())
+
+	result, err := r.Evaluate(e, rateTenant{Name: "acme"})
+	assert.NoError(t, err)
+	assert.Equal(t, Int(8), result)
+	assert.Equal(t, []string{"lookup(lookup('1'))", "lookup('1')"}, calls)
+
+	_, err = r.Evaluate(e, rateTenant{Name: "acme"})
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.EqualError(t, err, "rate limited: lookup: quota of acme used")
+
+	quotas["acme"] = 1
+	_, err = r.Evaluate(e, rateTenant{Name: "acme"})
+	assert.ErrorIs(t, err, ErrRateLimited)
+
+	e, err = r.Parse(Options{RootType: NameOf[rateTenant](), Expression: "double(2)"})
+	assert.NoError(t, err)
+	assert.Empty(t, e.ExpensiveValues())
+	result, err = r.Evaluate(e, rateTenant{Name: "other"})
+	assert.NoError(t, err)
+	assert.Equal(t, Int(4), result)
+
+	e, err = r.Parse(Options{RootType: NameOf[rateTenant](), Expression: "lookup(3)"})
+	assert.NoError(t, err)
+	quotas["acme"] = 0
+	_, err = r.Evaluate(e, rateTenant{Name: "acme"})
+	assert.ErrorIs(t, err, ErrRateLimited)
+
+	limited := Limiter(func(e *Expr, root any) error {
+		return ErrRateLimited
+	})
+	r.options.Limiter = limited
+	_, err = r.Evaluate(e, rateTenant{Name: "acme"})
+	assert.Equal(t, ErrRateLimited, err)
+}
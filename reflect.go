@@ -23,20 +23,50 @@ type ReflectConversion struct {
 type ReflectOptions struct {
 	Conversions map[reflect.Type]ReflectConversion
 	Types       map[reflect.Type]Type
+	// The boolean type returned by the comparison operators added to types, see SystemOptions.BoolType.
+	BoolType TypeName
+	// If enum types are given the enum operators, see SystemOptions.EnumOperators.
+	EnumOperators bool
+	// The integer type used by the values of list types, see SystemOptions.IntType. Slice fields of
+	// supported types are exposed as list types, see System.ListOf.
+	IntType TypeName
+	// The limits on the resources each evaluation can use.
+	Limits ResourceLimits
+	// Consulted before each expensive value is computed, see Value.Expensive.
+	Limiter Limiter
+	// If arguments computed during evaluation are checked against the constraints of their parameters,
+	// like Parameter.Min. Constant arguments are always checked when they're linked.
+	CheckParameters bool
 }
 
-type reflectGetter = func(v reflect.Value, root reflect.Value, e *Expr) (reflect.Value, error)
+type reflectGetter = func(v reflect.Value, env reflectEnv, e *Expr) (reflect.Value, error)
+
+// The values available to an evaluation.
+type reflectEnv struct {
+	root   reflect.Value
+	params map[string]any
+	// The precomputed results of expressions, see Reflect.CompilePartial.
+	fixed map[*Expr]reflect.Value
+	// The resources used by the evaluation, nil when there are no limits.
+	usage *resourceUsage
+	// The keys of expressions whose results are shared between evaluations, see Stream.
+	keys map[*Expr]string
+	// The results of the shared expressions evaluated so far keyed by their keys.
+	shared map[string]reflect.Value
+}
 
 type Reflect struct {
 	options ReflectOptions
 	system  System
 	getters map[TypeName]map[string]reflectGetter
+	types   map[TypeName]reflect.Type
 }
 
 func NewReflect(options ReflectOptions) (r *Reflect, err error) {
 	r = &Reflect{
 		options: options,
 		getters: make(map[TypeName]map[string]reflectGetter),
+		types:   make(map[TypeName]reflect.Type),
 	}
 
 	if options.Conversions == nil {
@@ -50,14 +80,22 @@ func NewReflect(options ReflectOptions) (r *Reflect, err error) {
 		}
 		supportedTypes[rt] = t.Name
 		options.Types[rt] = t
+		r.types[t.Name] = rt
 	}
 	for rt, c := range options.Conversions {
 		supportedTypes[rt] = c.Type
 	}
 
 	systemTypes := make([]Type, 0, len(options.Types))
+	supported := func(rt reflect.Type) TypeName {
+		if elem, isFuture := futureElem(rt); isFuture {
+			return supportedTypes[elem]
+		}
+		return supportedTypes[rt]
+	}
 
 	for rt, t := range options.Types {
+		rt := rt
 		r.getters[t.Name] = make(map[string]reflectGetter)
 
 		if t.Parse == nil && reflect.PointerTo(rt).Implements(TypeOf[encoding.TextUnmarshaler]()) {
@@ -71,10 +109,26 @@ func NewReflect(options ReflectOptions) (r *Reflect, err error) {
 			}
 		}
 
+		if t.Accepts == nil {
+			t.Accepts = func(v any) bool {
+				vt := reflect.TypeOf(v)
+				if vt == rt || vt == reflect.PointerTo(rt) {
+					return true
+				}
+				conversion, exists := options.Conversions[vt]
+				return exists && conversion.Type == supportedTypes[rt]
+			}
+		}
+
 		if rt.Kind() == reflect.Struct {
 			fields := getFields(rt)
 			for path, field := range fields {
-				if supportedTypes[field.Type] == "" {
+				field := field
+				fieldType := supported(field.Type)
+				if fieldType == "" && field.Type.Kind() == reflect.Slice && supportedTypes[field.Type.Elem()] != "" {
+					fieldType = ListTypeName(supportedTypes[field.Type.Elem()])
+				}
+				if fieldType == "" {
 					continue
 				}
 
@@ -87,13 +141,13 @@ func NewReflect(options ReflectOptions) (r *Reflect, err error) {
 					value.Path = path
 				}
 				if value.Type == "" {
-					value.Type = supportedTypes[field.Type]
+					value.Type = fieldType
 				}
 				if valueIndex != -1 {
 					t.Values[valueIndex] = *value
 				}
 
-				r.getters[t.Name][path] = func(v, root reflect.Value, e *Expr) (reflect.Value, error) {
+				r.getters[t.Name][path] = func(v reflect.Value, env reflectEnv, e *Expr) (reflect.Value, error) {
 					return v.FieldByIndexErr(field.Index)
 				}
 			}
@@ -103,7 +157,7 @@ func NewReflect(options ReflectOptions) (r *Reflect, err error) {
 		for i := 0; i < methods; i++ {
 			m := rt.Method(i)
 			mOut := m.Type.NumOut()
-			if mOut < 0 || mOut > 2 || (mOut == 2 && !m.Type.Out(1).Implements(TypeOf[error]())) || supportedTypes[m.Type.Out(0)] == "" {
+			if mOut < 0 || mOut > 2 || (mOut == 2 && !m.Type.Out(1).Implements(TypeOf[error]())) || supported(m.Type.Out(0)) == "" {
 				continue
 			}
 			mIn := m.Type.NumIn()
@@ -130,44 +184,66 @@ func NewReflect(options ReflectOptions) (r *Reflect, err error) {
 				value.Path = m.Name
 			}
 			if value.Type == "" {
-				value.Type = supportedTypes[m.Type.Out(0)]
+				value.Type = supported(m.Type.Out(0))
 			}
 
 			if m.Type.IsVariadic() {
 				value.Variadic = true
 			}
 
+			// Parameters given with the value (for their names, defaults, etc) are completed with their types.
+			declared := len(value.Parameters)
 			for k := 1; k < mIn; k++ {
 				in := m.Type.In(k)
 				param := Parameter{}
-				if m.Type.IsVariadic() && k == mIn-1 {
+				if k <= declared {
+					param = value.Parameters[k-1]
+				}
+				if param.Type == "" && m.Type.IsVariadic() && k == mIn-1 {
 					param.Type = supportedTypes[in.Elem()]
-				} else {
+				} else if param.Type == "" {
 					param.Type = supportedTypes[in]
 				}
-				value.Parameters = append(value.Parameters, param)
+				if k <= declared {
+					value.Parameters[k-1] = param
+				} else {
+					value.Parameters = append(value.Parameters, param)
+				}
 			}
 			if valueIndex != -1 {
 				t.Values[valueIndex] = *value
 			}
 
-			r.getters[t.Name][strings.ToLower(m.Name)] = func(v, root reflect.Value, e *Expr) (reflect.Value, error) {
+			r.getters[t.Name][strings.ToLower(m.Name)] = func(v reflect.Value, env reflectEnv, e *Expr) (reflect.Value, error) {
 				vm := v.Method(m.Index)
-				// lastArgumentIndex := m.Type.NumIn() - 1
-				// lastArgumentType := m.Type.In(lastArgumentIndex)
+				lastArgumentIndex := m.Type.NumIn() - 1
 				args := make([]reflect.Value, len(e.Arguments))
-				// variadicType :=
 				for i, arg := range e.Arguments {
-					argValue, err := r.eval(root, root, arg)
+					argValue, err := r.eval(env.root, env, arg)
 					if err != nil {
 						return reflect.Value{}, err
 					}
-					// inType := m.Type.In(lastArgumentIndex)
-					// if i < lastArgumentIndex {
-					// 	inType = m.Type.In(i + 1)
-					// }
 					args[i] = argValue
 				}
+				for i, arg := range e.Arguments {
+					argValue, err := r.settle(env, args[i], arg.Last())
+					if err != nil {
+						return reflect.Value{}, err
+					}
+					if err := r.checkArgument(arg, argValue); err != nil {
+						return reflect.Value{}, err
+					}
+					inType := m.Type.In(lastArgumentIndex)
+					if i+1 < lastArgumentIndex {
+						inType = m.Type.In(i + 1)
+					} else if m.Type.IsVariadic() {
+						inType = inType.Elem()
+					}
+					args[i], err = r.convertToExpected(argValue, inType)
+					if err != nil {
+						return reflect.Value{}, err
+					}
+				}
 				result := vm.Call(args)
 				if len(result) == 2 && !result[1].IsNil() {
 					if err, ok := result[1].Interface().(error); ok {
@@ -182,49 +258,377 @@ func NewReflect(options ReflectOptions) (r *Reflect, err error) {
 		options.Types[rt] = t
 	}
 
-	r.system, err = NewSystem(systemTypes)
+	r.system, err = NewSystemWithOptions(systemTypes, SystemOptions{
+		EnumOperators: options.EnumOperators,
+		BoolType:      options.BoolType,
+		IntType:       options.IntType,
+	})
+	if err != nil {
+		return
+	}
+
+	for _, t := range r.system.Types() {
+		for i := range t.Values {
+			if op, isOperator := t.Values[i].Operator(); isOperator {
+				ops := t.operations()
+				r.getters[t.Name][strings.ToLower(t.Values[i].Path)] = func(v reflect.Value, env reflectEnv, e *Expr) (reflect.Value, error) {
+					args, err := r.evalArguments(env, e)
+					if err != nil {
+						return reflect.Value{}, err
+					}
+					result, err := op.Apply(ops, v.Interface(), args)
+					if err != nil {
+						return reflect.Value{}, err
+					}
+					return reflect.ValueOf(result), nil
+				}
+			}
+		}
+	}
 
 	return
 }
 
 type ReflectCompiled func(root any) (any, error)
 
+// A compiled expression which is given the values of the bind parameters by name.
+type ReflectBoundCompiled func(root any, params map[string]any) (any, error)
+
+var _ Evaluator = Reflect{}
+
 func (r Reflect) Parse(opts Options) (*Expr, error) {
 	return r.system.Parse(opts)
 }
 
+// Returns the system generated from the reflected types.
+func (r Reflect) System() System {
+	return r.system
+}
+
+// Evaluates the expression against the given root.
+func (r Reflect) Evaluate(e *Expr, root any) (any, error) {
+	return r.Compile(e)(root)
+}
+
+// Compiles the expression into a prepared expression which checks its bind parameters.
+func (r Reflect) Prepare(e *Expr) *Prepared {
+	return NewPrepared(e, BoundRun(r.CompileBound(e)))
+}
+
 func (r Reflect) Compile(e *Expr) ReflectCompiled {
+	bound := r.CompileBound(e)
 	return func(root any) (any, error) {
-		rootReflect := reflect.ValueOf(root)
-		val, err := r.eval(rootReflect, rootReflect, e)
+		return bound(root, nil)
+	}
+}
+
+// Compiles the expression into a function which is given the root and the bind parameter values.
+func (r Reflect) CompileBound(e *Expr) ReflectBoundCompiled {
+	return r.compileFixed(e, nil)
+}
+
+// Compiles the expression where part of the root never changes. The fixed values are keyed by
+// the path of a value on the root type. Chains which start with a fixed value are evaluated now
+// as far as possible (until a value is given an argument which depends on the root or parameters),
+// so repeated evaluations only evaluate what depends on the rest of the root.
+func (r Reflect) CompilePartial(e *Expr, fixed map[string]any) (ReflectBoundCompiled, error) {
+	env := reflectEnv{fixed: make(map[*Expr]reflect.Value)}
+	if err := r.precompute(e, env, fixed); err != nil {
+		return nil, err
+	}
+	return r.compileFixed(e, env.fixed), nil
+}
+
+func (r Reflect) compileFixed(e *Expr, fixed map[*Expr]reflect.Value) ReflectBoundCompiled {
+	return func(root any, params map[string]any) (any, error) {
+		env := reflectEnv{
+			root:   reflect.ValueOf(root),
+			params: make(map[string]any, len(params)),
+			fixed:  fixed,
+		}
+		for name, value := range params {
+			env.params[strings.ToLower(name)] = value
+		}
+		return r.run(env, e)
+	}
+}
+
+// Evaluates the expression in the environment and settles its result.
+func (r Reflect) run(env reflectEnv, e *Expr) (any, error) {
+	if !r.options.Limits.Unlimited() {
+		env.usage = &resourceUsage{limits: r.options.Limits}
+	}
+	val, err := r.eval(env.root, env, e)
+	if err == nil && !val.IsValid() {
+		return nil, nil
+	}
+	if err == nil {
+		val, err = r.settle(env, val, e.Last())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return val.Interface(), nil
+}
+
+// Returns whether the value is absent, which skips the rest of a chain after ?.
+func isAbsent(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
+
+// Evaluates every chain in the expression which starts with a fixed root value as far as possible and
+// stores the results in env.fixed.
+func (r Reflect) precompute(e *Expr, env reflectEnv, fixed map[string]any) error {
+	for _, c := range e.Chain() {
+		for _, arg := range c.Arguments {
+			if err := r.precompute(arg, env, fixed); err != nil {
+				return err
+			}
+		}
+	}
+	if e.Prev != nil || e.Value == nil || e.Constant || e.Bind || e.Placeholder {
+		return nil
+	}
+	var current reflect.Value
+	found := false
+	for path, value := range fixed {
+		if e.ParentType.Value(path) == e.Value {
+			current, found = reflect.ValueOf(value), true
+		}
+	}
+	if !found {
+		return nil
+	}
+	for c := e; c != nil; c = c.Next {
+		if c != e {
+			if c.InNullableChain() && isAbsent(current) {
+				for absent := c; absent != nil; absent = absent.Next {
+					env.fixed[absent] = reflect.Value{}
+				}
+				break
+			}
+			if !r.isFixed(c, env) {
+				break
+			}
+			getter := r.getter(c.Prev.Type, c.Value)
+			if getter == nil {
+				return fmt.Errorf("no getter found for %s.%s", c.Prev.Type.Name, c.Value.Path)
+			}
+			if err := r.limit(env, c); err != nil {
+				return err
+			}
+			var err error
+			current, err = getter(current, env, c)
+			if err != nil {
+				return err
+			}
+		}
+		settled, err := r.settle(env, current, c)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		return val.Interface(), nil
+		current = settled
+		env.fixed[c] = current
 	}
+	return nil
 }
 
-func (r Reflect) eval(v, root reflect.Value, e *Expr) (reflect.Value, error) {
+// Returns whether the expression is a value whose arguments are all constants or precomputed.
+func (r Reflect) isFixed(e *Expr, env reflectEnv) bool {
+	if e.Value == nil || e.Constant || e.Bind || e.Placeholder {
+		return false
+	}
+	for _, arg := range e.Arguments {
+		_, precomputed := env.fixed[arg.Last()]
+		if !precomputed && !(arg.Constant && arg.Next == nil) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Reflect) eval(v reflect.Value, env reflectEnv, e *Expr) (reflect.Value, error) {
+	if value, precomputed := env.fixed[e]; precomputed {
+		if e.Next != nil {
+			return r.eval(value, env, e.Next)
+		}
+		return value, nil
+	}
+	if e.Placeholder {
+		return reflect.Value{}, NewParseErrorKind(e, ErrPlaceholder, "placeholders must be filled in before evaluation")
+	}
+	if e.InNullableChain() && isAbsent(v) {
+		return reflect.Value{}, nil
+	}
+	key := env.keys[e]
+	if value, evaluated := env.shared[key]; evaluated && key != "" {
+		if e.Next != nil {
+			return r.eval(value, env, e.Next)
+		}
+		return value, nil
+	}
+	if err := env.usage.execute(e); err != nil {
+		return reflect.Value{}, err
+	}
+	if err := r.limit(env, e); err != nil {
+		return reflect.Value{}, err
+	}
+	var nextValue reflect.Value
+	var err error
 	if e.Constant {
-		return reflect.ValueOf(e.Parsed), nil
+		nextValue = reflect.ValueOf(e.Parsed)
+	} else if e.Bind {
+		param, exists := env.params[strings.ToLower(e.Token)]
+		if !exists {
+			return reflect.Value{}, fmt.Errorf("no value given for parameter :%s", e.Token)
+		}
+		nextValue = reflect.ValueOf(param)
+	} else if e.List {
+		elements, err := r.evalArguments(env, e)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		nextValue = r.newList(e.Type.ElementType(), elements)
 	} else {
 		parent := e.ParentType
-		if parent == nil {
+		if e.Prev != nil {
 			parent = e.Prev.Type
 		}
-		getter := r.getters[parent.Name][strings.ToLower(e.Value.Path)]
+		getter := r.getter(parent, e.Value)
 		if getter == nil {
 			return reflect.Value{}, fmt.Errorf("no getter found for %s.%s", parent.Name, e.Value.Path)
 		}
-		nextValue, err := getter(v, root, e)
-		if e.Next != nil && err == nil {
-			nextValue, err = r.eval(nextValue, root, e.Next)
+		nextValue, err = getter(v, env, e)
+	}
+	if err == nil && (e.Next != nil || !isFuture(nextValue)) {
+		nextValue, err = r.settle(env, nextValue, e)
+		if err == nil && key != "" {
+			env.shared[key] = nextValue
+		}
+	}
+	if e.Next != nil && err == nil {
+		nextValue, err = r.eval(nextValue, env, e.Next)
+	}
+	return nextValue, err
+}
+
+// Returns the getter for the value on the given type. The values of list types are evaluated with their
+// ListOperation, the values given by DateValues and ZoneValues with their DateOperation, and the values
+// given by LogicValues with their LogicOperation.
+func (r Reflect) getter(parent *Type, value *Value) reflectGetter {
+	if getter := r.getters[parent.Name][strings.ToLower(value.Path)]; getter != nil {
+		return getter
+	}
+	var apply func(v any, args []any) (any, error)
+	if op, isListOperation := value.ListOperation(); isListOperation {
+		apply = op.Apply
+	} else if op, isDateOperation := value.DateOperation(); isDateOperation {
+		apply = op.Apply
+	} else if op, isLogicOperation := value.LogicOperation(); isLogicOperation {
+		apply = op.Apply
+	}
+	if apply == nil {
+		return nil
+	}
+	return func(v reflect.Value, env reflectEnv, e *Expr) (reflect.Value, error) {
+		args, err := r.evalArguments(env, e)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		result, err := apply(v.Interface(), args)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(result), nil
+	}
+}
+
+// Evaluates the arguments of the expression, awaiting them after they are all evaluated.
+func (r Reflect) evalArguments(env reflectEnv, e *Expr) ([]any, error) {
+	values := make([]reflect.Value, len(e.Arguments))
+	for i, arg := range e.Arguments {
+		argValue, err := r.eval(env.root, env, arg)
+		if err != nil {
+			return nil, err
 		}
-		return nextValue, err
+		values[i] = argValue
 	}
+	args := make([]any, len(e.Arguments))
+	for i, arg := range e.Arguments {
+		argValue, err := r.settle(env, values[i], arg.Last())
+		if err != nil {
+			return nil, err
+		}
+		if err := r.checkArgument(arg, argValue); err != nil {
+			return nil, err
+		}
+		if argValue.IsValid() {
+			args[i] = argValue.Interface()
+		}
+	}
+	return args, nil
+}
+
+// Checks the computed argument is present when it's in a nullable chain and against the constraints of its
+// parameter when the options check parameters. An absent argument is an error unless its parameter is
+// Nullable, so functions aren't given a zero value in place of null.
+func (r Reflect) checkArgument(arg *Expr, v reflect.Value) error {
+	last := arg.Last()
+	nullable := last.InNullableChain() || (last.Value != nil && last.Value.Nullable)
+	if nullable && isAbsent(v) && (arg.Parameter == nil || !arg.Parameter.Nullable) {
+		return fmt.Errorf("argument %s is absent (null) but its parameter is not nullable", arg)
+	}
+	if !r.options.CheckParameters || arg.Parameter == nil || !arg.Parameter.Constrained() || isConstantArgument(arg) || !v.IsValid() {
+		return nil
+	}
+	if err := checkArgument(arg, v.Interface()); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// Consults the limiter before the expensive value of the expression is computed.
+func (r Reflect) limit(env reflectEnv, e *Expr) error {
+	if r.options.Limiter == nil || e.Value == nil || !e.Value.Expensive {
+		return nil
+	}
+	var root any
+	if env.root.IsValid() {
+		root = env.root.Interface()
+	}
+	return r.options.Limiter.limit(e, root)
+}
+
+// Awaits the value if it's a future and converts it to the Go type of the expression's type. The
+// last expression in a chain is not settled when it's evaluated, so futures can be awaited together.
+func (r Reflect) settle(env reflectEnv, v reflect.Value, e *Expr) (reflect.Value, error) {
+	if isFuture(v) {
+		awaited, err := Await(v.Interface())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v = reflect.ValueOf(awaited)
+	}
+	if err := env.usage.check(e, v); err != nil {
+		return reflect.Value{}, err
+	}
+	if expected := r.types[e.Type.Name]; expected != nil && v.IsValid() {
+		return r.convertToExpected(v, expected)
+	}
+	return v, nil
 }
 
 func (r Reflect) convertToExpected(v reflect.Value, expected reflect.Type) (reflect.Value, error) {
+	if !v.IsValid() {
+		return reflect.Zero(expected), nil
+	}
 	if v.Type() == expected {
 		return v, nil
 	}
@@ -292,3 +696,26 @@ func getFields(rt reflect.Type) map[string]reflect.StructField {
 	}
 	return m
 }
+
+// Returns a slice of the Go type of the element type with the elements, or a []any when the element
+// type has no Go type or an element isn't one.
+func (r Reflect) newList(elementType *Type, elements []any) reflect.Value {
+	if elementType != nil {
+		if goType := r.types[elementType.Name]; goType != nil {
+			list := reflect.MakeSlice(reflect.SliceOf(goType), len(elements), len(elements))
+			converted := true
+			for i, element := range elements {
+				value := reflect.ValueOf(element)
+				if !value.IsValid() || !value.Type().AssignableTo(goType) {
+					converted = false
+					break
+				}
+				list.Index(i).Set(value)
+			}
+			if converted {
+				return list
+			}
+		}
+	}
+	return reflect.ValueOf(elements)
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 type Int int
@@ -174,4 +176,68 @@ func TestReflect(t *testing.T) {
 
 		fmt.Printf("Reflect expression result: %v", v)
 	})
+
+	t.Run("binds", func(t *testing.T) {
+		e, err := r.Parse(Options{
+			RootType:   NameOf[MessageContext](),
+			Expression: "time.now.hour.add(:offset).equals(:Expected)",
+			Parameters: map[string]TypeName{
+				"offset":   NameOf[Int](),
+				"expected": NameOf[Int](),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []string{"offset", "Expected"}, e.Binds())
+
+		eval := r.CompileBound(e)
+		root := MessageContext{Time: TimePackage{Now: time.Date(2023, 1, 1, 2, 0, 0, 0, time.UTC)}}
+
+		v, err := eval(root, map[string]any{"offset": 1, "expected": Int(3)})
+		assert.NoError(t, err)
+		assert.Equal(t, Bool(true), v)
+
+		_, err = eval(root, map[string]any{"offset": 1})
+		assert.EqualError(t, err, "no value given for parameter :Expected")
+
+		prepared := r.Prepare(e)
+		v, err = prepared.Execute(root, map[string]any{"offset": Int(2), "expected": 4})
+		assert.NoError(t, err)
+		assert.Equal(t, Bool(true), v)
+
+		_, err = prepared.Execute(root, map[string]any{"offset": "1", "expected": 3})
+		assert.ErrorIs(t, err, ErrInvalidParameter)
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		e, err := r.Parse(Options{
+			RootType:   NameOf[MessageContext](),
+			Expression: "time.now.hour.add(time.today.hour).equals(:expected).and(:expected.gt(time.now.hour))",
+			Parameters: map[string]TypeName{"expected": NameOf[Int]()},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		partial, err := r.CompilePartial(e, map[string]any{
+			"time": TimePackage{
+				Now:   time.Date(2023, 1, 1, 2, 0, 0, 0, time.UTC),
+				Today: time.Date(2023, 1, 1, 3, 0, 0, 0, time.UTC),
+			},
+		})
+		assert.NoError(t, err)
+
+		v, err := partial(MessageContext{}, map[string]any{"expected": 5})
+		assert.NoError(t, err)
+		assert.Equal(t, Bool(true), v)
+
+		v, err = partial(MessageContext{}, map[string]any{"expected": 4})
+		assert.NoError(t, err)
+		assert.Equal(t, Bool(false), v)
+
+		v, err = r.CompileBound(e)(MessageContext{}, map[string]any{"expected": 5})
+		assert.NoError(t, err)
+		assert.Equal(t, Bool(false), v)
+	})
 }
@@ -0,0 +1,112 @@
+package texpr
+
+import (
+	"fmt"
+	"sync"
+)
+
+// A function which returns the type with the given name when it was not given to a system, or nil
+// if there is no type with the name. The system prepares and links a copy of the type returned like
+// the types given to the system, and each type is only requested once by a system.
+type TypeResolver func(name TypeName) (*Type, error)
+
+// The types a system created after it was built, shared by all copies of the system.
+type lazyTypes struct {
+	// Guards types.
+	lock sync.RWMutex
+	// Held while types are being resolved so each type is only resolved once.
+	resolving sync.Mutex
+	types     map[TypeName]*Type
+}
+
+// Returns the type with the given name. When the system does not have the type, list types (see
+// System.ListOf) and types from SystemOptions.Resolver are created along with any types they refer to.
+// If there is no type with the name nil is returned.
+func (sys System) ResolveType(name TypeName) (*Type, error) {
+	if t := sys.Type(name); t != nil || name == "" || sys.lazy == nil {
+		return t, nil
+	}
+
+	sys.lazy.resolving.Lock()
+	defer sys.lazy.resolving.Unlock()
+
+	pending := make(map[TypeName]*Type)
+	t, err := sys.resolveIn(name, pending)
+	if err != nil || t == nil {
+		return nil, err
+	}
+
+	sys.lazy.lock.Lock()
+	defer sys.lazy.lock.Unlock()
+	for pendingName, pendingType := range pending {
+		sys.lazy.types[pendingName] = pendingType
+	}
+	return t, nil
+}
+
+// Returns the type in the system with the given name, or nil if it can't be resolved.
+func (sys System) resolveType(name TypeName) *Type {
+	t, _ := sys.ResolveType(name)
+	return t
+}
+
+// Resolves the type with the given name where the types resolved so far are in pending. Types are
+// added to pending before they are linked so types can refer to each other.
+func (sys System) resolveIn(name TypeName, pending map[TypeName]*Type) (*Type, error) {
+	if t := sys.Type(name); t != nil || name == "" {
+		return t, nil
+	}
+	if t := pending[name]; t != nil {
+		return t, nil
+	}
+
+	var t *Type
+	if element, isList := listElement(name); isList {
+		elementType, err := sys.resolveIn(element, pending)
+		if err != nil {
+			return nil, err
+		}
+		if elementType == nil {
+			return nil, SystemError{
+				Message: fmt.Sprintf("list element type %s could not be found", element),
+				Kind:    ErrUnknownType,
+			}
+		}
+		t = sys.newListType(elementType)
+	} else if sys.options.Resolver != nil {
+		resolved, err := sys.options.Resolver(name)
+		if err != nil {
+			return nil, SystemError{
+				Message: fmt.Sprintf("type %s could not be resolved: %v", name, err),
+				Kind:    ErrUnknownType,
+				Cause:   err,
+			}
+		}
+		if resolved == nil {
+			return nil, nil
+		}
+		if resolved.Name != name {
+			return nil, SystemError{
+				Message: fmt.Sprintf("type %s was resolved with the name %s", name, resolved.Name),
+				Type:    resolved,
+				Kind:    ErrUnknownType,
+			}
+		}
+		copied := copyType(*resolved)
+		t = &copied
+	} else {
+		return nil, nil
+	}
+
+	if err := sys.initType(t); err != nil {
+		return nil, err
+	}
+	pending[name] = t
+	err := sys.linkType(t, func(other TypeName) (*Type, error) {
+		return sys.resolveIn(other, pending)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,78 @@
+package texpr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeResolver(t *testing.T) {
+	requested := make([]TypeName, 0)
+	errTenant := errors.New("tenant is unavailable")
+	resolver := func(name TypeName) (*Type, error) {
+		requested = append(requested, name)
+		switch name {
+		case "customer":
+			return &Type{Name: name, Values: []Value{
+				{Path: "name", Type: "text"},
+				{Path: "manager", Type: "customer"},
+				{Path: "orders", Type: "list<order>"},
+			}}, nil
+		case "order":
+			return &Type{Name: name, Values: []Value{
+				{Path: "customer", Type: "customer"},
+			}}, nil
+		case "broken":
+			return nil, errTenant
+		case "renamed":
+			return &Type{Name: "other"}, nil
+		}
+		return nil, nil
+	}
+
+	s, err := NewSystemWithOptions([]Type{{
+		Name:  "text",
+		Parse: func(x string) (any, error) { return x, nil },
+	}, {
+		Name: "context",
+		Values: []Value{
+			{Path: "customer", Type: "customer"},
+		},
+	}}, SystemOptions{Resolver: resolver})
+	assert.NoError(t, err)
+	assert.Equal(t, []TypeName{"customer", "order"}, requested)
+
+	customer := s.Type("customer")
+	assert.NotNil(t, customer)
+	assert.Same(t, customer, customer.Value("manager").ValueType())
+	assert.Same(t, customer, s.Type("order").Value("customer").ValueType())
+
+	e, err := s.Parse(Options{RootType: "context", Expression: "customer.orders.first.customer.manager.name"})
+	assert.NoError(t, err)
+	assert.Equal(t, "text", string(e.Last().Type.Name))
+
+	e, err = s.Parse(Options{RootType: "order", Expression: "customer.name"})
+	assert.NoError(t, err)
+	assert.Equal(t, "text", string(e.Last().Type.Name))
+	assert.Equal(t, []TypeName{"customer", "order"}, requested)
+
+	_, err = s.Parse(Options{RootType: "invoice", Expression: "total"})
+	assert.Error(t, err)
+	assert.Equal(t, []TypeName{"customer", "order", "invoice"}, requested)
+
+	_, err = s.ResolveType("broken")
+	assert.ErrorIs(t, err, ErrUnknownType)
+	assert.ErrorIs(t, err, errTenant)
+
+	_, err = s.ResolveType("renamed")
+	assert.EqualError(t, err, "type renamed was resolved with the name other")
+
+	_, err = NewSystemWithOptions([]Type{{
+		Name: "context",
+		Values: []Value{
+			{Path: "broken", Type: "broken"},
+		},
+	}}, SystemOptions{Resolver: resolver})
+	assert.ErrorIs(t, err, errTenant)
+}
@@ -0,0 +1,101 @@
+package texpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A machine-readable description of the value an expression produces, see Expr.Result.
+type ResultSchema struct {
+	// The type of the value produced.
+	Type TypeName `json:"type"`
+	// If the value produced may be absent, because a value in the chain is Nullable or chained with ?.,
+	// or it's null or given null for a generic parameter.
+	Nullable bool `json:"nullable,omitempty"`
+	// The schema of the elements when the type is a list type (see System.ListOf).
+	Element *ResultSchema `json:"element,omitempty"`
+	// The values the result can be when the type has enums.
+	Enums []string `json:"enums,omitempty"`
+	// The description of the type of the value produced.
+	Description string `json:"description,omitempty"`
+}
+
+// Returns a description of the value the linked expression produces. An error is returned if the
+// expression was not linked by a system.
+func (e *Expr) Result() (ResultSchema, error) {
+	last := e.Last()
+	if last.Type == nil {
+		return ResultSchema{}, NewParseErrorKind(last, ErrUnknownType, fmt.Sprintf("expression %s was not linked to a type", e.String()))
+	}
+	result := resultSchemaOf(last.Type)
+	for c := e; c != nil; c = c.Next {
+		if c.Optional || c.Type == Null || (c.Value != nil && c.Value.Nullable) || hasNullArgument(c) {
+			result.Nullable = true
+		}
+	}
+	return result, nil
+}
+
+// Returns whether null was given to a generic parameter of the generic value, so it may produce null.
+func hasNullArgument(e *Expr) bool {
+	if e.Value == nil || !e.Value.Generic {
+		return false
+	}
+	for _, arg := range e.Arguments {
+		if arg.Parameter != nil && arg.Parameter.Generic && arg.Last().Type == Null {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the schema of a value of the type.
+func resultSchemaOf(t *Type) ResultSchema {
+	result := ResultSchema{
+		Type:        t.Name,
+		Enums:       t.Enums,
+		Description: t.Description,
+	}
+	if element := t.ElementType(); element != nil {
+		elementSchema := resultSchemaOf(element)
+		result.Element = &elementSchema
+	}
+	return result
+}
+
+// Returns the result as a JSON Schema fragment. Types in the given map are JSON types (ex: "integer",
+// "string"), enums are string enums, list types are arrays, and other types are references to a
+// definition named by the type (#/definitions/type). Nullable results also allow null.
+func (r ResultSchema) JSONSchema(jsonTypes map[TypeName]string) map[string]any {
+	schema := make(map[string]any)
+	switch {
+	case r.Element != nil:
+		schema["type"] = "array"
+		schema["items"] = r.Element.JSONSchema(jsonTypes)
+	case len(r.Enums) > 0:
+		enums := make([]any, len(r.Enums))
+		for i, enum := range r.Enums {
+			enums[i] = enum
+		}
+		schema["type"] = "string"
+		schema["enum"] = enums
+	case jsonTypes[r.Type] != "":
+		schema["type"] = jsonTypes[r.Type]
+	default:
+		schema["$ref"] = "#/definitions/" + strings.ReplaceAll(string(r.Type), "/", "~1")
+	}
+	if r.Description != "" {
+		schema["description"] = r.Description
+	}
+	if r.Nullable {
+		if jsonType, ok := schema["type"].(string); ok {
+			schema["type"] = []any{jsonType, "null"}
+			if enums, ok := schema["enum"].([]any); ok {
+				schema["enum"] = append(enums, nil)
+			}
+		} else {
+			return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+		}
+	}
+	return schema
+}
@@ -0,0 +1,85 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResult(t *testing.T) {
+	s := NewSystemRequired([]Type{{
+		Name:  "status",
+		Enums: []string{"open", "closed"},
+	}, {
+		Name: "num",
+		Parse: func(x string) (any, error) {
+			return x, nil
+		},
+	}, {
+		Name:        "ticket",
+		Description: "A support ticket",
+		Values: []Value{
+			{Path: "status", Type: "status"},
+			{Path: "assignee", Type: "user", Nullable: true},
+			{Path: "watchers", Type: ListTypeName("user")},
+			{Path: "priority", Type: "num"},
+		},
+	}, {
+		Name: "user",
+		Values: []Value{
+			{Path: "name", Type: "num"},
+		},
+	}})
+
+	tests := []struct {
+		expression string
+		result     ResultSchema
+		jsonSchema map[string]any
+	}{
+		{
+			expression: "priority",
+			result:     ResultSchema{Type: "num"},
+			jsonSchema: map[string]any{"type": "integer"},
+		},
+		{
+			expression: "status",
+			result:     ResultSchema{Type: "status", Enums: []string{"open", "closed"}},
+			jsonSchema: map[string]any{"type": "string", "enum": []any{"open", "closed"}},
+		},
+		{
+			expression: "assignee.name",
+			result:     ResultSchema{Type: "num", Nullable: true},
+			jsonSchema: map[string]any{"type": []any{"integer", "null"}},
+		},
+		{
+			expression: "assignee",
+			result:     ResultSchema{Type: "user", Nullable: true},
+			jsonSchema: map[string]any{"anyOf": []any{map[string]any{"$ref": "#/definitions/user"}, map[string]any{"type": "null"}}},
+		},
+		{
+			expression: "watchers",
+			result: ResultSchema{
+				Type:        "list<user>",
+				Description: "A list of user",
+				Element:     &ResultSchema{Type: "user"},
+			},
+			jsonSchema: map[string]any{
+				"type":        "array",
+				"description": "A list of user",
+				"items":       map[string]any{"$ref": "#/definitions/user"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		e, err := s.Parse(Options{RootType: "ticket", Expression: test.expression})
+		assert.NoError(t, err, test.expression)
+		result, err := e.Result()
+		assert.NoError(t, err, test.expression)
+		assert.Equal(t, test.result, result, test.expression)
+		assert.Equal(t, test.jsonSchema, result.JSONSchema(map[TypeName]string{"num": "integer"}), test.expression)
+	}
+
+	_, err := (&Expr{Token: "x"}).Result()
+	assert.ErrorIs(t, err, ErrUnknownType)
+}
@@ -0,0 +1,169 @@
+package texpr
+
+import (
+	"fmt"
+	"sort"
+)
+
+// How a RuleSet combines the results of the rules whose conditions match.
+type RuleStrategy string
+
+const (
+	// The result is the action result of the first matching rule, or nil when no rule matches.
+	RuleFirstMatch RuleStrategy = "firstMatch"
+	// The result is a slice of the action results of every matching rule.
+	RuleAllMatches RuleStrategy = "allMatches"
+	// The result is the action results of every matching rule combined with RuleSetOptions.Accumulate.
+	RuleAccumulate RuleStrategy = "accumulate"
+)
+
+// A rule which produces the result of its action when its condition is true.
+type Rule struct {
+	// The name of the rule, which is reported when it matches.
+	Name string
+	// Rules with a higher priority are evaluated first. Rules with the same priority are evaluated in
+	// the order they're given.
+	Priority int
+	// The condition which must evaluate to true for the rule to match. When nil the rule always matches.
+	Condition *Expr
+	// The action evaluated when the rule matches which produces the rule's result.
+	Action *Expr
+}
+
+// The options of a RuleSet.
+type RuleSetOptions struct {
+	// How the results of matching rules are combined, by default RuleFirstMatch.
+	Strategy RuleStrategy
+	// Compiles the conditions and actions of the rules, ex: BoundRun(reflect.CompileBound(e)).
+	Compile func(e *Expr) BoundRun
+	// Combines the accumulated result with the result of a matching rule for RuleAccumulate. By default
+	// results are added with StandardOperations.
+	Accumulate func(accumulated any, result any) (any, error)
+	// The accumulated result before any rule matches for RuleAccumulate.
+	Initial any
+}
+
+// The result of evaluating a RuleSet.
+type RuleResult struct {
+	// The names of the rules which matched in the order they were evaluated.
+	Matched []string
+	// The result of the strategy, see RuleStrategy.
+	Value any
+}
+
+// A prioritized list of compiled rules, see NewRuleSet.
+type RuleSet struct {
+	options RuleSetOptions
+	rules   []compiledRule
+}
+
+type compiledRule struct {
+	rule      Rule
+	condition BoundRun
+	action    BoundRun
+}
+
+// Returns a rule set which evaluates the rules in priority order with the strategy of the options.
+func NewRuleSet(rules []Rule, options RuleSetOptions) (*RuleSet, error) {
+	if options.Compile == nil {
+		return nil, fmt.Errorf("rule set requires a compile function")
+	}
+	if options.Strategy == "" {
+		options.Strategy = RuleFirstMatch
+	}
+	switch options.Strategy {
+	case RuleFirstMatch, RuleAllMatches:
+	case RuleAccumulate:
+		if options.Accumulate == nil {
+			options.Accumulate = func(accumulated any, result any) (any, error) {
+				if accumulated == nil {
+					return result, nil
+				}
+				return StandardOperations{}.Arithmetic(OpAdd, accumulated, result)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown rule strategy %s", options.Strategy)
+	}
+
+	set := &RuleSet{options: options, rules: make([]compiledRule, len(rules))}
+	for i, rule := range rules {
+		if rule.Action == nil {
+			return nil, fmt.Errorf("rule %s has no action", rule.Name)
+		}
+		set.rules[i] = compiledRule{rule: rule, action: options.Compile(rule.Action)}
+		if rule.Condition != nil {
+			set.rules[i].condition = options.Compile(rule.Condition)
+		}
+	}
+	sort.SliceStable(set.rules, func(i, j int) bool {
+		return set.rules[i].rule.Priority > set.rules[j].rule.Priority
+	})
+	return set, nil
+}
+
+// Returns the rules in the order they're evaluated.
+func (s *RuleSet) Rules() []Rule {
+	rules := make([]Rule, len(s.rules))
+	for i, r := range s.rules {
+		rules[i] = r.rule
+	}
+	return rules
+}
+
+// Evaluates the rules against the root and bind parameters and combines the results of the rules
+// which match with the strategy of the rule set.
+func (s *RuleSet) Evaluate(root any, params map[string]any) (RuleResult, error) {
+	result := RuleResult{Matched: make([]string, 0)}
+	results := make([]any, 0)
+	accumulated := s.options.Initial
+	for _, r := range s.rules {
+		matches, err := r.matches(root, params)
+		if err != nil {
+			return result, err
+		}
+		if !matches {
+			continue
+		}
+		value, err := r.action(root, params)
+		if err != nil {
+			return result, fmt.Errorf("rule %s action: %w", r.rule.Name, err)
+		}
+		result.Matched = append(result.Matched, r.rule.Name)
+		switch s.options.Strategy {
+		case RuleFirstMatch:
+			result.Value = value
+			return result, nil
+		case RuleAllMatches:
+			results = append(results, value)
+		case RuleAccumulate:
+			accumulated, err = s.options.Accumulate(accumulated, value)
+			if err != nil {
+				return result, fmt.Errorf("rule %s accumulate: %w", r.rule.Name, err)
+			}
+		}
+	}
+	switch s.options.Strategy {
+	case RuleAllMatches:
+		result.Value = results
+	case RuleAccumulate:
+		result.Value = accumulated
+	}
+	return result, nil
+}
+
+// Returns whether the condition of the rule is true.
+func (r compiledRule) matches(root any, params map[string]any) (bool, error) {
+	if r.condition == nil {
+		return true, nil
+	}
+	value, err := r.condition(root, params)
+	if err != nil {
+		return false, fmt.Errorf("rule %s condition: %w", r.rule.Name, err)
+	}
+	matches, isBool := value.(bool)
+	if !isBool {
+		return false, fmt.Errorf("rule %s condition returned %v (%T) instead of a boolean", r.rule.Name, value, value)
+	}
+	return matches, nil
+}
@@ -0,0 +1,105 @@
+package texpr
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rulesCart struct {
+	Total int
+	Items int
+	Code  string
+}
+
+func TestRuleSet(t *testing.T) {
+	r, err := NewReflect(ReflectOptions{
+		BoolType: "bool",
+		Types: map[reflect.Type]Type{
+			TypeOf[bool](): {Name: "bool"},
+			TypeOf[int](): {
+				Name:       "int",
+				Comparable: true,
+				Ordered:    true,
+				Numeric:    true,
+				Parse: func(x string) (any, error) {
+					return strconv.Atoi(x)
+				},
+			},
+			TypeOf[string](): {
+				Name:       "text",
+				Comparable: true,
+				ParseOrder: -1,
+				Parse: func(x string) (any, error) {
+					return x, nil
+				},
+			},
+			TypeOf[rulesCart](): {},
+		},
+	})
+	assert.NoError(t, err)
+	root := NameOf[rulesCart]()
+	parse := func(expression string, expected TypeName) *Expr {
+		e, err := r.Parse(Options{RootType: root, Expression: expression, ExpectedTypes: []TypeName{expected}})
+		assert.NoError(t, err)
+		return e
+	}
+	rules := []Rule{
+		{Name: "bulk", Condition: parse("items.>=(10)", "bool"), Action: parse("5", "int")},
+		{Name: "default", Priority: -1, Action: parse("0", "int")},
+		{Name: "coupon", Priority: 10, Condition: parse("code.=('SAVE')", "bool"), Action: parse("total./(10)", "int")},
+		{Name: "large", Condition: parse("total.>(100)", "bool"), Action: parse("3", "int")},
+	}
+	compile := func(e *Expr) BoundRun {
+		return BoundRun(r.CompileBound(e))
+	}
+
+	tests := []struct {
+		strategy RuleStrategy
+		cart     rulesCart
+		matched  []string
+		value    any
+	}{
+		{strategy: RuleFirstMatch, cart: rulesCart{Total: 200, Items: 12, Code: "SAVE"}, matched: []string{"coupon"}, value: 20},
+		{strategy: RuleFirstMatch, cart: rulesCart{Total: 200, Items: 12}, matched: []string{"bulk"}, value: 5},
+		{strategy: RuleFirstMatch, cart: rulesCart{Total: 50, Items: 1}, matched: []string{"default"}, value: 0},
+		{strategy: RuleAllMatches, cart: rulesCart{Total: 200, Items: 12, Code: "SAVE"}, matched: []string{"coupon", "bulk", "large", "default"}, value: []any{20, 5, 3, 0}},
+		{strategy: RuleAccumulate, cart: rulesCart{Total: 200, Items: 12}, matched: []string{"bulk", "large", "default"}, value: 8},
+	}
+	for _, test := range tests {
+		t.Run(string(test.strategy), func(t *testing.T) {
+			set, err := NewRuleSet(rules, RuleSetOptions{Strategy: test.strategy, Compile: compile})
+			assert.NoError(t, err)
+			result, err := set.Evaluate(test.cart, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, test.matched, result.Matched)
+			assert.Equal(t, test.value, result.Value)
+		})
+	}
+
+	set, err := NewRuleSet(rules, RuleSetOptions{
+		Strategy: RuleAccumulate,
+		Compile:  compile,
+		Initial:  100,
+		Accumulate: func(accumulated any, result any) (any, error) {
+			return accumulated.(int) - result.(int), nil
+		},
+	})
+	assert.NoError(t, err)
+	result, err := set.Evaluate(rulesCart{Total: 200, Items: 12}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 92, result.Value)
+	assert.Equal(t, "coupon", set.Rules()[0].Name)
+
+	set, err = NewRuleSet([]Rule{{Name: "bad", Condition: parse("total", "int"), Action: parse("1", "int")}}, RuleSetOptions{Compile: compile})
+	assert.NoError(t, err)
+	_, err = set.Evaluate(rulesCart{Total: 1}, nil)
+	assert.EqualError(t, err, "rule bad condition returned 1 (int) instead of a boolean")
+
+	_, err = NewRuleSet(rules, RuleSetOptions{Strategy: "random", Compile: compile})
+	assert.EqualError(t, err, "unknown rule strategy random")
+	_, err = NewRuleSet([]Rule{{Name: "empty"}}, RuleSetOptions{Compile: compile})
+	assert.EqualError(t, err, "rule empty has no action")
+}
@@ -0,0 +1,197 @@
+package texpr
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The options used to generate types from a database schema, see SchemaTypes.
+type SchemaOptions struct {
+	// The schemas whose tables are used. When empty every schema other than information_schema and
+	// pg_catalog is used.
+	Schemas []string
+	// The types of columns keyed by their lowercase SQL data type, ex: "integer", "character varying".
+	DataTypes map[string]TypeName
+	// The type of columns whose data type is not in DataTypes. When empty those columns are skipped.
+	DefaultType TypeName
+	// Columns with one of these lowercase data types and no more than EnumThreshold distinct values
+	// are given an enum type of the distinct values. When zero enums are not detected.
+	EnumThreshold int
+	// The data types which are checked for enums. When empty char, varchar, character varying, text,
+	// enum, and USER-DEFINED columns are checked.
+	EnumDataTypes []string
+	// Returns the type name of a table. By default the table name is used, or the schema and table name
+	// joined by an underscore when tables in more than one of the schemas have the name.
+	TableType func(schema, table string) TypeName
+	// Returns the type name of the enum detected for a column. By default it's the table type name
+	// and column name joined by an underscore.
+	EnumType func(table TypeName, column string) TypeName
+	// Quotes a schema, table, or column name in a query. By default names are wrapped in double quotes.
+	Quote func(name string) string
+}
+
+// A column read from information_schema.columns.
+type schemaColumn struct {
+	schema   string
+	table    string
+	column   string
+	dataType string
+}
+
+var defaultEnumDataTypes = []string{"char", "varchar", "character varying", "text", "enum", "user-defined"}
+
+// Returns the types of the tables and columns in the database by reading information_schema.columns.
+// Each table is a type with a value for each column, enums are detected by querying the distinct values
+// of columns (see SchemaOptions.EnumThreshold). The types returned can be given to a system along with
+// the types in SchemaOptions.DataTypes.
+func SchemaTypes(ctx context.Context, db *sql.DB, options SchemaOptions) ([]Type, error) {
+	columns, err := schemaColumns(ctx, db, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.TableType == nil {
+		schemas := make(map[string]map[string]bool)
+		for _, c := range columns {
+			if schemas[c.table] == nil {
+				schemas[c.table] = make(map[string]bool)
+			}
+			schemas[c.table][c.schema] = true
+		}
+		options.TableType = func(schema, table string) TypeName {
+			if len(schemas[table]) > 1 {
+				return TypeName(schema + "_" + table)
+			}
+			return TypeName(table)
+		}
+	}
+	if options.EnumType == nil {
+		options.EnumType = func(table TypeName, column string) TypeName {
+			return TypeName(string(table) + "_" + column)
+		}
+	}
+	if options.Quote == nil {
+		options.Quote = func(name string) string {
+			return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+		}
+	}
+	if len(options.EnumDataTypes) == 0 {
+		options.EnumDataTypes = defaultEnumDataTypes
+	}
+
+	types := make([]Type, 0)
+	enums := make([]Type, 0)
+	tableIndex := make(map[string]int)
+	tableNames := make(map[TypeName]string)
+	for _, c := range columns {
+		key := c.schema + "." + c.table
+		index, exists := tableIndex[key]
+		if !exists {
+			name := options.TableType(c.schema, c.table)
+			if other, taken := tableNames[name]; taken {
+				return nil, fmt.Errorf("tables %s and %s both have the type name %s", other, key, name)
+			}
+			tableNames[name] = key
+			index = len(types)
+			tableIndex[key] = index
+			types = append(types, Type{
+				Name:        name,
+				Description: fmt.Sprintf("The %s table", c.table),
+			})
+		}
+		table := &types[index]
+
+		valueType := options.DataTypes[strings.ToLower(c.dataType)]
+		if options.EnumThreshold > 0 && containsFold(options.EnumDataTypes, c.dataType) {
+			values, err := schemaEnumValues(ctx, db, c, options)
+			if err != nil {
+				return nil, err
+			}
+			if len(values) > 0 && len(values) <= options.EnumThreshold {
+				enum := Type{
+					Name:        options.EnumType(table.Name, c.column),
+					Description: fmt.Sprintf("The values of %s.%s", c.table, c.column),
+					Enums:       values,
+				}
+				enums = append(enums, enum)
+				valueType = enum.Name
+			}
+		}
+		if valueType == "" {
+			valueType = options.DefaultType
+		}
+		if valueType == "" {
+			continue
+		}
+
+		table.Values = append(table.Values, Value{
+			Path:        c.column,
+			Type:        valueType,
+			Description: fmt.Sprintf("The %s column (%s)", c.column, c.dataType),
+		})
+	}
+
+	return append(types, enums...), nil
+}
+
+// Returns the columns of the tables in the schemas of the options in order.
+func schemaColumns(ctx context.Context, db *sql.DB, options SchemaOptions) ([]schemaColumn, error) {
+	rows, err := db.QueryContext(ctx, "SELECT table_schema, table_name, column_name, data_type FROM information_schema.columns ORDER BY table_schema, table_name, ordinal_position")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make([]schemaColumn, 0)
+	for rows.Next() {
+		c := schemaColumn{}
+		if err := rows.Scan(&c.schema, &c.table, &c.column, &c.dataType); err != nil {
+			return nil, err
+		}
+		if len(options.Schemas) > 0 {
+			if !containsFold(options.Schemas, c.schema) {
+				continue
+			}
+		} else if strings.EqualFold(c.schema, "information_schema") || strings.EqualFold(c.schema, "pg_catalog") {
+			continue
+		}
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
+
+// Returns the sorted distinct non-null values of the column, reading no more than one more than the enum threshold.
+func schemaEnumValues(ctx context.Context, db *sql.DB, c schemaColumn, options SchemaOptions) ([]string, error) {
+	quote := options.Quote
+	query := fmt.Sprintf("SELECT DISTINCT %s FROM %s.%s WHERE %s IS NOT NULL LIMIT %d",
+		quote(c.column), quote(c.schema), quote(c.table), quote(c.column), options.EnumThreshold+1)
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make([]string, 0)
+	for rows.Next() && len(values) <= options.EnumThreshold {
+		value := ""
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values, rows.Err()
+}
+
+// Returns whether the values contain the value, case insensitive.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package texpr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A database/sql driver which answers queries with fixed results keyed by query.
+type schemaDriver struct {
+	results map[string][][]driver.Value
+}
+
+func (d schemaDriver) Open(name string) (driver.Conn, error) {
+	return schemaConn{d}, nil
+}
+
+type schemaConn struct {
+	driver schemaDriver
+}
+
+func (c schemaConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare is not supported")
+}
+func (c schemaConn) Close() error {
+	return nil
+}
+func (c schemaConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("transactions are not supported")
+}
+func (c schemaConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	for prefix, rows := range c.driver.results {
+		if strings.HasPrefix(query, prefix) {
+			return &schemaRows{rows: rows}, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected query %s", query)
+}
+
+type schemaRows struct {
+	rows [][]driver.Value
+}
+
+func (r *schemaRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return []string{"value"}
+	}
+	columns := make([]string, len(r.rows[0]))
+	for i := range columns {
+		columns[i] = fmt.Sprintf("c%d", i)
+	}
+	return columns
+}
+func (r *schemaRows) Close() error {
+	return nil
+}
+func (r *schemaRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+var registerSchemaDriver sync.Once
+
+func TestSchemaTypes(t *testing.T) {
+	registerSchemaDriver.Do(func() {
+		sql.Register("texpr-schema", schemaDriver{results: map[string][][]driver.Value{
+			"SELECT table_schema": {
+				{"public", "orders", "id", "integer"},
+				{"public", "orders", "status", "character varying"},
+				{"public", "orders", "note", "text"},
+				{"public", "orders", "location", "point"},
+				{"public", "users", "id", "integer"},
+				{"public", "users", "name", "text"},
+				{"pg_catalog", "pg_class", "relname", "name"},
+				{"audit", "events", "id", "integer"},
+				{"audit", "users", "id", "integer"},
+			},
+			`SELECT DISTINCT "status" FROM "public"."orders"`:                               {{"shipped"}, {"pending"}},
+			`SELECT DISTINCT "note" FROM "public"."orders"`:                                 {{"a"}, {"b"}, {"c"}, {"d"}},
+			`SELECT DISTINCT "name" FROM "public"."users" WHERE "name" IS NOT NULL LIMIT 4`: {},
+		}})
+	})
+	db, err := sql.Open("texpr-schema", "")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	options := SchemaOptions{
+		Schemas: []string{"public"},
+		DataTypes: map[string]TypeName{
+			"integer":           "int",
+			"character varying": "text",
+			"text":              "text",
+		},
+		EnumThreshold: 3,
+	}
+	types, err := SchemaTypes(context.Background(), db, options)
+	assert.NoError(t, err)
+
+	describe := func(types []Type) []string {
+		out := make([]string, 0)
+		for _, t := range types {
+			values := make([]string, len(t.Values))
+			for i, v := range t.Values {
+				values[i] = v.Path + " " + string(v.Type)
+			}
+			out = append(out, fmt.Sprintf("%s: %s %s", t.Name, strings.Join(values, ", "), strings.Join(t.Enums, "|")))
+		}
+		return out
+	}
+	assert.Equal(t, []string{
+		"orders: id int, status orders_status, note text ",
+		"users: id int, name text ",
+		"orders_status:  pending|shipped",
+	}, describe(types))
+
+	s, err := NewSystem(append(types, Type{Name: "int"}, Type{Name: "text"}))
+	assert.NoError(t, err)
+	_, err = s.Parse(Options{RootType: "orders", Expression: "status", ExpectedTypes: []TypeName{"orders_status"}})
+	assert.NoError(t, err)
+
+	options.Schemas = nil
+	options.EnumThreshold = 0
+	options.DefaultType = "any"
+	types, err = SchemaTypes(context.Background(), db, options)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"orders: id int, status text, note text, location any ",
+		"public_users: id int, name text ",
+		"events: id int ",
+		"audit_users: id int ",
+	}, describe(types))
+
+	options.TableType = func(schema, table string) TypeName { return TypeName(table) }
+	_, err = SchemaTypes(context.Background(), db, options)
+	assert.EqualError(t, err, "tables public.users and audit.users both have the type name users")
+}
@@ -0,0 +1,171 @@
+package texpr
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// The options of System.SearchWithOptions.
+type SearchOptions struct {
+	// The locale of the descriptions searched and returned.
+	Locale string
+	// The roles of the user searching, see Options.Roles. Types and values the roles are not allowed to
+	// use are not found. When nil everything can be found.
+	Roles []string
+	// The kinds of entries which can be found, by default every kind.
+	Kinds []DocKind
+	// The maximum number of results, when zero every match is returned.
+	Limit int
+}
+
+// An entry of the documentation index (see System.Docs) found by a search.
+type SearchResult struct {
+	DocEntry
+	// How relevant the entry is to the query, higher is more relevant.
+	Score int
+}
+
+// The scores of the ways a word of a query can match an entry.
+const (
+	searchExact       = 100
+	searchPrefix      = 80
+	searchWordPrefix  = 60
+	searchSubstring   = 40
+	searchFuzzy       = 10
+	searchParent      = 20
+	searchDescription = 15
+)
+
+// Searches the types, values, and enum options of the system for the query, like for an "insert field"
+// picker. See SearchWithOptions.
+func (sys System) Search(query string) []SearchResult {
+	return sys.SearchWithOptions(query, SearchOptions{})
+}
+
+// Searches the types, values, and enum options of the system for the query and returns the matches
+// ordered by relevance. Each word of the query must match the name (type name, value path or alias,
+// enum option or label) or the description of an entry, or the start of the type name of a value or
+// enum option (ex: user date finds user.createDate). Words score highest when they are a name, followed
+// by the start of a name, the start of a word in a name (ex: date in createDate), part of a name, the
+// letters of a name in order (ex: crdt in createDate), the type name, and the start of a word in the
+// description. An empty query returns every entry in the order of the documentation index.
+func (sys System) SearchWithOptions(query string, options SearchOptions) []SearchResult {
+	words := strings.Fields(strings.ToLower(query))
+	kinds := make(map[DocKind]bool, len(options.Kinds))
+	for _, kind := range options.Kinds {
+		kinds[kind] = true
+	}
+
+	results := make([]SearchResult, 0)
+	for _, entry := range sys.LocalizedDocs(options.Locale).Index {
+		if len(kinds) > 0 && !kinds[entry.Kind] {
+			continue
+		}
+		t := sys.Type(entry.Type)
+		names := []string{string(entry.Type)}
+		switch entry.Kind {
+		case DocKindValue:
+			v := t.Value(entry.Path)
+			if !t.Allows(v, options.Roles) {
+				continue
+			}
+			names = append([]string{v.Path}, v.Aliases...)
+		case DocKindEnum:
+			names = []string{entry.Path, entry.Label}
+		}
+		if options.Roles != nil && !hasRole(t.Roles, options.Roles) {
+			continue
+		}
+
+		score := 0
+		for _, word := range words {
+			wordScore := searchScore(word, names, entry.Description)
+			if wordScore == 0 && entry.Kind != DocKindType && strings.HasPrefix(strings.ToLower(string(entry.Type)), word) {
+				wordScore = searchParent
+			}
+			if wordScore == 0 {
+				score = 0
+				break
+			}
+			score += wordScore
+		}
+		if score > 0 || len(words) == 0 {
+			results = append(results, SearchResult{DocEntry: entry, Score: score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if options.Limit > 0 && len(results) > options.Limit {
+		results = results[:options.Limit]
+	}
+	return results
+}
+
+// Returns how well the lowercase word matches the names or description, or 0 if it doesn't match.
+func searchScore(word string, names []string, description string) int {
+	best := 0
+	better := func(score int) {
+		if score > best {
+			best = score
+		}
+	}
+	for _, name := range names {
+		lower := strings.ToLower(name)
+		switch {
+		case lower == "":
+		case lower == word:
+			better(searchExact)
+		case strings.HasPrefix(lower, word):
+			better(searchPrefix)
+		case strings.Contains(lower, word):
+			better(searchSubstring)
+			for _, part := range splitWords(name) {
+				if strings.HasPrefix(strings.ToLower(part), word) {
+					better(searchWordPrefix)
+				}
+			}
+		default:
+			if span := fuzzySpan(word, lower); span > 0 {
+				better(searchFuzzy + searchFuzzy*len(word)/span)
+			}
+		}
+	}
+	if best == 0 {
+		for _, part := range strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if strings.HasPrefix(part, word) {
+				better(searchDescription)
+			}
+		}
+	}
+	return best
+}
+
+// Returns the length of the shortest part of the text which has the letters of the word in order, or
+// 0 if the text doesn't have them.
+func fuzzySpan(word string, text string) int {
+	shortest := 0
+	for start := 0; start < len(text); start++ {
+		if text[start] != word[0] {
+			continue
+		}
+		i := 1
+		end := start + 1
+		for ; end < len(text) && i < len(word); end++ {
+			if text[end] == word[i] {
+				i++
+			}
+		}
+		if i < len(word) {
+			break
+		}
+		if span := end - start; shortest == 0 || span < shortest {
+			shortest = span
+		}
+	}
+	return shortest
+}
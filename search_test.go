@@ -0,0 +1,71 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	first := func(query string, options SearchOptions) SearchResult {
+		results := sys.SearchWithOptions(query, options)
+		if !assert.NotEmpty(t, results, query) {
+			return SearchResult{}
+		}
+		return results[0]
+	}
+
+	tests := []struct {
+		query   string
+		options SearchOptions
+		kind    DocKind
+		typ     TypeName
+		path    string
+		score   int
+	}{
+		{query: "user", kind: DocKindType, typ: typeUser, score: searchExact},
+		{query: "createDate", kind: DocKindValue, typ: typeUser, path: "createDate", score: searchExact},
+		{query: "CREATE", kind: DocKindValue, typ: typeUser, path: "createDate", score: searchPrefix},
+		{query: "min", options: SearchOptions{Kinds: []DocKind{DocKindValue}}, kind: DocKindValue, path: "minute", score: searchExact},
+		{query: "crdt", kind: DocKindValue, typ: typeUser, path: "createDate", score: searchFuzzy + searchFuzzy*4/10},
+		{query: "unambiguous", kind: DocKindValue, typ: typeTimePackage, path: "sunday", score: searchDescription},
+		{query: "week", options: SearchOptions{Kinds: []DocKind{DocKindEnum}}, kind: DocKindEnum, typ: typeDuration, path: "week", score: searchExact},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			result := first(test.query, test.options)
+			assert.Equal(t, test.kind, result.Kind)
+			if test.typ != "" {
+				assert.Equal(t, test.typ, result.Type)
+			}
+			assert.Equal(t, test.path, result.Path)
+			assert.Equal(t, test.score, result.Score)
+		})
+	}
+
+	results := sys.Search("user date")
+	assert.NotEmpty(t, results)
+	for _, result := range results {
+		assert.Equal(t, typeUser, result.Type)
+	}
+	assert.Equal(t, "createDate", results[0].Path)
+
+	results = sys.Search("create")
+	for i := 1; i < len(results); i++ {
+		assert.GreaterOrEqual(t, results[i-1].Score, results[i].Score)
+	}
+
+	assert.Len(t, sys.SearchWithOptions("", SearchOptions{Limit: 3}), 3)
+	assert.Len(t, sys.Search(""), len(sys.Docs().Index))
+	assert.Empty(t, sys.Search("zzzqqq"))
+
+	restricted := NewSystemRequired([]Type{{
+		Name: "customer",
+		Values: []Value{
+			{Path: "taxId", Type: "customer", Roles: []string{"admin"}},
+		},
+	}})
+	assert.Len(t, restricted.Search("tax"), 1)
+	assert.Empty(t, restricted.SearchWithOptions("tax", SearchOptions{Roles: []string{"user"}}))
+	assert.Len(t, restricted.SearchWithOptions("tax", SearchOptions{Roles: []string{"admin"}}), 1)
+}
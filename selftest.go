@@ -0,0 +1,67 @@
+package texpr
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// The result of running a single value test.
+type SelfTestResult struct {
+	// The type the tested value is on.
+	Type *Type
+	// The tested value.
+	Value *Value
+	// The test that was ran.
+	Test *ValueTest
+	// The result of the evaluation, if it got that far.
+	Actual any
+	// The reason the test failed, or nil if it passed.
+	Err error
+}
+
+// Returns whether the test passed.
+func (r SelfTestResult) Passed() bool {
+	return r.Err == nil
+}
+
+// Runs every test defined on the values of the system with the given evaluator and
+// returns the results in the order the types and values were defined.
+func (sys System) SelfTest(evaluator Evaluator) []SelfTestResult {
+	results := make([]SelfTestResult, 0)
+	for _, t := range sys.types {
+		for k := range t.Values {
+			v := &t.Values[k]
+			for i := range v.Tests {
+				results = append(results, sys.runValueTest(evaluator, t, v, &v.Tests[i]))
+			}
+		}
+	}
+	return results
+}
+
+// Runs the value test and returns its result.
+func (sys System) runValueTest(evaluator Evaluator, t *Type, v *Value, test *ValueTest) SelfTestResult {
+	result := SelfTestResult{
+		Type:  t,
+		Value: v,
+		Test:  test,
+	}
+	e, err := sys.Parse(Options{RootType: sys.exampleRoot(t), Expression: test.Expression})
+	if err != nil {
+		result.Err = fmt.Errorf("test %s for %s.%s is invalid: %w", test.Expression, t.Name, v.Path, err)
+		return result
+	}
+	if !e.Uses(v) {
+		result.Err = fmt.Errorf("test %s for %s.%s does not use the value", test.Expression, t.Name, v.Path)
+		return result
+	}
+	result.Actual, err = evaluator.Evaluate(e, test.Root)
+	if err != nil {
+		result.Err = fmt.Errorf("test %s for %s.%s failed evaluation: %w", test.Expression, t.Name, v.Path, err)
+		return result
+	}
+	if !reflect.DeepEqual(result.Actual, test.Expected) {
+		result.Err = fmt.Errorf("test %s for %s.%s expected %v but was %v", test.Expression, t.Name, v.Path, test.Expected, result.Actual)
+	}
+	return result
+}
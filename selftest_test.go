@@ -0,0 +1,47 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var runEvaluator = EvaluatorFunc(func(e *Expr, root any) (any, error) {
+	compiled, err := Compile[Run](e, compileOptions)
+	if err != nil {
+		return nil, err
+	}
+	return compiled(root)
+})
+
+func TestSelfTest(t *testing.T) {
+	results := sys.SelfTest(runEvaluator)
+	assert.Len(t, results, 5)
+	for _, result := range results {
+		assert.True(t, result.Passed(), "%v", result.Err)
+	}
+
+	failing := NewSystemRequired([]Type{{
+		Name:  typeText,
+		Parse: func(x string) (any, error) { return x, nil },
+		Values: []Value{
+			{Path: "length", Type: typeInt, Tests: []ValueTest{
+				{Expression: "length", Root: "abc", Expected: 4},
+				{Expression: "lower", Root: "abc", Expected: 3},
+				{Expression: "lower", Root: "abc", Expected: 3},
+			}},
+			{Path: "lower", Type: typeText, Tests: []ValueTest{
+				{Expression: "lower", Root: 3, Expected: "3"},
+			}},
+		},
+	}, {
+		Name: typeInt,
+	}})
+
+	results = failing.SelfTest(runEvaluator)
+	assert.Len(t, results, 4)
+	assert.EqualError(t, results[0].Err, "test length for text.length expected 4 but was 3")
+	assert.Equal(t, 3, results[0].Actual)
+	assert.EqualError(t, results[1].Err, "test lower for text.length does not use the value")
+	assert.EqualError(t, results[3].Err, "test lower for text.lower failed evaluation: unexpected type: int, wanted string")
+}
@@ -0,0 +1,129 @@
+package texpr
+
+import (
+	"strconv"
+)
+
+// Returns whether a backend can compute a single expression in a chain, ignoring its arguments.
+// CompileSourceLookup.Supports can be used for a backend with compilers.
+type PushDownSupport func(e *Expr) bool
+
+// An expression split into the chains a backend like SQL computes and a residual expression which
+// is evaluated in memory with the results the backend returns, see SplitPushDown.
+type PushDownSplit struct {
+	// The expression that was split.
+	Expr *Expr
+	// The chains the backend computes. Each is a copy of the start of a chain in the expression,
+	// either the expression itself or one of the arguments, which starts on the root.
+	Pushed []*Expr
+	// A copy of the expression where each pushed chain is replaced with a bind parameter named by
+	// PushedParameter. When the whole expression is pushed this is the single bind parameter.
+	Residual *Expr
+}
+
+// The prefix of the bind parameters which replace pushed chains in a residual expression.
+const PushedParameterPrefix = "_pushed"
+
+// Returns the name of the bind parameter which replaces the pushed chain at the given index.
+func PushedParameter(index int) string {
+	return PushedParameterPrefix + strconv.Itoa(index)
+}
+
+// Splits the linked expression so the backend computes as much as it supports. For each chain in the
+// expression (and in its arguments) the longest start of the chain which the backend supports, including
+// all of its arguments, is pushed as long as it depends on the root. The rest of the chain is left in
+// the residual, and the arguments which are not pushed with their chain are split the same way.
+func SplitPushDown(e *Expr, supports PushDownSupport) PushDownSplit {
+	split := PushDownSplit{
+		Expr:   e,
+		Pushed: make([]*Expr, 0),
+	}
+	split.Residual = split.chain(e.Clone(), supports)
+	return split
+}
+
+// Returns whether the split pushed the whole expression to the backend.
+func (s PushDownSplit) Complete() bool {
+	return len(s.Pushed) == 1 && s.Residual.Bind && s.Residual.Next == nil && s.Residual.Token == PushedParameter(0)
+}
+
+// Returns the bind parameters of the residual expression given the values the backend computed
+// for the pushed chains, in the order of Pushed, and the bind parameters of the original expression.
+func (s PushDownSplit) Parameters(pushed []any, params map[string]any) map[string]any {
+	all := make(map[string]any, len(pushed)+len(params))
+	for name, value := range params {
+		all[name] = value
+	}
+	for i, value := range pushed {
+		all[PushedParameter(i)] = value
+	}
+	return all
+}
+
+// Returns a function which evaluates the residual for a row returned by the backend, given the
+// root, the values of the pushed chains for the row, and the bind parameters of the expression.
+func (s PushDownSplit) Compose(residual BoundRun) func(root any, pushed []any, params map[string]any) (any, error) {
+	return func(root any, pushed []any, params map[string]any) (any, error) {
+		return residual(root, s.Parameters(pushed, params))
+	}
+}
+
+// Replaces the pushed start of the chain with a bind parameter, splits the arguments of the rest of
+// the chain, and returns the new start of the chain.
+func (s *PushDownSplit) chain(first *Expr, supports PushDownSupport) *Expr {
+	var last *Expr
+	dependsOnRoot := false
+	for c := first; c != nil && pushDownSupported(c, supports); c = c.Next {
+		last = c
+		dependsOnRoot = dependsOnRoot || (c.Value != nil && !c.Constant && !c.Bind)
+	}
+
+	rest := first
+	if last != nil && dependsOnRoot {
+		next := last.Next
+		last.Next = nil
+		pushed := first.Clone()
+		last.Next = next
+
+		bind := &Expr{
+			Token:      PushedParameter(len(s.Pushed)),
+			Start:      first.Start,
+			End:        last.End,
+			Bind:       true,
+			ParentType: first.ParentType,
+			Type:       last.Type,
+			Next:       next,
+			Parent:     first.Parent,
+			Parameter:  first.Parameter,
+			System:     first.System,
+		}
+		if next != nil {
+			next.Prev = bind
+		}
+		s.Pushed = append(s.Pushed, pushed)
+		first, rest = bind, next
+	}
+
+	for c := rest; c != nil; c = c.Next {
+		for i, arg := range c.Arguments {
+			c.Arguments[i] = s.chain(arg, supports)
+			c.Arguments[i].Parent = c
+		}
+	}
+	return first
+}
+
+// Returns whether the backend supports the expression and every expression in its arguments.
+func pushDownSupported(e *Expr, supports PushDownSupport) bool {
+	if !supports(e) {
+		return false
+	}
+	for _, arg := range e.Arguments {
+		for c := arg; c != nil; c = c.Next {
+			if !pushDownSupported(c, supports) {
+				return false
+			}
+		}
+	}
+	return true
+}
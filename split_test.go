@@ -0,0 +1,94 @@
+package texpr
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type splitName string
+
+func (n splitName) Lower() splitName {
+	return splitName(strings.ToLower(string(n)))
+}
+
+func (n splitName) Is(other splitName) Bool {
+	return n == other
+}
+
+type splitRow struct {
+	Name splitName
+	Age  Int
+}
+
+func TestSplitPushDown(t *testing.T) {
+	r, err := NewReflect(ReflectOptions{
+		Types: map[reflect.Type]Type{
+			TypeOf[Int]():       {Parse: func(x string) (any, error) { return strconv.Atoi(x) }},
+			TypeOf[Bool]():      {Parse: func(x string) (any, error) { return strconv.ParseBool(x) }},
+			TypeOf[splitName](): {ParseOrder: -1, Parse: func(x string) (any, error) { return splitName(x), nil }},
+			TypeOf[splitRow]():  {},
+		},
+	})
+	assert.NoError(t, err)
+
+	column := func(name string) Compiler[string] {
+		return func(e *Expr, root *Type, previous string, arguments []string) (string, error) {
+			return name, nil
+		}
+	}
+	operator := func(op string) Compiler[string] {
+		return func(e *Expr, root *Type, previous string, arguments []string) (string, error) {
+			return "(" + previous + " " + op + " " + strings.Join(arguments, ", ") + ")", nil
+		}
+	}
+	sql := CompileSourceLookup[string]{
+		TypeCompilers: TypeCompilers[string]{
+			NameOf[splitRow]():  {"name": column("name"), "age": column("age")},
+			NameOf[Int]():       {"gt": operator(">")},
+			NameOf[Bool]():      {"and": operator("AND")},
+			NameOf[splitName](): {"is": operator("=")},
+		},
+		ConstantCompiler: func(e *Expr, root *Type, previous string, arguments []string) (string, error) {
+			return "'" + e.Token + "'", nil
+		},
+	}
+
+	e, err := r.Parse(Options{RootType: NameOf[splitRow](), Expression: "age.gt(18).and(name.lower.is(bob))"})
+	assert.NoError(t, err)
+
+	split := SplitPushDown(e, sql.Supports)
+	assert.False(t, split.Complete())
+	assert.Len(t, split.Pushed, 2)
+	assert.Equal(t, "age.gt('18')", split.Pushed[0].String())
+	assert.Equal(t, "name", split.Pushed[1].String())
+	assert.Equal(t, ":_pushed0.and(:_pushed1.lower.is('bob'))", split.Residual.String())
+	assert.Equal(t, "age.gt('18').and(name.lower.is('bob'))", e.String())
+
+	query, err := Compile[string](split.Pushed[0], sql)
+	assert.NoError(t, err)
+	assert.Equal(t, "(age > '18')", query)
+
+	evaluate := split.Compose(BoundRun(r.CompileBound(split.Residual)))
+	result, err := evaluate(splitRow{}, []any{Bool(true), splitName("BOB")}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Bool(true), result)
+	result, err = evaluate(splitRow{}, []any{Bool(true), splitName("Al")}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Bool(false), result)
+
+	e, err = r.Parse(Options{RootType: NameOf[splitRow](), Expression: "age.gt(18).and(name.is(bob))"})
+	assert.NoError(t, err)
+	split = SplitPushDown(e, sql.Supports)
+	assert.True(t, split.Complete())
+	assert.Equal(t, ":_pushed0", split.Residual.String())
+
+	e, err = r.Parse(Options{RootType: NameOf[splitRow](), Expression: "name.lower"})
+	assert.NoError(t, err)
+	split = SplitPushDown(e, func(e *Expr) bool { return false })
+	assert.Empty(t, split.Pushed)
+	assert.Equal(t, "name.lower", split.Residual.String())
+}
@@ -0,0 +1,182 @@
+package texpr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// A stream of root values which are published to boolean expressions subscribed to it, see
+// Reflect.NewStream. Values used by more than one subscription with the same arguments (like
+// `total` in `total.>(100)` and `total.<(10)`) are evaluated once per published root. It's safe
+// for concurrent use.
+type Stream struct {
+	reflect       Reflect
+	lock          sync.RWMutex
+	subscriptions []*Subscription
+	// The keys of the expressions used by more than one subscription.
+	keys map[*Expr]string
+}
+
+// An expression subscribed to a stream, see Stream.Subscribe.
+type Subscription struct {
+	// The subscribed expression.
+	Expr *Expr
+
+	stream   *Stream
+	callback func(root any)
+}
+
+// Returns a stream which publishes roots to expressions evaluated by this reflect.
+func (r Reflect) NewStream() *Stream {
+	return &Stream{reflect: r, keys: make(map[*Expr]string)}
+}
+
+// Subscribes the boolean expression to the stream, the callback is called with each published root the
+// expression is true for. When the system has a SystemOptions.BoolType the expression must be that type.
+func (s *Stream) Subscribe(e *Expr, callback func(root any)) (*Subscription, error) {
+	if boolType := s.reflect.system.options.BoolType; boolType != "" && e.Last().Type != nil && e.Last().Type.Name != boolType {
+		return nil, fmt.Errorf("subscribed expression %s returns %s instead of %s", e.String(), e.Last().Type.Name, boolType)
+	}
+	if len(e.Placeholders()) > 0 {
+		return nil, NewParseErrorKind(e, ErrPlaceholder, "placeholders must be filled in before subscribing")
+	}
+	sub := &Subscription{Expr: e, stream: s, callback: callback}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.subscriptions = append(s.subscriptions, sub)
+	s.share()
+	return sub, nil
+}
+
+// Removes the subscription from its stream, its callback is not called for roots published after.
+func (sub *Subscription) Unsubscribe() {
+	s := sub.stream
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i, other := range s.subscriptions {
+		if other == sub {
+			s.subscriptions = append(s.subscriptions[:i:i], s.subscriptions[i+1:]...)
+			s.share()
+			return
+		}
+	}
+}
+
+// Returns the number of subscriptions to the stream.
+func (s *Stream) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.subscriptions)
+}
+
+// Returns the number of expressions in the subscriptions whose results are shared with other subscriptions.
+func (s *Stream) Shared() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.keys)
+}
+
+// Evaluates every subscription against the root and bind parameters and then calls the callbacks of
+// the subscriptions which are true, in the order they subscribed. A subscription which fails to
+// evaluate does not stop the others and its error is returned with the others joined. Returns the
+// number of subscriptions which matched.
+func (s *Stream) Publish(root any, params map[string]any) (int, error) {
+	s.lock.RLock()
+	subscriptions := s.subscriptions
+	env := reflectEnv{
+		root:   reflect.ValueOf(root),
+		params: make(map[string]any, len(params)),
+		keys:   s.keys,
+		shared: make(map[string]reflect.Value, len(s.keys)),
+	}
+	s.lock.RUnlock()
+	for name, value := range params {
+		env.params[strings.ToLower(name)] = value
+	}
+
+	matched := make([]*Subscription, 0)
+	errs := make([]error, 0)
+	for _, sub := range subscriptions {
+		result, err := s.reflect.run(env, sub.Expr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("subscription %s: %w", sub.Expr.String(), err))
+			continue
+		}
+		matches, isBool := result.(bool)
+		if !isBool {
+			errs = append(errs, fmt.Errorf("subscription %s returned %v (%T) instead of a boolean", sub.Expr.String(), result, result))
+			continue
+		}
+		if matches {
+			matched = append(matched, sub)
+		}
+	}
+	for _, sub := range matched {
+		sub.callback(root)
+	}
+	return len(matched), errors.Join(errs...)
+}
+
+// Finds the expressions used by more than one subscription. The map is replaced rather than
+// modified so publishes in progress keep the keys they started with.
+func (s *Stream) share() {
+	keys := make(map[*Expr]string)
+	counts := make(map[string]int)
+	for _, sub := range s.subscriptions {
+		streamKeys(sub.Expr, keys)
+	}
+	for _, key := range keys {
+		counts[key]++
+	}
+	s.keys = make(map[*Expr]string)
+	for e, key := range keys {
+		if counts[key] > 1 {
+			s.keys[e] = key
+		}
+	}
+}
+
+// Adds the keys of the values in the chain and its arguments to the map and returns the key of the
+// last expression in the chain. Expressions with the same key have the same result for a root and
+// parameters. Constants and bind parameters are part of keys but don't have keys of their own,
+// and volatile values have no key (nor does anything computed from them).
+func streamKeys(e *Expr, keys map[*Expr]string) string {
+	key := strings.Builder{}
+	if e.ParentType != nil {
+		key.WriteString(string(e.ParentType.Name))
+	}
+	for _, c := range e.Chain() {
+		args := make([]string, len(c.Arguments))
+		for i, arg := range c.Arguments {
+			args[i] = streamKeys(arg, keys)
+		}
+		switch {
+		case c.Placeholder:
+			return ""
+		case c.Constant:
+			key.WriteString(fmt.Sprintf("|%s%q", c.Type.Name, c.Token))
+			continue
+		case c.Bind:
+			key.WriteString(":" + strings.ToLower(c.Token))
+			continue
+		case c.Value == nil || c.Value.Volatility != VolatilityNone:
+			return ""
+		}
+		key.WriteString("." + strings.ToLower(c.Value.Path) + "(")
+		for i, arg := range args {
+			if arg == "" {
+				return ""
+			}
+			if i > 0 {
+				key.WriteString(",")
+			}
+			key.WriteString(arg)
+		}
+		key.WriteString(")")
+		keys[c] = key.String()
+	}
+	return key.String()
+}
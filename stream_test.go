@@ -0,0 +1,95 @@
+package texpr
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type streamOrder struct {
+	Amount  int
+	Country string
+	lookups *int
+}
+
+func (o streamOrder) Risk() int {
+	*o.lookups++
+	return o.Amount / 10
+}
+
+func TestStream(t *testing.T) {
+	r, err := NewReflect(ReflectOptions{
+		BoolType: "bool",
+		Types: map[reflect.Type]Type{
+			TypeOf[bool](): {Name: "bool"},
+			TypeOf[int](): {
+				Name:       "int",
+				Comparable: true,
+				Ordered:    true,
+				Numeric:    true,
+				Parse: func(x string) (any, error) {
+					return strconv.Atoi(x)
+				},
+			},
+			TypeOf[string](): {
+				Name:       "text",
+				Comparable: true,
+				ParseOrder: -1,
+				Parse: func(x string) (any, error) {
+					return x, nil
+				},
+			},
+			TypeOf[streamOrder](): {},
+		},
+	})
+	assert.NoError(t, err)
+	root := NameOf[streamOrder]()
+	parse := func(expression string) *Expr {
+		e, err := r.Parse(Options{RootType: root, Expression: expression, Parameters: map[string]TypeName{"limit": "int"}})
+		assert.NoError(t, err)
+		return e
+	}
+
+	stream := r.NewStream()
+	fired := make(map[string]int)
+	subscribe := func(expression string) *Subscription {
+		sub, err := stream.Subscribe(parse(expression), func(root any) {
+			fired[expression]++
+		})
+		assert.NoError(t, err)
+		return sub
+	}
+	subscribe("risk.>(50)")
+	subscribe("risk.>(:limit)")
+	subscribe("risk.<(5)")
+	foreign := subscribe("country.=('NZ')")
+
+	// risk is shared by the first three, country is used once.
+	assert.Equal(t, 3, stream.Shared())
+
+	lookups := 0
+	matched, err := stream.Publish(streamOrder{Amount: 600, Country: "NZ", lookups: &lookups}, map[string]any{"limit": 20})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, matched)
+	assert.Equal(t, 1, lookups)
+	assert.Equal(t, map[string]int{"risk.>(50)": 1, "risk.>(:limit)": 1, "country.=('NZ')": 1}, fired)
+
+	foreign.Unsubscribe()
+	assert.Equal(t, 3, stream.Len())
+	matched, err = stream.Publish(streamOrder{Amount: 30, Country: "NZ", lookups: &lookups}, map[string]any{"limit": 20})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, matched)
+	assert.Equal(t, 2, lookups)
+	assert.Equal(t, 1, fired["risk.<(5)"])
+	assert.Equal(t, 1, fired["country.=('NZ')"])
+
+	matched, err = stream.Publish(streamOrder{Amount: 600, lookups: &lookups}, nil)
+	assert.Equal(t, 1, matched)
+	assert.EqualError(t, err, "subscription risk>(:limit): no value given for parameter :limit")
+	assert.Equal(t, 2, fired["risk.>(50)"])
+
+	_, err = stream.Subscribe(parse("amount"), func(root any) {})
+	assert.EqualError(t, err, "subscribed expression amount returns int instead of bool")
+}
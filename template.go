@@ -0,0 +1,288 @@
+package texpr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// The options of System.TemplateFunc.
+type TemplateOptions struct {
+	// The name of the function in the templates, by default "expr".
+	Name string
+	// The options expressions are parsed with, like the root type and the bind parameters. The expression
+	// is given by the template.
+	Options Options
+	// Compiles the parsed expressions, ex: BoundRun(reflect.CompileBound(e)).
+	Compile func(e *Expr) BoundRun
+}
+
+// Returns functions for a text/template or html/template FuncMap which run the compiled expressions,
+// keyed by the names the templates call them with. Each function is given the root and then the values
+// of the bind parameters as name and value pairs, like {{ discount . "rate" 0.2 }}. The map can be given
+// to Funcs of either template package.
+func TemplateFuncs(exprs map[string]BoundRun) map[string]any {
+	funcs := make(map[string]any, len(exprs))
+	for name, run := range exprs {
+		funcs[name] = templateFunc(run)
+	}
+	return funcs
+}
+
+// Returns a function for a text/template or html/template FuncMap which parses, compiles, and runs the
+// expression it's given against the root, like {{ expr "user.name.upper" . }}. Bind parameter values
+// follow the root as name and value pairs. Each expression is parsed and compiled once and reused by
+// later calls, which makes the function safe for concurrent use by templates.
+func (sys System) TemplateFunc(options TemplateOptions) map[string]any {
+	if options.Name == "" {
+		options.Name = "expr"
+	}
+	type compiled struct {
+		run func(root any, pairs ...any) (any, error)
+		err error
+	}
+	cache := sync.Map{}
+	expr := func(expression string, root any, pairs ...any) (any, error) {
+		cached, exists := cache.Load(expression)
+		if !exists {
+			opts := options.Options
+			opts.Expression = expression
+			e, err := sys.Parse(opts)
+			c := compiled{err: err}
+			if err == nil {
+				c.run = templateFunc(options.Compile(e))
+			}
+			cached, _ = cache.LoadOrStore(expression, c)
+		}
+		c := cached.(compiled)
+		if c.err != nil {
+			return nil, c.err
+		}
+		return c.run(root, pairs...)
+	}
+	return map[string]any{options.Name: expr}
+}
+
+// Returns a template function which runs the compiled expression with the root and the parameters given as name and value pairs.
+func templateFunc(run BoundRun) func(root any, pairs ...any) (any, error) {
+	return func(root any, pairs ...any) (any, error) {
+		if len(pairs)%2 != 0 {
+			return nil, errors.New("parameters must be given as name and value pairs")
+		}
+		var params map[string]any
+		if len(pairs) > 0 {
+			params = make(map[string]any, len(pairs)/2)
+			for i := 0; i < len(pairs); i += 2 {
+				name, ok := pairs[i].(string)
+				if !ok {
+					return nil, fmt.Errorf("parameter name %v is not a string", pairs[i])
+				}
+				params[name] = pairs[i+1]
+			}
+		}
+		return run(root, params)
+	}
+}
+
+// No text type was given in the system options for parsing templates.
+var ErrNoTextType = NewParseError(nil, "undefined text type")
+
+// Parses a template of text with expressions embedded in braces, like
+// `Hello {user.name.upper}, it is {time.today.dayOfWeek}`, into a single expression of the text type
+// (see SystemOptions.TextType) which concatenates the text and the expressions with the + value of the
+// text type, like `'Hello '.+(user.name.upper).+(', it is ').+(time.today.dayOfWeek)`. The embedded
+// expressions are linked expecting the text type so they're converted to it, and `{{` and `}}` are
+// written for literal braces. The options give the root type, the parameters, etc of every embedded
+// expression and their positions are in the template. Like Parse the expression is returned with all
+// the errors found.
+func (sys System) ParseTemplate(template string, opts Options) (*Expr, error) {
+	if len(sys.Types()) == 0 {
+		return nil, ErrNoTypes
+	}
+	if template == "" {
+		return nil, ErrNoExpression
+	}
+	if sys.options.TextType == "" {
+		return nil, ErrNoTextType
+	}
+	ctx, _, err := sys.linkOptions(opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	textType := sys.Type(sys.options.TextType)
+	concat := textType.Value("+")
+
+	parts, errs := sys.splitTemplate(template, opts)
+	var first, last *Expr
+	for _, part := range parts {
+		if part.Constant {
+			if err := sys.setConstant(part, []*Type{textType}, true, ctx); err != nil {
+				errs = append(errs, *err)
+				part.Type = Unknown
+			}
+		} else {
+			errs = append(errs, sys.link(part, []*Type{textType}, ctx)...)
+		}
+		if first == nil {
+			first = part
+			last = part.Last()
+			continue
+		}
+		plus := &Expr{
+			Token:      concat.Path,
+			Called:     true,
+			Value:      concat,
+			Type:       concat.ValueType(),
+			ParentType: last.Type,
+			Arguments:  []*Expr{part},
+			Prev:       last,
+			Start:      part.Start,
+			End:        part.Start,
+		}
+		part.Parent = plus
+		part.Parameter = concat.Parameter(0)
+		last.Next = plus
+		last = plus
+	}
+	if len(errs) == 0 {
+		errs = ctx.checkConstraints(first)
+	}
+	return first, joinParseErrors(errs)
+}
+
+// Splits the template into text constants and the unlinked expressions embedded in braces, returning
+// errors for braces which are not closed or not escaped and for the syntax of the expressions.
+func (sys System) splitTemplate(template string, opts Options) ([]*Expr, []ParseError) {
+	parts := make([]*Expr, 0)
+	errs := make([]ParseError, 0)
+	text := strings.Builder{}
+	textStart := Position{}
+	at := Position{}
+	line := 0
+	lineAt := 0
+
+	addText := func() {
+		if text.Len() > 0 {
+			parts = append(parts, &Expr{Token: text.String(), Constant: true, Start: textStart, End: at})
+			text.Reset()
+		}
+	}
+	advance := func(i int) {
+		for at.Index < i {
+			if template[at.Index] == '\n' {
+				line++
+				lineAt = at.Index + 1
+			}
+			at.Index++
+			at.Line = line
+			at.Column = at.Index - lineAt
+		}
+	}
+
+	for at.Index < len(template) {
+		i := at.Index
+		b := template[i]
+		if (b == '{' || b == '}') && i+1 < len(template) && template[i+1] == b {
+			if text.Len() == 0 {
+				textStart = at
+			}
+			text.WriteByte(b)
+			advance(i + 2)
+			continue
+		}
+		if b == '}' {
+			err := NewParseErrorKind(nil, ErrSyntax, fmt.Sprintf("unexpected } at %v, use }} for a } in the text", at))
+			start := at
+			err.Start = &start
+			errs = append(errs, err)
+			advance(i + 1)
+			continue
+		}
+		if b != '{' {
+			if text.Len() == 0 {
+				textStart = at
+			}
+			text.WriteByte(b)
+			advance(i + 1)
+			continue
+		}
+
+		addText()
+		start := at
+		end := indexUnquoted(template, i+1, '}')
+		if end == -1 {
+			err := NewParseErrorKind(nil, ErrSyntax, fmt.Sprintf("template expression starting at %v did not have a terminating }", start))
+			err.Start = &start
+			errs = append(errs, err)
+			advance(len(template))
+			break
+		}
+		advance(i + 1)
+		p := newParser(template, sys.options.Identifiers)
+		p.i = i + 1
+		p.n = end
+		p.line = line
+		p.lineReset = lineAt
+		expr, syntaxErrs := sys.parseSyntax(opts, p)
+		errs = append(errs, syntaxErrs...)
+		if expr == nil {
+			err := NewParseErrorKind(nil, ErrSyntax, fmt.Sprintf("template expression starting at %v is empty", start))
+			err.Start = &start
+			errs = append(errs, err)
+		} else {
+			parts = append(parts, expr)
+		}
+		advance(end + 1)
+	}
+	addText()
+	return parts, errs
+}
+
+// Returns the index of the first character at or after the index which is not in a quoted constant or
+// path of an expression, or -1 if there isn't one. Constants surrounded with three quotes have no
+// escapes and end at the next three quotes, like the parser reads them.
+func indexUnquoted(text string, i int, c byte) int {
+	var quote byte
+	for ; i < len(text); i++ {
+		b := text[i]
+		switch {
+		case quote != 0 && b == '\\':
+			i++
+		case quote != 0 && b == quote:
+			quote = 0
+		case quote != 0:
+		case (b == '\'' || b == '"') && i+2 < len(text) && text[i+1] == b && text[i+2] == b:
+			end := strings.Index(text[i+3:], text[i:i+3])
+			if end == -1 {
+				return -1
+			}
+			i += 3 + end + 2
+		case b == '\'' || b == '"' || b == '`':
+			quote = b
+		case b == c:
+			return i
+		}
+	}
+	return -1
+}
+
+// Returns an error if the text type of templates can't be found or can't concatenate text.
+func (sys System) checkTextType() error {
+	textType := sys.Type(sys.options.TextType)
+	if textType == nil {
+		return SystemError{
+			Message: fmt.Sprintf("text type %s could not be found", sys.options.TextType),
+			Kind:    ErrUnknownType,
+		}
+	}
+	concat := textType.Value("+")
+	if concat == nil || concat.ValueType() != textType || concat.MinParameters() != 1 || concat.Parameter(0).parameterType != textType {
+		return SystemError{
+			Message: fmt.Sprintf("text type %s must have a + value which is given text and returns text", textType.Name),
+			Type:    textType,
+			Kind:    ErrUnknownValue,
+		}
+	}
+	return nil
+}
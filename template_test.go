@@ -0,0 +1,135 @@
+package texpr
+
+import (
+	htmltemplate "html/template"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type templateOrder struct {
+	Customer string
+	Total    int
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	r, err := NewReflect(ReflectOptions{
+		BoolType: "bool",
+		Types: map[reflect.Type]Type{
+			TypeOf[bool](): {Name: "bool"},
+			TypeOf[int](): {
+				Name:       "int",
+				Comparable: true,
+				Ordered:    true,
+				Numeric:    true,
+				Parse: func(x string) (any, error) {
+					return strconv.Atoi(x)
+				},
+			},
+			TypeOf[string](): {
+				Name:       "text",
+				ParseOrder: -1,
+				Parse: func(x string) (any, error) {
+					return x, nil
+				},
+			},
+			TypeOf[templateOrder](): {},
+		},
+	})
+	assert.NoError(t, err)
+	root := NameOf[templateOrder]()
+	order := templateOrder{Customer: "<Ann>", Total: 40}
+	compile := func(e *Expr) BoundRun {
+		return BoundRun(r.CompileBound(e))
+	}
+	execute := func(funcs map[string]any, text string) (string, error) {
+		out := strings.Builder{}
+		tmpl, err := template.New("order").Funcs(funcs).Parse(text)
+		if err != nil {
+			return "", err
+		}
+		err = tmpl.Execute(&out, order)
+		return out.String(), err
+	}
+
+	discount, err := r.Parse(Options{RootType: root, Expression: "total.*(:rate)", Parameters: map[string]TypeName{"rate": "int"}})
+	assert.NoError(t, err)
+	funcs := TemplateFuncs(map[string]BoundRun{"discount": compile(discount)})
+	out, err := execute(funcs, `{{ discount . "rate" 2 }}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "80", out)
+
+	_, err = execute(funcs, `{{ discount . "rate" }}`)
+	assert.ErrorContains(t, err, "parameters must be given as name and value pairs")
+	_, err = execute(funcs, `{{ discount . 1 2 }}`)
+	assert.ErrorContains(t, err, "parameter name 1 is not a string")
+
+	funcs = r.System().TemplateFunc(TemplateOptions{Options: Options{RootType: root}, Compile: compile})
+	out, err = execute(funcs, `{{ if expr "total.>(30)" . }}{{ expr "customer" . }} spent {{ expr "total" . }}{{ end }}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "<Ann> spent 40", out)
+	_, err = execute(funcs, `{{ expr "total.nope" . }}`)
+	assert.ErrorContains(t, err, "invalid value nope")
+
+	html := strings.Builder{}
+	tmpl := htmltemplate.Must(htmltemplate.New("order").Funcs(r.System().TemplateFunc(TemplateOptions{Name: "field", Options: Options{RootType: root}, Compile: compile})).Parse(`<b>{{ field "customer" . }}</b>`))
+	assert.NoError(t, tmpl.Execute(&html, order))
+	assert.Equal(t, "<b>&lt;Ann&gt;</b>", html.String())
+}
+
+func TestParseTemplate(t *testing.T) {
+	templates, err := NewSystemWithOptions(sys.definitions, SystemOptions{TextType: typeText})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		template string
+		expected string
+		err      error
+		message  string
+	}{
+		{template: "Hello {user.name.upper}, it is {time.today.dayOfWeek}", expected: "'Hello '+(user.name.upper)+(', it is ')+(time.today.dayOfWeek.text)"},
+		{template: "{user.name} is {time.now.hour} hours in", expected: "user.name+(' is ')+(time.now.hour.text)+(' hours in')"},
+		{template: "{{literal}} and {user.name.contains('}')}", expected: "'{literal} and '+(user.name.contains('}').text)"},
+		{template: "no expressions", expected: "'no expressions'"},
+		{template: "Hi {user}", expected: "'Hi '+(user.name)"},
+		{template: "Hi {user.nope}", err: ErrUnknownValue, message: "invalid value nope"},
+		{template: "Hi {user.name", err: ErrSyntax, message: "template expression starting at (index: 3, line: 0, column: 3) did not have a terminating }"},
+		{template: "Hi }", err: ErrSyntax, message: "unexpected } at (index: 3, line: 0, column: 3), use }} for a } in the text"},
+		{template: "Hi {}", err: ErrSyntax, message: "template expression starting at (index: 3, line: 0, column: 3) is empty"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.template, func(t *testing.T) {
+			e, err := templates.ParseTemplate(test.template, Options{RootType: typeContext})
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				if test.message != "" {
+					assert.ErrorContains(t, err, test.message)
+				}
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, TypeName(typeText), e.Last().Type.Name)
+			}
+			if test.expected != "" {
+				assert.Equal(t, test.expected, e.String())
+			}
+		})
+	}
+
+	e, err := templates.ParseTemplate("Hi\n  {user.nope}", Options{RootType: typeContext})
+	var parseError ParseError
+	if assert.ErrorAs(t, err, &parseError) {
+		assert.Equal(t, &Position{Index: 11, Line: 1, Column: 8}, parseError.Start)
+	}
+	assert.Equal(t, "Hi\n  ", e.Token)
+
+	_, err = sys.ParseTemplate("Hi", Options{RootType: typeContext})
+	assert.ErrorIs(t, err, ErrNoTextType)
+	_, err = NewSystemWithOptions(sys.definitions, SystemOptions{TextType: "missing"})
+	assert.ErrorIs(t, err, ErrUnknownType)
+	_, err = NewSystemWithOptions(sys.definitions, SystemOptions{TextType: typeBool})
+	assert.ErrorIs(t, err, ErrUnknownValue)
+}
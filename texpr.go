@@ -1,11 +1,16 @@
 package texpr
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // A name for a type.
@@ -18,6 +23,8 @@ type Type struct {
 	Name TypeName `json:"name"`
 	// A description of this type.
 	Description string `json:"description,omitempty"`
+	// The description of this type in other locales.
+	Descriptions Localized `json:"descriptions,omitempty"`
 	// All values of this type.
 	Values []Value `json:"values,omitempty"`
 	// All types that this type can be converted to, and which value path can be used to do it.
@@ -25,18 +32,49 @@ type Type struct {
 	// The type might be an enumerated value which means it has to be one of the specified values.
 	// Parse can be specified to validate this and return a different data type other than string.
 	Enums []string `json:"enums,omitempty"`
+	// The metadata of enum options keyed by the enum option, like labels, localized descriptions,
+	// deprecation, and sort order.
+	EnumOptions map[string]EnumOption `json:"enumOptions,omitempty"`
+	// The names of the mixins (see SystemOptions.Mixins) whose values are added to this type.
+	Mixins []string `json:"mixins,omitempty"`
+	// The roles allowed to use the values of this type, see Options.Roles. When empty everyone is allowed.
+	Roles []string `json:"roles,omitempty"`
+	// If values of this type can be compared for equality. The system adds `=` and `!=` values
+	// (see ComparableOperators) which return SystemOptions.BoolType.
+	Comparable bool `json:"comparable,omitempty"`
+	// If values of this type can be ordered. The system adds `<`, `<=`, `>`, `>=`, `min`, and `max`
+	// values (see OrderedOperators).
+	Ordered bool `json:"ordered,omitempty"`
+	// If values of this type are numbers. The system adds `+`, `-`, `*`, and `/` values (see NumericOperators).
+	// Constants of numeric types can group digits with underscores like 1_000_000, or with commas in
+	// quoted constants like '1,000', and the separators are removed before Parse. Integers can also be
+	// written in hexadecimal, binary, or octal like 0xFF, 0b1010, or 0o755, and are given to Parse in decimal.
+	Numeric bool `json:"numeric,omitempty"`
+	// The runtime behavior of the values added for Comparable, Ordered, and Numeric. When nil
+	// StandardOperations is used by evaluators.
+	Operations Operations `json:"-"`
 	// A custom parse function that converts a constant into a real value that is stored in Expression.Parsed.
-	// If the given input does not match the type an error must be returned.
+	// If the given input does not match the type an error must be returned, which can be a ConstantError to
+	// locate the problem within the constant.
 	Parse func(x string) (any, error) `json:"-"`
+	// The time layouts (see time.Parse) constants of this type can be written in, tried in order before Parse.
+	// The layout a constant matched is stored in Expr.Layout.
+	Layouts []string `json:"layouts,omitempty"`
+	// An optional function which returns whether a runtime value is a value of this type. This is
+	// used to check the values given to bind parameters, see Prepared.
+	Accepts func(v any) bool `json:"-"`
 	// The parse order of the type. By default all types are considered equal and have an order of 0.
 	// Higher parse orders are used first. For all types with the same parse order they are ordered
 	// whether they have a Parse function (it prefers this). For two types with equivalent parse function
 	// specificity they are ordered by type name length (preferring longer types before shorter).
 	ParseOrder int `json:"parseOrder,omitempty"`
 
-	values map[string]*Value
-	as     map[TypeName]*Value
-	enums  map[string]string
+	values      map[string]*Value
+	prefixes    map[string]*Value
+	as          map[TypeName]*Value
+	enums       map[string]string
+	enumOptions map[string]EnumOption
+	element     *Type
 }
 
 // Returns the value with the given path, case insensitive. If this type was not given
@@ -45,6 +83,45 @@ func (t Type) Value(path string) *Value {
 	return t.values[strings.ToLower(path)]
 }
 
+// Returns the value which is called by writing the symbol before an expression of this type, like
+// `not` for `!`, or nil if there isn't one. See Value.Prefix.
+func (t Type) PrefixValue(symbol string) *Value {
+	return t.prefixes[symbol]
+}
+
+// A value of a type as it was linked by a system, see Type.AllValues.
+type LinkedValue struct {
+	// The value, which is the same value returned by Type.Value.
+	Value *Value
+	// The path of the value followed by its aliases, which are the names the value can be referred to by.
+	Names []string
+	// The type of the value, nil for generic values.
+	Type *Type
+	// The types of the parameters of the value in order, nil for generic parameters.
+	ParameterTypes []*Type
+}
+
+// Returns the values of the type as they were linked by the system, including the values added by
+// mixins and capabilities, in the order they were added. Values of types which were not given to a
+// system are returned without types.
+func (t Type) AllValues() []LinkedValue {
+	values := make([]LinkedValue, len(t.Values))
+	for i := range t.Values {
+		v := &t.Values[i]
+		parameterTypes := make([]*Type, len(v.Parameters))
+		for k := range v.Parameters {
+			parameterTypes[k] = v.Parameters[k].ParameterType()
+		}
+		values[i] = LinkedValue{
+			Value:          v,
+			Names:          append([]string{v.Path}, v.Aliases...),
+			Type:           v.ValueType(),
+			ParameterTypes: parameterTypes,
+		}
+	}
+	return values
+}
+
 // Returns the value that's used to convert to the given type. If this type was not given
 // to a system then a nil panic will occur.
 func (t Type) AsValue(other TypeName) *Value {
@@ -58,27 +135,87 @@ func (t Type) EnumFor(input string) (string, bool) {
 	return value, ok
 }
 
-// Parses the constant input and returns a matching value. If there is no parse or matching
-// enum option then an error is returned.
+// Parses the constant input and returns a matching value. If there is no parse, matching layout,
+// or matching enum option then an error is returned.
 func (t Type) ParseInput(input string) (any, error) {
+	parsed, _, err := t.ParseLayout(input)
+	return parsed, err
+}
+
+// Parses the constant input like ParseInput and also returns the layout it matched, if it matched
+// one of the type's Layouts.
+func (t Type) ParseLayout(input string) (any, string, error) {
+	if len(t.Layouts) > 0 {
+		causes := make([]error, 0, len(t.Layouts)+1)
+		for _, layout := range t.Layouts {
+			parsed, err := time.Parse(layout, input)
+			if err == nil {
+				return parsed, layout, nil
+			}
+			causes = append(causes, err)
+		}
+		if t.Parse == nil {
+			return nil, "", fmt.Errorf("%s did not match a layout of %v: %w", input, t.Name, errors.Join(causes...))
+		}
+	}
 	if t.Parse == nil {
 		value, exists := t.EnumFor(input)
 		if exists {
-			return value, nil
+			return value, "", nil
 		}
-		return nil, fmt.Errorf("parsing is not supported for %v", t.Name)
+		return nil, "", fmt.Errorf("parsing is not supported for %v", t.Name)
+	}
+	if t.Numeric {
+		input = normalizeNumber(input)
 	}
-	return t.Parse(input)
+	parsed, err := t.Parse(input)
+	return parsed, "", err
+}
+
+var (
+	underscoreGroups = regexp.MustCompile(`^[+-]?\d+(_\d+)+(\.\d+(_\d+)*)?([eE][+-]?\d+)?$|^[+-]?\d+(\.\d+(_\d+)+)([eE][+-]?\d+)?$`)
+	commaGroups      = regexp.MustCompile(`^[+-]?\d{1,3}(,\d{3})+(\.\d+)?([eE][+-]?\d+)?$`)
+	prefixedInteger  = regexp.MustCompile(`^[+-]?0([xX][0-9a-fA-F_]+|[bB][01_]+|[oO][0-7_]+)$`)
+)
+
+// Returns the number in decimal without the separators of its digit groups, like 1_000 or 1,000 as 1000
+// and 0xFF as 255. Input which is not a number with valid digit groups is returned as is.
+func normalizeNumber(input string) string {
+	if prefixedInteger.MatchString(input) {
+		if i, err := strconv.ParseInt(input, 0, 64); err == nil {
+			return strconv.FormatInt(i, 10)
+		}
+		if u, err := strconv.ParseUint(strings.TrimPrefix(input, "+"), 0, 64); err == nil {
+			return strconv.FormatUint(u, 10)
+		}
+		return input
+	}
+	if underscoreGroups.MatchString(input) {
+		return strings.ReplaceAll(input, "_", "")
+	}
+	if commaGroups.MatchString(input) {
+		return strings.ReplaceAll(input, ",", "")
+	}
+	return input
+}
+
+// Returns whether constants of the type are parsed with Parse or Layouts.
+func (t Type) parses() bool {
+	return t.Parse != nil || len(t.Layouts) > 0
 }
 
 // A value (possibly with parameters) on a type.
 type Value struct {
-	// The main path for the value. Alternatives can be specified with Aliases.
+	// The main path for the value. Alternatives can be specified with Aliases. Paths with spaces or
+	// other characters which end a token are written surrounded with backticks, like user.`Price (USD)`.
+	// Paths can't have periods.
 	Path string `json:"path"`
 	// The aliases to the path, to allow for more than one way to refer to the value.
 	Aliases []string `json:"aliases,omitempty"`
 	// The description of the value.
 	Description string `json:"description,omitempty"`
+	// The description of the value in other locales.
+	Descriptions Localized `json:"descriptions,omitempty"`
 	// The type of the value.
 	Type TypeName `json:"type,omitempty"`
 	// If the value is has a generic type that's determined based on one or more generic parameters.
@@ -87,8 +224,36 @@ type Value struct {
 	Parameters []Parameter `json:"parameters,omitempty"`
 	// If the last parameter can be specified any number of times.
 	Variadic bool `json:"variadic,omitempty"`
-
-	valueType *Type
+	// A symbol which can be written before an expression of the type to call this value on it, like `!`
+	// for a `not` value so `!user.active` is `user.active.not`. The value must not require parameters.
+	// A symbol followed by a digit is part of a constant, so `-1` is still a number. Prefix symbols are
+	// recognized when they are on a type given to the system, not only types from a Resolver.
+	Prefix string `json:"prefix,omitempty"`
+	// If the value may be absent (null) when it's evaluated.
+	Nullable bool `json:"nullable,omitempty"`
+	// Why the value may produce a different result for the same root and parameters, if it can.
+	// See Expr.IsDeterministic.
+	Volatility Volatility `json:"volatility,omitempty"`
+	// If the value is expensive to compute, like a lookup in an external service. Evaluators consult
+	// their Limiter before computing expensive values, see ReflectOptions.Limiter.
+	Expensive bool `json:"expensive,omitempty"`
+	// Example expressions which use this value. They are parsed when the value is given to a system
+	// so they are guaranteed to be valid. See SystemOptions.Root.
+	Examples []string `json:"examples,omitempty"`
+	// Test cases for the value which are ran by System.SelfTest.
+	Tests []ValueTest `json:"tests,omitempty"`
+	// The roles allowed to use this value, see Options.Roles. When empty everyone is allowed.
+	Roles []string `json:"roles,omitempty"`
+	// An optional function which computes this value for a parsed constant of the type it's on. When this
+	// value is used by `As` to convert a constant the conversion is done while linking and stored in the
+	// constant's Parsed value, instead of being evaluated each time the expression is.
+	Convert func(parsed any) (any, error) `json:"-"`
+
+	valueType      *Type
+	operator       Operator
+	listOperation  ListOperation
+	dateOperation  DateOperation
+	logicOperation LogicOperation
 }
 
 // The calculated type of the value. This will only be non-nil when the value is passed to a system.
@@ -110,7 +275,7 @@ func (v Value) MinParameters() int {
 	min := 0
 	if v.Parameters != nil {
 		for i, p := range v.Parameters {
-			if p.Default != nil {
+			if p.Default == nil {
 				min = i + 1
 			}
 		}
@@ -138,16 +303,33 @@ func (v Value) GetType(e *Expr) *Type {
 		return v.valueType
 	}
 	genericTypes := make([]*Type, 0)
+	null := false
 	if len(e.Arguments) > 0 {
 		for _, arg := range e.Arguments {
-			if arg.Type != nil && arg.Parameter.Generic {
+			if arg.Type == Null && arg.Parameter.Generic {
+				null = true
+			} else if arg.Type != nil && arg.Parameter.Generic {
 				genericTypes = append(genericTypes, arg.Type)
 			}
 		}
 	}
+	if null && len(genericTypes) == 0 {
+		return Null
+	}
 	return getBaseType(genericTypes)
 }
 
+// A test case for a value. The expression is parsed like Value.Examples are and is evaluated
+// against the root by System.SelfTest.
+type ValueTest struct {
+	// The expression to test, it should use the value the test is defined on.
+	Expression string `json:"expression"`
+	// The sample root value given to the evaluator.
+	Root any `json:"root,omitempty"`
+	// The expected result of evaluating the expression.
+	Expected any `json:"expected,omitempty"`
+}
+
 // A parameter to a parameterized value. Type or Generic is required.
 type Parameter struct {
 	// The expected type for the parameter. Either this or Generic is required.
@@ -159,10 +341,30 @@ type Parameter struct {
 	Name string `json:"name,omitempty"`
 	// A more detailed description of the parameter.
 	Description string `json:"description,omitempty"`
+	// The description of the parameter in other locales.
+	Descriptions Localized `json:"descriptions,omitempty"`
 	// A default value, making this an optional parameter. This must be a valid value that can be parsed by the type.
 	Default *string `json:"default,omitempty"`
+	// If null can be given for the parameter, see Null.
+	Nullable bool `json:"nullable,omitempty"`
+	// The smallest number the parameter accepts. Constant arguments are checked when linked, and computed
+	// arguments are checked by evaluators which check parameters, see ReflectOptions.CheckParameters.
+	Min *float64 `json:"min,omitempty"`
+	// The largest number the parameter accepts, checked like Min.
+	Max *float64 `json:"max,omitempty"`
+	// The parameter only accepts multiples of the step from Min (or zero when there's no Min), checked like Min.
+	Step *float64 `json:"step,omitempty"`
+	// A regular expression text given to the parameter must match, checked like Min.
+	Pattern string `json:"pattern,omitempty"`
+	// The fewest characters text given to the parameter can have, checked like Min.
+	MinLength *int `json:"minLength,omitempty"`
+	// The most characters text given to the parameter can have, checked like Min.
+	MaxLength *int `json:"maxLength,omitempty"`
+	// The only text the parameter accepts (case insensitive) when given, checked like Min.
+	Options []string `json:"options,omitempty"`
 
 	parameterType *Type
+	pattern       *regexp.Regexp
 }
 
 func (p Parameter) ParameterType() *Type {
@@ -193,8 +395,42 @@ type Expr struct {
 	End Position
 	// If this expression is a constant value and not a value.
 	Constant bool
+	// If this expression is a named bind parameter (`:name`) whose value is given at evaluation.
+	// The Token is the name of the parameter.
+	Bind bool
+	// If this expression is a placeholder (`?`) which must be replaced before compilation.
+	// A placeholder takes on the expected type where it's given.
+	Placeholder bool
+	// If this expression was written with parentheses, like `now()`, even when it has no arguments.
+	Called bool
+	// If the arguments of this expression were written with a comma after the last one, like
+	// `add(1, day,)`. Trailing commas are allowed, this lets editors and linters flag them.
+	TrailingComma bool
+	// If this value is an operator which was written between its operands, like `hour > 12` with
+	// Options.Infix. It's linked like `hour.>(12)` where the right operand is its only argument.
+	Infix bool
+	// If this expression was chained with ?., like the name of `user.manager?.name`, so when the value
+	// before it is absent (null) the rest of the chain is skipped and the chain is absent.
+	Optional bool
+	// The prefix written directly before the quotes of a typed constant, like date in `date'2023-01-05'`,
+	// which chooses the type that parses it (see SystemOptions.Literals).
+	Literal string
+	// The name of the parameter this argument was given for, like amount in `add(amount: 3)`, which is
+	// only set on the first expression of an argument. Named arguments are moved to the position of their
+	// parameter when linked and the parameters they skip over are given their defaults.
+	ArgumentName string
+	// If this expression is a list literal, like `[a, b, c]`, whose elements are its arguments. Its type is
+	// the list type of its element type (see System.ListOf), which is the element type of the expected list
+	// type or the type of its first element.
+	List bool
+	// If this value was written as a symbol before the expression it's called on, like the `!` of
+	// `!user.active` (see Value.Prefix). It's moved after the expression, like `user.active.!`, and
+	// linked to the value with the prefix.
+	Prefix bool
 	// The parsed value if this expression is a constant.
 	Parsed any
+	// The layout of the constant's type (see Type.Layouts) the constant matched, if any.
+	Layout string
 	// The value this expression is in the parent type.
 	Value *Value
 	// The parent type if any. If prev is nil this represents the root type.
@@ -214,6 +450,23 @@ type Expr struct {
 	Parameter *Parameter
 	// The system that created the expression.
 	System *System
+
+	// If this expression is a conversion added by the system to meet an expected type.
+	converted bool
+	// The roles the expression was linked with, so it's linked with them again when composed or translated.
+	roles []string
+	// The number of quotes surrounding this constant in the input, 1 for 'a' and 3 for '''a'''.
+	quotes int
+	// The text between the quotes of this constant in the input when it has escapes, and the index in
+	// that text of each byte of the token followed by the index of its end.
+	source  string
+	offsets []int
+	// The text between the backticks of a quoted path without escapes processed, which is the raw
+	// constant the path is when it's at the start of a chain and is not a value, like `C:\temp\d+`.
+	raw string
+	// If this expression is parentheses grouping an expression, like `(a.or(b)).then(x, y)` or the
+	// operands of infix operators, which are removed before linking.
+	group bool
 }
 
 // Converts the expression to a string.
@@ -221,21 +474,57 @@ func (e Expr) String() string {
 	out := strings.Builder{}
 	c := &e
 	for c != nil {
-		if c.Prev != nil && wordChars[c.Token[0]] {
+		token := c.Token
+		if c.Prefix && c.Value != nil {
+			token = c.Value.Path
+		} else if c.Prefix {
+			written := out.String()
+			out.Reset()
+			out.WriteString(c.Token + written)
+			c = c.Next
+			continue
+		}
+		quoted := !c.Constant && !c.Bind && !c.Placeholder && !c.List && needsPathQuotes(token)
+		if c.Optional {
+			out.WriteString("?.")
+		} else if c.Prev != nil && (quoted || wordChars[token[0]]) {
 			out.WriteString(".")
 		}
 		if c.Constant {
-			out.WriteString("'" + strings.ReplaceAll(c.Token, "'", "\\'") + "'")
-		} else {
+			out.WriteString(c.Literal)
+		}
+		if c.Constant && strings.Contains(c.Token, "\n") && !strings.Contains(c.Token, "'''") && !strings.HasSuffix(c.Token, "'") {
+			out.WriteString("'''" + c.Token + "'''")
+		} else if c.Constant && c.Type == Null {
 			out.WriteString(c.Token)
+		} else if c.Constant {
+			out.WriteString("'" + strings.ReplaceAll(strings.ReplaceAll(c.Token, "\\", "\\\\"), "'", "\\'") + "'")
+		} else if c.List {
+			out.WriteString("[")
+			for i, element := range c.Arguments {
+				if i > 0 {
+					out.WriteString(",")
+				}
+				out.WriteString(element.String())
+			}
+			out.WriteString("]")
+		} else if c.Bind {
+			out.WriteString(":" + c.Token)
+		} else if quoted {
+			out.WriteString("`" + strings.ReplaceAll(strings.ReplaceAll(token, "\\", "\\\\"), "`", "\\`") + "`")
+		} else {
+			out.WriteString(token)
 		}
-		if len(c.Arguments) > 0 {
+		if !c.List && (len(c.Arguments) > 0 || c.Called) {
 			out.WriteString("(")
 			for i, arg := range c.Arguments {
 				argSerialized := arg.String()
 				if i > 0 {
 					out.WriteString(",")
 				}
+				if arg.ArgumentName != "" {
+					out.WriteString(arg.ArgumentName + ":")
+				}
 				out.WriteString(argSerialized)
 			}
 			out.WriteString(")")
@@ -255,6 +544,33 @@ func (e *Expr) Last() *Expr {
 	return c
 }
 
+// Returns a deep copy of the chain starting with this expression, including all arguments. The
+// copy is detached from any parent expression, but shares the types and values of the original.
+func (e *Expr) Clone() *Expr {
+	var first, prev *Expr
+	for c := e; c != nil; c = c.Next {
+		clone := &Expr{}
+		*clone = *c
+		clone.Prev = prev
+		clone.Next = nil
+		if prev != nil {
+			prev.Next = clone
+		} else {
+			first = clone
+			clone.Parent = nil
+		}
+		if len(c.Arguments) > 0 {
+			clone.Arguments = make([]*Expr, len(c.Arguments))
+			for i, arg := range c.Arguments {
+				clone.Arguments[i] = arg.Clone()
+				clone.Arguments[i].Parent = clone
+			}
+		}
+		prev = clone
+	}
+	return first
+}
+
 // Returns a slice of all expressions in the chain starting with this expression.
 func (e *Expr) Chain() []*Expr {
 	chain := make([]*Expr, 0)
@@ -266,6 +582,110 @@ func (e *Expr) Chain() []*Expr {
 	return chain
 }
 
+// Returns the names of the bind parameters referenced in this expression, its chain, and its
+// arguments in the order they appear without duplicates.
+func (e *Expr) Binds() []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	var walk func(e *Expr)
+	walk = func(e *Expr) {
+		for _, c := range e.Chain() {
+			if c.Bind && !seen[strings.ToLower(c.Token)] {
+				seen[strings.ToLower(c.Token)] = true
+				names = append(names, c.Token)
+			}
+			for _, arg := range c.Arguments {
+				walk(arg)
+			}
+		}
+	}
+	walk(e)
+	return names
+}
+
+// Returns all placeholders in this expression, its chain, and its arguments.
+func (e *Expr) Placeholders() []*Expr {
+	placeholders := make([]*Expr, 0)
+	for _, c := range e.Chain() {
+		if c.Placeholder {
+			placeholders = append(placeholders, c)
+		}
+		for _, arg := range c.Arguments {
+			placeholders = append(placeholders, arg.Placeholders()...)
+		}
+	}
+	return placeholders
+}
+
+// Returns whether the given value is used anywhere in this expression, its chain, or its arguments.
+func (e *Expr) Uses(v *Value) bool {
+	for _, c := range e.Chain() {
+		if c.Value == v {
+			return true
+		}
+		for _, arg := range c.Arguments {
+			if arg.Uses(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns the deepest expression written in the input (in this chain or its arguments) whose token
+// contains the index, or nil if there is none. An index at the end of a token is on the token, and
+// when two tokens touch (like name and > in name>(1)) the later one is returned.
+func (e *Expr) At(index int) *Expr {
+	var found *Expr
+	for _, c := range e.Chain() {
+		if c.written() && c.Start.Index <= index && index <= c.End.Index {
+			found = c
+		}
+		for _, arg := range c.Arguments {
+			if inner := arg.At(index); inner != nil {
+				found = inner
+			}
+		}
+	}
+	return found
+}
+
+// Returns the expressions written in the input (in this chain or its arguments) whose tokens are
+// entirely between the start and end indices, in the order they appear in the input.
+func (e *Expr) Within(start, end int) []*Expr {
+	within := make([]*Expr, 0)
+	for _, c := range e.Chain() {
+		if c.written() && start <= c.Start.Index && c.End.Index <= end {
+			within = append(within, c)
+		}
+		for _, arg := range c.Arguments {
+			within = append(within, arg.Within(start, end)...)
+		}
+	}
+	return within
+}
+
+// Returns whether the expression is in a chain which may be absent (null) when it's reached, because a
+// value before it is Nullable or it or an expression before it was chained with ?. (see Expr.Optional).
+// Compilers can use this to generate code which propagates null.
+func (e *Expr) InNullableChain() bool {
+	if e.Optional {
+		return true
+	}
+	for c := e.Prev; c != nil; c = c.Prev {
+		if c.Optional || (c.Value != nil && c.Value.Nullable) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns whether the expression was written in the input, rather than added by linking like
+// conversions and default arguments.
+func (e *Expr) written() bool {
+	return !e.converted && e.End.Index > e.Start.Index
+}
+
 // Returns if the type on this expression is one of the given types.
 // If this expression is nil or has no type then this will return whether the given types are empty.
 // Otherwise the type on the expression must match one of the given types.
@@ -281,6 +701,36 @@ func (e *Expr) TypeOneOf(types []*Type) bool {
 	return false
 }
 
+// The kinds of errors a ParseError or SystemError can be, which can be checked with errors.Is.
+var (
+	// The input has a syntax error, like an unexpected or missing parenthesis or a missing value.
+	ErrSyntax = errors.New("syntax error")
+	// A quoted constant did not have a terminating quote.
+	ErrUnterminatedConstant = errors.New("unterminated constant")
+	// A token could not be resolved to a value or a constant.
+	ErrUnknownValue = errors.New("unknown value")
+	// A type name could not be resolved to a type.
+	ErrUnknownType = errors.New("unknown type")
+	// A value was given too few or too many arguments.
+	ErrArity = errors.New("invalid number of arguments")
+	// An expression or constant did not match the expected type(s).
+	ErrTypeMismatch = errors.New("type mismatch")
+	// A value path is not valid.
+	ErrInvalidPath = errors.New("invalid path")
+	// A value has an invalid generic definition.
+	ErrInvalidGeneric = errors.New("invalid generic")
+	// A value example is not valid.
+	ErrInvalidExample = errors.New("invalid example")
+	// A placeholder is invalid or was not filled in before compilation.
+	ErrPlaceholder = errors.New("placeholder")
+	// A value path or alias is used by more than one value on a type.
+	ErrDuplicatePath = errors.New("duplicate path")
+	// The As conversions of types form a cycle.
+	ErrConversionCycle = errors.New("conversion cycle")
+	// A type uses a mixin which could not be found.
+	ErrUnknownMixin = errors.New("unknown mixin")
+)
+
 // An error occurred during the parsing or linking of System.Parse.
 type ParseError struct {
 	Message   string
@@ -288,6 +738,10 @@ type ParseError struct {
 	Parameter *Parameter
 	Start     *Position
 	End       *Position
+	// The kind of error, one of the Err variables like ErrArity.
+	Kind error
+	// The error which caused this error, if any.
+	Cause error
 }
 
 var _ error = ParseError{}
@@ -305,11 +759,23 @@ func NewParseError(expr *Expr, message string) ParseError {
 	return e
 }
 
+// Creates a new parse error of the given kind given the expression (if any) and the message.
+func NewParseErrorKind(expr *Expr, kind error, message string) ParseError {
+	e := NewParseError(expr, message)
+	e.Kind = kind
+	return e
+}
+
 // The parse error message.
 func (e ParseError) Error() string {
 	return e.Message
 }
 
+// Returns the kind and cause of the error so they can be used with errors.Is and errors.As.
+func (e ParseError) Unwrap() []error {
+	return unwrapErrors(e.Kind, e.Cause)
+}
+
 // An error occurred building a system from types.
 type SystemError struct {
 	Message   string
@@ -317,6 +783,10 @@ type SystemError struct {
 	Value     *Value
 	Parameter *Parameter
 	Path      *string
+	// The kind of error, one of the Err variables like ErrUnknownType.
+	Kind error
+	// The error which caused this error, if any.
+	Cause error
 }
 
 var _ error = SystemError{}
@@ -325,11 +795,262 @@ func (e SystemError) Error() string {
 	return e.Message
 }
 
+// Returns the kind and cause of the error so they can be used with errors.Is and errors.As.
+func (e SystemError) Unwrap() []error {
+	return unwrapErrors(e.Kind, e.Cause)
+}
+
+func unwrapErrors(errs ...error) []error {
+	unwrapped := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			unwrapped = append(unwrapped, err)
+		}
+	}
+	return unwrapped
+}
+
+// Multiple errors found while parsing and linking an expression.
+type ParseErrors []ParseError
+
+var _ error = ParseErrors{}
+
+// The messages of all the errors, one per line.
+func (e ParseErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Returns the errors so they can be used with errors.Is and errors.As.
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// An error returned by Type.Parse which locates the problem within the constant. The linker
+// translates the offset into the Start and End of the ParseError for the constant.
+type ConstantError struct {
+	Message string
+	// The byte offset of the problem in the constant.
+	Offset int
+	// The number of bytes of the problem, at least 1 byte is used.
+	Length int
+	// The error which caused this error, if any.
+	Cause error
+}
+
+var _ error = ConstantError{}
+
+// Creates a new constant error at the offset and length within the constant with the message.
+func NewConstantError(offset int, length int, message string) ConstantError {
+	return ConstantError{Message: message, Offset: offset, Length: length}
+}
+
+func (e ConstantError) Error() string {
+	return e.Message
+}
+
+// Returns the cause of the error so it can be used with errors.Is and errors.As.
+func (e ConstantError) Unwrap() error {
+	return e.Cause
+}
+
+// Narrows the error to the part of the constant the constant error is for and adds it to the message.
+func (e *ParseError) locate(constant *Expr, cause ConstantError) {
+	// The constant starts at its literal prefix, like date in date'2023-01-05', followed by its quotes.
+	start := constant.Start
+	start.Index += len(constant.Literal) + constant.quotes
+	start.Column += len(constant.Literal) + constant.quotes
+	offset := cause.Offset
+	if offset < 0 || offset > len(constant.Token) {
+		offset = 0
+	}
+	length := cause.Length
+	if length < 1 {
+		length = 1
+	}
+	text := constant.Token
+	if constant.offsets != nil {
+		end := offset + length
+		if end > len(constant.Token) {
+			end = len(constant.Token)
+		}
+		text = constant.source
+		offset, end = constant.offsets[offset], constant.offsets[end]
+		if end > offset {
+			length = end - offset
+		}
+	}
+	advance := func(p Position, text string) Position {
+		for i := 0; i < len(text); i++ {
+			p.Index++
+			p.Column++
+			if text[i] == '\n' {
+				p.Line++
+				p.Column = 0
+			}
+		}
+		return p
+	}
+	problemStart := advance(start, text[:offset])
+	problemEnd := problemStart
+	problemEnd.Index += length
+	problemEnd.Column += length
+	e.Start = &problemStart
+	e.End = &problemEnd
+	e.Message = fmt.Sprintf("%s: %s at %v", e.Message, cause.Message, problemStart)
+}
+
+// The type of expressions which could not be resolved to a value or constant. Linking continues
+// past unresolved expressions, so the rest of the expression can still be linked.
+var Unknown = &Type{
+	Name:        "<unknown>",
+	Description: "A value or constant which could not be determined.",
+	values:      map[string]*Value{},
+	as:          map[TypeName]*Value{},
+	enums:       map[string]string{},
+}
+
+// The type of the null constant, written `null`, which is absent. Null can be given to Nullable
+// parameters, generic parameters (so `then(x, null)` is nullable), and expressions with Options.Nullable.
+var Null = &Type{
+	Name:        "null",
+	Description: "The absence of a value.",
+	values:      map[string]*Value{},
+	as:          map[TypeName]*Value{},
+	enums:       map[string]string{},
+}
+
+// The token of the null constant.
+const nullToken = "null"
+
 // A type system that validates types, values, parameters, etc.
 type System struct {
 	types      []*Type
 	typeMap    map[TypeName]*Type
 	parseOrder []*Type
+	options    SystemOptions
+	mixins     map[string]*Mixin
+	lazy       *lazyTypes
+	constants  *constantCache
+	// Copies of the types the system was built from, used by Clone.
+	definitions []Type
+	// The symbols of the prefix values of the types, see Value.Prefix.
+	prefixes map[string]bool
+	// The types of typed literals keyed by lowercase prefix, see SystemOptions.Literals.
+	literals map[string]*Type
+}
+
+// The options used when building a system.
+type SystemOptions struct {
+	// The root type value examples are parsed against. When empty each example is parsed with the
+	// type that declares the value as the root.
+	Root TypeName
+	// The mixins types can use to share sets of values, see Type.Mixins.
+	Mixins []Mixin
+	// The boolean type returned by the comparison values added for Type.Comparable, Type.Ordered, and
+	// EnumOperators.
+	BoolType TypeName
+	// If every type with Enums is given the enum operators (see the EnumOperators variable), which return
+	// BoolType. Operators a type already has a value for are not added.
+	EnumOperators bool
+	// The integer type used by the values of list types, see System.ListOf.
+	IntType TypeName
+	// Resolves the types which are not given to the system when they are first referred to, by values
+	// given to the system or when parsing.
+	Resolver TypeResolver
+	// The maximum number of distinct constants whose tokens and parsed values are shared by all expressions
+	// the system parses, so rule sets which repeat the same constants parse each once. The parsed values
+	// are shared so they must not be modified. When zero constants are only shared within an expression.
+	InternConstants int
+	// The characters value paths and bind parameter names can be made of when parsing, in addition to
+	// ASCII letters, digits, and underscores.
+	Identifiers IdentifierChars
+	// Chooses the type of a constant which more than one type can parse, like monday which an enum and
+	// a text type can both parse. When nil the first type in the parse order (or expected type) is used.
+	ResolveAmbiguity AmbiguityResolver
+	// Records how long System.Parse takes and the errors it returns.
+	Metrics Metrics
+	// The text type of templates, which must have a + value that concatenates text, see System.ParseTemplate.
+	TextType TypeName
+	// The types of constants written with a prefix directly before their quotes keyed by prefix, like
+	// "date" for `date'2023-01-05'` or "d" for `d'3h'`. The constant is parsed only by that type instead
+	// of the expected types or parse order. Prefixes are identifiers and are matched ignoring case.
+	Literals map[string]TypeName
+}
+
+// The characters identifiers (value paths and bind parameter names) can be made of besides ASCII letters,
+// digits, and underscores. Other characters end an identifier, so `first-name` is parsed as the value
+// first followed by the value -name unless - is an identifier character.
+type IdentifierChars struct {
+	// If unicode letters and digits are identifier characters, like the é in café.
+	Unicode bool
+	// Other identifier characters, like "-" for kebab-case names or "$". When - is an identifier
+	// character the - operator must be separated from the value before it, like `a.-(b)`. Whitespace,
+	// quotes, and the characters .,():?`[] can't be identifier characters.
+	Extra string
+	// Returns whether other characters are identifier characters, like unicode.Is(unicode.Cyrillic, r)
+	// for names in one script. It's never given an ASCII letter, digit, or underscore, and it can't
+	// accept whitespace, quotes, or the characters .,():?`[].
+	Is func(r rune) bool
+}
+
+// Returns whether the rune is an identifier character.
+func (c IdentifierChars) has(r rune) bool {
+	if r < utf8.RuneSelf && wordChars[byte(r)] {
+		return true
+	}
+	if c.Unicode && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+		return true
+	}
+	return strings.ContainsRune(c.Extra, r) || (c.Is != nil && c.Is(r))
+}
+
+// Returns the length in bytes of the identifier character at the start of s, or 0 if s does not start with one.
+func (c IdentifierChars) at(s string) int {
+	if s == "" {
+		return 0
+	}
+	if wordChars[s[0]] {
+		return 1
+	}
+	if c.Extra == "" && !c.Unicode && c.Is == nil {
+		return 0
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if c.has(r) {
+		return size
+	}
+	return 0
+}
+
+// Returns an error if an extra identifier character would make expressions ambiguous.
+func (c IdentifierChars) validate() error {
+	for _, r := range c.Extra {
+		if unicode.IsSpace(r) || strings.ContainsRune(".,():?`[]'\"", r) {
+			return SystemError{
+				Message: fmt.Sprintf("%q can't be an identifier character", r),
+				Kind:    ErrSyntax,
+			}
+		}
+	}
+	if c.Is != nil {
+		for _, r := range " \t\n\r\f\v.,():?`[]'\"" {
+			if c.Is(r) {
+				return SystemError{
+					Message: fmt.Sprintf("%q can't be an identifier character", r),
+					Kind:    ErrSyntax,
+				}
+			}
+		}
+	}
+	return nil
 }
 
 // Returns a System given a set of types and panics if any of the types, values, parameters, etc are malformed.
@@ -341,134 +1062,467 @@ func NewSystemRequired(types []Type) System {
 	return sys
 }
 
-var pathValidator = regexp.MustCompile(`^([a-zA-Z0-9_]+|[^a-zA-Z0-9_,\.\(\)][^,\.\(\)]*)$`)
+var pathValidator = regexp.MustCompile(`^([a-zA-Z0-9_]+|[^a-zA-Z0-9_,\.\(\)][^,\.\(\)]*|[^\s\.]([^\.]*[^\s\.])?)$`)
 
 // Returns a new system and if any errors were found building the system.
 func NewSystem(types []Type) (System, error) {
+	return NewSystemWithOptions(types, SystemOptions{})
+}
+
+// Returns a new system built with the given options and if any errors were found building the system.
+// The system is built from copies of the types and mixins, so they are not changed by the system and
+// changing them afterwards does not change the system.
+func NewSystemWithOptions(types []Type, options SystemOptions) (System, error) {
+	definitions := copyTypes(types)
+	types = copyTypes(types)
+	options.Mixins = copyMixins(options.Mixins)
+	options.Literals = copyMap(options.Literals)
 	sys := System{
-		types:      make([]*Type, len(types)),
-		typeMap:    make(map[TypeName]*Type),
-		parseOrder: make([]*Type, 0, len(types)),
+		types:       make([]*Type, len(types)),
+		typeMap:     make(map[TypeName]*Type),
+		parseOrder:  make([]*Type, 0, len(types)),
+		options:     options,
+		mixins:      make(map[string]*Mixin, len(options.Mixins)),
+		lazy:        &lazyTypes{types: make(map[TypeName]*Type)},
+		definitions: definitions,
+		prefixes:    make(map[string]bool),
+		literals:    make(map[string]*Type, len(options.Literals)),
+	}
+	if err := options.Identifiers.validate(); err != nil {
+		return sys, err
+	}
+	if options.InternConstants > 0 {
+		sys.constants = &constantCache{limit: options.InternConstants, constants: make(map[constantKey]internedConstant)}
+	}
+	for i := range options.Mixins {
+		sys.mixins[options.Mixins[i].Name] = &options.Mixins[i]
 	}
 	for i := range types {
 		t := &types[i]
-		t.values = make(map[string]*Value)
-		t.as = make(map[TypeName]*Value)
-		t.enums = make(map[string]string)
-
-		if len(t.Values) > 0 {
-			for k := range t.Values {
-				v := &t.Values[k]
-				if !pathValidator.MatchString(v.Path) {
-					return sys, SystemError{
-						Message: fmt.Sprintf("%s is not a valid path in %s", v.Path, t.Name),
-						Type:    t,
-					}
+		if err := sys.initType(t); err != nil {
+			return sys, err
+		}
+
+		sys.types[i] = t
+		sys.typeMap[t.Name] = t
+		for symbol := range t.prefixes {
+			sys.prefixes[symbol] = true
+		}
+
+		if t.parses() || len(t.Enums) > 0 {
+			sys.parseOrder = append(sys.parseOrder, t)
+		}
+	}
+
+	for _, t := range sys.types {
+		if err := sys.linkType(t, sys.ResolveType); err != nil {
+			return sys, err
+		}
+	}
+
+	if err := sys.checkConversionCycles(); err != nil {
+		return sys, err
+	}
+
+	// Prefer types with parse logic, then enums. Sort by name length preferring longest.
+	sort.Slice(sys.parseOrder, func(i, j int) bool {
+		a := sys.parseOrder[i]
+		b := sys.parseOrder[j]
+		if a.ParseOrder != b.ParseOrder {
+			return a.ParseOrder > b.ParseOrder
+		}
+		if a.parses() != b.parses() {
+			return a.parses()
+		}
+		return len(string(a.Name)) > len(string(b.Name))
+	})
+
+	if options.Root != "" && sys.Type(options.Root) == nil {
+		return sys, SystemError{
+			Message: fmt.Sprintf("root type %s could not be found", options.Root),
+			Kind:    ErrUnknownType,
+		}
+	}
+
+	if options.TextType != "" {
+		if err := sys.checkTextType(); err != nil {
+			return sys, err
+		}
+	}
+
+	for prefix, typeName := range options.Literals {
+		if err := sys.addLiteral(prefix, typeName); err != nil {
+			return sys, err
+		}
+	}
+
+	for _, t := range sys.types {
+		for k := range t.Values {
+			v := &t.Values[k]
+			for e := range v.Examples {
+				err := sys.validateExample(t, v, &v.Examples[e])
+				if err != nil {
+					return sys, err
 				}
+			}
+		}
+	}
 
-				t.values[strings.ToLower(v.Path)] = v
-				if len(v.Aliases) > 0 {
-					for _, a := range v.Aliases {
-						t.values[strings.ToLower(a)] = v
-					}
+	return sys, nil
+}
+
+// Prepares the type to be used by the system: adds the values of its mixins and capabilities and
+// builds the lookups of its values, conversions, and enums.
+func (sys System) initType(t *Type) error {
+	if err := applyMixins(t, sys.mixins); err != nil {
+		return err
+	}
+	if err := addCapabilityOperators(t, sys.options.BoolType, sys.options.EnumOperators); err != nil {
+		return err
+	}
+	t.values = make(map[string]*Value)
+	t.prefixes = make(map[string]*Value)
+	t.as = make(map[TypeName]*Value)
+	t.enums = make(map[string]string)
+
+	if len(t.Values) > 0 {
+		namedBy := make(map[string]string, len(t.Values))
+		for k := range t.Values {
+			v := &t.Values[k]
+			if !pathValidator.MatchString(v.Path) {
+				return SystemError{
+					Message: fmt.Sprintf("%s is not a valid path in %s", v.Path, t.Name),
+					Type:    t,
+					Kind:    ErrInvalidPath,
 				}
+			}
 
-				if v.Generic == (v.Type != "") {
-					return sys, SystemError{
-						Message: fmt.Sprintf("value %s.%s must have either a type or generic but not both", t.Name, v.Path),
-						Type:    t,
+			if err := addValuePath(t, v, v.Path, "path", namedBy); err != nil {
+				return err
+			}
+			if len(v.Aliases) > 0 {
+				for _, a := range v.Aliases {
+					if err := addValuePath(t, v, a, "alias", namedBy); err != nil {
+						return err
 					}
 				}
-				if v.Generic {
-					genericCount := 0
-					if len(v.Parameters) > 0 {
-						for _, param := range v.Parameters {
-							if param.Generic {
-								genericCount++
-							}
+			}
+			if v.Prefix != "" {
+				if err := addValuePrefix(t, v); err != nil {
+					return err
+				}
+			}
+
+			if v.Generic == (v.Type != "") {
+				return SystemError{
+					Message: fmt.Sprintf("value %s.%s must have either a type or generic but not both", t.Name, v.Path),
+					Type:    t,
+					Kind:    ErrInvalidGeneric,
+				}
+			}
+			if v.Generic {
+				genericCount := 0
+				if len(v.Parameters) > 0 {
+					for _, param := range v.Parameters {
+						if param.Generic {
+							genericCount++
 						}
 					}
-					if genericCount == 0 {
-						return sys, SystemError{
-							Message: fmt.Sprintf("value %s.%s cannot have a generic type without one or more generic parameters.", t.Name, v.Path),
-							Type:    t,
-						}
+				}
+				if genericCount == 0 {
+					return SystemError{
+						Message: fmt.Sprintf("value %s.%s cannot have a generic type without one or more generic parameters.", t.Name, v.Path),
+						Type:    t,
+						Kind:    ErrInvalidGeneric,
 					}
 				}
 			}
 		}
-		if len(t.As) > 0 {
-			for typeName, valuePath := range t.As {
-				value := t.Value(valuePath)
-				if value == nil {
-					return sys, SystemError{
-						Message: fmt.Sprintf("%s as %s using value %s could not be found", t.Name, typeName, valuePath),
-						Type:    t,
-						Path:    &valuePath,
-					}
+	}
+	if len(t.As) > 0 {
+		for typeName, valuePath := range t.As {
+			value := t.Value(valuePath)
+			if value == nil {
+				return SystemError{
+					Message: fmt.Sprintf("%s as %s using value %s could not be found", t.Name, typeName, valuePath),
+					Type:    t,
+					Path:    &valuePath,
+					Kind:    ErrUnknownValue,
 				}
-				t.as[typeName] = value
 			}
+			t.as[typeName] = value
 		}
-		if len(t.Enums) > 0 {
-			for _, enumValue := range t.Enums {
-				t.enums[strings.ToLower(enumValue)] = enumValue
+	}
+	if len(t.Enums) > 0 {
+		for _, enumValue := range t.Enums {
+			t.enums[strings.ToLower(enumValue)] = enumValue
+		}
+	}
+	if len(t.EnumOptions) > 0 {
+		t.enumOptions = make(map[string]EnumOption, len(t.EnumOptions))
+		for enumValue, option := range t.EnumOptions {
+			key := strings.ToLower(enumValue)
+			if _, exists := t.enums[key]; !exists {
+				return SystemError{
+					Message: fmt.Sprintf("enum option %s on %s could not be found", enumValue, t.Name),
+					Type:    t,
+					Path:    &enumValue,
+					Kind:    ErrUnknownValue,
+				}
 			}
+			t.enumOptions[key] = option
 		}
+	}
+	return nil
+}
 
-		sys.types[i] = t
-		sys.typeMap[t.Name] = t
+// Links the value and parameter types of the type's values, types are found with the given resolve function.
+func (sys System) linkType(t *Type, resolve func(name TypeName) (*Type, error)) error {
+	for k := range t.Values {
+		v := &t.Values[k]
+		valueType, err := resolve(v.Type)
+		if err != nil {
+			return err
+		}
+		v.valueType = valueType
+		if v.valueType == nil && !v.Generic {
+			return SystemError{
+				Message: fmt.Sprintf("type %s on %s.%s could not be found", v.Type, t.Name, v.Path),
+				Value:   v,
+				Kind:    ErrUnknownType,
+			}
+		}
 
-		if t.Parse != nil || len(t.Enums) > 0 {
-			sys.parseOrder = append(sys.parseOrder, t)
+		if len(v.Parameters) > 0 {
+			for k := range v.Parameters {
+				p := &v.Parameters[k]
+				parameterType, err := resolve(p.Type)
+				if err != nil {
+					return err
+				}
+				p.parameterType = parameterType
+				if p.parameterType == nil && !v.Generic {
+					return SystemError{
+						Message:   fmt.Sprintf("type %s on %s.%s (parameter %s) could not be found", p.Type, t.Name, v.Path, p.Name),
+						Value:     v,
+						Type:      t,
+						Parameter: p,
+						Kind:      ErrUnknownType,
+					}
+				}
+				if err := p.checkConstraints(); err != nil {
+					return SystemError{
+						Message:   fmt.Sprintf("constraints on %s.%s (parameter %s) are invalid: %v", t.Name, v.Path, p.Name, err),
+						Value:     v,
+						Type:      t,
+						Parameter: p,
+						Kind:      ErrConstraint,
+						Cause:     err,
+					}
+				}
+				if err := p.checkDefault(); err != nil {
+					return SystemError{
+						Message:   fmt.Sprintf("default %s on %s.%s (parameter %s) is invalid: %v", *p.Default, t.Name, v.Path, p.Name, err),
+						Value:     v,
+						Type:      t,
+						Parameter: p,
+						Kind:      ErrConstraint,
+						Cause:     err,
+					}
+				}
+			}
 		}
 	}
+	return nil
+}
 
-	for _, t := range sys.typeMap {
-		for _, v := range t.values {
-			v.valueType = sys.Type(v.Type)
-			if v.valueType == nil && !v.Generic {
-				return sys, SystemError{
-					Message: fmt.Sprintf("type %s on %s.%s could not be found", v.Type, t.Name, v.Path),
-					Value:   v,
+// Returns an error if the As conversions of the types in the system form a cycle, ex: a as b and b as a.
+func (sys System) checkConversionCycles() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[*Type]int, len(sys.types))
+	path := make([]*Type, 0)
+
+	var visit func(t *Type) error
+	visit = func(t *Type) error {
+		switch state[t] {
+		case visited:
+			return nil
+		case visiting:
+			names := make([]string, 0, len(path)+1)
+			for i := len(path) - 1; i >= 0; i-- {
+				if path[i] == t {
+					for _, p := range path[i:] {
+						names = append(names, string(p.Name))
+					}
+					break
 				}
 			}
+			names = append(names, string(t.Name))
+			return SystemError{
+				Message: fmt.Sprintf("conversion cycle %s", strings.Join(names, " as ")),
+				Type:    t,
+				Kind:    ErrConversionCycle,
+			}
+		}
+		state[t] = visiting
+		path = append(path, t)
 
-			if len(v.Parameters) > 0 {
-				for _, p := range v.Parameters {
-					p.parameterType = sys.Type(p.Type)
-					if p.parameterType == nil && !v.Generic {
-						return sys, SystemError{
-							Message:   fmt.Sprintf("type %s on %s.%s (parameter %s) could not be found", v.Type, t.Name, v.Path, p.Name),
-							Value:     v,
-							Type:      t,
-							Parameter: &p,
-						}
-					}
+		targets := make([]string, 0, len(t.As))
+		for name := range t.As {
+			targets = append(targets, string(name))
+		}
+		sort.Strings(targets)
+		for _, name := range targets {
+			if other := sys.Type(TypeName(name)); other != nil {
+				if err := visit(other); err != nil {
+					return err
 				}
 			}
 		}
+
+		path = path[:len(path)-1]
+		state[t] = visited
+		return nil
+	}
+
+	for _, t := range sys.types {
+		if err := visit(t); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	// Prefer types with parse logic, then enums. Sort by name length preferring longest.
-	sort.Slice(sys.parseOrder, func(i, j int) bool {
-		a := sys.parseOrder[i]
-		b := sys.parseOrder[j]
-		if a.ParseOrder != b.ParseOrder {
-			return a.ParseOrder > b.ParseOrder
+// Adds the path or alias of the value to the type, returning an error if it's already used by another value.
+// The kinds of names already added are tracked by lowercase name in namedBy.
+func addValuePath(t *Type, v *Value, name string, kind string, namedBy map[string]string) error {
+	key := strings.ToLower(name)
+	if existing := t.values[key]; existing != nil && existing != v {
+		return SystemError{
+			Message: fmt.Sprintf("%s %s on %s.%s collides with the %s of %s.%s", kind, name, t.Name, v.Path, namedBy[key], t.Name, existing.Path),
+			Type:    t,
+			Value:   v,
+			Path:    &name,
+			Kind:    ErrDuplicatePath,
+		}
+	}
+	if _, exists := namedBy[key]; !exists {
+		namedBy[key] = kind
+	}
+	t.values[key] = v
+	return nil
+}
+
+// Adds the prefix symbol of the value to the type.
+func addValuePrefix(t *Type, v *Value) error {
+	if !prefixValidator.MatchString(v.Prefix) {
+		return SystemError{
+			Message: fmt.Sprintf("prefix %s on %s.%s must be symbols which are not quotes, whitespace, or any of .,():?`[]", v.Prefix, t.Name, v.Path),
+			Type:    t,
+			Value:   v,
+			Path:    &v.Prefix,
+			Kind:    ErrInvalidPath,
+		}
+	}
+	if v.MinParameters() > 0 {
+		return SystemError{
+			Message: fmt.Sprintf("prefix %s on %s.%s can't be used because the value requires parameters", v.Prefix, t.Name, v.Path),
+			Type:    t,
+			Value:   v,
+			Path:    &v.Prefix,
+			Kind:    ErrInvalidPath,
+		}
+	}
+	if existing := t.prefixes[v.Prefix]; existing != nil && existing != v {
+		return SystemError{
+			Message: fmt.Sprintf("prefix %s on %s.%s collides with the prefix of %s.%s", v.Prefix, t.Name, v.Path, t.Name, existing.Path),
+			Type:    t,
+			Value:   v,
+			Path:    &v.Prefix,
+			Kind:    ErrDuplicatePath,
+		}
+	}
+	t.prefixes[v.Prefix] = v
+	return nil
+}
+
+// Adds the prefix of typed literals of the type, see SystemOptions.Literals.
+func (sys System) addLiteral(prefix string, typeName TypeName) error {
+	if prefix == "" || strings.IndexFunc(prefix, func(r rune) bool { return !sys.options.Identifiers.has(r) }) != -1 {
+		return SystemError{
+			Message: fmt.Sprintf("literal prefix %q of %s must be an identifier", prefix, typeName),
+			Path:    &prefix,
+			Kind:    ErrInvalidPath,
+		}
+	}
+	key := strings.ToLower(prefix)
+	if sys.literals[key] != nil {
+		return SystemError{
+			Message: fmt.Sprintf("literal prefix %s of %s is already the prefix of %s", prefix, typeName, sys.literals[key].Name),
+			Path:    &prefix,
+			Kind:    ErrDuplicatePath,
+		}
+	}
+	t := sys.Type(typeName)
+	if t == nil {
+		return SystemError{
+			Message: fmt.Sprintf("type %s of literal prefix %s could not be found", typeName, prefix),
+			Path:    &prefix,
+			Kind:    ErrUnknownType,
+		}
+	}
+	sys.literals[key] = t
+	return nil
+}
+
+// The symbols a prefix can be made of.
+var prefixValidator = regexp.MustCompile("^[^a-zA-Z0-9_\\s.,():?`'\"\\[\\]]+$")
+
+// Returns the root type examples and tests of values on the given type are parsed against.
+func (sys System) exampleRoot(t *Type) TypeName {
+	if sys.options.Root != "" {
+		return sys.options.Root
+	}
+	return t.Name
+}
+
+// Parses the example and ensures it is valid and uses the value it's defined on.
+func (sys System) validateExample(t *Type, v *Value, example *string) error {
+	e, err := sys.Parse(Options{RootType: sys.exampleRoot(t), Expression: *example})
+	if err != nil {
+		return SystemError{
+			Message: fmt.Sprintf("example %s for %s.%s is invalid: %v", *example, t.Name, v.Path, err),
+			Type:    t,
+			Value:   v,
+			Path:    example,
+			Kind:    ErrInvalidExample,
+			Cause:   err,
 		}
-		if (a.Parse != nil) != (b.Parse != nil) {
-			return (a.Parse != nil)
+	}
+	if !e.Uses(v) {
+		return SystemError{
+			Message: fmt.Sprintf("example %s for %s.%s does not use the value", *example, t.Name, v.Path),
+			Type:    t,
+			Value:   v,
+			Path:    example,
+			Kind:    ErrInvalidExample,
 		}
-		return len(string(a.Name)) > len(string(b.Name))
-	})
-
-	return sys, nil
+	}
+	return nil
 }
 
 // Returns the type in the system with the given name, or nil if none exists.
 func (s System) Type(name TypeName) *Type {
-	return s.typeMap[name]
+	if t := s.typeMap[name]; t != nil || s.lazy == nil {
+		return t
+	}
+	s.lazy.lock.RLock()
+	defer s.lazy.lock.RUnlock()
+	return s.lazy.types[name]
 }
 
 // Returns the types given to the system.
@@ -491,6 +1545,36 @@ type Options struct {
 	ExpectedTypes []TypeName
 	// The expression to parse.
 	Expression string
+	// The locale descriptions are returned in by completions.
+	Locale string
+	// The named bind parameters (`:name`) which can be referenced by the expression and their types.
+	// The values of the parameters are supplied when the expression is evaluated.
+	Parameters map[string]TypeName
+	// The roles of the user authoring the expression. Using a value the roles are not allowed to use
+	// (see Type.Roles and Value.Roles) is an ErrPermission error, conversions to expected types the roles
+	// can't use are not added, and completions only suggest allowed values. When nil roles are not checked.
+	Roles []string
+	// If expressions which don't have an expected type are type mismatches instead of being converted
+	// to an expected type with the As values of their type, so the type of the expression is always the
+	// type of what was written. Constants are also not converted to parameter types.
+	NoAutoCast bool
+	// If the expression can be null (see Null) when expected types are given.
+	Nullable bool
+	// What the expression must reference, like at least one value on the root type. An expression which
+	// doesn't meet them has ErrExprConstraint errors.
+	Constraints ExprConstraints
+	// Called with each decision made while linking the expression, like which type a constant was
+	// recognized as or which conversion was added, to diagnose why an expression resolved the way it did.
+	Trace func(event TraceEvent)
+	// The operators which can be written between their operands and their precedence, like
+	// `time.now.hour > 12 and user.name.contains('Ma')`, see DefaultPrecedence. Infix operators are
+	// rewritten into the values they call before linking, so `a > 12` is linked like `a.>(12)`, and
+	// parentheses can group operands. When nil operators can only be called like other values.
+	Infix Precedence
+	// If unquoted numbers with a fraction at the start of an expression or argument are one constant,
+	// like `total.=(0.3)`. Otherwise the . ends the number like it ends other tokens, so `1.5` is the
+	// value 5 on the constant 1, and decimals are quoted ('1.5') or written with an exponent (1.5e0).
+	Decimals bool
 }
 
 // No types are defined in the system.
@@ -506,100 +1590,333 @@ var ErrNoRoot = NewParseError(nil, "undefined root type")
 // returned and all attempts of determining types and values will be made to best inform the user
 // precisely what is wrong and what is valid.
 func (sys System) Parse(opts Options) (*Expr, error) {
+	if sys.options.Metrics == nil {
+		return sys.parseFrom(opts, newParser(opts.Expression, sys.options.Identifiers), nil)
+	}
+	start := time.Now()
+	e, err := sys.parseFrom(opts, newParser(opts.Expression, sys.options.Identifiers), nil)
+	sys.options.Metrics.Parsed(time.Since(start), err)
+	return e, err
+}
+
+// Parses a single constant like a threshold or date entered by a user, without parsing an expression.
+// The input is the text of the constant without quotes. When expected types are given the constant
+// must be one of them or a type which converts to one of them, like a lone constant in an expression
+// with Options.ExpectedTypes, otherwise the first type in the parse order which parses it is used.
+// Returns the parsed value and its type, or a ParseError positioned in the input.
+func (sys System) ParseConstant(input string, expected ...TypeName) (any, *Type, error) {
+	if len(sys.Types()) == 0 {
+		return nil, nil, ErrNoTypes
+	}
+	if input == "" {
+		return nil, nil, ErrNoExpression
+	}
+	expectedTypes := make([]*Type, len(expected))
+	for i, name := range expected {
+		expectedTypes[i] = sys.resolveType(name)
+		if expectedTypes[i] == nil {
+			return nil, nil, NewParseErrorKind(nil, ErrUnknownType, fmt.Sprintf("undefined expected type: %s", name))
+		}
+	}
+
+	constant := &Expr{Token: input, End: Position{Index: len(input)}}
+	for i := 0; i < len(input); i++ {
+		if input[i] == '\n' {
+			constant.End.Line++
+			constant.End.Column = 0
+		} else {
+			constant.End.Column++
+		}
+	}
+	ctx := newLinkContext(nil, nil)
+	if len(expectedTypes) > 0 {
+		if err := sys.setConstant(constant, expectedTypes, true, ctx); err != nil && !sys.coerceConstant(constant, expectedTypes, ctx) {
+			return nil, nil, *err
+		}
+	} else if sys.setConstant(constant, sys.parseOrder, false, ctx); constant.Type == nil {
+		return nil, nil, NewParseErrorKind(constant, ErrUnknownValue, fmt.Sprintf("type could not be determined for %s", input))
+	}
+	return constant.Parsed, constant.Type, nil
+}
+
+// Parses and links the expression the parser is given with the options, the expression in the options
+// is ignored. The parser can start and end anywhere in its input so positions are relative to the input.
+// The types of additional bind parameters can be given keyed by lowercase name.
+func (sys System) parseFrom(opts Options, p parser, binds map[string]*Type) (*Expr, error) {
 	if len(sys.Types()) == 0 {
 		return nil, ErrNoTypes
 	}
-	if len(opts.Expression) == 0 {
+	if !p.hasData() {
 		return nil, ErrNoExpression
 	}
+	ctx, expectedTypes, err := sys.linkOptions(opts, binds)
+	if err != nil {
+		return nil, err
+	}
+
+	// Always try to link the types, values, parameters, etc to expressions even if there was a parse error
+	first, errs := sys.parseSyntax(opts, p)
+	if first != nil {
+		errs = append(errs, sys.link(first, expectedTypes, ctx)...)
+	}
+	if len(errs) == 0 {
+		errs = ctx.checkConstraints(first)
+	}
+	return first, joinParseErrors(errs)
+}
+
+// Parses the expression the parser is given without linking it, returning its first expression with
+// infix operators and prefixes rewritten and the syntax error found, if any.
+func (sys System) parseSyntax(opts Options, p parser) (*Expr, []ParseError) {
+	p.operators = opts.Infix.lowered()
+	p.prefixes = sys.prefixes
+	p.literals = sys.literals
+	p.decimals = opts.Decimals
+	var err error
+	for p.hasData() && err == nil {
+		_, err = p.parseExpr()
+	}
+
+	errs := make([]ParseError, 0)
+	if parseError, ok := err.(ParseError); ok {
+		errs = append(errs, parseError)
+	}
+	if p.first != nil {
+		p.first = p.rewriteOperators(p.first, &errs)
+	}
+	return p.first, errs
+}
+
+// Returns the context and expected types for linking expressions with the options and extra bind parameters.
+func (sys System) linkOptions(opts Options, binds map[string]*Type) (*linkContext, []*Type, error) {
 	if opts.RootType == "" {
-		return nil, ErrNoRoot
+		return nil, nil, ErrNoRoot
 	}
 
-	root := sys.Type(opts.RootType)
+	root := sys.resolveType(opts.RootType)
 	if root == nil {
-		return nil, NewParseError(nil, fmt.Sprintf("undefined root type: %s", opts.RootType))
+		return nil, nil, NewParseErrorKind(nil, ErrUnknownType, fmt.Sprintf("undefined root type: %s", opts.RootType))
 	}
 
 	expectedTypes := make([]*Type, len(opts.ExpectedTypes))
-	if len(opts.ExpectedTypes) >= 0 {
-		for i, name := range opts.ExpectedTypes {
-			expectedTypes[i] = sys.Type(name)
-			if expectedTypes[i] == nil {
-				return nil, NewParseError(nil, fmt.Sprintf("undefined expected type: %s", name))
-			}
+	for i, name := range opts.ExpectedTypes {
+		expectedTypes[i] = sys.resolveType(name)
+		if expectedTypes[i] == nil {
+			return nil, nil, NewParseErrorKind(nil, ErrUnknownType, fmt.Sprintf("undefined expected type: %s", name))
 		}
 	}
+	if opts.Nullable && len(expectedTypes) > 0 {
+		expectedTypes = append(expectedTypes, Null)
+	}
 
-	err := error(nil)
-	p := newParser(opts.Expression)
+	parameters, err := sys.parameterTypes(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	for name, t := range binds {
+		parameters[name] = t
+	}
+	ctx := newLinkContext(root, parameters)
+	ctx.roles = opts.Roles
+	ctx.noAutoCast = opts.NoAutoCast
+	ctx.constraints = opts.Constraints
+	ctx.trace = opts.Trace
+	if ctx.required, err = sys.requiredValues(opts.Constraints); err != nil {
+		return nil, nil, err
+	}
+	return ctx, expectedTypes, nil
+}
 
-	for p.hasData() && err == nil {
-		_, err = p.parseExpr()
+// Returns nil for no errors, the error when there's one, and otherwise ParseErrors.
+func joinParseErrors(errs []ParseError) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return ParseErrors(errs)
 	}
+}
 
-	// Always try to link the types, values, parameters, etc to expressions even if there was a parse error
-	linkError := sys.link(p.first, expectedTypes, root)
-	if err == nil {
-		err = linkError
+// Returns the types of the bind parameters in the options.
+func (sys System) parameterTypes(opts Options) (map[string]*Type, error) {
+	parameters := make(map[string]*Type, len(opts.Parameters))
+	for name, typeName := range opts.Parameters {
+		parameters[strings.ToLower(name)] = sys.resolveType(typeName)
+		if parameters[strings.ToLower(name)] == nil {
+			return nil, NewParseErrorKind(nil, ErrUnknownType, fmt.Sprintf("undefined type %s for parameter %s", typeName, name))
+		}
 	}
+	return parameters, nil
+}
+
+// The state shared by all expressions linked in a single parse.
+type linkContext struct {
+	// The type that is used as the root of the expressions.
+	root *Type
+	// The types of the bind parameters keyed by lowercase name.
+	parameters map[string]*Type
+	// The constants parsed so far, so identical constants share their token and parsed value.
+	constants map[constantKey]internedConstant
+	// The roles of the user authoring the expression, see Options.Roles.
+	roles []string
+	// If expressions are not converted to the expected types, see Options.NoAutoCast.
+	noAutoCast bool
+	// What the expression must reference, see Options.Constraints.
+	constraints ExprConstraints
+	// The values the expression must use, see ExprConstraints.RequireValues.
+	required []requiredValue
+	// Given the decisions made while linking, see Options.Trace.
+	trace func(event TraceEvent)
+}
 
-	return p.first, err
+// Returns a new context for linking expressions against the root with the given bind parameter types.
+func newLinkContext(root *Type, parameters map[string]*Type) *linkContext {
+	return &linkContext{root: root, parameters: parameters, constants: make(map[constantKey]internedConstant)}
 }
 
-func (sys System) link(e *Expr, expectedTypes []*Type, root *Type) error {
+// Links the chain of expressions starting at e to the types and values of the system. When a token
+// can't be resolved it is linked to the Unknown type and linking continues so everything after the
+// mistake is still linked. All errors found are returned.
+func (sys System) link(e *Expr, expectedTypes []*Type, ctx *linkContext) []ParseError {
+	errs := make([]ParseError, 0)
 	current := e
-	parentType := root
+	parentType := ctx.root
 	var parent *Expr
+	if e != nil {
+		e.roles = ctx.roles
+	}
 
 	for current != nil {
 		currentValue := parentType.Value(current.Token)
+		if current.Prefix {
+			currentValue = parentType.PrefixValue(current.Token)
+		}
 
 		current.ParentType = parentType
 
-		// if it matches a value on the parent type and is not a constant
-		if currentValue != nil && !current.Constant {
+		// if the parent could not be determined neither can this
+		if parentType == Unknown {
+			current.Type = Unknown
+			errs = append(errs, sys.linkArguments(current, ctx)...)
+
+			// list literals are the list type of their elements
+		} else if current.List {
+			listExpected := expectedTypes
+			if current.Next != nil {
+				listExpected = nil
+			}
+			errs = append(errs, sys.linkList(current, listExpected, ctx)...)
+
+			// placeholders take on the expected type
+		} else if current.Placeholder {
+			if current.Prev != nil {
+				errs = append(errs, NewParseErrorKind(current, ErrPlaceholder, "a placeholder must be at the start of an expression"))
+			} else if len(expectedTypes) == 0 {
+				errs = append(errs, NewParseErrorKind(current, ErrPlaceholder, "a placeholder must be given where a type is expected"))
+			} else {
+				current.Type = expectedTypes[0]
+				ctx.tracef(TraceEvent{Kind: TracePlaceholder, Expr: current, Type: current.Type}, "placeholder ? is the expected type %s", current.Type.Name)
+			}
+			if current.Type == nil {
+				current.Type = Unknown
+			}
+			errs = append(errs, sys.linkArguments(current, ctx)...)
+
+			// bind parameters have the type given in the options
+		} else if current.Bind {
+			if current.Prev != nil {
+				errs = append(errs, NewParseErrorKind(current, ErrSyntax, fmt.Sprintf("parameter :%s must be at the start of an expression", current.Token)))
+			} else if current.Type = ctx.parameters[strings.ToLower(current.Token)]; current.Type == nil {
+				errs = append(errs, NewParseErrorKind(current, ErrUnknownValue, fmt.Sprintf("undefined parameter :%s", current.Token)))
+			} else {
+				ctx.tracef(TraceEvent{Kind: TraceBind, Expr: current, Type: current.Type}, "parameter :%s is %s", current.Token, current.Type.Name)
+			}
+			if current.Type == nil {
+				current.Type = Unknown
+			}
+			errs = append(errs, sys.linkArguments(current, ctx)...)
+
+			// if it matches a value on the parent type and is not a constant
+		} else if currentValue != nil && !current.Constant {
 			current.Type = currentValue.ValueType()
 			current.Value = currentValue
+			if strings.EqualFold(current.Token, currentValue.Path) {
+				ctx.tracef(TraceEvent{Kind: TraceValue, Expr: current, Type: current.Type, Value: currentValue}, "%s is the value %s.%s", current.Token, parentType.Name, currentValue.Path)
+			} else {
+				ctx.tracef(TraceEvent{Kind: TraceAlias, Expr: current, Type: current.Type, Value: currentValue}, "%s is an alias of the value %s.%s", current.Token, parentType.Name, currentValue.Path)
+			}
 
-			err := sys.linkArguments(current, root)
-			if err != nil {
-				return err
+			if !parentType.Allows(currentValue, ctx.roles) {
+				errs = append(errs, NewParseErrorKind(current, ErrPermission, fmt.Sprintf("%s.%s is not allowed", parentType.Name, currentValue.Path)))
 			}
 
+			errs = append(errs, sys.linkArguments(current, ctx)...)
+
 			// For generic values, calculate the type now that the argument types are determined.
 			if currentValue.Generic {
 				current.Type = currentValue.GetType(current)
 				if current.Type == nil {
-					return NewParseError(current, fmt.Sprintf("generic type could not be determined for %s", current.Token))
-				}
-				// Convert the generic arguments to the expected types
-				for _, arg := range current.Arguments {
-					if arg.Parameter.Generic {
-						sys.convertToExpected(arg.Last(), []*Type{current.Type})
+					current.Type = Unknown
+					errs = append(errs, NewParseErrorKind(current, ErrTypeMismatch, fmt.Sprintf("generic type could not be determined for %s", current.Token)))
+				} else if current.Type != Unknown {
+					// Convert the generic arguments to the expected types
+					for _, arg := range current.Arguments {
+						if arg.Parameter == nil || !arg.Parameter.Generic {
+							continue
+						}
+						if !ctx.noAutoCast {
+							sys.convertToExpected(arg.Last(), []*Type{current.Type}, ctx)
+						} else if last := arg.Last(); last.Type != Unknown && last.Type != Null && last.Type.Name != current.Type.Name {
+							errs = append(errs, NewParseErrorKind(last, ErrTypeMismatch, fmt.Sprintf("expected type %s but was given %s instead", current.Type.Name, last.Type.Name)))
+						}
 					}
 				}
 			}
 
 			// if it is a constant or does not match a value on the parent type
-		} else if current.Constant || currentValue == nil {
-			// if its a lone constant and an expected type is given, parse using only that
-			if current.Next == nil && len(expectedTypes) > 0 {
-				err := sys.setConstant(current, expectedTypes, true)
-				if err != nil {
-					return err
+		} else {
+			if current.Prev == nil && current.raw != "" {
+				current.Token = current.raw
+				current.quotes = 1
+			}
+			// null is the null constant unless it's quoted
+			if current.Prev == nil && !current.Constant && current.quotes == 0 && strings.EqualFold(current.Token, nullToken) {
+				current.Type = Null
+				current.Constant = true
+				current.Parsed = nil
+				ctx.tracef(TraceEvent{Kind: TraceConstant, Expr: current, Type: current.Type}, "constant %s is %s", current.Token, current.Type.Name)
+				// typed literals are parsed by the type of their prefix
+			} else if current.Literal != "" {
+				literalType := sys.literals[strings.ToLower(current.Literal)]
+				if err := sys.setConstant(current, []*Type{literalType}, true, ctx); err != nil {
+					errs = append(errs, *err)
+				}
+				// if its a lone constant and an expected type is given, parse using only that
+			} else if current.Prev == nil && current.Next == nil && len(expectedTypes) > 0 {
+				err := sys.setConstant(current, expectedTypes, true, ctx)
+				if err != nil && (ctx.noAutoCast || !sys.coerceConstant(current, expectedTypes, ctx)) {
+					errs = append(errs, *err)
 				}
 				// its not a lone constant or there is no expected type
 			} else if current.Prev == nil {
-				sys.setConstant(current, sys.parseOrder, false)
+				sys.setConstant(current, sys.parseOrder, false, ctx)
 				if current.Type == nil {
-					return NewParseError(current, fmt.Sprintf("type could not be determined for %s", current.Token))
+					errs = append(errs, NewParseErrorKind(current, ErrUnknownValue, fmt.Sprintf("type could not be determined for %s", current.Token)))
+					ctx.tracef(TraceEvent{Kind: TraceUnknown, Expr: current, Tried: sys.parseOrder}, "%s is not a value of %s and no type parsed it", current.Token, parentType.Name)
 				}
-			} else {
-				return NewParseError(current, fmt.Sprintf("invalid value %s", current.Token))
+			} else if current.Prefix {
+				errs = append(errs, NewParseErrorKind(current, ErrUnknownValue, fmt.Sprintf("%s can't be written before %s", current.Token, parentType.Name)))
+				ctx.tracef(TraceEvent{Kind: TraceUnknown, Expr: current}, "%s is not a prefix of %s", current.Token, parentType.Name)
+			} else if current.Token != "" {
+				errs = append(errs, NewParseErrorKind(current, ErrUnknownValue, fmt.Sprintf("invalid value %s", current.Token)))
+				ctx.tracef(TraceEvent{Kind: TraceUnknown, Expr: current}, "%s is not a value of %s", current.Token, parentType.Name)
 			}
-		} else {
-			return NewParseError(current, fmt.Sprintf("unexpected token %s", current.Token))
+			if current.Type == nil {
+				current.Type = Unknown
+			}
+			errs = append(errs, sys.linkArguments(current, ctx)...)
 		}
 
 		parent = current
@@ -608,33 +1925,46 @@ func (sys System) link(e *Expr, expectedTypes []*Type, root *Type) error {
 	}
 
 	// Try to auto-cast the last expression to an expected type in the order they were given.
-	parent = sys.convertToExpected(parent, expectedTypes)
+	if !ctx.noAutoCast {
+		parent = sys.convertToExpected(parent, expectedTypes, ctx)
+	}
 
 	// If the last expression does not match an expected type, error.
-	if parent != nil && len(expectedTypes) > 0 && !parent.TypeOneOf(expectedTypes) {
-		return NewParseError(parent, fmt.Sprintf("expected type(s) %s but was given %s instead", getTypeNames(expectedTypes), parent.Type.Name))
+	if parent != nil && parent.Type != Unknown && len(expectedTypes) > 0 && !parent.TypeOneOf(expectedTypes) {
+		errs = append(errs, NewParseErrorKind(parent, ErrTypeMismatch, fmt.Sprintf("expected type(s) %s but was given %s instead", getTypeNames(expectedTypes), parent.Type.Name)))
 	}
 
-	return nil
+	return errs
 }
 
-func (sys System) convertToExpected(last *Expr, expectedTypes []*Type) *Expr {
-	if last == nil || len(expectedTypes) == 0 || last.TypeOneOf(expectedTypes) {
+func (sys System) convertToExpected(last *Expr, expectedTypes []*Type, ctx *linkContext) *Expr {
+	if last == nil || last.Type == Unknown || len(expectedTypes) == 0 || last.TypeOneOf(expectedTypes) {
 		return last
 	}
 
 	for _, expectedType := range expectedTypes {
 		convert := last.Type.AsValue(expectedType.Name)
-		if convert != nil {
+		if convert != nil && last.Type.Allows(convert, ctx.roles) {
+			if last.Constant && last.Prev == nil && convert.Convert != nil {
+				converted, err := convert.Convert(last.Parsed)
+				if err == nil {
+					ctx.tracef(TraceEvent{Kind: TraceConversion, Expr: last, Type: expectedType, Value: convert}, "constant %s of %s was converted to the expected type %s", last.Token, last.Type.Name, expectedType.Name)
+					last.Type = expectedType
+					last.Parsed = converted
+					break
+				}
+			}
 			next := &Expr{
 				Token:      convert.Path,
 				Type:       expectedType,
 				Value:      convert,
 				Prev:       last,
 				ParentType: last.Type,
+				converted:  true,
 			}
 			last.Next = next
 			last = next
+			ctx.tracef(TraceEvent{Kind: TraceConversion, Expr: next, Type: expectedType, Value: convert}, "%s was added to convert %s to the expected type %s", convert.Path, next.ParentType.Name, expectedType.Name)
 
 			break
 		}
@@ -643,75 +1973,191 @@ func (sys System) convertToExpected(last *Expr, expectedTypes []*Type) *Expr {
 	return last
 }
 
-func (sys System) setConstant(current *Expr, tryTypes []*Type, required bool) error {
-	for _, parser := range tryTypes {
-		parsed, err := parser.ParseInput(current.Token)
+func (sys System) setConstant(current *Expr, tryTypes []*Type, required bool, ctx *linkContext) *ParseError {
+	causes := make([]error, 0, len(tryTypes))
+	for i, parser := range tryTypes {
+		constant, err := sys.parseConstant(parser, current.Token, ctx)
 		if err == nil {
+			current.Token = constant.token
 			current.Type = parser
 			current.Constant = true
-			current.Parsed = parsed
+			current.Parsed = constant.parsed
+			current.Layout = constant.layout
+			if i+1 < len(tryTypes) && (sys.options.ResolveAmbiguity != nil || ctx.trace != nil) {
+				sys.resolveAmbiguity(current, tryTypes[i+1:], ctx)
+			}
+			if i == 0 {
+				ctx.tracef(TraceEvent{Kind: TraceConstant, Expr: current, Type: current.Type}, "constant %s is %s", current.Token, current.Type.Name)
+			} else {
+				ctx.tracef(TraceEvent{Kind: TraceConstant, Expr: current, Type: current.Type, Tried: tryTypes[:i]}, "constant %s is %s after %s did not parse it", current.Token, current.Type.Name, getTypeNames(tryTypes[:i]))
+			}
 			return nil
 		}
+		causes = append(causes, err)
 	}
 
 	if required {
-		return NewParseError(current, fmt.Sprintf("constant %s did not match expected type(s) %s", current.Token, getTypeNames(tryTypes)))
+		err := NewParseErrorKind(current, ErrTypeMismatch, fmt.Sprintf("constant %s did not match expected type(s) %s", current.Token, getTypeNames(tryTypes)))
+		err.Cause = errors.Join(causes...)
+		var constantError ConstantError
+		if errors.As(err.Cause, &constantError) {
+			err.locate(current, constantError)
+		}
+		return &err
 	}
 
 	return nil
 }
 
-func (sys System) linkArguments(current *Expr, root *Type) error {
+// Tries to parse the constant as a type which can be converted to one of the expected types during
+// linking. Returns whether the constant was converted.
+func (sys System) coerceConstant(current *Expr, expectedTypes []*Type, ctx *linkContext) bool {
+	for _, parser := range sys.parseOrder {
+		for _, expectedType := range expectedTypes {
+			convert := parser.AsValue(expectedType.Name)
+			if convert == nil || convert.Convert == nil || !parser.Allows(convert, ctx.roles) {
+				continue
+			}
+			parsed, layout, err := parser.ParseLayout(current.Token)
+			if err != nil {
+				break
+			}
+			converted, err := convert.Convert(parsed)
+			if err != nil {
+				continue
+			}
+			current.Type = expectedType
+			current.Constant = true
+			current.Parsed = converted
+			current.Layout = layout
+			ctx.tracef(TraceEvent{Kind: TraceCoercion, Expr: current, Type: expectedType, Value: convert}, "constant %s was parsed as %s and converted to the expected type %s", current.Token, parser.Name, expectedType.Name)
+			return true
+		}
+	}
+	return false
+}
+
+// Links the arguments of the expression to the parameters of its value, adding arguments for
+// any missing parameters with default values. When the expression has no value (it could not be
+// determined) the arguments are still linked so everything inside them can be resolved.
+func (sys System) linkArguments(current *Expr, ctx *linkContext) []ParseError {
+	errs := make([]ParseError, 0)
 	args := current.Arguments
 	argCount := len(args)
+
+	if current.Value == nil {
+		// Parentheses which don't group one expression were reported when they were removed.
+		if current.Called && !current.group && (current.Constant || current.Bind || current.Placeholder) {
+			errs = append(errs, NewParseErrorKind(current, ErrSyntax, fmt.Sprintf("%s is not a value and can't be called", current.Token)))
+		}
+		for _, arg := range args {
+			errs = append(errs, sys.link(arg, nil, ctx)...)
+		}
+		return errs
+	}
+
+	errs = append(errs, sys.orderArguments(current)...)
+	args = current.Arguments
+	argCount = len(args)
+
 	argMin := current.Value.MinParameters()
 	argMax := current.Value.MaxParameters()
 
-	if argCount < argMin {
-		return NewParseError(current, fmt.Sprintf("%s.%s expects at least %d parameters", current.Token, current.ParentType.Name, argMin))
+	if current.Called && argCount == 0 && argMin > 0 {
+		errs = append(errs, NewParseErrorKind(current, ErrArity, fmt.Sprintf("%s was called with no arguments but expects at least %d parameters", current.Token, argMin)))
+	} else if argCount < argMin {
+		errs = append(errs, NewParseErrorKind(current, ErrArity, fmt.Sprintf("%s.%s expects at least %d parameters", current.Token, current.ParentType.Name, argMin)))
 	}
 	if argCount > argMax {
-		return NewParseError(current, fmt.Sprintf("%s.%s expects no more than %d parameters", current.Token, current.ParentType.Name, argMax))
+		errs = append(errs, NewParseErrorKind(current, ErrArity, fmt.Sprintf("%s.%s expects no more than %d parameters", current.Token, current.ParentType.Name, argMax)))
 	}
 
 	for i := 0; i < argCount; i++ {
 		param := current.Value.Parameter(i)
+		if current.Arguments[i] == nil {
+			if param == nil {
+				continue
+			}
+			arg, err := sys.defaultArgument(current, param, i, ctx)
+			if err != nil {
+				errs = append(errs, *err)
+			} else if namedAfter(current.Arguments[i+1:]) {
+				// Named since it's before named arguments, so the expression can be written back out.
+				arg.ArgumentName = param.Name
+			}
+			current.Arguments[i] = arg
+			continue
+		}
 		parameterType := make([]*Type, 0)
-		if param.parameterType != nil {
+		if param != nil && param.parameterType != nil {
 			parameterType = append(parameterType, param.parameterType)
+			if param.Nullable {
+				parameterType = append(parameterType, Null)
+			}
 		}
-		err := sys.link(current.Arguments[i], parameterType, root)
-		if err != nil {
-			return err
-		}
+		errs = append(errs, sys.link(current.Arguments[i], parameterType, ctx)...)
 		current.Arguments[i].Parameter = param
+		if isConstantArgument(current.Arguments[i]) {
+			if err := checkArgument(current.Arguments[i], current.Arguments[i].Parsed); err != nil {
+				errs = append(errs, *err)
+			}
+		}
+	}
+
+	// Remove the skipped parameters which had no default, their errors were returned.
+	given := current.Arguments[:0]
+	for _, arg := range current.Arguments {
+		if arg != nil {
+			given = append(given, arg)
+		}
 	}
+	current.Arguments = given
 
 	for i := argCount; i < len(current.Value.Parameters); i++ {
 		param := current.Value.Parameter(i)
-		if param.Default == nil {
-			err := NewParseError(current, fmt.Sprintf("parameter %s at %d was not given a value or a default value", param.Name, i))
-			err.Parameter = param
-			return err
+		if param.Default == nil && argCount < argMin {
+			break
 		}
-		parsed, parseError := param.parameterType.ParseInput(*param.Default)
-		if parseError != nil {
-			err := NewParseError(current, parseError.Error())
-			err.Parameter = param
-			return err
+		arg, err := sys.defaultArgument(current, param, i, ctx)
+		if err != nil {
+			errs = append(errs, *err)
+			break
 		}
-		arg := &Expr{
-			Token:     *param.Default,
-			Constant:  true,
-			Type:      param.parameterType,
-			Parameter: param,
-			Parent:    current,
-			Parsed:    parsed,
+		if n := len(current.Arguments); n > 0 && current.Arguments[n-1].ArgumentName != "" {
+			arg.ArgumentName = param.Name
 		}
 		current.Arguments = append(current.Arguments, arg)
 	}
 
-	return nil
+	return errs
+}
+
+// Returns a constant argument of the parameter's default value for the value expression, or an error if
+// the parameter at the index has no default or it could not be parsed.
+func (sys System) defaultArgument(current *Expr, param *Parameter, index int, ctx *linkContext) (*Expr, *ParseError) {
+	if param.Default == nil {
+		err := NewParseErrorKind(current, ErrArity, fmt.Sprintf("parameter %s at %d was not given a value or a default value", param.Name, index))
+		err.Parameter = param
+		return nil, &err
+	}
+	parsed, layout, parseError := param.parameterType.ParseLayout(*param.Default)
+	if parseError != nil {
+		err := NewParseErrorKind(current, ErrTypeMismatch, parseError.Error())
+		err.Parameter = param
+		err.Cause = parseError
+		return nil, &err
+	}
+	arg := &Expr{
+		Token:     *param.Default,
+		Constant:  true,
+		Type:      param.parameterType,
+		Parameter: param,
+		Parent:    current,
+		Parsed:    parsed,
+		Layout:    layout,
+	}
+	ctx.tracef(TraceEvent{Kind: TraceDefault, Expr: arg, Type: arg.Type}, "parameter %s of %s was given its default %s", param.Name, current.Token, arg.Token)
+	return arg, nil
 }
 
 type parser struct {
@@ -731,14 +2177,39 @@ type parser struct {
 	lineReset int
 	// the current line
 	line int
+	// the characters identifiers are made of
+	identifiers IdentifierChars
+	// the infix operators keyed by lowercase token, or nil when operators can't be written infix.
+	operators Precedence
+	// the symbols which can be written before an expression, see Value.Prefix.
+	prefixes map[string]bool
+	// the types of typed literals keyed by lowercase prefix, see SystemOptions.Literals.
+	literals map[string]*Type
+	// if numbers with a fraction are one token, see Options.Decimals.
+	decimals bool
+	// if a . was parsed since the previous expression, so the next token is a value in the chain.
+	dotted bool
+	// the name given to the argument being parsed, see Expr.ArgumentName.
+	argumentName string
+	// if a ?. was parsed since the previous expression, see Expr.Optional.
+	optional bool
 }
 
 // Creates a new parser for the given expression.
-func newParser(e string) parser {
+func newParser(e string, identifiers IdentifierChars) parser {
 	return parser{
-		e: e,
-		n: len(e),
+		e:           e,
+		n:           len(e),
+		identifiers: identifiers,
+	}
+}
+
+// Returns the length in bytes of the identifier character at the index, or 0 if there isn't one.
+func (p *parser) identifierAt(i int) int {
+	if i >= p.n {
+		return 0
 	}
+	return p.identifiers.at(p.e[i:p.n])
 }
 
 // If the parser still has expressions to parse.
@@ -771,24 +2242,108 @@ func (p *parser) parseExpr() (expr *Expr, err error) {
 		case ' ', '\t', '\r', '\f', '\v':
 			p.i++
 		case '(':
+			if p.prev == nil || p.prev.Infix || p.prev.Prefix {
+				p.newExpr(&Expr{Start: p.position(), group: true})
+			}
+			if p.prev == nil {
+				return expr, NewParseErrorKind(expr, ErrSyntax, fmt.Sprintf("unexpected ( at %v, parentheses must follow a value", p.position()))
+			}
+			if p.prev.Called {
+				return expr, NewParseErrorKind(expr, ErrSyntax, fmt.Sprintf("unexpected ( at %v, %s already has parentheses", p.position(), p.prev.Token))
+			}
+			p.prev.Called = true
 			p.parents = append(p.parents, p.prev)
 			p.prev = nil
 			p.i++
 		case ')':
 			n := len(p.parents) - 1
 			if n == -1 {
-				return expr, NewParseError(expr, fmt.Sprintf("unexpected ) at %v", p.position()))
+				return expr, NewParseErrorKind(expr, ErrSyntax, fmt.Sprintf("unexpected ) at %v", p.position()))
+			}
+			if p.parents[n].List {
+				return expr, NewParseErrorKind(expr, ErrSyntax, fmt.Sprintf("unexpected ) at %v, expecting ] to end the list", p.position()))
+			}
+			if p.argumentName != "" {
+				return expr, NewParseErrorKind(expr, ErrSyntax, fmt.Sprintf("unexpected ) at %v, expecting a value for %s", p.position(), p.argumentName))
+			}
+			if p.prev == nil && len(p.parents[n].Arguments) > 0 {
+				p.parents[n].TrailingComma = true
+			}
+			p.prev = p.parents[n]
+			p.parents = p.parents[:n]
+			p.i++
+			if p.prev.group {
+				p.prev.End = p.position()
+			}
+		case '[':
+			if p.prev != nil && !p.prev.Infix && !p.prev.Prefix {
+				return expr, NewParseErrorKind(expr, ErrSyntax, fmt.Sprintf("unexpected [ at %v, a list must start an expression or argument", p.position()))
+			}
+			p.parents = append(p.parents, p.newExpr(&Expr{Start: p.position(), List: true}))
+			p.prev = nil
+			p.i++
+		case ']':
+			n := len(p.parents) - 1
+			if n == -1 || !p.parents[n].List {
+				return expr, NewParseErrorKind(expr, ErrSyntax, fmt.Sprintf("unexpected ] at %v", p.position()))
+			}
+			if p.prev == nil && len(p.parents[n].Arguments) > 0 {
+				p.parents[n].TrailingComma = true
 			}
 			p.prev = p.parents[n]
 			p.parents = p.parents[:n]
 			p.i++
+			p.prev.End = p.position()
 		case ',':
+			if p.argumentName != "" {
+				return expr, NewParseErrorKind(expr, ErrSyntax, fmt.Sprintf("unexpected , at %v, expecting a value for %s", p.position(), p.argumentName))
+			}
+			if p.prev == nil && len(p.parents) > 0 {
+				parent := p.parents[len(p.parents)-1]
+				if parent.List || parent.group {
+					return expr, NewParseErrorKind(expr, ErrSyntax, fmt.Sprintf("unexpected , at %v, expecting an argument before it", p.position()))
+				}
+				// An empty argument skips its parameter like _ does.
+				p.newExpr(&Expr{Token: skipToken, Start: p.position(), End: p.position()})
+			}
 			p.prev = nil
 			p.i++
 		case '.':
+			p.dotted = true
 			p.i++
 		case '"', '\'':
-			expr, err = p.parseConstant()
+			if p.i+2 < p.n && p.e[p.i+1] == b && p.e[p.i+2] == b {
+				expr, err = p.parseRawConstant()
+			} else {
+				expr, err = p.parseConstant()
+			}
+			searching = false
+		case '`':
+			expr, err = p.parseQuotedPath()
+			searching = false
+		case ':':
+			if p.namesArgument() {
+				p.i++
+				continue
+			}
+			if p.identifierAt(p.i+1) > 0 {
+				expr, err = p.parseBind()
+			} else {
+				expr, err = p.parseToken()
+			}
+			searching = false
+		case '?':
+			if p.prev != nil && p.i+1 < p.n && p.e[p.i+1] == '.' {
+				p.optional = true
+				p.dotted = true
+				p.i += 2
+				break
+			}
+			if p.i+1 == p.n || stopChars[p.e[p.i+1]] || spaceChars[p.e[p.i+1]] {
+				expr, err = p.parsePlaceholder()
+			} else {
+				expr, err = p.parseToken()
+			}
 			searching = false
 		default:
 			expr, err = p.parseToken()
@@ -798,7 +2353,19 @@ func (p *parser) parseExpr() (expr *Expr, err error) {
 	}
 
 	if p.i == p.n && err == nil && len(p.parents) != 0 {
-		err = NewParseError(expr, fmt.Sprintf("expression missing %d terminating parenthesis", len(p.parents)))
+		closing := ""
+		for i := len(p.parents) - 1; i >= 0; i-- {
+			if p.parents[i].List {
+				closing += "]"
+			} else {
+				closing += ")"
+			}
+		}
+		if strings.Contains(closing, "]") {
+			err = NewParseErrorKind(expr, ErrSyntax, fmt.Sprintf("expression missing terminating %s", closing))
+		} else {
+			err = NewParseErrorKind(expr, ErrSyntax, fmt.Sprintf("expression missing %d terminating parenthesis", len(p.parents)))
+		}
 	}
 
 	// When an error has occurred and the previous character indicated we expect something
@@ -807,7 +2374,7 @@ func (p *parser) parseExpr() (expr *Expr, err error) {
 	if p.i > 0 && nextChars[p.e[p.i-1]] {
 		expr = p.newExpr(&Expr{Start: p.position(), End: p.position()})
 		if err == nil {
-			err = NewParseError(expr, fmt.Sprintf("expression expecting a value but found nothing"))
+			err = NewParseErrorKind(expr, ErrSyntax, "expression expecting a value but found nothing")
 		}
 	}
 
@@ -829,12 +2396,17 @@ func (p *parser) newExpr(e *Expr) *Expr {
 	}
 	// This is the new prev
 	p.prev = e
+	p.dotted = false
+	e.Optional = p.optional && e.Prev != nil
+	p.optional = false
 	// If this is the first expresion in an argument, add it to the parent expressions
 	// argument list and set parent.
 	if len(p.parents) > 0 && e.Prev == nil {
 		parent := p.parents[len(p.parents)-1]
 		parent.Arguments = append(parent.Arguments, e)
 		e.Parent = parent
+		e.ArgumentName = p.argumentName
+		p.argumentName = ""
 	}
 	return e
 }
@@ -842,35 +2414,112 @@ func (p *parser) newExpr(e *Expr) *Expr {
 // Parses a token. A token is a value on type (parameterized and non-parameterized)
 // or a constant not surrounded with quotes.
 func (p *parser) parseToken() (*Expr, error) {
+	if operator := p.operatorAt(); operator > 0 {
+		start := p.position()
+		p.i += operator
+		return p.newExpr(&Expr{Token: p.e[p.i-operator : p.i], Infix: true, Start: start, End: p.position()}), nil
+	}
+	if number := p.numberAt(); number > 0 {
+		start := p.position()
+		p.i += number
+		return p.newExpr(&Expr{Token: p.e[p.i-number : p.i], Start: start, End: p.position()}), nil
+	}
+	if prefix := p.prefixAt(); prefix > 0 {
+		start := p.position()
+		p.i += prefix
+		return p.newExpr(&Expr{Token: p.e[p.i-prefix : p.i], Prefix: true, Start: start, End: p.position()}), nil
+	}
 	out := strings.Builder{}
-	b := p.e[p.i]
-	word := wordChars[b]
+	word := p.identifierAt(p.i) > 0
 	start := p.position()
 	for p.i < p.n {
-		b = p.e[p.i]
-		if stopChars[b] || (word && !wordChars[b]) {
+		b := p.e[p.i]
+		if stopChars[b] || (p.operators != nil && spaceChars[b]) {
 			break
 		}
+		if word {
+			size := p.identifierAt(p.i)
+			if size == 0 {
+				break
+			}
+			out.WriteString(p.e[p.i : p.i+size])
+			p.i += size
+			continue
+		}
 		out.WriteByte(b)
 		p.i++
 	}
+	if word && p.i < p.n && (p.e[p.i] == '\'' || p.e[p.i] == '"') && (p.prev == nil || p.prev.Infix || p.prev.Prefix) && p.literals[strings.ToLower(out.String())] != nil {
+		return p.parseLiteral(out.String(), start)
+	}
 	return p.newExpr(&Expr{Token: out.String(), Start: start, End: p.position()}), nil
 }
 
+// A number with an optional sign, fraction, and exponent, like -5, 1.5, -2.5e6, or 2E-3.
+var numberLiteral = regexp.MustCompile(`^[+-]?\d[\d_]*(\.\d[\d_]*)?([eE][+-]?\d[\d_]*)?`)
+
+// Returns the length of the number at the start of a chain or operand, which is one token even though
+// it can contain a . or a sign, or 0 if there isn't one. So `-5` is a constant and not the `-` value.
+// A fraction without an exponent is only part of the number with Options.Decimals.
+func (p *parser) numberAt() int {
+	if p.prev != nil && !p.prev.Infix && !p.prev.Prefix {
+		return 0
+	}
+	match := numberLiteral.FindStringSubmatchIndex(p.e[p.i:p.n])
+	if match == nil {
+		return 0
+	}
+	length := match[1]
+	if fraction, exponent := match[2], match[4]; !p.decimals && fraction >= 0 && exponent < 0 {
+		length = fraction
+	}
+	end := p.i + length
+	if end < p.n && !stopChars[p.e[end]] && !spaceChars[p.e[end]] {
+		return 0
+	}
+	return length
+}
+
+// Parses a named bind parameter.
+func (p *parser) parseBind() (*Expr, error) {
+	start := p.position()
+	p.i++
+	name := strings.Builder{}
+	for size := p.identifierAt(p.i); size > 0; size = p.identifierAt(p.i) {
+		name.WriteString(p.e[p.i : p.i+size])
+		p.i += size
+	}
+	return p.newExpr(&Expr{Token: name.String(), Bind: true, Start: start, End: p.position()}), nil
+}
+
+// Parses a placeholder.
+func (p *parser) parsePlaceholder() (*Expr, error) {
+	start := p.position()
+	p.i++
+	return p.newExpr(&Expr{Token: "?", Placeholder: true, Start: start, End: p.position()}), nil
+}
+
 // Parses a constant surrounded with quotes.
 func (p *parser) parseConstant() (*Expr, error) {
 	out := strings.Builder{}
 	escaped := false
+	var escapeErr *ParseError
 	end := p.e[p.i]
 	start := p.position()
-	for p.i < p.n {
+	from := p.i + 1
+	offsets := make([]int, 0)
+	hasEscapes := false
+	for p.i+1 < p.n {
 		p.i++
 		b := p.e[p.i]
 		if b == '\\' && !escaped {
 			escaped = true
+			hasEscapes = true
 			continue
 		}
+		at := p.i - from
 		if escaped {
+			at--
 			switch b {
 			case 'n':
 				b = '\n'
@@ -878,25 +2527,199 @@ func (p *parser) parseConstant() (*Expr, error) {
 				b = '\r'
 			case 't':
 				b = '\t'
+			case 'x', 'u', 'U':
+				if err := p.parseEscape(&out); err != nil && escapeErr == nil {
+					escapeErr = err
+				}
+				for len(offsets) < out.Len() {
+					offsets = append(offsets, at)
+				}
+				escaped = false
+				continue
+			case '\\', '\'', '"':
+				// Quotes and backslashes are written as is.
+			default:
+				if escapeErr == nil {
+					start := p.position()
+					start.Index--
+					start.Column--
+					escapeErr = p.escapeError(start, fmt.Sprintf("invalid escape \\%c at %v, unknown escape character", b, start))
+				}
+				out.WriteByte('\\')
+				offsets = append(offsets, at)
+				at++
 			}
 		}
 		if b == end && !escaped {
+			source := p.e[from:p.i]
+			p.i++
+			expr := p.newExpr(&Expr{Token: out.String(), Constant: true, quotes: 1, Start: start, End: p.position()})
+			if hasEscapes {
+				expr.source = source
+				expr.offsets = append(offsets, len(source))
+			}
+			if escapeErr != nil {
+				escapeErr.Expr = expr
+				return expr, *escapeErr
+			}
+			return expr, nil
+		}
+		out.WriteByte(b)
+		offsets = append(offsets, at)
+		escaped = false
+	}
+
+	p.i = p.n
+	err := NewParseErrorKind(nil, ErrUnterminatedConstant, fmt.Sprintf("quoted constant starting at %v did not have a terminating %s", start, string([]byte{end})))
+	err.Start = &start
+	return nil, err
+}
+
+// Parses a typed literal, like `date'2023-01-05'`, where the current character is the quote after the prefix.
+func (p *parser) parseLiteral(prefix string, start Position) (*Expr, error) {
+	var expr *Expr
+	var err error
+	if p.i+2 < p.n && p.e[p.i+1] == p.e[p.i] && p.e[p.i+2] == p.e[p.i] {
+		expr, err = p.parseRawConstant()
+	} else {
+		expr, err = p.parseConstant()
+	}
+	if expr != nil {
+		expr.Literal = prefix
+		expr.Start = start
+	}
+	return expr, err
+}
+
+// Parses a constant surrounded with three quotes, like """a "b" c""", which can span lines and has no escapes.
+func (p *parser) parseRawConstant() (*Expr, error) {
+	quote := p.e[p.i : p.i+3]
+	start := p.position()
+	p.i += 3
+	from := p.i
+	for p.i < p.n {
+		if strings.HasPrefix(p.e[p.i:p.n], quote) {
+			token := p.e[from:p.i]
+			p.i += 3
+			return p.newExpr(&Expr{Token: token, Constant: true, quotes: 3, Start: start, End: p.position()}), nil
+		}
+		if p.e[p.i] == '\n' {
+			p.line++
+			p.lineReset = p.i + 1
+		}
+		p.i++
+	}
+
+	err := NewParseErrorKind(nil, ErrUnterminatedConstant, fmt.Sprintf("quoted constant starting at %v did not have a terminating %s", start, quote))
+	err.Start = &start
+	return nil, err
+}
+
+// Parses the hex digits of a \xNN, \uNNNN, or \UNNNNNNNN escape in a quoted constant where the current
+// character is the x, u, or U, and writes the byte or character. A malformed escape is written as is and
+// an error is returned.
+func (p *parser) parseEscape(out *strings.Builder) *ParseError {
+	start := p.position()
+	start.Index--
+	start.Column--
+	kind := p.e[p.i]
+	digits := map[byte]int{'x': 2, 'u': 4, 'U': 8}[kind]
+	end := p.i + 1
+	for end < p.n && end-p.i <= digits && isHexDigit(p.e[end]) {
+		end++
+	}
+	hex := p.e[p.i+1 : end]
+	p.i = end - 1
+	code, _ := strconv.ParseUint(hex, 16, 32)
+
+	message := ""
+	switch {
+	case len(hex) < digits:
+		message = fmt.Sprintf("invalid escape \\%c%s at %v, expected %d hex digits", kind, hex, start, digits)
+	case kind == 'x':
+		out.WriteByte(byte(code))
+		return nil
+	case !utf8.ValidRune(rune(code)):
+		message = fmt.Sprintf("invalid escape \\%c%s at %v, not a valid unicode character", kind, hex, start)
+	default:
+		out.WriteRune(rune(code))
+		return nil
+	}
+	out.WriteString("\\" + string(kind) + hex)
+	return p.escapeError(start, message)
+}
+
+// Returns a syntax error for a malformed escape from the start to after the current character.
+func (p *parser) escapeError(start Position, message string) *ParseError {
+	err := NewParseErrorKind(nil, ErrSyntax, message)
+	end := p.position()
+	end.Index++
+	end.Column++
+	err.Start = &start
+	err.End = &end
+	return &err
+}
+
+// Returns whether the character is a hexadecimal digit.
+func isHexDigit(b byte) bool {
+	return (b >= '0' && b <= '9') || (b >= 'a' && b <= 'f') || (b >= 'A' && b <= 'F')
+}
+
+// Parses a value path surrounded with backticks, like `first name`, which can contain spaces
+// and characters which would otherwise end a token. At the start of a chain a quoted path which is
+// not a value is a raw constant of the text between the backticks, like `C:\temp\d+`, where \ has
+// no meaning except before a backtick.
+func (p *parser) parseQuotedPath() (*Expr, error) {
+	out := strings.Builder{}
+	escaped := false
+	start := p.position()
+	from := p.i + 1
+	for p.i+1 < p.n {
+		p.i++
+		b := p.e[p.i]
+		if b == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		if b == '`' && !escaped {
+			raw := p.e[from:p.i]
 			p.i++
-			return p.newExpr(&Expr{Token: out.String(), Constant: true, Start: start, End: p.position()}), nil
+			return p.newExpr(&Expr{Token: out.String(), raw: raw, Start: start, End: p.position()}), nil
 		}
 		out.WriteByte(b)
 		escaped = false
 	}
 
-	return nil, NewParseError(nil, fmt.Sprintf("quoted constant starting at %v did not have a terminating %s", start, string([]byte{end})))
+	p.i = p.n
+	err := NewParseErrorKind(nil, ErrSyntax, fmt.Sprintf("quoted path starting at %v did not have a terminating `", start))
+	err.Start = &start
+	return nil, err
+}
+
+// Returns whether the value path must be surrounded with backticks to be parsed back as one token.
+func needsPathQuotes(path string) bool {
+	if path == "" {
+		return false
+	}
+	word := wordChars[path[0]]
+	for i := 0; i < len(path); i++ {
+		b := path[i]
+		if (word && !wordChars[b]) || stopChars[b] || spaceChars[b] || b == '`' {
+			return true
+		}
+	}
+	return !word && (path[0] == ':' || path[0] == '?' || path[0] == '"' || path[0] == '\'' || path[0] >= utf8.RuneSelf)
 }
 
 // Any chars that end a token.
-var stopChars = charsToMap(".,()")
+var stopChars = charsToMap(".,()]")
 
 // Any chars that are valid ".name" values.
 var wordChars = charsToMap("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_")
 
+// Any chars which are whitespace.
+var spaceChars = charsToMap(" \t\n\r\f\v")
+
 // Any chars where you would expect another expression to follow
 var nextChars = charsToMap("(,.")
 
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 	"time"
+	"unicode"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -78,7 +79,7 @@ var sys = NewSystemRequired([]Type{{
 		{Path: "month", Type: typeInt},
 		{Path: "dayOfMonth", Type: typeInt},
 		{Path: "dayOfWeek", Type: typeDayOfWeek},
-		{Path: "add", Type: typeDate, Parameters: []Parameter{
+		{Path: "add", Type: typeDate, Examples: []string{"add(2, day)"}, Parameters: []Parameter{
 			{Name: "amount", Type: typeInt},
 			{Name: "duration", Type: typeDuration},
 		}},
@@ -138,7 +139,10 @@ var sys = NewSystemRequired([]Type{{
 	},
 	Values: []Value{
 		{Path: "text", Type: typeText},
-		{Path: ">", Type: typeBool, Parameters: []Parameter{
+		{Path: ">", Type: typeBool, Tests: []ValueTest{
+			{Expression: ">(2)", Root: 3, Expected: true},
+			{Expression: ">(3)", Root: 3, Expected: false},
+		}, Parameters: []Parameter{
 			{Name: "value", Type: typeInt},
 		}},
 		{Path: ">=", Type: typeBool, Parameters: []Parameter{
@@ -243,8 +247,13 @@ var sys = NewSystemRequired([]Type{{
 }, {
 	Name: typeText,
 	Values: []Value{
-		{Path: "length", Type: typeInt, Aliases: []string{"len"}},
-		{Path: "lower", Type: typeText},
+		{Path: "length", Type: typeInt, Aliases: []string{"len"}, Tests: []ValueTest{
+			{Expression: "length", Root: "hello", Expected: 5},
+		}},
+		{Path: "lower", Type: typeText, Tests: []ValueTest{
+			{Expression: "lower", Root: "HeLLo", Expected: "hello"},
+			{Expression: "lower.length", Root: "ABC", Expected: 3},
+		}},
 		{Path: "upper", Type: typeText},
 		{Path: "isLower", Type: typeBool},
 		{Path: "isUpper", Type: typeBool},
@@ -257,7 +266,7 @@ var sys = NewSystemRequired([]Type{{
 		{Path: "!=", Type: typeBool, Parameters: []Parameter{
 			{Name: "value", Type: typeText},
 		}},
-		{Path: "contains", Type: typeBool, Parameters: []Parameter{
+		{Path: "contains", Type: typeBool, Examples: []string{"lower.contains('ma')"}, Parameters: []Parameter{
 			{Name: "value", Type: typeText},
 		}},
 	},
@@ -339,6 +348,29 @@ var compileOptions = CompileSourceLookup[Run]{
 	},
 }
 
+func TestMinParameters(t *testing.T) {
+	one := "1"
+	tests := []struct {
+		name       string
+		parameters []Parameter
+		expected   int
+	}{
+		{name: "none", expected: 0},
+		{name: "required", parameters: []Parameter{{Name: "a"}, {Name: "b"}}, expected: 2},
+		{name: "trailing defaults", parameters: []Parameter{{Name: "a"}, {Name: "b", Default: &one}}, expected: 1},
+		{name: "all defaults", parameters: []Parameter{{Name: "a", Default: &one}, {Name: "b", Default: &one}}, expected: 0},
+		{name: "default before required", parameters: []Parameter{{Name: "a", Default: &one}, {Name: "b"}}, expected: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Value{Parameters: test.parameters}.MinParameters())
+		})
+	}
+
+	_, err := sys.Parse(Options{RootType: typeContext, Expression: "time.today.add(1)"})
+	assert.EqualError(t, err, "add.date expects at least 2 parameters")
+}
+
 func TestIt(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -449,7 +481,7 @@ func TestIt(t *testing.T) {
 			assert.Equal(t, e.Type.Name, typeTimePackage)
 			assert.NotNil(t, e.Next)
 			assert.Equal(t, e.Next.Token, "sun")
-			assert.Nil(t, e.Next.Type)
+			assert.Equal(t, Unknown, e.Next.Type)
 			assert.Nil(t, e.Next.Value)
 			assert.Nil(t, e.Next.Parsed)
 		},
@@ -466,7 +498,7 @@ func TestIt(t *testing.T) {
 			assert.Equal(t, e.Type.Name, typeTimePackage)
 			assert.NotNil(t, e.Next)
 			assert.Equal(t, e.Next.Token, "")
-			assert.Nil(t, e.Next.Type)
+			assert.Equal(t, Unknown, e.Next.Type)
 			assert.Nil(t, e.Next.Value)
 			assert.Nil(t, e.Next.Parsed)
 		},
@@ -618,6 +650,596 @@ func TestIt(t *testing.T) {
 	}
 }
 
+func TestPlaceholders(t *testing.T) {
+	e, err := sys.Parse(Options{RootType: typeContext, Expression: "time.today.add(?, day).year>( ? )"})
+	assert.NoError(t, err)
+	assert.Equal(t, "time.today.add(?,'day').year>(?)", e.String())
+
+	placeholders := e.Placeholders()
+	assert.Len(t, placeholders, 2)
+	assert.Equal(t, typeInt, placeholders[0].Type.Name)
+	assert.Equal(t, typeInt, placeholders[1].Type.Name)
+
+	_, err = Compile[Run](e, compileOptions)
+	assert.ErrorIs(t, err, ErrPlaceholder)
+
+	e, err = sys.Parse(Options{RootType: typeContext, Expression: "?.not", ExpectedTypes: []TypeName{typeBool}})
+	assert.NoError(t, err)
+	assert.Equal(t, typeBool, e.Type.Name)
+
+	_, err = sys.Parse(Options{RootType: typeContext, Expression: "?"})
+	assert.EqualError(t, err, "a placeholder must be given where a type is expected")
+
+	_, err = sys.Parse(Options{RootType: typeContext, Expression: "user.?", ExpectedTypes: []TypeName{typeText}})
+	assert.ErrorIs(t, err, ErrPlaceholder)
+}
+
+// A compile source which only implements the required CompileSource methods.
+type requiredSource struct {
+	lookup CompileSourceLookup[Run]
+}
+
+func (s requiredSource) GetInitial(e *Expr) (Run, error) {
+	return s.lookup.GetInitial(e)
+}
+func (s requiredSource) GetConstantCompiled(e *Expr, root *Type, previous Run, arguments []Run) (Run, error) {
+	return s.lookup.GetConstantCompiled(e, root, previous, arguments)
+}
+func (s requiredSource) GetValueCompiler(e *Expr, root *Type, previous Run) (Compiler[Run], error) {
+	return s.lookup.GetValueCompiler(e, root, previous)
+}
+
+func TestBinds(t *testing.T) {
+	opts := Options{
+		RootType:   typeContext,
+		Expression: "user.name.contains(:search).and(:flag, :search.len>(2))",
+		Parameters: map[string]TypeName{
+			"search": typeText,
+			"flag":   typeBool,
+		},
+	}
+	e, err := sys.Parse(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "user.name.contains(:search).and(:flag,:search.len>('2'))", e.String())
+	assert.Equal(t, []string{"search", "flag"}, e.Binds())
+
+	and := e.Last()
+	assert.Equal(t, typeBool, and.Arguments[0].Type.Name)
+	assert.Equal(t, typeInt, and.Arguments[1].Next.Type.Name)
+
+	compiled, err := Compile[Run](e, CompileSourceLookup[Run]{
+		Initial:          compileOptions.Initial,
+		ConstantCompiler: compileOptions.ConstantCompiler,
+		TypeCompilers:    compileOptions.TypeCompilers,
+		BindCompiler: func(e *Expr, root *Type, previous Run, arguments []Run) (Run, error) {
+			values := map[string]any{"search": "Ma", "flag": true}
+			return func(root any) (any, error) {
+				return values[e.Token], nil
+			}, nil
+		},
+	})
+	assert.NoError(t, err)
+	result, err := compiled(map[string]any{"user": map[string]any{"name": "Mason"}})
+	assert.NoError(t, err)
+	assert.Equal(t, false, result)
+
+	_, err = Compile[Run](e, compileOptions)
+	assert.EqualError(t, err, "no bind compiler specified for parameter :search")
+
+	_, err = Compile[Run](e, requiredSource{compileOptions})
+	assert.EqualError(t, err, "bind parameter :search is not supported by the compile source")
+
+	opts.Expression = "user.name.contains(:missing)"
+	_, err = sys.Parse(opts)
+	assert.EqualError(t, err, "undefined parameter :missing")
+
+	opts.Parameters["search"] = "missing"
+	_, err = sys.Parse(opts)
+	assert.ErrorIs(t, err, ErrUnknownType)
+}
+
+func TestExamples(t *testing.T) {
+	newTypes := func(examples ...string) []Type {
+		return []Type{{
+			Name:  typeText,
+			Parse: func(x string) (any, error) { return x, nil },
+			Values: []Value{
+				{Path: "lower", Type: typeText},
+				{Path: "upper", Type: typeText, Examples: examples},
+			},
+		}, {
+			Name: typeUser,
+			Values: []Value{
+				{Path: "name", Type: typeText},
+			},
+		}}
+	}
+
+	_, err := NewSystem(newTypes("upper", "lower.upper"))
+	assert.NoError(t, err)
+
+	_, err = NewSystem(newTypes("lower"))
+	assert.EqualError(t, err, "example lower for text.upper does not use the value")
+
+	_, err = NewSystem(newTypes("upper.missing"))
+	assert.EqualError(t, err, "example upper.missing for text.upper is invalid: invalid value missing")
+
+	_, err = NewSystemWithOptions(newTypes("name.upper"), SystemOptions{Root: typeUser})
+	assert.NoError(t, err)
+
+	_, err = NewSystemWithOptions(newTypes("name.upper"), SystemOptions{Root: typeDate})
+	assert.EqualError(t, err, "root type date could not be found")
+}
+
+func TestConstantCoercion(t *testing.T) {
+	coercion := NewSystemRequired([]Type{{
+		Name:  "number",
+		Parse: func(x string) (any, error) { return strconv.ParseFloat(x, 64) },
+		As: map[TypeName]string{
+			"money": "money",
+		},
+		Values: []Value{
+			{Path: "money", Type: "money", Convert: func(parsed any) (any, error) {
+				return int(parsed.(float64) * 100), nil
+			}},
+		},
+	}, {
+		Name: "money",
+		Values: []Value{
+			{Path: "plus", Type: "money", Parameters: []Parameter{
+				{Name: "amount", Type: "money"},
+			}},
+		},
+	}, {
+		Name: "cart",
+		Values: []Value{
+			{Path: "total", Type: "money"},
+		},
+	}})
+
+	e, err := coercion.Parse(Options{RootType: "cart", Expression: "total.plus(12)"})
+	assert.NoError(t, err)
+	arg := e.Next.Arguments[0]
+	assert.Nil(t, arg.Next)
+	assert.True(t, arg.Constant)
+	assert.Equal(t, TypeName("money"), arg.Type.Name)
+	assert.Equal(t, 1200, arg.Parsed)
+	assert.Equal(t, "total.plus('12')", e.String())
+
+	e, err = coercion.Parse(Options{RootType: "cart", Expression: "12", ExpectedTypes: []TypeName{"money"}})
+	assert.NoError(t, err)
+	assert.Nil(t, e.Next)
+	assert.Equal(t, 1200, e.Parsed)
+
+	_, err = coercion.Parse(Options{RootType: "cart", Expression: "total.plus(abc)"})
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+}
+
+func TestExprAt(t *testing.T) {
+	expression := "user.name.contains('a').or(time.today.add(1, day).year>(2000))"
+	e, err := sys.Parse(Options{RootType: typeContext, Expression: expression, ExpectedTypes: []TypeName{typeBool}})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		at    string
+		token string
+	}{
+		{at: "user", token: "user"},
+		{at: ".name", token: "user"},
+		{at: "ame", token: "name"},
+		{at: "'a'", token: "a"},
+		{at: "today", token: "today"},
+		{at: "(1", token: "add"},
+		{at: "1,", token: "1"},
+		{at: "ay)", token: "day"},
+		{at: ">", token: ">"},
+		{at: "000", token: "2000"},
+	}
+	for _, test := range tests {
+		t.Run(test.at, func(t *testing.T) {
+			found := e.At(strings.Index(expression, test.at))
+			if assert.NotNil(t, found) {
+				assert.Equal(t, test.token, found.Token)
+			}
+		})
+	}
+	assert.Nil(t, e.At(len(expression)+1))
+
+	tokens := func(exprs []*Expr) []string {
+		out := make([]string, len(exprs))
+		for i, c := range exprs {
+			out[i] = c.Token
+		}
+		return out
+	}
+	start := strings.Index(expression, "time")
+	assert.Equal(t, []string{"time", "today", "add", "1", "day", "year"}, tokens(e.Within(start, start+len("time.today.add(1, day).year"))))
+	assert.Equal(t, []string{"name", "contains", "a"}, tokens(e.Within(5, 22)))
+	assert.Empty(t, e.Within(6, 8))
+
+	// Conversions added while linking are not in the input.
+	e, err = sys.Parse(Options{RootType: typeContext, Expression: "user", ExpectedTypes: []TypeName{typeText}})
+	assert.NoError(t, err)
+	assert.Equal(t, "user.name", e.String())
+	assert.Same(t, e, e.At(4))
+	assert.Equal(t, []string{"user"}, tokens(e.Within(0, 4)))
+}
+
+func TestConstantEscapes(t *testing.T) {
+	tests := []struct {
+		expression string
+		token      string
+		message    string
+		start      int
+		end        int
+	}{
+		{expression: `'caf\u00e9'`, token: "café"},
+		{expression: `'\U0001F600!'`, token: "😀!"},
+		{expression: `'\x41\x62c'`, token: "Abc"},
+		{expression: `'tab\tquote\'slash\\'`, token: "tab\tquote'slash\\"},
+		{expression: `'\u00e'`, token: `\u00e`, message: "invalid escape \\u00e at (index: 1, line: 0, column: 1), expected 4 hex digits", start: 1, end: 6},
+		{expression: `'a\xg'`, token: `a\xg`, message: "invalid escape \\x at (index: 2, line: 0, column: 2), expected 2 hex digits", start: 2, end: 4},
+		{expression: `'ends \x4'`, token: `ends \x4`, message: "invalid escape \\x4 at (index: 6, line: 0, column: 6), expected 2 hex digits", start: 6, end: 9},
+		{expression: `'\UFFFFFFFF'`, token: `\UFFFFFFFF`, message: "invalid escape \\UFFFFFFFF at (index: 1, line: 0, column: 1), not a valid unicode character", start: 1, end: 11},
+		{expression: `'\uD800'`, token: `\uD800`, message: "invalid escape \\uD800 at (index: 1, line: 0, column: 1), not a valid unicode character", start: 1, end: 7},
+		{expression: `"say \"hi\""`, token: `say "hi"`},
+		{expression: `'\d+'`, token: `\d+`, message: "invalid escape \\d at (index: 1, line: 0, column: 1), unknown escape character", start: 1, end: 3},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := sys.Parse(Options{RootType: typeContext, Expression: test.expression, ExpectedTypes: []TypeName{typeText}})
+			assert.Equal(t, test.token, e.Token)
+			if test.message == "" {
+				assert.NoError(t, err)
+				reparsed, err := sys.Parse(Options{RootType: typeContext, Expression: e.String(), ExpectedTypes: []TypeName{typeText}})
+				assert.NoError(t, err)
+				assert.Equal(t, e.Token, reparsed.Token)
+				return
+			}
+			assert.ErrorIs(t, err, ErrSyntax)
+			assert.EqualError(t, err, test.message)
+			var parseError ParseError
+			if assert.ErrorAs(t, err, &parseError) {
+				assert.Equal(t, test.start, parseError.Start.Index)
+				assert.Equal(t, test.end, parseError.End.Index)
+				assert.Same(t, e, parseError.Expr)
+			}
+		})
+	}
+}
+
+func TestRawConstants(t *testing.T) {
+	expression := "user.name.contains('''Dear 'friend',\n  \\d+ \\n''').or(user.name.contains(\"\"\"it's\"\"\"))"
+	e, err := sys.Parse(Options{RootType: typeContext, Expression: expression})
+	assert.NoError(t, err)
+	contains := e.Next.Next
+	assert.Equal(t, "Dear 'friend',\n  \\d+ \\n", contains.Arguments[0].Token)
+	assert.Equal(t, "it's", contains.Next.Arguments[0].Last().Arguments[0].Token)
+	assert.Equal(t, Position{Index: 50, Line: 1, Column: 13}, contains.Next.Start)
+
+	reparsed, err := sys.Parse(Options{RootType: typeContext, Expression: e.String()})
+	assert.NoError(t, err)
+	assert.Equal(t, e.String(), reparsed.String())
+	assert.Equal(t, contains.Arguments[0].Token, reparsed.Next.Next.Arguments[0].Token)
+
+	_, err = sys.Parse(Options{RootType: typeContext, Expression: "user.name.contains(\"\"\"abc\"\")"})
+	assert.ErrorIs(t, err, ErrUnterminatedConstant)
+}
+
+func TestQuotedPaths(t *testing.T) {
+	fields := NewSystemRequired([]Type{{
+		Name:  "text",
+		Parse: func(x string) (any, error) { return x, nil },
+	}, {
+		Name: "row",
+		Values: []Value{
+			{Path: "first name", Type: "row"},
+			{Path: "Cost: $USD", Type: "text"},
+			{Path: "Price (USD)", Type: "text"},
+			{Path: "a,b", Type: "text"},
+			{Path: "a`b", Type: "text"},
+			{Path: "upper", Type: "text"},
+		},
+	}})
+
+	tests := []struct {
+		expression string
+		serialized string
+	}{
+		{expression: "`first name`.`Cost: $USD`", serialized: "`first name`.`Cost: $USD`"},
+		{expression: "`first name`.upper", serialized: "`first name`.upper"},
+		{expression: "`first name`.`Price (USD)`", serialized: "`first name`.`Price (USD)`"},
+		{expression: "`first name`.`a,b`", serialized: "`first name`.`a,b`"},
+		{expression: "`upper`", serialized: "upper"},
+		{expression: "`first name`.`a\\`b`", serialized: "`first name`.`a\\`b`"},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := fields.Parse(Options{RootType: "row", Expression: test.expression})
+			assert.NoError(t, err)
+			assert.NotNil(t, e.Last().Value)
+			assert.Equal(t, test.serialized, e.String())
+
+			reparsed, err := fields.Parse(Options{RootType: "row", Expression: e.String()})
+			assert.NoError(t, err)
+			assert.Equal(t, e.String(), reparsed.String())
+		})
+	}
+
+	raw, err := fields.Parse(Options{RootType: "row", Expression: "`a\\b c`", ExpectedTypes: []TypeName{"text"}})
+	assert.NoError(t, err)
+	assert.True(t, raw.Constant)
+	assert.Equal(t, `a\b c`, raw.Parsed)
+
+	raw, err = sys.Parse(Options{RootType: typeContext, Expression: "user.name.contains(`C:\\temp\\d+\\.txt`)"})
+	assert.NoError(t, err)
+	assert.Equal(t, `C:\temp\d+\.txt`, raw.Last().Arguments[0].Parsed)
+	assert.Equal(t, `user.name.contains('C:\\temp\\d+\\.txt')`, raw.String())
+
+	_, err = sys.Parse(Options{RootType: typeContext, Expression: "user.`C:\\temp`"})
+	assert.ErrorIs(t, err, ErrUnknownValue)
+
+	_, err = fields.Parse(Options{RootType: "row", Expression: "`first name"})
+	assert.ErrorIs(t, err, ErrSyntax)
+	assert.EqualError(t, err, "quoted path starting at (index: 0, line: 0, column: 0) did not have a terminating `")
+
+	_, err = fields.Parse(Options{RootType: "row", Expression: "first name"})
+	assert.ErrorIs(t, err, ErrUnknownValue)
+
+	_, err = NewSystem([]Type{{Name: "row", Values: []Value{{Path: "first name ", Type: "row"}}}})
+	assert.ErrorIs(t, err, ErrInvalidPath)
+	_, err = NewSystem([]Type{{Name: "row", Values: []Value{{Path: "price.usd", Type: "row"}}}})
+	assert.ErrorIs(t, err, ErrInvalidPath)
+}
+
+func TestAllValues(t *testing.T) {
+	linked := func(values []LinkedValue, path string) LinkedValue {
+		for _, v := range values {
+			if v.Value.Path == path {
+				return v
+			}
+		}
+		return LinkedValue{}
+	}
+
+	dateTime := sys.Type(typeDateTime)
+	values := dateTime.AllValues()
+	assert.Len(t, values, len(dateTime.Values))
+	for _, v := range values {
+		assert.Same(t, dateTime.Value(v.Value.Path), v.Value)
+	}
+
+	minute := linked(values, "minute")
+	assert.Equal(t, []string{"minute", "min"}, minute.Names)
+	assert.Same(t, sys.Type(typeInt), minute.Type)
+	assert.Empty(t, minute.ParameterTypes)
+
+	add := linked(values, "add")
+	assert.Same(t, sys.Type(typeDate), add.Type)
+	assert.Equal(t, []*Type{sys.Type(typeInt), sys.Type(typeDuration)}, add.ParameterTypes)
+
+	then := linked(sys.Type(typeBool).AllValues(), "then")
+	assert.Nil(t, then.Type)
+	assert.Equal(t, []*Type{nil, nil}, then.ParameterTypes)
+
+	// Values added by capabilities follow the values of the type.
+	numbers, err := NewSystemWithOptions([]Type{{
+		Name:    "number",
+		Ordered: true,
+		Values:  []Value{{Path: "abs", Type: "number"}},
+	}, {
+		Name: "bool",
+	}}, SystemOptions{BoolType: "bool"})
+	assert.NoError(t, err)
+	values = numbers.Type("number").AllValues()
+	assert.Equal(t, "abs", values[0].Value.Path)
+	assert.Same(t, numbers.Type("bool"), linked(values, ">=").Type)
+
+	assert.Equal(t, []string{"name"}, (&Type{Values: []Value{{Path: "name"}}}).AllValues()[0].Names)
+}
+
+func TestIdentifierChars(t *testing.T) {
+	types := []Type{{
+		Name:  "text",
+		Parse: func(x string) (any, error) { return x, nil },
+		Values: []Value{
+			{Path: "-", Type: "text", Parameters: []Parameter{{Name: "suffix", Type: "text"}}},
+		},
+	}, {
+		Name: "row",
+		Values: []Value{
+			{Path: "café", Type: "text"},
+			{Path: "first-name", Type: "text"},
+			{Path: "$total", Type: "text"},
+		},
+	}}
+	fields, err := NewSystemWithOptions(types, SystemOptions{Identifiers: IdentifierChars{Unicode: true, Extra: "-$"}})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		expression string
+		serialized string
+	}{
+		{expression: "café", serialized: "`café`"},
+		{expression: "first-name.-(:prénom)", serialized: "`first-name`-(:prénom)"},
+		{expression: "$total", serialized: "$total"},
+		{expression: "`first-name`", serialized: "`first-name`"},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := fields.Parse(Options{RootType: "row", Expression: test.expression, Parameters: map[string]TypeName{"prénom": "text"}})
+			assert.NoError(t, err)
+			assert.NotNil(t, e.Value)
+			assert.Equal(t, test.serialized, e.String())
+		})
+	}
+
+	doc, err := fields.ParseDocument("full-name: first-name.-(café)\nlabel: :full-name", Options{RootType: "row"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"full-name"}, doc.Expressions[1].References)
+
+	ascii := NewSystemRequired(types)
+	_, err = ascii.Parse(Options{RootType: "row", Expression: "first-name"})
+	assert.ErrorIs(t, err, ErrUnknownValue)
+	_, err = ascii.Parse(Options{RootType: "row", Expression: "`first-name`"})
+	assert.NoError(t, err)
+
+	_, err = NewSystemWithOptions(types, SystemOptions{Identifiers: IdentifierChars{Extra: "-."}})
+	assert.EqualError(t, err, "'.' can't be an identifier character")
+
+	german, err := NewSystemWithOptions([]Type{{
+		Name:  "size",
+		Enums: []string{"größer", "kleiner"},
+	}, {
+		Name:   "row",
+		Values: []Value{{Path: "größe", Type: "size"}, {Path: "maß", Type: "size"}},
+	}}, SystemOptions{Identifiers: IdentifierChars{Is: func(r rune) bool { return strings.ContainsRune("äöüß", unicode.ToLower(r)) }}})
+	assert.NoError(t, err)
+	e, err := german.Parse(Options{RootType: "row", Expression: "GRÖßE", ExpectedTypes: []TypeName{"size"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "größe", e.Value.Path)
+	e, err = german.Parse(Options{RootType: "row", Expression: "Größer", ExpectedTypes: []TypeName{"size"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "größer", e.Parsed)
+	_, err = german.Parse(Options{RootType: "row", Expression: "maß.größe"})
+	assert.ErrorIs(t, err, ErrUnknownValue)
+	_, err = german.Parse(Options{RootType: "row", Expression: "café", ExpectedTypes: []TypeName{"size"}})
+	assert.ErrorIs(t, err, ErrUnknownValue)
+
+	_, err = NewSystemWithOptions(types, SystemOptions{Identifiers: IdentifierChars{Is: unicode.IsPrint}})
+	assert.EqualError(t, err, "' ' can't be an identifier character")
+}
+
+func TestNumericConstants(t *testing.T) {
+	numbers := NewSystemRequired([]Type{{
+		Name:    "number",
+		Numeric: true,
+		Parse:   func(x string) (any, error) { return strconv.ParseFloat(x, 64) },
+	}, {
+		Name:  "code",
+		Parse: func(x string) (any, error) { return strconv.Atoi(x) },
+	}, {
+		Name: "order",
+		Values: []Value{
+			{Path: "total", Type: "number"},
+			{Path: "code", Type: "code"},
+		},
+	}})
+
+	tests := []struct {
+		expression string
+		parsed     any
+		kind       error
+	}{
+		{expression: "1_000_000", parsed: 1000000.0},
+		{expression: "'-1_000.000_5'", parsed: -1000.0005},
+		{expression: "'1,000'", parsed: 1000.0},
+		{expression: "'12,345,678.25'", parsed: 12345678.25},
+		{expression: "'1,00'", kind: ErrTypeMismatch},
+		{expression: "1__000", kind: ErrTypeMismatch},
+		{expression: "_1000", kind: ErrTypeMismatch},
+		{expression: "0xFF", parsed: 255.0},
+		{expression: "'-0x1_00'", parsed: -256.0},
+		{expression: "0b1010", parsed: 10.0},
+		{expression: "0o755", parsed: 493.0},
+		{expression: "0xFFFFFFFFFFFFFFFF", parsed: 18446744073709551615.0},
+		{expression: "0b102", kind: ErrTypeMismatch},
+		{expression: "0o8", kind: ErrTypeMismatch},
+		{expression: "-5", parsed: -5.0},
+		{expression: "+5", parsed: 5.0},
+		{expression: "-1_000 ", parsed: -1000.0},
+		{expression: "-2.5e3\n", parsed: -2500.0},
+		{expression: "-5a", kind: ErrTypeMismatch},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := numbers.Parse(Options{RootType: "order", Expression: test.expression, ExpectedTypes: []TypeName{"number"}})
+			if test.kind != nil {
+				assert.ErrorIs(t, err, test.kind)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.parsed, e.Parsed)
+		})
+	}
+
+	_, err := numbers.Parse(Options{RootType: "order", Expression: "1_000", ExpectedTypes: []TypeName{"code"}})
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+
+	e, err := sys.Parse(Options{RootType: typeContext, Expression: "time.today.add(-5 , day)"})
+	assert.NoError(t, err)
+	assert.Equal(t, "time.today.add('-5','day')", e.String())
+	assert.Equal(t, -5, e.Last().Arguments[0].Parsed)
+}
+
+func TestNoAutoCast(t *testing.T) {
+	e, err := sys.Parse(Options{RootType: typeContext, Expression: "user", ExpectedTypes: []TypeName{typeText}})
+	assert.NoError(t, err)
+	assert.Equal(t, "user.name", e.String())
+
+	e, err = sys.Parse(Options{RootType: typeContext, Expression: "user", ExpectedTypes: []TypeName{typeText}, NoAutoCast: true})
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+	assert.EqualError(t, err, "expected type(s) text but was given user instead")
+	assert.Equal(t, "user", e.String())
+
+	e, err = sys.Parse(Options{RootType: typeContext, Expression: "user.name.contains(time.today.dayOfWeek)", NoAutoCast: true})
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+	assert.Equal(t, "user.name.contains(time.today.dayOfWeek)", e.String())
+
+	_, err = sys.Parse(Options{RootType: typeContext, Expression: "user.name.isUpper.then('a', user.createDate.minute)", NoAutoCast: true})
+	assert.EqualError(t, err, "expected type text but was given int instead")
+
+	_, err = sys.Parse(Options{RootType: typeContext, Expression: "user.name.isUpper.then('a', 'b')", NoAutoCast: true})
+	assert.NoError(t, err)
+}
+
+func TestParseConstant(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []TypeName
+		parsed   any
+		typeName TypeName
+		err      string
+	}{
+		{input: "12", parsed: 12, typeName: typeInt},
+		{input: "12", expected: []TypeName{typeText}, parsed: "12", typeName: typeText},
+		{input: "2024-01-02", parsed: time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC), typeName: typeDate},
+		{input: "monday", parsed: "monday", typeName: typeDayOfWeek},
+		{input: "day", expected: []TypeName{typeDayOfWeek, typeDuration}, parsed: "day", typeName: typeDuration},
+		{input: "monday", expected: []TypeName{typeInt}, err: "constant monday did not match expected type(s) int"},
+		{input: "", err: "undefined expression"},
+		{input: "12", expected: []TypeName{"missing"}, err: "undefined expected type: missing"},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			parsed, parsedType, err := sys.ParseConstant(test.input, test.expected...)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.parsed, parsed)
+			assert.Equal(t, test.typeName, parsedType.Name)
+		})
+	}
+
+	months := NewSystemRequired([]Type{{
+		Name: "month",
+		Parse: func(x string) (any, error) {
+			return nil, NewConstantError(5, 2, "invalid month")
+		},
+	}})
+	_, _, err := months.ParseConstant("2024-13", "month")
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+	var parseError ParseError
+	assert.ErrorAs(t, err, &parseError)
+	assert.Equal(t, &Position{Index: 5, Column: 5}, parseError.Start)
+	assert.Equal(t, &Position{Index: 7, Column: 7}, parseError.End)
+
+	_, _, err = months.ParseConstant("2024-13")
+	assert.ErrorIs(t, err, ErrUnknownValue)
+}
+
 func runCompiler[T any](call func(v T, args []any) (any, error)) Compiler[Run] {
 	return func(e *Expr, root *Type, previous Run, arguments []Run) (Run, error) {
 		return func(root any) (any, error) {
@@ -674,3 +1296,40 @@ func mapValueCompiler(keys ...string) ValueCompilers[Run] {
 	}
 	return vc
 }
+
+func TestLayouts(t *testing.T) {
+	layouts := NewSystemRequired([]Type{{
+		Name:    "day",
+		Layouts: []string{time.RFC3339, time.DateOnly, "01/02/2006", "Jan 2 2006"},
+	}, {
+		Name: "booking",
+		Values: []Value{
+			{Path: "after", Type: "day", Parameters: []Parameter{
+				{Name: "day", Type: "day"},
+			}},
+		},
+	}})
+
+	tests := []struct {
+		constant string
+		layout   string
+		parsed   time.Time
+	}{
+		{constant: "2024-03-05", layout: time.DateOnly, parsed: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{constant: "03/05/2024", layout: "01/02/2006", parsed: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{constant: "Mar 5 2024", layout: "Jan 2 2006", parsed: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{constant: "2024-03-05T10:00:00Z", layout: time.RFC3339, parsed: time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		e, err := layouts.Parse(Options{RootType: "booking", Expression: "after('" + test.constant + "')"})
+		assert.NoError(t, err)
+		arg := e.Arguments[0]
+		assert.Equal(t, test.layout, arg.Layout)
+		assert.Equal(t, test.parsed, arg.Parsed)
+	}
+
+	_, err := layouts.Parse(Options{RootType: "booking", Expression: "after('5th of March')"})
+	assert.ErrorContains(t, err, "constant 5th of March did not match expected type(s) day")
+
+	assert.Equal(t, []*Type{layouts.Type("day")}, layouts.ParseOrder())
+}
@@ -0,0 +1,68 @@
+package texpr
+
+import (
+	"fmt"
+)
+
+// A kind of decision made while linking an expression, see TraceEvent.
+type TraceKind string
+
+const (
+	// A token was linked to a value on its parent type by its path.
+	TraceValue TraceKind = "value"
+	// A token was linked to a value on its parent type by one of its aliases.
+	TraceAlias TraceKind = "alias"
+	// A bind parameter was given its type from the options.
+	TraceBind TraceKind = "bind"
+	// A placeholder took on the expected type.
+	TracePlaceholder TraceKind = "placeholder"
+	// A list literal was given the list type of its elements.
+	TraceList TraceKind = "list"
+	// A constant was recognized as a type. TraceEvent.Tried has the types which did not parse it first.
+	TraceConstant TraceKind = "constant"
+	// More than one type parsed a constant, TraceEvent.Candidates has the types and TraceEvent.Type
+	// is the type chosen, see SystemOptions.ResolveAmbiguity.
+	TraceAmbiguous TraceKind = "ambiguous"
+	// A constant which did not match the expected types was parsed as another type and converted to an
+	// expected type.
+	TraceCoercion TraceKind = "coercion"
+	// A conversion to an expected type was added after an expression (see Type.As).
+	TraceConversion TraceKind = "conversion"
+	// An argument was added for a missing parameter with its default value.
+	TraceDefault TraceKind = "default"
+	// A token could not be linked to a value, bind parameter, or constant.
+	TraceUnknown TraceKind = "unknown"
+)
+
+// A decision made while linking an expression, given to Options.Trace so system authors can see why an
+// expression resolved the way it did.
+type TraceEvent struct {
+	// The kind of decision.
+	Kind TraceKind
+	// The expression the decision was made for. For conversions this is the conversion added.
+	Expr *Expr
+	// The type the expression was given, if any.
+	Type *Type
+	// The value the expression was linked to, if any. For conversions this is the conversion value.
+	Value *Value
+	// The types which were tried before the type of a constant, in the order they were tried.
+	Tried []*Type
+	// The types which parsed an ambiguous constant, in the order they were tried.
+	Candidates []*Type
+	// A human readable description of the decision.
+	Message string
+}
+
+// The description of the decision.
+func (e TraceEvent) String() string {
+	return fmt.Sprintf("%s: %s", e.Kind, e.Message)
+}
+
+// Gives the event to the trace of the options with the formatted message, if there is a trace.
+func (ctx *linkContext) tracef(event TraceEvent, format string, args ...any) {
+	if ctx.trace == nil {
+		return
+	}
+	event.Message = fmt.Sprintf(format, args...)
+	ctx.trace(event)
+}
@@ -0,0 +1,98 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrace(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   []TypeName
+		events     []string
+	}{
+		{
+			expression: "user.createDate.min",
+			expected:   []TypeName{typeText},
+			events: []string{
+				"value: user is the value context.user",
+				"value: createDate is the value user.createDate",
+				"alias: min is an alias of the value dateTime.minute",
+				"conversion: text was added to convert int to the expected type text",
+			},
+		},
+		{
+			expression: "time.today.add(2, day)",
+			events: []string{
+				"value: time is the value context.time",
+				"value: today is the value timePackage.today",
+				"value: add is the value date.add",
+				"constant: constant 2 is int",
+				"constant: constant day is duration",
+			},
+		},
+		{
+			expression: ":name.contains(user)",
+			events: []string{
+				"bind: parameter :name is text",
+				"value: contains is the value text.contains",
+				"value: user is the value context.user",
+				"conversion: name was added to convert user to the expected type text",
+			},
+		},
+		{
+			expression: "user.name.isUpper.then('a', 'b')",
+			events: []string{
+				"value: user is the value context.user",
+				"value: name is the value user.name",
+				"value: isUpper is the value text.isUpper",
+				"value: then is the value bool.then",
+				"constant: constant a is text after dayOfWeek, duration, dateTime, date, time, bool, int did not parse it",
+				"constant: constant b is text after dayOfWeek, duration, dateTime, date, time, bool, int did not parse it",
+			},
+		},
+		{
+			expression: "?.add(1, day)",
+			expected:   []TypeName{typeDate},
+			events: []string{
+				"placeholder: placeholder ? is the expected type date",
+				"value: add is the value date.add",
+				"constant: constant 1 is int",
+				"constant: constant day is duration",
+			},
+		},
+		{
+			expression: "user.nam",
+			events: []string{
+				"value: user is the value context.user",
+				"unknown: nam is not a value of user",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			events := make([]string, 0)
+			sys.Parse(Options{
+				RootType:      typeContext,
+				Expression:    test.expression,
+				ExpectedTypes: test.expected,
+				Parameters:    map[string]TypeName{"name": typeText},
+				Trace: func(event TraceEvent) {
+					events = append(events, event.String())
+				},
+			})
+			assert.Equal(t, test.events, events)
+		})
+	}
+
+	var constant TraceEvent
+	_, err := sys.Parse(Options{RootType: typeContext, Expression: "?.add(1, day)", ExpectedTypes: []TypeName{typeDate}, Trace: func(event TraceEvent) {
+		if event.Kind == TraceConstant && event.Expr.Token == "day" {
+			constant = event
+		}
+	}})
+	assert.NoError(t, err)
+	assert.Same(t, sys.Type(typeDuration), constant.Type)
+	assert.Empty(t, constant.Tried)
+}
@@ -0,0 +1,180 @@
+package texpr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A boolean expression has more conditions than a truth table is generated for, see TruthOptions.MaxConditions.
+var ErrTooManyConditions = errors.New("too many conditions")
+
+// The most conditions a truth table can be generated for, since the rows are counted with an int.
+const maxTruthConditions = 62
+
+// The options of AnalyzeTruth.
+type TruthOptions struct {
+	// The most conditions a truth table is generated for, which has 2^MaxConditions rows. By default 10,
+	// and at most 62.
+	MaxConditions int
+}
+
+// A part of a boolean expression, see TruthAnalysis.
+type TruthCondition struct {
+	// The first expression of the chain.
+	Expr *Expr
+	// The last expression of the chain, the chain after it is not part of the condition.
+	Last *Expr
+	// The chain from Expr to Last as a string.
+	Text string
+}
+
+// A row of a truth table.
+type TruthRow struct {
+	// The values of the conditions, in the order of TruthAnalysis.Conditions.
+	Values []bool
+	// The result of the expression for the values.
+	Result bool
+}
+
+// The analysis of a boolean expression built with the values given by LogicValues, see AnalyzeTruth.
+type TruthAnalysis struct {
+	// The atomic conditions of the expression, the operands of the logic values which are not logic
+	// values themselves. Conditions with the same text are the same condition.
+	Conditions []TruthCondition
+	// Every combination of the values of the conditions and the result of the expression for them.
+	Rows []TruthRow
+	// If the expression is true for every row.
+	Tautology bool
+	// If the expression is false for every row.
+	Contradiction bool
+	// The operands of the logic values which are never evaluated because the operands before them
+	// always determine the result, like b in `a.or(a.not, b)`.
+	Unreachable []TruthCondition
+}
+
+// An operand in the logic tree of an expression, which is an operation or a condition.
+type truthNode struct {
+	condition TruthCondition
+	operation LogicOperation
+	operands  []*truthNode
+	index     int
+}
+
+// Enumerates the atomic conditions of the boolean expression, generates its truth table, and finds
+// tautologies, contradictions, and unreachable operands. When there are more conditions than the
+// options allow the analysis only has the conditions and an ErrTooManyConditions error is returned.
+func AnalyzeTruth(e *Expr, options TruthOptions) (*TruthAnalysis, error) {
+	if options.MaxConditions == 0 {
+		options.MaxConditions = 10
+	}
+	if options.MaxConditions > maxTruthConditions {
+		options.MaxConditions = maxTruthConditions
+	}
+	analysis := &TruthAnalysis{
+		Conditions:  make([]TruthCondition, 0),
+		Rows:        make([]TruthRow, 0),
+		Unreachable: make([]TruthCondition, 0),
+	}
+	root, conditions, err := truthTree(e)
+	if err != nil {
+		return nil, err
+	}
+	analysis.Conditions = conditions
+	if len(analysis.Conditions) > options.MaxConditions {
+		return analysis, fmt.Errorf("%w: %d conditions is more than the limit of %d", ErrTooManyConditions, len(analysis.Conditions), options.MaxConditions)
+	}
+
+	reached := make(map[*truthNode]bool)
+	analysis.Tautology, analysis.Contradiction = true, true
+	for combination := 0; combination < 1<<len(analysis.Conditions); combination++ {
+		row := TruthRow{Values: make([]bool, len(analysis.Conditions))}
+		for i := range row.Values {
+			row.Values[i] = combination&(1<<(len(row.Values)-1-i)) != 0
+		}
+		row.Result = root.evaluate(row.Values, reached)
+		analysis.Tautology = analysis.Tautology && row.Result
+		analysis.Contradiction = analysis.Contradiction && !row.Result
+		analysis.Rows = append(analysis.Rows, row)
+	}
+
+	var unreachable func(node *truthNode)
+	unreachable = func(node *truthNode) {
+		if !reached[node] {
+			analysis.Unreachable = append(analysis.Unreachable, node.condition)
+			return
+		}
+		for _, operand := range node.operands {
+			unreachable(operand)
+		}
+	}
+	unreachable(root)
+	return analysis, nil
+}
+
+// Returns the logic tree of the boolean expression and its distinct conditions in the order they appear.
+func truthTree(e *Expr) (*truthNode, []TruthCondition, error) {
+	conditions := make([]TruthCondition, 0)
+	indices := make(map[string]int)
+	var build func(first *Expr, last *Expr) (*truthNode, error)
+	build = func(first *Expr, last *Expr) (*truthNode, error) {
+		node := &truthNode{condition: TruthCondition{Expr: first, Last: last, Text: chainPrefix(first, last).String()}}
+		op, operands := logicOperands(first, last)
+		if op == "" {
+			if boolType := truthBoolType(e); boolType != "" && last.Type != nil && last.Type.Name != boolType {
+				return nil, fmt.Errorf("condition %s is %s instead of %s", node.condition.Text, last.Type.Name, boolType)
+			}
+			index, exists := indices[node.condition.Text]
+			if !exists {
+				index = len(conditions)
+				indices[node.condition.Text] = index
+				conditions = append(conditions, node.condition)
+			}
+			node.index = index
+			return node, nil
+		}
+		node.operation = op
+		for _, operand := range operands {
+			child, err := build(operand.first, operand.last)
+			if err != nil {
+				return nil, err
+			}
+			node.operands = append(node.operands, child)
+		}
+		return node, nil
+	}
+	root, err := build(e, e.Last())
+	return root, conditions, err
+}
+
+// Returns the result of the node for the values of the conditions, evaluating operands in order until
+// the result is known. The nodes evaluated are added to reached.
+func (node *truthNode) evaluate(values []bool, reached map[*truthNode]bool) bool {
+	reached[node] = true
+	switch node.operation {
+	case LogicNot:
+		return !node.operands[0].evaluate(values, reached)
+	case LogicAnd:
+		for _, operand := range node.operands {
+			if !operand.evaluate(values, reached) {
+				return false
+			}
+		}
+		return true
+	case LogicOr:
+		for _, operand := range node.operands {
+			if operand.evaluate(values, reached) {
+				return true
+			}
+		}
+		return false
+	}
+	return values[node.index]
+}
+
+// Returns the boolean type of the system of the expression, if any.
+func truthBoolType(e *Expr) TypeName {
+	if e.System == nil {
+		return ""
+	}
+	return e.System.options.BoolType
+}
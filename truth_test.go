@@ -0,0 +1,97 @@
+package texpr
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type truthAccount struct {
+	Age      int
+	Verified bool
+	Admin    bool
+}
+
+func TestAnalyzeTruth(t *testing.T) {
+	r, err := NewReflect(ReflectOptions{
+		BoolType: "bool",
+		Types: map[reflect.Type]Type{
+			TypeOf[bool](): {Name: "bool", Values: LogicValues("bool")},
+			TypeOf[int](): {
+				Name:    "int",
+				Ordered: true,
+				Parse: func(x string) (any, error) {
+					return strconv.Atoi(x)
+				},
+			},
+			TypeOf[truthAccount](): {},
+		},
+	})
+	assert.NoError(t, err)
+	root := NameOf[truthAccount]()
+
+	tests := []struct {
+		expression    string
+		conditions    []string
+		results       []bool
+		tautology     bool
+		contradiction bool
+		unreachable   []string
+	}{
+		{expression: "verified.and(admin)", conditions: []string{"verified", "admin"}, results: []bool{false, false, false, true}},
+		{expression: "verified.or(verified.not, admin)", conditions: []string{"verified", "admin"}, results: []bool{true, true, true, true}, tautology: true, unreachable: []string{"admin"}},
+		{expression: "admin.and(admin.not)", conditions: []string{"admin"}, results: []bool{false, false}, contradiction: true},
+		{expression: "age.>=(18).or(admin).and(verified).not", conditions: []string{"age>=('18')", "admin", "verified"}, results: []bool{true, true, true, false, true, false, true, false}},
+		{expression: "admin.or(admin.or(verified).not, verified)", conditions: []string{"admin", "verified"}, results: []bool{true, true, true, true}, tautology: true},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := r.Parse(Options{RootType: root, Expression: test.expression})
+			assert.NoError(t, err)
+			analysis, err := AnalyzeTruth(e, TruthOptions{})
+			assert.NoError(t, err)
+
+			conditions := make([]string, len(analysis.Conditions))
+			for i, c := range analysis.Conditions {
+				conditions[i] = c.Text
+			}
+			assert.Equal(t, test.conditions, conditions)
+			results := make([]bool, len(analysis.Rows))
+			for i, row := range analysis.Rows {
+				results[i] = row.Result
+			}
+			assert.Equal(t, test.results, results)
+			assert.Equal(t, test.tautology, analysis.Tautology)
+			assert.Equal(t, test.contradiction, analysis.Contradiction)
+			unreachable := make([]string, 0)
+			for _, c := range analysis.Unreachable {
+				unreachable = append(unreachable, c.Text)
+			}
+			if test.unreachable == nil {
+				test.unreachable = []string{}
+			}
+			assert.Equal(t, test.unreachable, unreachable)
+		})
+	}
+
+	e, err := r.Parse(Options{RootType: root, Expression: "verified.and(admin, age.>(1), age.>(2))"})
+	assert.NoError(t, err)
+	analysis, err := AnalyzeTruth(e, TruthOptions{MaxConditions: 3})
+	assert.ErrorIs(t, err, ErrTooManyConditions)
+	assert.EqualError(t, err, "too many conditions: 4 conditions is more than the limit of 3")
+	assert.Len(t, analysis.Conditions, 4)
+	assert.Empty(t, analysis.Rows)
+
+	conditions := make([]string, 63)
+	for i := range conditions {
+		conditions[i] = fmt.Sprintf("age.>(%d)", i)
+	}
+	e, err = r.Parse(Options{RootType: root, Expression: "verified.and(" + strings.Join(conditions, ", ") + ")"})
+	assert.NoError(t, err)
+	_, err = AnalyzeTruth(e, TruthOptions{MaxConditions: 64})
+	assert.EqualError(t, err, "too many conditions: 64 conditions is more than the limit of 62")
+}
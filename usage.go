@@ -0,0 +1,152 @@
+package texpr
+
+import (
+	"sort"
+)
+
+// How often a value is used across a corpus of expressions, see System.Usage.
+type ValueUsage struct {
+	// The type the value is on.
+	Type *Type
+	// The value used.
+	Value *Value
+	// The number of times the value is used, counting every use in each expression.
+	Count int
+	// The IDs of the stored expressions which use the value, in the order they were given.
+	Expressions []string
+}
+
+// How often a type is produced across a corpus of expressions, see System.Usage.
+type TypeUsage struct {
+	// The type produced.
+	Type *Type
+	// The number of expressions, constants, and parameters which produce the type.
+	Count int
+	// The IDs of the stored expressions which produce the type, in the order they were given.
+	Expressions []string
+}
+
+// The usage of the values and types of a system across a corpus of expressions.
+type UsageReport struct {
+	// The values used, from most used to least used.
+	Values []ValueUsage
+	// The types produced, from most used to least used.
+	Types []TypeUsage
+	// The values of the system which are never used, in the order the types and values were defined.
+	Unused []ValueUsage
+	// The IDs of the stored expressions which could not be parsed and were not counted.
+	Invalid []string
+}
+
+// Returns the usage of the value on the type, which has a zero count when it's not used.
+func (r UsageReport) Value(typeName TypeName, path string) ValueUsage {
+	for _, usage := range r.Values {
+		if usage.Type.Name == typeName && usage.Type.Value(path) == usage.Value {
+			return usage
+		}
+	}
+	return ValueUsage{}
+}
+
+// Returns the number of times each value is used keyed by the name of its type and then its path,
+// which can be used with systems other than the one which made the report, see System.CompleteByUsage.
+func (r UsageReport) Counts() map[TypeName]map[string]int {
+	counts := make(map[TypeName]map[string]int)
+	for _, usage := range r.Values {
+		if counts[usage.Type.Name] == nil {
+			counts[usage.Type.Name] = make(map[string]int)
+		}
+		counts[usage.Type.Name][usage.Value.Path] += usage.Count
+	}
+	return counts
+}
+
+// Returns how often each value and type are used by the stored expressions, and which values are
+// never used. Conversions added to meet expected types are counted as uses of the conversion value.
+// Expressions which don't parse are listed as invalid and not counted.
+func (sys System) Usage(corpus []StoredExpression) UsageReport {
+	values := make(map[*Value]*ValueUsage)
+	valueOrder := make([]*Value, 0)
+	types := make(map[*Type]*TypeUsage)
+	typeOrder := make([]*Type, 0)
+	report := UsageReport{Invalid: make([]string, 0)}
+
+	for _, stored := range corpus {
+		e, err := sys.Parse(stored.Options)
+		if err != nil {
+			report.Invalid = append(report.Invalid, stored.ID)
+			continue
+		}
+		usageWalk(e, func(c *Expr, parent *Type) {
+			if c.Type != nil && c.Type != Unknown {
+				usage := types[c.Type]
+				if usage == nil {
+					usage = &TypeUsage{Type: c.Type}
+					types[c.Type] = usage
+					typeOrder = append(typeOrder, c.Type)
+				}
+				usage.Count++
+				usage.Expressions = usageAppend(usage.Expressions, stored.ID)
+			}
+			if c.Value != nil && !c.Constant && !c.Bind && parent != nil {
+				usage := values[c.Value]
+				if usage == nil {
+					usage = &ValueUsage{Type: parent, Value: c.Value}
+					values[c.Value] = usage
+					valueOrder = append(valueOrder, c.Value)
+				}
+				usage.Count++
+				usage.Expressions = usageAppend(usage.Expressions, stored.ID)
+			}
+		})
+	}
+
+	report.Values = make([]ValueUsage, len(valueOrder))
+	for i, v := range valueOrder {
+		report.Values[i] = *values[v]
+	}
+	sort.SliceStable(report.Values, func(i, j int) bool {
+		return report.Values[i].Count > report.Values[j].Count
+	})
+
+	report.Types = make([]TypeUsage, len(typeOrder))
+	for i, t := range typeOrder {
+		report.Types[i] = *types[t]
+	}
+	sort.SliceStable(report.Types, func(i, j int) bool {
+		return report.Types[i].Count > report.Types[j].Count
+	})
+
+	report.Unused = make([]ValueUsage, 0)
+	for _, t := range sys.types {
+		for i := range t.Values {
+			if v := &t.Values[i]; values[v] == nil {
+				report.Unused = append(report.Unused, ValueUsage{Type: t, Value: v})
+			}
+		}
+	}
+
+	return report
+}
+
+// Calls fn for every expression in the chain and its arguments with the type the expression is on.
+func usageWalk(e *Expr, fn func(c *Expr, parent *Type)) {
+	for c := e; c != nil; c = c.Next {
+		parent := c.ParentType
+		if c.Prev != nil {
+			parent = c.Prev.Type
+		}
+		fn(c, parent)
+		for _, arg := range c.Arguments {
+			usageWalk(arg, fn)
+		}
+	}
+}
+
+// Appends the ID to the IDs if it's not already the last ID.
+func usageAppend(ids []string, id string) []string {
+	if len(ids) > 0 && ids[len(ids)-1] == id {
+		return ids
+	}
+	return append(ids, id)
+}
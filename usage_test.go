@@ -0,0 +1,40 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsage(t *testing.T) {
+	report := sys.Usage([]StoredExpression{
+		{ID: "a", Options: Options{RootType: typeUser, Expression: "name.lower.contains(name)"}},
+		{ID: "b", Options: Options{RootType: typeUser, Expression: "name.upper"}},
+		{ID: "c", Options: Options{RootType: typeUser, Expression: "name.missing"}},
+		{ID: "d", Options: Options{RootType: typeUser, Expression: "name.len", ExpectedTypes: []TypeName{typeText}}},
+	})
+
+	name := report.Value(typeUser, "name")
+	assert.Equal(t, 4, name.Count)
+	assert.Equal(t, []string{"a", "b", "d"}, name.Expressions)
+	assert.Equal(t, "name", report.Values[0].Value.Path)
+
+	length := report.Value(typeText, "len")
+	assert.Equal(t, "length", length.Value.Path)
+	assert.Equal(t, []string{"d"}, length.Expressions)
+	assert.Equal(t, 1, report.Value(typeInt, "text").Count)
+	assert.Equal(t, 0, report.Value(typeText, "isLower").Count)
+
+	assert.Equal(t, typeText, report.Types[0].Type.Name)
+	assert.Equal(t, []string{"a", "b", "d"}, report.Types[0].Expressions)
+	assert.Equal(t, []string{"c"}, report.Invalid)
+
+	unused := make(map[string]bool)
+	for _, usage := range report.Unused {
+		unused[string(usage.Type.Name)+"."+usage.Value.Path] = true
+	}
+	assert.True(t, unused["text.isLower"])
+	assert.True(t, unused["user.createDate"])
+	assert.False(t, unused["user.name"])
+	assert.False(t, unused["text.contains"])
+}
@@ -0,0 +1,58 @@
+package texpr
+
+// Why a value may produce a different result each time it's evaluated with the same root and parameters.
+type Volatility string
+
+const (
+	// The value always produces the same result for the same root, parameters, and arguments.
+	VolatilityNone Volatility = ""
+	// The value depends on the current time, like `now`.
+	VolatilityTime Volatility = "time"
+	// The value is random, like `random` or a generated identifier.
+	VolatilityRandom Volatility = "random"
+	// The value depends on state outside of the root, like a database or service lookup.
+	VolatilityExternal Volatility = "external"
+)
+
+// An expression in a chain or argument which uses a volatile value, see Expr.Volatile.
+type VolatileExpr struct {
+	// The expression which uses the value.
+	Expr *Expr
+	// The volatility of the value.
+	Volatility Volatility
+}
+
+// Returns whether the expression always produces the same result for the same root and parameters,
+// which means its result can be cached or precomputed. This is false when any value used in the
+// chain or its arguments has a Volatility.
+func (e *Expr) IsDeterministic() bool {
+	return len(e.Volatile()) == 0
+}
+
+// Returns the expressions in the chain and its arguments which use a volatile value, in the order
+// they appear.
+func (e *Expr) Volatile() []VolatileExpr {
+	volatile := make([]VolatileExpr, 0)
+	for _, c := range e.Chain() {
+		if c.Value != nil && !c.Constant && !c.Bind && c.Value.Volatility != VolatilityNone {
+			volatile = append(volatile, VolatileExpr{Expr: c, Volatility: c.Value.Volatility})
+		}
+		for _, arg := range c.Arguments {
+			volatile = append(volatile, arg.Volatile()...)
+		}
+	}
+	return volatile
+}
+
+// Returns the distinct volatilities of the values used in the expression, in the order they appear.
+func (e *Expr) Volatilities() []Volatility {
+	volatilities := make([]Volatility, 0)
+	seen := make(map[Volatility]bool)
+	for _, v := range e.Volatile() {
+		if !seen[v.Volatility] {
+			seen[v.Volatility] = true
+			volatilities = append(volatilities, v.Volatility)
+		}
+	}
+	return volatilities
+}
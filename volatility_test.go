@@ -0,0 +1,50 @@
+package texpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolatility(t *testing.T) {
+	s := NewSystemRequired([]Type{{
+		Name:  "num",
+		Parse: func(x string) (any, error) { return x, nil },
+		Values: []Value{
+			{Path: "plus", Type: "num", Parameters: []Parameter{{Name: "value", Type: "num"}}},
+		},
+	}, {
+		Name: "root",
+		Values: []Value{
+			{Path: "total", Type: "num"},
+			{Path: "now", Type: "num", Volatility: VolatilityTime},
+			{Path: "random", Type: "num", Volatility: VolatilityRandom},
+			{Path: "rate", Type: "num", Volatility: VolatilityExternal},
+		},
+	}})
+
+	tests := []struct {
+		expression    string
+		deterministic bool
+		volatile      []string
+		volatilities  []Volatility
+	}{
+		{expression: "total.plus(1)", deterministic: true, volatile: []string{}, volatilities: []Volatility{}},
+		{expression: "total.plus(:x)", deterministic: true, volatile: []string{}, volatilities: []Volatility{}},
+		{expression: "now.plus(total)", volatile: []string{"now"}, volatilities: []Volatility{VolatilityTime}},
+		{expression: "total.plus(random.plus(rate)).plus(now).plus(rate)", volatile: []string{"random", "rate", "now", "rate"}, volatilities: []Volatility{VolatilityRandom, VolatilityExternal, VolatilityTime}},
+	}
+
+	for _, test := range tests {
+		e, err := s.Parse(Options{RootType: "root", Expression: test.expression, Parameters: map[string]TypeName{"x": "num"}})
+		assert.NoError(t, err, test.expression)
+		assert.Equal(t, test.deterministic, e.IsDeterministic(), test.expression)
+		volatile := make([]string, 0)
+		for _, v := range e.Volatile() {
+			volatile = append(volatile, v.Expr.Token)
+			assert.Equal(t, v.Expr.Value.Volatility, v.Volatility)
+		}
+		assert.Equal(t, test.volatile, volatile, test.expression)
+		assert.Equal(t, test.volatilities, e.Volatilities(), test.expression)
+	}
+}
@@ -0,0 +1,83 @@
+package texpr
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// Returns the date at the same instant in the given zone.
+	DateInZone DateOperation = "inZone"
+	// Returns the date at the same instant in UTC.
+	DateToUTC DateOperation = "toUTC"
+	// Returns the name of the zone of the date, like America/New_York or UTC.
+	DateZoneName DateOperation = "zoneName"
+	// Returns the offset of the date's zone from UTC in minutes at the date.
+	DateOffsetMinutes DateOperation = "offsetMinutes"
+)
+
+// Returns the inZone, toUTC, zoneName, and offsetMinutes values (see DateOperation) to add to a dateTime
+// type, where zone names have the given text type and offsets have the given int type. A zone is an
+// IANA zone name like America/New_York. Values whose type is not given are not returned.
+func ZoneValues(dateTime TypeName, text TypeName, integer TypeName) []Value {
+	values := []Value{{
+		Path:          string(DateToUTC),
+		Type:          dateTime,
+		Description:   "The same instant in UTC",
+		dateOperation: DateToUTC,
+	}}
+	if text != "" {
+		values = append(values, Value{
+			Path:          string(DateInZone),
+			Type:          dateTime,
+			Description:   "The same instant in the zone, like America/New_York",
+			Parameters:    []Parameter{{Name: "zone", Type: text}},
+			dateOperation: DateInZone,
+		}, Value{
+			Path:          string(DateZoneName),
+			Type:          text,
+			Description:   "The name of the zone, like America/New_York or UTC",
+			dateOperation: DateZoneName,
+		})
+	}
+	if integer != "" {
+		values = append(values, Value{
+			Path:          string(DateOffsetMinutes),
+			Type:          integer,
+			Description:   "The offset of the zone from UTC in minutes",
+			dateOperation: DateOffsetMinutes,
+		})
+	}
+	return values
+}
+
+// Returns the result of the zone operation on the time given the argument values.
+func (op DateOperation) applyZone(t time.Time, args []any) (any, error) {
+	expected := 0
+	if op == DateInZone {
+		expected = 1
+	}
+	if len(args) != expected {
+		return nil, fmt.Errorf("date operation %s expects %d argument(s) but was given %d", op, expected, len(args))
+	}
+	switch op {
+	case DateInZone:
+		name, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("date operation %s expects a zone name but was given %T", op, args[0])
+		}
+		zone, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("unknown zone %s: %w", name, err)
+		}
+		return t.In(zone), nil
+	case DateToUTC:
+		return t.UTC(), nil
+	case DateZoneName:
+		return t.Location().String(), nil
+	case DateOffsetMinutes:
+		_, offset := t.Zone()
+		return offset / 60, nil
+	}
+	return nil, fmt.Errorf("unknown date operation %s", op)
+}
@@ -0,0 +1,68 @@
+package texpr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type zoneEvent struct {
+	At time.Time
+}
+
+func TestZoneValues(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("zone database is not available")
+	}
+	r, err := NewReflect(ReflectOptions{
+		BoolType: "bool",
+		Types: map[reflect.Type]Type{
+			TypeOf[bool]():   {Name: "bool"},
+			TypeOf[int]():    {Name: "int"},
+			TypeOf[string](): {Name: "text", Parse: func(x string) (any, error) { return x, nil }},
+			TypeOf[time.Time](): {
+				Name:       "dateTime",
+				Comparable: true,
+				Ordered:    true,
+				Values:     ZoneValues("dateTime", "text", "int"),
+			},
+			TypeOf[zoneEvent](): {},
+		},
+	})
+	assert.NoError(t, err)
+
+	event := zoneEvent{At: time.Date(2024, 7, 1, 3, 30, 0, 0, time.UTC)}
+	tests := []struct {
+		expression string
+		result     any
+	}{
+		{expression: "at.inZone('America/New_York')", result: time.Date(2024, 6, 30, 23, 30, 0, 0, newYork)},
+		{expression: "at.inZone('America/New_York').zoneName", result: "America/New_York"},
+		{expression: "at.inZone('America/New_York').offsetMinutes", result: -240},
+		{expression: "at.inZone('America/New_York').toUTC", result: event.At},
+		{expression: "at.inZone('America/New_York').=(at)", result: true},
+		{expression: "at.zoneName", result: "UTC"},
+		{expression: "at.offsetMinutes", result: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := r.Parse(Options{RootType: NameOf[zoneEvent](), Expression: test.expression})
+			if !assert.NoError(t, err) {
+				return
+			}
+			result, err := r.Evaluate(e, event)
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+
+	e, err := r.Parse(Options{RootType: NameOf[zoneEvent](), Expression: "at.inZone('Mars/Olympus')"})
+	assert.NoError(t, err)
+	_, err = r.Evaluate(e, event)
+	assert.ErrorContains(t, err, "unknown zone Mars/Olympus")
+
+	assert.Len(t, ZoneValues("dateTime", "", ""), 1)
+}